@@ -21,6 +21,7 @@ package account
 import (
 	"github.com/ontio/ontology-crypto/keypair"
 	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/types"
@@ -93,6 +94,20 @@ func (this *Account) Scheme() s.SignatureScheme {
 	return this.SigScheme
 }
 
+// Sign return the serialized signature of data signed by account's private key
+func (this *Account) Sign(data []byte) ([]byte, error) {
+	sig, err := s.Sign(this.SigScheme, this.PrivateKey, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.Serialize(sig)
+}
+
+// Vrf return the vrf value and proof of data computed by account's private key
+func (this *Account) Vrf(data []byte) ([]byte, []byte, error) {
+	return vrf.Vrf(this.PrivateKey, data)
+}
+
 //AccountMetadata all account info without private key
 type AccountMetadata struct {
 	IsDefault bool   //Is default account
@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+)
+
+// Methods of remote signer protocol
+const (
+	REMOTE_SIGNER_GET_PUBKEY = "getpubkey"
+	REMOTE_SIGNER_SIGN       = "sign"
+	REMOTE_SIGNER_VRF        = "vrf"
+)
+
+const DEFAULT_REMOTE_SIGNER_TIMEOUT = 5 * time.Second
+
+// RemoteSignerRequest is one line of json sent to signing daemon
+type RemoteSignerRequest struct {
+	Qid    string `json:"qid"`
+	Method string `json:"method"`
+	Data   string `json:"data,omitempty"` //hex encoded data to sign
+}
+
+// RemoteSignerResponse is one line of json replied by signing daemon
+type RemoteSignerResponse struct {
+	Qid       string `json:"qid"`
+	PubKey    string `json:"pubkey,omitempty"`    //hex encoded public key, for getpubkey
+	Scheme    string `json:"scheme,omitempty"`    //signature scheme name, for getpubkey
+	Signature string `json:"signature,omitempty"` //hex encoded serialized signature, for sign
+	VrfValue  string `json:"vrf_value,omitempty"` //hex encoded vrf value, for vrf
+	VrfProof  string `json:"vrf_proof,omitempty"` //hex encoded vrf proof, for vrf
+	ErrorCode int    `json:"error_code"`
+	ErrorInfo string `json:"error_info"`
+}
+
+// RemoteSigner sign data by an external signing daemon listening on local socket.
+// Requests and responses are newline delimited json objects, one request in flight at a time.
+type RemoteSigner struct {
+	network   string
+	address   string
+	timeout   time.Duration
+	publicKey keypair.PublicKey
+	sigScheme s.SignatureScheme
+	Address   common.Address
+
+	lock   sync.Mutex
+	qid    uint64
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRemoteSigner connect to signing daemon at addr and load its public key.
+// addr is a unix socket path, or "tcp://host:port" for loopback tcp daemon.
+func NewRemoteSigner(addr string, timeout time.Duration) (*RemoteSigner, error) {
+	if timeout == 0 {
+		timeout = DEFAULT_REMOTE_SIGNER_TIMEOUT
+	}
+	network, address := "unix", strings.TrimPrefix(addr, "unix://")
+	if strings.HasPrefix(addr, "tcp://") {
+		network, address = "tcp", strings.TrimPrefix(addr, "tcp://")
+	}
+	signer := &RemoteSigner{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+	rsp, err := signer.call(REMOTE_SIGNER_GET_PUBKEY, nil)
+	if err != nil {
+		signer.Close()
+		return nil, fmt.Errorf("get public key from remote signer error:%s", err)
+	}
+	pkData, err := hex.DecodeString(rsp.PubKey)
+	if err != nil {
+		signer.Close()
+		return nil, fmt.Errorf("invalid public key from remote signer:%s", err)
+	}
+	signer.publicKey, err = keypair.DeserializePublicKey(pkData)
+	if err != nil {
+		signer.Close()
+		return nil, fmt.Errorf("invalid public key from remote signer:%s", err)
+	}
+	signer.sigScheme, err = s.GetScheme(rsp.Scheme)
+	if err != nil {
+		signer.Close()
+		return nil, fmt.Errorf("invalid signature scheme from remote signer:%s", err)
+	}
+	signer.Address = types.AddressFromPubKey(signer.publicKey)
+	return signer, nil
+}
+
+func (this *RemoteSigner) PubKey() keypair.PublicKey {
+	return this.publicKey
+}
+
+func (this *RemoteSigner) Scheme() s.SignatureScheme {
+	return this.sigScheme
+}
+
+// Sign return the serialized signature of data signed by remote signer,
+// which must be valid for the public key loaded from remote signer
+func (this *RemoteSigner) Sign(data []byte) ([]byte, error) {
+	rsp, err := this.call(REMOTE_SIGNER_SIGN, data)
+	if err != nil {
+		return nil, err
+	}
+	sigData, err := hex.DecodeString(rsp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer:%s", err)
+	}
+	sig, err := s.Deserialize(sigData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature from remote signer:%s", err)
+	}
+	if !s.Verify(this.publicKey, data, sig) {
+		return nil, fmt.Errorf("signature from remote signer verify failed")
+	}
+	return sigData, nil
+}
+
+// Vrf return the vrf value and proof of data computed by remote signer,
+// which must be valid for the public key loaded from remote signer
+func (this *RemoteSigner) Vrf(data []byte) ([]byte, []byte, error) {
+	rsp, err := this.call(REMOTE_SIGNER_VRF, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := hex.DecodeString(rsp.VrfValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vrf value from remote signer:%s", err)
+	}
+	proof, err := hex.DecodeString(rsp.VrfProof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vrf proof from remote signer:%s", err)
+	}
+	if ok, err := vrf.Verify(this.publicKey, data, value, proof); err != nil || !ok {
+		return nil, nil, fmt.Errorf("vrf from remote signer verify failed")
+	}
+	return value, proof, nil
+}
+
+// Close close the connection to signing daemon
+func (this *RemoteSigner) Close() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.closeConn()
+}
+
+func (this *RemoteSigner) closeConn() {
+	if this.conn != nil {
+		this.conn.Close()
+		this.conn = nil
+		this.reader = nil
+	}
+}
+
+func (this *RemoteSigner) call(method string, data []byte) (*RemoteSignerResponse, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.conn == nil {
+		conn, err := net.DialTimeout(this.network, this.address, this.timeout)
+		if err != nil {
+			return nil, fmt.Errorf("dial remote signer %s error:%s", this.address, err)
+		}
+		this.conn = conn
+		this.reader = bufio.NewReader(conn)
+	}
+	this.qid++
+	req := &RemoteSignerRequest{
+		Qid:    strconv.FormatUint(this.qid, 10),
+		Method: method,
+	}
+	if data != nil {
+		req.Data = hex.EncodeToString(data)
+	}
+	reqData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal request error:%s", err)
+	}
+	this.conn.SetDeadline(time.Now().Add(this.timeout))
+	if _, err = this.conn.Write(append(reqData, '\n')); err != nil {
+		this.closeConn()
+		return nil, fmt.Errorf("send request to remote signer error:%s", err)
+	}
+	line, err := this.reader.ReadBytes('\n')
+	if err != nil {
+		this.closeConn()
+		return nil, fmt.Errorf("read response from remote signer error:%s", err)
+	}
+	rsp := &RemoteSignerResponse{}
+	if err = json.Unmarshal(line, rsp); err != nil {
+		this.closeConn()
+		return nil, fmt.Errorf("json.Unmarshal response error:%s", err)
+	}
+	if rsp.Qid != req.Qid {
+		this.closeConn()
+		return nil, fmt.Errorf("remote signer response qid:%s unmatch request qid:%s", rsp.Qid, req.Qid)
+	}
+	if rsp.ErrorCode != 0 {
+		return nil, fmt.Errorf("remote signer %s error code:%d, info:%s", method, rsp.ErrorCode, rsp.ErrorInfo)
+	}
+	return rsp, nil
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveRemoteSigner serve the public key of acc, and sign by signer
+func serveRemoteSigner(t *testing.T, l net.Listener, acc, signer *Account) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for {
+				line, err := reader.ReadBytes('\n')
+				if err != nil {
+					return
+				}
+				req := &RemoteSignerRequest{}
+				if err := json.Unmarshal(line, req); err != nil {
+					t.Errorf("json.Unmarshal request error:%s", err)
+					return
+				}
+				rsp := &RemoteSignerResponse{Qid: req.Qid}
+				data, _ := hex.DecodeString(req.Data)
+				switch req.Method {
+				case REMOTE_SIGNER_GET_PUBKEY:
+					rsp.PubKey = hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
+					rsp.Scheme = acc.SigScheme.Name()
+				case REMOTE_SIGNER_SIGN:
+					sig, _ := signer.Sign(data)
+					rsp.Signature = hex.EncodeToString(sig)
+				case REMOTE_SIGNER_VRF:
+					value, proof, _ := signer.Vrf(data)
+					rsp.VrfValue = hex.EncodeToString(value)
+					rsp.VrfProof = hex.EncodeToString(proof)
+				default:
+					rsp.ErrorCode = 1004
+					rsp.ErrorInfo = "unsupport method"
+				}
+				rspData, _ := json.Marshal(rsp)
+				conn.Write(append(rspData, '\n'))
+			}
+		}(conn)
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote_signer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sockPath := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", sockPath)
+	assert.Nil(t, err)
+	defer l.Close()
+
+	acc := NewAccount("")
+	go serveRemoteSigner(t, l, acc, acc)
+
+	signer, err := NewRemoteSigner(sockPath, 0)
+	assert.Nil(t, err)
+	defer signer.Close()
+	assert.Equal(t, acc.Address, signer.Address)
+	assert.Equal(t, acc.SigScheme, signer.Scheme())
+	assert.Equal(t, keypair.SerializePublicKey(acc.PublicKey), keypair.SerializePublicKey(signer.PubKey()))
+
+	data := []byte{1, 2, 3}
+	sigData, err := signer.Sign(data)
+	assert.Nil(t, err)
+	sig, err := s.Deserialize(sigData)
+	assert.Nil(t, err)
+	assert.True(t, s.Verify(acc.PublicKey, data, sig))
+
+	value, proof, err := signer.Vrf(data)
+	assert.Nil(t, err)
+	ok, err := vrf.Verify(acc.PublicKey, data, value, proof)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = signer.call("unknown", nil)
+	assert.NotNil(t, err)
+}
+
+func TestRemoteSignerInvalidSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote_signer")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	sockPath := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", sockPath)
+	assert.Nil(t, err)
+	defer l.Close()
+
+	acc := NewAccount("")
+	go serveRemoteSigner(t, l, acc, NewAccount(""))
+
+	signer, err := NewRemoteSigner(sockPath, 0)
+	assert.Nil(t, err)
+	defer signer.Close()
+
+	_, err = signer.Sign([]byte{1, 2, 3})
+	assert.NotNil(t, err)
+	_, _, err = signer.Vrf([]byte{1, 2, 3})
+	assert.NotNil(t, err)
+}
+
+func TestRemoteSignerUnavailable(t *testing.T) {
+	_, err := NewRemoteSigner(filepath.Join(os.TempDir(), "not_exist_signer.sock"), 0)
+	assert.NotNil(t, err)
+}
@@ -39,6 +39,8 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setWebSocketConfig(ctx, cfg.Ws)
+	setGrpcConfig(ctx, cfg.Grpc)
+	setHealthConfig(ctx, cfg.Health)
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -120,12 +122,14 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
 	cfg.LogLevel = ctx.Uint(utils.GetFlagName(utils.LogLevelFlag))
 	cfg.EnableEventLog = !ctx.Bool(utils.GetFlagName(utils.DisableEventLogFlag))
+	cfg.StateUndoBlocks = ctx.Uint(utils.GetFlagName(utils.StateUndoBlocksFlag))
 	cfg.DataDir = ctx.String(utils.GetFlagName(utils.DataDirFlag))
 }
 
 func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) {
 	cfg.EnableConsensus = ctx.Bool(utils.GetFlagName(utils.EnableConsensusFlag))
 	cfg.MaxTxInBlock = ctx.Uint(utils.GetFlagName(utils.MaxTxInBlockFlag))
+	cfg.RemoteSigner = ctx.String(utils.GetFlagName(utils.RemoteSignerFlag))
 }
 
 func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
@@ -166,6 +170,9 @@ func setRpcConfig(ctx *cli.Context, cfg *config.RpcConfig) {
 	cfg.EnableHttpJsonRpc = !ctx.Bool(utils.GetFlagName(utils.RPCDisabledFlag))
 	cfg.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
 	cfg.HttpLocalPort = ctx.Uint(utils.GetFlagName(utils.RPCLocalProtFlag))
+	cfg.HttpLocalSocket = ctx.String(utils.GetFlagName(utils.RPCLocalSocketFlag))
+	cfg.AuthFile = ctx.String(utils.GetFlagName(utils.RPCAuthFileFlag))
+	cfg.LegacyEnvelope = ctx.Bool(utils.GetFlagName(utils.RPCLegacyEnvelopeFlag))
 }
 
 func setRestfulConfig(ctx *cli.Context, cfg *config.RestfulConfig) {
@@ -179,6 +186,23 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setGrpcConfig(ctx *cli.Context, cfg *config.GrpcConfig) {
+	cfg.EnableGrpc = ctx.Bool(utils.GetFlagName(utils.GrpcEnableFlag))
+	cfg.GrpcPort = ctx.Uint(utils.GetFlagName(utils.GrpcPortFlag))
+}
+
+func setHealthConfig(ctx *cli.Context, cfg *config.HealthConfig) {
+	cfg.MaxBlockLag = ctx.Uint(utils.GetFlagName(utils.HealthMaxBlockLagFlag))
+	cfg.MaxBlockAge = ctx.Uint(utils.GetFlagName(utils.HealthMaxBlockAgeFlag))
+	cfg.MaxTxPoolUsage = ctx.Uint(utils.GetFlagName(utils.HealthMaxTxPoolUsageFlag))
+}
+
+func SetLocalRpcPort(ctx *cli.Context) {
+	if ctx.IsSet(utils.GetFlagName(utils.RPCLocalProtFlag)) {
+		config.DefConfig.Rpc.HttpLocalPort = ctx.Uint(utils.GetFlagName(utils.RPCLocalProtFlag))
+	}
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
@@ -47,6 +47,7 @@ var ImportCommand = cli.Command{
 		utils.ConfigFlag,
 		utils.NetworkIdFlag,
 		utils.DisableEventLogFlag,
+		utils.StateUndoBlocksFlag,
 	},
 	Description: "Note that import cmd doesn't support testmode",
 }
@@ -19,12 +19,15 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/polynetwork/poly/cmd/utils"
 	"github.com/polynetwork/poly/core/types"
 	httpcom "github.com/polynetwork/poly/http/base/common"
 	"github.com/urfave/cli"
+	"io/ioutil"
 	"strconv"
 )
 
@@ -72,6 +75,19 @@ var InfoCommand = cli.Command{
 				utils.RPCPortFlag,
 			},
 		},
+		{
+			Action:    roundHistory,
+			Name:      "roundhistory",
+			Usage:     "Display consensus round history of latest blocks",
+			ArgsUsage: "",
+			Description: `Display proposal, endorse and commit messages, timer events and view changes of latest blocks
+kept by vbft consensus of local node, or export them as json trace for offline analysis.`,
+			Flags: []cli.Flag{
+				utils.RPCLocalProtFlag,
+				utils.RoundHistoryCountFlag,
+				utils.RoundTraceFileFlag,
+			},
+		},
 	},
 	Description: `Query information command can query information such as blocks, transactions, and transaction executions. 
 You can use the ./Ontology info block --help command to view help information.`,
@@ -189,3 +205,26 @@ func showTx(ctx *cli.Context) error {
 	PrintJsonObject(txInfo)
 	return nil
 }
+
+func roundHistory(ctx *cli.Context) error {
+	SetLocalRpcPort(ctx)
+	count := ctx.Uint(utils.GetFlagName(utils.RoundHistoryCountFlag))
+	data, err := utils.GetRoundTrace(count)
+	if err != nil {
+		return fmt.Errorf("GetRoundTrace error:%s", err)
+	}
+	traceFile := ctx.String(utils.GetFlagName(utils.RoundTraceFileFlag))
+	if traceFile == "" {
+		PrintJsonData(data)
+		return nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return fmt.Errorf("json.Indent error:%s", err)
+	}
+	if err := ioutil.WriteFile(traceFile, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write trace file error:%s", err)
+	}
+	PrintInfoMsg("Round history exported to %s", traceFile)
+	return nil
+}
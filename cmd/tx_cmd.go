@@ -19,7 +19,11 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/trace"
 	"github.com/urfave/cli"
 )
 
@@ -32,6 +36,8 @@ var SendTxCommand = cli.Command{
 	Flags: []cli.Flag{
 		utils.RPCPortFlag,
 		utils.PrepareExecTransactionFlag,
+		utils.SimulateTransactionFlag,
+		utils.SimulateHeightFlag,
 	},
 }
 
@@ -44,6 +50,14 @@ func sendTx(ctx *cli.Context) error {
 	}
 	rawTx := ctx.Args().First()
 
+	if ctx.IsSet(utils.GetFlagName(utils.SimulateTransactionFlag)) {
+		result, err := utils.SimulateTransaction(rawTx, uint32(ctx.Uint(utils.GetFlagName(utils.SimulateHeightFlag))))
+		if err != nil {
+			return err
+		}
+		printTrace(result)
+		return nil
+	}
 	isPre := ctx.IsSet(utils.GetFlagName(utils.PrepareExecTransactionFlag))
 	if isPre {
 		preResult, err := utils.PrepareSendRawTransaction(rawTx)
@@ -67,3 +81,69 @@ func sendTx(ctx *cli.Context) error {
 	PrintInfoMsg("  Using './ontology info status %s' to query transaction status.", txHash)
 	return nil
 }
+
+//max hex chars of args, results and values printed in a trace
+const maxTraceHexLen = 64
+
+func shortHex(data string) string {
+	if len(data) <= maxTraceHexLen {
+		return data
+	}
+	return fmt.Sprintf("%s...(%d bytes)", data[:maxTraceHexLen], len(data)/2)
+}
+
+func printTrace(result *trace.Result) {
+	if result.State == event.CONTRACT_STATE_SUCCESS {
+		PrintInfoMsg("Simulate transaction at height %d success.", result.Height)
+		PrintInfoMsg("  Result:%s", shortHex(result.Result))
+	} else {
+		PrintErrorMsg("Simulate transaction at height %d failed.", result.Height)
+		PrintInfoMsg("  Error:%s", result.Error)
+	}
+	if result.Failure != nil {
+		PrintInfoMsg("  Failing step:%s", strings.Join(result.Failure.Path, " > "))
+		PrintInfoMsg("  Failing error:%s", result.Failure.Error)
+	}
+	if result.Call != nil {
+		PrintInfoMsg("Call stack:")
+		printCall(result.Call, "  ")
+	}
+	if len(result.Notify) > 0 {
+		PrintInfoMsg("Notify:")
+		for _, notify := range result.Notify {
+			PrintInfoMsg("  %s %v", notify.ContractAddress, notify.States)
+		}
+	}
+	if len(result.CrossHashes) > 0 {
+		PrintInfoMsg("Cross hashes:")
+		for _, hash := range result.CrossHashes {
+			PrintInfoMsg("  %s", hash)
+		}
+	}
+	if len(result.StateDiff) > 0 {
+		PrintInfoMsg("State diff:")
+		for _, change := range result.StateDiff {
+			PrintInfoMsg("  %s", change.DecodedKey)
+			PrintInfoMsg("    - %s", shortHex(change.Before))
+			PrintInfoMsg("    + %s", shortHex(change.After))
+		}
+	}
+}
+
+func printCall(call *trace.Call, indent string) {
+	PrintInfoMsg("%s%s(%s)", indent, call.Name(), shortHex(call.Args))
+	for _, access := range call.Accesses {
+		PrintInfoMsg("%s  %-6s %s = %s", indent, access.Op, access.DecodedKey, shortHex(access.Value))
+	}
+	for _, notify := range call.Notify {
+		PrintInfoMsg("%s  notify %v", indent, notify.States)
+	}
+	for _, c := range call.Calls {
+		printCall(c, indent+"  ")
+	}
+	if call.Error != "" {
+		PrintInfoMsg("%s  => error:%s", indent, call.Error)
+	} else {
+		PrintInfoMsg("%s  => %s", indent, shortHex(call.Result))
+	}
+}
@@ -100,7 +100,14 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.ConfigFlag,
 			utils.LogLevelFlag,
+			utils.LogFormatFlag,
+			utils.LogModulesFlag,
+			utils.LogMaxSizeFlag,
+			utils.LogMaxAgeFlag,
+			utils.LogMaxBackupsFlag,
+			utils.DisableLogCompressFlag,
 			utils.DisableEventLogFlag,
+			utils.StateUndoBlocksFlag,
 			utils.DataDirFlag,
 		},
 	},
@@ -132,6 +139,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.EnableConsensusFlag,
 			utils.MaxTxInBlockFlag,
+			utils.RemoteSignerFlag,
 		},
 	},
 	{
@@ -161,8 +169,11 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.RPCDisabledFlag,
 			utils.RPCPortFlag,
+			utils.RPCLegacyEnvelopeFlag,
 			utils.RPCLocalEnableFlag,
 			utils.RPCLocalProtFlag,
+			utils.RPCLocalSocketFlag,
+			utils.RPCAuthFileFlag,
 		},
 	},
 	{
@@ -180,6 +191,21 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WsPortFlag,
 		},
 	},
+	{
+		Name: "GRPC",
+		Flags: []cli.Flag{
+			utils.GrpcEnableFlag,
+			utils.GrpcPortFlag,
+		},
+	},
+	{
+		Name: "HEALTH",
+		Flags: []cli.Flag{
+			utils.HealthMaxBlockLagFlag,
+			utils.HealthMaxBlockAgeFlag,
+			utils.HealthMaxTxPoolUsageFlag,
+		},
+	},
 	{
 		Name: "TEST MODE",
 		Flags: []cli.Flag{
@@ -217,6 +243,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ForceSendTxFlag,
 			utils.TransactionPayerFlag,
 			utils.PrepareExecTransactionFlag,
+			utils.SimulateTransactionFlag,
+			utils.SimulateHeightFlag,
 			utils.TransferFromAmountFlag,
 			utils.WithdrawONGReceiveAccountFlag,
 			utils.WithdrawONGAmountFlag,
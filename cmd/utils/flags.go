@@ -43,10 +43,41 @@ var (
 		Usage: "Set the log level to `<level>` (0~6). 0:Trace 1:Debug 2:Info 3:Warn 4:Error 5:Fatal 6:MaxLevel",
 		Value: config.DEFAULT_LOG_LEVEL,
 	}
+	LogFormatFlag = cli.StringFlag{
+		Name:  "logformat",
+		Usage: "Log output `<format>`, text or json",
+		Value: config.DEFAULT_LOG_FORMAT,
+	}
+	LogModulesFlag = cli.StringFlag{
+		Name:  "logmodules",
+		Usage: "Log level of modules, like `consensus/vbft=debug,p2pserver=warn`, other modules use loglevel",
+	}
+	LogMaxSizeFlag = cli.UintFlag{
+		Name:  "logmaxsize",
+		Usage: "Rotate log file when it exceeds `<MByte>`, 0 to disable",
+		Value: config.DEFAULT_MAX_LOG_SIZE,
+	}
+	LogMaxAgeFlag = cli.UintFlag{
+		Name:  "logmaxage",
+		Usage: "Rotate log file when it is written for `<hours>`, 0 to disable",
+	}
+	LogMaxBackupsFlag = cli.UintFlag{
+		Name:  "logmaxbackups",
+		Usage: "Max `<number>` of rotated log files kept, 0 to keep all",
+	}
+	DisableLogCompressFlag = cli.BoolFlag{
+		Name:  "disable-log-compress",
+		Usage: "Keep rotated log files uncompressed",
+	}
 	DisableEventLogFlag = cli.BoolFlag{
 		Name:  "disable-event-log",
 		Usage: "Discard event log output by smart contract execution",
 	}
+	StateUndoBlocksFlag = cli.UintFlag{
+		Name:  "state-undo-blocks",
+		Usage: "Keep the previous states of recent `<number>` blocks to simulate transactions against them, 0 to disable",
+		Value: config.DEFAULT_STATE_UNDO_BLOCKS,
+	}
 	WalletFileFlag = cli.StringFlag{
 		Name:  "wallet,w",
 		Value: config.DEFAULT_WALLET_FILE_NAME,
@@ -78,6 +109,10 @@ var (
 		Usage: "Max transaction `<number>` in block",
 		Value: config.DEFAULT_MAX_TX_IN_BLOCK,
 	}
+	RemoteSignerFlag = cli.StringFlag{
+		Name:  "remote-signer",
+		Usage: "Sign consensus messages by external signing daemon at unix socket `<path>` (or tcp://host:port) instead of wallet account",
+	}
 
 	//Test Mode setting
 	EnableTestModeFlag = cli.BoolFlag{
@@ -149,6 +184,10 @@ var (
 		Usage: "Json rpc server listening port `<number>`",
 		Value: config.DEFAULT_RPC_PORT,
 	}
+	RPCLegacyEnvelopeFlag = cli.BoolFlag{
+		Name:  "rpc-legacy-envelope",
+		Usage: "Answer json rpc calls with the legacy error/desc/result envelope instead of JSON-RPC 2.0 error objects",
+	}
 	RPCLocalEnableFlag = cli.BoolFlag{
 		Name:  "localrpc",
 		Usage: "Enable local rpc server",
@@ -158,6 +197,14 @@ var (
 		Usage: "Json rpc local server listening port `<number>`",
 		Value: config.DEFAULT_RPC_LOCAL_PORT,
 	}
+	RPCLocalSocketFlag = cli.StringFlag{
+		Name:  "localrpcsocket",
+		Usage: "Also serve local rpc on unix socket `<path>`, accessible only to the node owner",
+	}
+	RPCAuthFileFlag = cli.StringFlag{
+		Name:  "rpcauthfile",
+		Usage: "Access control `<file>` of rpc, restful, websocket and grpc methods, local rpc admin methods are only served to localhost without it",
+	}
 
 	//Websocket setting
 	WsEnabledFlag = cli.BoolFlag{
@@ -170,6 +217,34 @@ var (
 		Value: config.DEFAULT_WS_PORT,
 	}
 
+	//Grpc setting
+	GrpcEnableFlag = cli.BoolFlag{
+		Name:  "grpc",
+		Usage: "Enable grpc server",
+	}
+	GrpcPortFlag = cli.UintFlag{
+		Name:  "grpcport",
+		Usage: "Grpc server listening port `<number>`",
+		Value: config.DEFAULT_GRPC_PORT,
+	}
+
+	//Health setting
+	HealthMaxBlockLagFlag = cli.UintFlag{
+		Name:  "health-max-block-lag",
+		Usage: "Max `<number>` of blocks behind best known peer for node to be ready",
+		Value: config.DEFAULT_HEALTH_MAX_BLOCK_LAG,
+	}
+	HealthMaxBlockAgeFlag = cli.UintFlag{
+		Name:  "health-max-block-age",
+		Usage: "Max `<seconds>` since last block for node to be ready, 0 to disable",
+		Value: config.DEFAULT_HEALTH_MAX_BLOCK_AGE,
+	}
+	HealthMaxTxPoolUsageFlag = cli.UintFlag{
+		Name:  "health-max-txpool-usage",
+		Usage: "Max `<percent>` of txpool capacity in use for node to be ready",
+		Value: config.DEFAULT_HEALTH_MAX_TXPOOL_USAGE,
+	}
+
 	//Restful setting
 	RestfulEnableFlag = cli.BoolFlag{
 		Name:  "rest",
@@ -377,6 +452,14 @@ var (
 		Name:  "prepare,p",
 		Usage: "Prepare execute transaction, without commit to ledger",
 	}
+	SimulateTransactionFlag = cli.BoolFlag{
+		Name:  "simulate",
+		Usage: "Simulate transaction without commit to ledger, printing the trace of execution",
+	}
+	SimulateHeightFlag = cli.UintFlag{
+		Name:  "simulate-height",
+		Usage: "Simulate transaction against the state at `<height>`, current block if 0. Other heights need the node to run with --state-undo-blocks",
+	}
 	WithdrawONGReceiveAccountFlag = cli.StringFlag{
 		Name:  "receive",
 		Usage: "ONG receive `<address>`，Default the same with owner account",
@@ -413,6 +496,14 @@ var (
 	}
 
 	//Export setting
+	RoundHistoryCountFlag = cli.UintFlag{
+		Name:  "count",
+		Usage: "Latest `<number>` of blocks of round history, 0 for all kept blocks",
+	}
+	RoundTraceFileFlag = cli.StringFlag{
+		Name:  "trace-file",
+		Usage: "Write round history as json trace to `<file>` instead of printing it",
+	}
 	ExportFileFlag = cli.StringFlag{
 		Name:  "export-file",
 		Usage: "Export `<file>` path",
@@ -64,13 +64,54 @@ type JsonRpcRequest struct {
 }
 
 //JsonRpcResponse object response for JsonRpcRequest
+//Error is the legacy error code or a JSON-RPC 2.0 error object
 type JsonRpcResponse struct {
-	Error  int64           `json:"error"`
+	Error  json.RawMessage `json:"error"`
 	Desc   string          `json:"desc"`
 	Result json.RawMessage `json:"result"`
 }
 
+//JsonRpcError object of a JSON-RPC 2.0 error response
+type JsonRpcError struct {
+	Code    int64           `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+//ErrorCode returns the legacy error code of the response
+func (this *JsonRpcResponse) ErrorCode() (int64, error) {
+	if len(this.Error) == 0 || string(this.Error) == "null" {
+		return ERROR_ONTOLOGY_SUCCESS, nil
+	}
+	var code int64
+	if err := json.Unmarshal(this.Error, &code); err == nil {
+		return code, nil
+	}
+	rpcErr := &JsonRpcError{}
+	if err := json.Unmarshal(this.Error, rpcErr); err != nil {
+		return 0, err
+	}
+	switch rpcErr.Code {
+	case rpcerr.JSONRPC_INVALID_PARAMS:
+		return rpcerr.INVALID_PARAMS, nil
+	case rpcerr.JSONRPC_METHOD_NOT_FOUND:
+		return rpcerr.INVALID_METHOD, nil
+	case rpcerr.JSONRPC_INTERNAL_ERROR:
+		return rpcerr.INTERNAL_ERROR, nil
+	}
+	return rpcErr.Code, nil
+}
+
 func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError) {
+	return postRpcRequest(fmt.Sprintf("http://localhost:%d", config.DefConfig.Rpc.HttpJsonPort), method, params)
+}
+
+//sendLocalRpcRequest send request to local rpc server of node
+func sendLocalRpcRequest(method string, params []interface{}) ([]byte, *OntologyError) {
+	return postRpcRequest(fmt.Sprintf("http://localhost:%d/local", config.DefConfig.Rpc.HttpLocalPort), method, params)
+}
+
+func postRpcRequest(addr string, method string, params []interface{}) ([]byte, *OntologyError) {
 	rpcReq := &JsonRpcRequest{
 		Version: JSON_RPC_VERSION,
 		Id:      "cli",
@@ -82,7 +123,6 @@ func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError
 		return nil, NewOntologyError(fmt.Errorf("JsonRpcRequest json.Marshal error:%s", err))
 	}
 
-	addr := fmt.Sprintf("http://localhost:%d", config.DefConfig.Rpc.HttpJsonPort)
 	resp, err := http.Post(addr, "application/json", strings.NewReader(string(data)))
 	if err != nil {
 		return nil, NewOntologyError(err)
@@ -98,8 +138,12 @@ func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError
 	if err != nil {
 		return nil, NewOntologyError(fmt.Errorf("json.Unmarshal JsonRpcResponse:%s error:%s", body, err))
 	}
-	if rpcRsp.Error != 0 {
-		return nil, NewOntologyError(fmt.Errorf("\n %s ", string(body)), rpcRsp.Error)
+	errCode, err := rpcRsp.ErrorCode()
+	if err != nil {
+		return nil, NewOntologyError(fmt.Errorf("json.Unmarshal JsonRpcResponse:%s error:%s", body, err))
+	}
+	if errCode != ERROR_ONTOLOGY_SUCCESS {
+		return nil, NewOntologyError(fmt.Errorf("\n %s ", string(body)), errCode)
 	}
 	return rpcRsp.Result, nil
 }
@@ -31,10 +31,9 @@ import (
 	"strings"
 
 	"github.com/ontio/ontology-crypto/keypair"
-	sig "github.com/ontio/ontology-crypto/signature"
-	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/native/trace"
 )
 
 func GetJsonObjectFromFile(filePath string, jsonObject interface{}) error {
@@ -101,6 +100,19 @@ func PrepareSendRawTransaction(txData string) (*states.PreExecResult, error) {
 	return preResult, nil
 }
 
+func SimulateTransaction(txData string, height uint32) (*trace.Result, error) {
+	data, ontErr := sendRpcRequest("simulatetransaction", []interface{}{txData, height})
+	if ontErr != nil {
+		return nil, ontErr.Error
+	}
+	result := &trace.Result{}
+	err := json.Unmarshal(data, result)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal trace.Result:%s error:%s", data, err)
+	}
+	return result, nil
+}
+
 func GetRawTransaction(txHash string) ([]byte, error) {
 	data, ontErr := sendRpcRequest("getrawtransaction", []interface{}{txHash, 1})
 	if ontErr == nil {
@@ -172,6 +184,15 @@ func GetBlockCount() (uint32, error) {
 	return num, nil
 }
 
+//GetRoundTrace return the json vbft round history of latest count blocks from local rpc
+func GetRoundTrace(count uint) ([]byte, error) {
+	data, ontErr := sendLocalRpcRequest("getroundhistory", []interface{}{count})
+	if ontErr != nil {
+		return nil, ontErr.Error
+	}
+	return data, nil
+}
+
 func GetTxHeight(txHash string) (uint32, error) {
 	data, ontErr := sendRpcRequest("getblockheightbytxhash", []interface{}{txHash})
 	if ontErr != nil {
@@ -189,7 +210,7 @@ func GetTxHeight(txHash string) (uint32, error) {
 	return height, nil
 }
 
-func SignTransaction(signer *account.Account, tx *types.Transaction) error {
+func SignTransaction(signer signature.Signer, tx *types.Transaction) error {
 	txHash := tx.Hash()
 	sigData, err := Sign(txHash.ToArray(), signer)
 	if err != nil {
@@ -197,8 +218,8 @@ func SignTransaction(signer *account.Account, tx *types.Transaction) error {
 	}
 	hasSig := false
 	for i, sig := range tx.Sigs {
-		if len(sig.PubKeys) == 1 && pubKeysEqual(sig.PubKeys, []keypair.PublicKey{signer.PublicKey}) {
-			if hasAlreadySig(txHash.ToArray(), signer.PublicKey, sig.SigData) {
+		if len(sig.PubKeys) == 1 && pubKeysEqual(sig.PubKeys, []keypair.PublicKey{signer.PubKey()}) {
+			if hasAlreadySig(txHash.ToArray(), signer.PubKey(), sig.SigData) {
 				//has already signed
 				return nil
 			}
@@ -209,7 +230,7 @@ func SignTransaction(signer *account.Account, tx *types.Transaction) error {
 	}
 	if !hasSig {
 		tx.Sigs = append(tx.Sigs, types.Sig{
-			PubKeys: []keypair.PublicKey{signer.PublicKey},
+			PubKeys: []keypair.PublicKey{signer.PubKey()},
 			M:       1,
 			SigData: [][]byte{sigData},
 		})
@@ -217,14 +238,14 @@ func SignTransaction(signer *account.Account, tx *types.Transaction) error {
 	return nil
 }
 
-func MultiSigTransaction(mutTx *types.Transaction, m uint16, pubKeys []keypair.PublicKey, signer *account.Account) error {
+func MultiSigTransaction(mutTx *types.Transaction, m uint16, pubKeys []keypair.PublicKey, signer signature.Signer) error {
 	pkSize := len(pubKeys)
 	if m == 0 || int(m) > pkSize || pkSize > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
 		return fmt.Errorf("invalid params")
 	}
 	validPubKey := false
 	for _, pk := range pubKeys {
-		if keypair.ComparePublicKey(pk, signer.PublicKey) {
+		if keypair.ComparePublicKey(pk, signer.PubKey()) {
 			validPubKey = true
 			break
 		}
@@ -249,7 +270,7 @@ func MultiSigTransaction(mutTx *types.Transaction, m uint16, pubKeys []keypair.P
 			continue
 		}
 		hasMutilSig = true
-		if hasAlreadySig(txHash.ToArray(), signer.PublicKey, sigs.SigData) {
+		if hasAlreadySig(txHash.ToArray(), signer.PubKey(), sigs.SigData) {
 			break
 		}
 		sigs.SigData = append(sigs.SigData, sigData)
@@ -314,15 +335,11 @@ func pubKeysEqual(pks1, pks2 []keypair.PublicKey) bool {
 	return true
 }
 
-//Sign sign return the signature to the data of private key
-func Sign(data []byte, signer *account.Account) ([]byte, error) {
-	s, err := sig.Sign(signer.SigScheme, signer.PrivateKey, data, nil)
-	if err != nil {
-		return nil, err
-	}
-	sigData, err := sig.Serialize(s)
+//Sign sign return the signature to the data of signer
+func Sign(data []byte, signer signature.Signer) ([]byte, error) {
+	sigData, err := signer.Sign(data)
 	if err != nil {
-		return nil, fmt.Errorf("sig.Serialize error:%s", err)
+		return nil, fmt.Errorf("sign data error:%s", err)
 	}
 	return sigData, nil
 }
@@ -48,12 +48,17 @@ const (
 
 	DEFAULT_LOG_LEVEL                       = log.InfoLog
 	DEFAULT_MAX_LOG_SIZE                    = 100 //MByte
+	DEFAULT_LOG_FORMAT                      = log.FORMAT_TEXT
 	DEFAULT_NODE_PORT                       = uint(20338)
 	DEFAULT_CONSENSUS_PORT                  = uint(20339)
 	DEFAULT_RPC_PORT                        = uint(20336)
 	DEFAULT_RPC_LOCAL_PORT                  = uint(20337)
 	DEFAULT_REST_PORT                       = uint(20334)
 	DEFAULT_WS_PORT                         = uint(20335)
+	DEFAULT_GRPC_PORT                       = uint(20333)
+	DEFAULT_HEALTH_MAX_BLOCK_LAG            = uint(10)
+	DEFAULT_HEALTH_MAX_BLOCK_AGE            = uint(0)
+	DEFAULT_HEALTH_MAX_TXPOOL_USAGE         = uint(90)
 	DEFAULT_REST_MAX_CONN                   = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND               = uint(1024)
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
@@ -63,6 +68,7 @@ const (
 	DEFAULT_MAX_SYNC_HEADER                 = 500
 	DEFAULT_ENABLE_CONSENSUS                = true
 	DEFAULT_ENABLE_EVENT_LOG                = true
+	DEFAULT_STATE_UNDO_BLOCKS               = uint(0)
 	DEFAULT_CLI_RPC_PORT                    = uint(20000)
 	DEFUALT_CLI_RPC_ADDRESS                 = "127.0.0.1"
 	DEFAULT_GAS_LIMIT                       = 20000
@@ -118,6 +124,16 @@ var HECO120_HEIGHT = map[uint32]uint64{
 	NETWORK_ID_TEST_NET: constants.HECO120_HEIGHT_TESTNET,
 }
 
+var FEE_LEDGER_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.FEE_LEDGER_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.FEE_LEDGER_HEIGHT_TESTNET,
+}
+
+var PEER_PERFORMANCE_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.PEER_PERFORMANCE_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.PEER_PERFORMANCE_HEIGHT_TESTNET,
+}
+
 var POLYGON_SNAP_CHAINID = map[uint32]uint32{
 	NETWORK_ID_MAIN_NET: constants.POLYGON_SNAP_CHAINID_MAINNET,
 }
@@ -165,6 +181,14 @@ func GetExtraInfoHeight(id uint32) uint32 {
 	return EXTRA_INFO_HEIGHT[id]
 }
 
+func GetFeeLedgerHeight(id uint32) uint32 {
+	return FEE_LEDGER_HEIGHT[id]
+}
+
+func GetPeerPerformanceHeight(id uint32) uint32 {
+	return PEER_PERFORMANCE_HEIGHT[id]
+}
+
 func GetNetworkName(id uint32) string {
 	name, ok := NETWORK_NAME[id]
 	if ok {
@@ -427,18 +451,20 @@ type SOLOConfig struct {
 }
 
 type CommonConfig struct {
-	LogLevel       uint
-	NodeType       string
-	EnableEventLog bool
-	SystemFee      map[string]int64
-	GasLimit       uint64
-	GasPrice       uint64
-	DataDir        string
+	LogLevel        uint
+	NodeType        string
+	EnableEventLog  bool
+	StateUndoBlocks uint //count of recent blocks whose previous states are kept for simulation, 0 to disable
+	SystemFee       map[string]int64
+	GasLimit        uint64
+	GasPrice        uint64
+	DataDir         string
 }
 
 type ConsensusConfig struct {
 	EnableConsensus bool
 	MaxTxInBlock    uint
+	RemoteSigner    string
 }
 
 type P2PRsvConfig struct {
@@ -470,6 +496,9 @@ type RpcConfig struct {
 	EnableHttpJsonRpc bool
 	HttpJsonPort      uint
 	HttpLocalPort     uint
+	HttpLocalSocket   string //unix socket path local rpc also listens on, empty to disable
+	LegacyEnvelope    bool
+	AuthFile          string //access control of rpc, restful, websocket and grpc methods, empty to disable
 }
 
 type RestfulConfig struct {
@@ -487,6 +516,17 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+type GrpcConfig struct {
+	EnableGrpc bool
+	GrpcPort   uint
+}
+
+type HealthConfig struct {
+	MaxBlockLag    uint //max blocks behind best known peer to be ready
+	MaxBlockAge    uint //max seconds since last block to be ready, 0 to disable
+	MaxTxPoolUsage uint //max percent of txpool capacity in use to be ready
+}
+
 type OntologyConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
@@ -495,17 +535,20 @@ type OntologyConfig struct {
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	Ws        *WebSocketConfig
+	Grpc      *GrpcConfig
+	Health    *HealthConfig
 }
 
 func NewOntologyConfig() *OntologyConfig {
 	return &OntologyConfig{
 		Genesis: MainNetConfig,
 		Common: &CommonConfig{
-			LogLevel:       DEFAULT_LOG_LEVEL,
-			EnableEventLog: DEFAULT_ENABLE_EVENT_LOG,
-			SystemFee:      make(map[string]int64),
-			GasLimit:       DEFAULT_GAS_LIMIT,
-			DataDir:        DEFAULT_DATA_DIR,
+			LogLevel:        DEFAULT_LOG_LEVEL,
+			EnableEventLog:  DEFAULT_ENABLE_EVENT_LOG,
+			StateUndoBlocks: DEFAULT_STATE_UNDO_BLOCKS,
+			SystemFee:       make(map[string]int64),
+			GasLimit:        DEFAULT_GAS_LIMIT,
+			DataDir:         DEFAULT_DATA_DIR,
 		},
 		Consensus: &ConsensusConfig{
 			EnableConsensus: true,
@@ -543,6 +586,15 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		Grpc: &GrpcConfig{
+			EnableGrpc: false,
+			GrpcPort:   DEFAULT_GRPC_PORT,
+		},
+		Health: &HealthConfig{
+			MaxBlockLag:    DEFAULT_HEALTH_MAX_BLOCK_LAG,
+			MaxBlockAge:    DEFAULT_HEALTH_MAX_BLOCK_AGE,
+			MaxTxPoolUsage: DEFAULT_HEALTH_MAX_TXPOOL_USAGE,
+		},
 	}
 }
 
@@ -56,3 +56,12 @@ const POLYGON_SNAP_CHAINID_MAINNET = 16
 
 // eth arrow glacier upgrade
 const ETH4345_HEIGHT_MAINNET = 13_773_000
+
+// fee ledger start height, not scheduled on main net and test net yet
+const FEE_LEDGER_HEIGHT_MAINNET = 1<<32 - 1
+const FEE_LEDGER_HEIGHT_TESTNET = 1<<32 - 1
+
+// peer performance start height, since which blocks record prev block signers and commitDpos takes the
+// peer performance of consensus, not scheduled on main net and test net yet
+const PEER_PERFORMANCE_HEIGHT_MAINNET = 1<<32 - 1
+const PEER_PERFORMANCE_HEIGHT_TESTNET = 1<<32 - 1
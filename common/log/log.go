@@ -30,6 +30,8 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -65,6 +67,15 @@ var (
 		FatalLog: Color(Red, "[FATAL]"),
 		TraceLog: Color(Pink, "[TRACE]"),
 	}
+	levelNames = map[int]string{
+		TraceLog:    "trace",
+		DebugLog:    "debug",
+		InfoLog:     "info",
+		WarnLog:     "warn",
+		ErrorLog:    "error",
+		FatalLog:    "fatal",
+		MaxLevelLog: "max",
+	}
 	Stdout = os.Stdout
 )
 
@@ -76,6 +87,12 @@ const (
 	PATH                 = "./Log/"
 )
 
+//output format of log records
+const (
+	FORMAT_TEXT = "text"
+	FORMAT_JSON = "json"
+)
+
 func GetGID() uint64 {
 	var buf [64]byte
 	b := buf[:runtime.Stack(buf[:], false)]
@@ -112,18 +129,41 @@ func NameLevel(name string) int {
 	return level
 }
 
+//ParseLevel parse level from its number or name, like "1" or "debug"
+func ParseLevel(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for k, v := range levelNames {
+		if v == s {
+			return k, nil
+		}
+	}
+	level, err := strconv.Atoi(s)
+	if err != nil || level < 0 || level > MaxLevelLog {
+		return 0, fmt.Errorf("invalid log level %q", s)
+	}
+	return level, nil
+}
+
 type Logger struct {
-	level   int
+	level   int32
+	format  atomic.Value //string
+	modules atomic.Value //map[string]int, module => level, replaced on change
 	logger  *log.Logger
-	logFile *os.File
+	out     io.Writer
+	lock    sync.Mutex //serialize json records and module level changes
+	logFile *RotateWriter
 }
 
-func New(out io.Writer, prefix string, flag, level int, file *os.File) *Logger {
-	return &Logger{
-		level:   level,
+func New(out io.Writer, prefix string, flag, level int, file *RotateWriter) *Logger {
+	l := &Logger{
+		level:   int32(level),
 		logger:  log.New(out, prefix, flag),
+		out:     out,
 		logFile: file,
 	}
+	l.format.Store(FORMAT_TEXT)
+	l.modules.Store(map[string]int(nil))
+	return l
 }
 
 func (l *Logger) SetDebugLevel(level int) error {
@@ -131,184 +171,257 @@ func (l *Logger) SetDebugLevel(level int) error {
 		return errors.New("Invalid Debug Level")
 	}
 
-	l.level = level
+	atomic.StoreInt32(&l.level, int32(level))
 	return nil
 }
 
-func (l *Logger) Output(level int, a ...interface{}) error {
-	if level >= l.level {
-		gid := GetGID()
-		gidStr := strconv.FormatUint(gid, 10)
-
-		a = append([]interface{}{LevelName(level), "GID",
-			gidStr + ","}, a...)
+//GetDebugLevel return the level of modules without their own level
+func (l *Logger) GetDebugLevel() int {
+	return int(atomic.LoadInt32(&l.level))
+}
 
-		return l.logger.Output(CALL_DEPTH, fmt.Sprintln(a...))
+//SetFormat set the output format of records, text or json
+func (l *Logger) SetFormat(format string) error {
+	if format != FORMAT_TEXT && format != FORMAT_JSON {
+		return fmt.Errorf("invalid log format %q", format)
 	}
+	l.format.Store(format)
 	return nil
 }
 
+func (l *Logger) Output(level int, a ...interface{}) error {
+	return l.print(1, level, false, "", a, nil)
+}
+
 func (l *Logger) Outputf(level int, format string, v ...interface{}) error {
-	if level >= l.level {
-		gid := GetGID()
-		v = append([]interface{}{LevelName(level), "GID",
-			gid}, v...)
+	return l.print(1, level, true, format, v, nil)
+}
 
-		return l.logger.Output(CALL_DEPTH, fmt.Sprintf("%s %s %d, "+format+"\n", v...))
+//print write the record if level is enabled for module of the caller, which is depth frames above print
+func (l *Logger) print(depth, level int, formatted bool, format string, a []interface{}, kv []interface{}) error {
+	module, ok := l.enabled(depth+1, level)
+	if !ok {
+		return nil
 	}
-	return nil
+	gid := GetGID()
+	var msg string
+	if formatted {
+		msg = fmt.Sprintf(format, a...)
+	} else {
+		msg = strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+	}
+	if l.format.Load().(string) == FORMAT_JSON {
+		return l.writeJSON(level, module, gid, msg, kv)
+	}
+	var buf strings.Builder
+	if formatted {
+		fmt.Fprintf(&buf, "%s %s %d, %s", LevelName(level), "GID", gid, msg)
+	} else {
+		buf.WriteString(strings.TrimSuffix(fmt.Sprintln(LevelName(level), "GID", strconv.FormatUint(gid, 10)+","), "\n"))
+		if len(a) > 0 {
+			buf.WriteString(" " + msg)
+		}
+	}
+	for i := 0; i < len(kv); i += 2 {
+		key, value := keyValue(kv, i)
+		fmt.Fprintf(&buf, " %s=%v", key, value)
+	}
+	buf.WriteString("\n")
+	return l.logger.Output(CALL_DEPTH, buf.String())
 }
 
 func (l *Logger) Trace(a ...interface{}) {
-	l.Output(TraceLog, a...)
+	l.print(1, TraceLog, false, "", a, nil)
 }
 
 func (l *Logger) Tracef(format string, a ...interface{}) {
-	l.Outputf(TraceLog, format, a...)
+	l.print(1, TraceLog, true, format, a, nil)
+}
+
+func (l *Logger) Tracew(msg string, kv ...interface{}) {
+	l.print(1, TraceLog, false, "", []interface{}{msg}, kv)
 }
 
 func (l *Logger) Debug(a ...interface{}) {
-	l.Output(DebugLog, a...)
+	l.print(1, DebugLog, false, "", a, nil)
 }
 
 func (l *Logger) Debugf(format string, a ...interface{}) {
-	l.Outputf(DebugLog, format, a...)
+	l.print(1, DebugLog, true, format, a, nil)
+}
+
+func (l *Logger) Debugw(msg string, kv ...interface{}) {
+	l.print(1, DebugLog, false, "", []interface{}{msg}, kv)
 }
 
 func (l *Logger) Info(a ...interface{}) {
-	l.Output(InfoLog, a...)
+	l.print(1, InfoLog, false, "", a, nil)
 }
 
 func (l *Logger) Infof(format string, a ...interface{}) {
-	l.Outputf(InfoLog, format, a...)
+	l.print(1, InfoLog, true, format, a, nil)
+}
+
+func (l *Logger) Infow(msg string, kv ...interface{}) {
+	l.print(1, InfoLog, false, "", []interface{}{msg}, kv)
 }
 
 func (l *Logger) Warn(a ...interface{}) {
-	l.Output(WarnLog, a...)
+	l.print(1, WarnLog, false, "", a, nil)
 }
 
 func (l *Logger) Warnf(format string, a ...interface{}) {
-	l.Outputf(WarnLog, format, a...)
+	l.print(1, WarnLog, true, format, a, nil)
+}
+
+func (l *Logger) Warnw(msg string, kv ...interface{}) {
+	l.print(1, WarnLog, false, "", []interface{}{msg}, kv)
 }
 
 func (l *Logger) Error(a ...interface{}) {
-	l.Output(ErrorLog, a...)
+	l.print(1, ErrorLog, false, "", a, nil)
 }
 
 func (l *Logger) Errorf(format string, a ...interface{}) {
-	l.Outputf(ErrorLog, format, a...)
+	l.print(1, ErrorLog, true, format, a, nil)
+}
+
+func (l *Logger) Errorw(msg string, kv ...interface{}) {
+	l.print(1, ErrorLog, false, "", []interface{}{msg}, kv)
 }
 
 func (l *Logger) Fatal(a ...interface{}) {
-	l.Output(FatalLog, a...)
+	l.print(1, FatalLog, false, "", a, nil)
 }
 
 func (l *Logger) Fatalf(format string, a ...interface{}) {
-	l.Outputf(FatalLog, format, a...)
+	l.print(1, FatalLog, true, format, a, nil)
+}
+
+func (l *Logger) Fatalw(msg string, kv ...interface{}) {
+	l.print(1, FatalLog, false, "", []interface{}{msg}, kv)
+}
+
+//caller return the function name and file:line of the caller skip frames above
+func caller(skip int) (string, string, int) {
+	pc := make([]uintptr, 1)
+	runtime.Callers(skip+2, pc)
+	frame, _ := runtime.CallersFrames(pc).Next()
+	return frame.Function, filepath.Base(frame.File), frame.Line
 }
 
 func Trace(a ...interface{}) {
-	if TraceLog < Log.level {
+	if _, ok := Log.enabled(1, TraceLog); !ok {
 		return
 	}
 
-	pc := make([]uintptr, 10)
-	runtime.Callers(2, pc)
-	f := runtime.FuncForPC(pc[0])
-	file, line := f.FileLine(pc[0])
-	fileName := filepath.Base(file)
-
-	nameFull := f.Name()
+	nameFull, fileName, line := caller(1)
 	nameEnd := filepath.Ext(nameFull)
 	funcName := strings.TrimPrefix(nameEnd, ".")
 
 	a = append([]interface{}{funcName + "()", fileName + ":" + strconv.Itoa(line)}, a...)
 
-	Log.Trace(a...)
+	Log.print(1, TraceLog, false, "", a, nil)
 }
 
 func Tracef(format string, a ...interface{}) {
-	if TraceLog < Log.level {
+	if _, ok := Log.enabled(1, TraceLog); !ok {
 		return
 	}
 
-	pc := make([]uintptr, 10)
-	runtime.Callers(2, pc)
-	f := runtime.FuncForPC(pc[0])
-	file, line := f.FileLine(pc[0])
-	fileName := filepath.Base(file)
-
-	nameFull := f.Name()
+	nameFull, fileName, line := caller(1)
 	nameEnd := filepath.Ext(nameFull)
 	funcName := strings.TrimPrefix(nameEnd, ".")
 
 	a = append([]interface{}{funcName, fileName, line}, a...)
 
-	Log.Tracef("%s() %s:%d "+format, a...)
+	Log.print(1, TraceLog, true, "%s() %s:%d "+format, a, nil)
 }
 
 func Debug(a ...interface{}) {
-	if DebugLog < Log.level {
+	if _, ok := Log.enabled(1, DebugLog); !ok {
 		return
 	}
 
-	pc := make([]uintptr, 10)
-	runtime.Callers(2, pc)
-	f := runtime.FuncForPC(pc[0])
-	file, line := f.FileLine(pc[0])
-	fileName := filepath.Base(file)
+	funcName, fileName, line := caller(1)
 
-	a = append([]interface{}{f.Name(), fileName + ":" + strconv.Itoa(line)}, a...)
+	a = append([]interface{}{funcName, fileName + ":" + strconv.Itoa(line)}, a...)
 
-	Log.Debug(a...)
+	Log.print(1, DebugLog, false, "", a, nil)
 }
 
 func Debugf(format string, a ...interface{}) {
-	if DebugLog < Log.level {
+	if _, ok := Log.enabled(1, DebugLog); !ok {
 		return
 	}
 
-	pc := make([]uintptr, 10)
-	runtime.Callers(2, pc)
-	f := runtime.FuncForPC(pc[0])
-	file, line := f.FileLine(pc[0])
-	fileName := filepath.Base(file)
+	funcName, fileName, line := caller(1)
 
-	a = append([]interface{}{f.Name(), fileName, line}, a...)
+	a = append([]interface{}{funcName, fileName, line}, a...)
 
-	Log.Debugf("%s %s:%d "+format, a...)
+	Log.print(1, DebugLog, true, "%s %s:%d "+format, a, nil)
 }
 
 func Info(a ...interface{}) {
-	Log.Info(a...)
+	Log.print(1, InfoLog, false, "", a, nil)
 }
 
 func Warn(a ...interface{}) {
-	Log.Warn(a...)
+	Log.print(1, WarnLog, false, "", a, nil)
 }
 
 func Error(a ...interface{}) {
-	Log.Error(a...)
+	Log.print(1, ErrorLog, false, "", a, nil)
 }
 
 func Fatal(a ...interface{}) {
-	Log.Fatal(a...)
+	Log.print(1, FatalLog, false, "", a, nil)
 }
 
 func Infof(format string, a ...interface{}) {
-	Log.Infof(format, a...)
+	Log.print(1, InfoLog, true, format, a, nil)
 }
 
 func Warnf(format string, a ...interface{}) {
-	Log.Warnf(format, a...)
+	Log.print(1, WarnLog, true, format, a, nil)
 }
 
 func Errorf(format string, a ...interface{}) {
-	Log.Errorf(format, a...)
+	Log.print(1, ErrorLog, true, format, a, nil)
 }
 
 func Fatalf(format string, a ...interface{}) {
-	Log.Fatalf(format, a...)
+	Log.print(1, FatalLog, true, format, a, nil)
+}
+
+//Tracew log msg with key value pairs, like Tracew("msg", "height", 1)
+func Tracew(msg string, kv ...interface{}) {
+	Log.print(1, TraceLog, false, "", []interface{}{msg}, kv)
+}
+
+//Debugw log msg with key value pairs, like Debugw("msg", "height", 1)
+func Debugw(msg string, kv ...interface{}) {
+	Log.print(1, DebugLog, false, "", []interface{}{msg}, kv)
+}
+
+//Infow log msg with key value pairs, like Infow("msg", "height", 1)
+func Infow(msg string, kv ...interface{}) {
+	Log.print(1, InfoLog, false, "", []interface{}{msg}, kv)
+}
+
+//Warnw log msg with key value pairs, like Warnw("msg", "height", 1)
+func Warnw(msg string, kv ...interface{}) {
+	Log.print(1, WarnLog, false, "", []interface{}{msg}, kv)
+}
+
+//Errorw log msg with key value pairs, like Errorw("msg", "err", err)
+func Errorw(msg string, kv ...interface{}) {
+	Log.print(1, ErrorLog, false, "", []interface{}{msg}, kv)
+}
+
+//Fatalw log msg with key value pairs, like Fatalw("msg", "err", err)
+func Fatalw(msg string, kv ...interface{}) {
+	Log.print(1, FatalLog, false, "", []interface{}{msg}, kv)
 }
 
 func FileOpen(path string) (*os.File, error) {
@@ -339,9 +452,11 @@ func Init(a ...interface{}) {
 	InitLog(InfoLog, a...)
 }
 
+//InitLog init the global logger writing to a, which are log directory paths, *os.File or *RotateWriter,
+//the files in log directory path are rotated by size only
 func InitLog(logLevel int, a ...interface{}) {
 	writers := []io.Writer{}
-	var logFile *os.File
+	var logFile *RotateWriter
 	var err error
 	if len(a) == 0 {
 		writers = append(writers, ioutil.Discard)
@@ -349,12 +464,15 @@ func InitLog(logLevel int, a ...interface{}) {
 		for _, o := range a {
 			switch o.(type) {
 			case string:
-				logFile, err = FileOpen(o.(string))
+				logFile, err = NewRotateWriter(o.(string), RotateConfig{MaxSize: DEFAULT_MAX_LOG_SIZE})
 				if err != nil {
 					fmt.Println("error: open log file failed")
 					os.Exit(1)
 				}
 				writers = append(writers, logFile)
+			case *RotateWriter:
+				logFile = o.(*RotateWriter)
+				writers = append(writers, logFile)
 			case *os.File:
 				writers = append(writers, o.(*os.File))
 			default:
@@ -368,11 +486,10 @@ func InitLog(logLevel int, a ...interface{}) {
 }
 
 func GetLogFileSize() (int64, error) {
-	f, e := Log.logFile.Stat()
-	if e != nil {
-		return 0, e
+	if Log.logFile == nil {
+		return 0, os.ErrInvalid
 	}
-	return f.Size(), nil
+	return Log.logFile.Size(), nil
 }
 
 func GetMaxLogChangeInterval(maxLogSize int64) int64 {
@@ -18,9 +18,13 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
+	"log"
 	"os"
 	"testing"
 	"time"
@@ -83,3 +87,80 @@ func TestNewLogFile(t *testing.T) {
 	}
 	assert.Equal(t, len(logfileNum1), (len(logfileNum2) - 1))
 }
+
+func TestModuleLevels(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New(buf, "", log.Ldate, WarnLog, nil)
+	l.Info("info")
+	assert.Equal(t, 0, buf.Len())
+
+	modules, err := ParseModuleLevels("common=debug, consensus/vbft=1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"common": DebugLog, "consensus/vbft": DebugLog}, modules)
+	assert.Nil(t, l.SetModuleLevels(modules))
+	l.Info("info")
+	assert.Contains(t, buf.String(), "info")
+
+	buf.Reset()
+	assert.Nil(t, l.SetModuleLevel("common/log", ErrorLog))
+	l.Warn("warn")
+	assert.Equal(t, 0, buf.Len())
+	assert.Nil(t, l.SetModuleLevel("common/log", -1))
+	l.Warn("warn")
+	assert.Contains(t, buf.String(), "warn")
+	assert.Equal(t, "common=debug,consensus/vbft=debug", FormatModuleLevels(l.GetModuleLevels()))
+
+	_, err = ParseModuleLevels("p2pserver")
+	assert.NotNil(t, err)
+	_, err = ParseModuleLevels("p2pserver=verbose")
+	assert.NotNil(t, err)
+}
+
+func TestJSONFormat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := New(buf, "", log.Ldate, InfoLog, nil)
+	assert.NotNil(t, l.SetFormat("xml"))
+	assert.Nil(t, l.SetFormat(FORMAT_JSON))
+	l.Infow("block saved", "height", 10, "err", errors.New("none"))
+	l.Infof("height %d", 11)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Equal(t, 2, len(lines))
+	record := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(lines[0], &record))
+	assert.Equal(t, "info", record["level"])
+	assert.Equal(t, "common/log", record["module"])
+	assert.Equal(t, "block saved", record["msg"])
+	assert.Equal(t, float64(10), record["height"])
+	assert.Equal(t, "none", record["err"])
+	record = make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(lines[1], &record))
+	assert.Equal(t, "height 11", record["msg"])
+}
+
+func TestRotateWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := NewRotateWriter(dir, RotateConfig{MaxSize: 1, MaxBackups: 1, Compress: true})
+	assert.Nil(t, err)
+	first := w.Name()
+	line := bytes.Repeat([]byte("a"), 1024)
+	for i := 0; i < BYTE_TO_MB/len(line); i++ {
+		_, err = w.Write(line)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int64(BYTE_TO_MB), w.Size())
+	time.Sleep(time.Second)
+	_, err = w.Write(line)
+	assert.Nil(t, err)
+	assert.NotEqual(t, first, w.Name())
+	assert.Equal(t, int64(len(line)), w.Size())
+	assert.Nil(t, w.Close())
+
+	_, err = os.Stat(first + COMPRESS_SUFFIX)
+	assert.Nil(t, err)
+	_, err = os.Stat(first)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//MODULE_ROOT is trimmed from package path to get module name, like consensus/vbft
+const MODULE_ROOT = "github.com/polynetwork/poly/"
+
+//pc => module of caller
+var callerModules sync.Map
+
+//callerModule return the module of the caller skip frames above
+func callerModule(skip int) string {
+	pc := make([]uintptr, 1)
+	if runtime.Callers(skip+2, pc) == 0 {
+		return ""
+	}
+	if module, ok := callerModules.Load(pc[0]); ok {
+		return module.(string)
+	}
+	//frames take inlined functions into account, which share pc with their callers
+	frame, _ := runtime.CallersFrames(pc).Next()
+	module := packageModule(frame.Function)
+	callerModules.Store(pc[0], module)
+	return module
+}
+
+//packageModule return module of full function name, like github.com/polynetwork/poly/consensus/vbft.(*Server).run
+func packageModule(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[lastSlash+1:], "."); dot >= 0 {
+		funcName = funcName[:lastSlash+1+dot]
+	}
+	return strings.TrimPrefix(funcName, MODULE_ROOT)
+}
+
+//enabled check whether level is enabled for module of the caller depth frames above,
+//module is only looked up when needed by module levels or json format
+func (l *Logger) enabled(depth, level int) (string, bool) {
+	modules := l.modules.Load().(map[string]int)
+	if len(modules) == 0 && l.format.Load().(string) != FORMAT_JSON {
+		return "", level >= l.GetDebugLevel()
+	}
+	module := callerModule(depth + 1)
+	return module, level >= l.moduleLevel(modules, module)
+}
+
+//moduleLevel return the level of module or its nearest parent module, or the global level
+func (l *Logger) moduleLevel(modules map[string]int, module string) int {
+	for m := module; len(modules) > 0; {
+		if level, ok := modules[m]; ok {
+			return level
+		}
+		i := strings.LastIndex(m, "/")
+		if i < 0 {
+			break
+		}
+		m = m[:i]
+	}
+	return l.GetDebugLevel()
+}
+
+//SetModuleLevel set the level of module and its sub modules, level below 0 remove the module level
+func (l *Logger) SetModuleLevel(module string, level int) error {
+	if level > MaxLevelLog {
+		return fmt.Errorf("invalid log level %d", level)
+	}
+	module = strings.Trim(module, "/")
+	if module == "" {
+		return fmt.Errorf("empty log module")
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	modules := make(map[string]int)
+	for k, v := range l.modules.Load().(map[string]int) {
+		modules[k] = v
+	}
+	if level < 0 {
+		delete(modules, module)
+	} else {
+		modules[module] = level
+	}
+	l.modules.Store(modules)
+	return nil
+}
+
+//SetModuleLevels replace the levels of all modules
+func (l *Logger) SetModuleLevels(modules map[string]int) error {
+	levels := make(map[string]int, len(modules))
+	for module, level := range modules {
+		if level < 0 || level > MaxLevelLog {
+			return fmt.Errorf("invalid log level %d of module %s", level, module)
+		}
+		levels[strings.Trim(module, "/")] = level
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.modules.Store(levels)
+	return nil
+}
+
+//GetModuleLevels return a copy of the levels of modules
+func (l *Logger) GetModuleLevels() map[string]int {
+	modules := make(map[string]int)
+	for k, v := range l.modules.Load().(map[string]int) {
+		modules[k] = v
+	}
+	return modules
+}
+
+//ParseModuleLevels parse module levels like "consensus/vbft=debug,p2pserver=3"
+func ParseModuleLevels(s string) (map[string]int, error) {
+	modules := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || strings.Trim(strings.TrimSpace(kv[0]), "/") == "" {
+			return nil, fmt.Errorf("invalid module level %q, should be module=level", item)
+		}
+		level, err := ParseLevel(kv[1])
+		if err != nil {
+			return nil, err
+		}
+		modules[strings.Trim(strings.TrimSpace(kv[0]), "/")] = level
+	}
+	return modules, nil
+}
+
+//FormatModuleLevels format module levels in the form accepted by ParseModuleLevels
+func FormatModuleLevels(modules map[string]int) string {
+	items := make([]string, 0, len(modules))
+	for module, level := range modules {
+		items = append(items, module+"="+levelNames[level])
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+//writeJSON write a record as one json object per line
+func (l *Logger) writeJSON(level int, module string, gid uint64, msg string, kv []interface{}) error {
+	buf := make([]byte, 0, 256)
+	buf = append(buf, `{"time":`...)
+	buf = strconv.AppendQuote(buf, time.Now().Format("2006-01-02T15:04:05.000000Z07:00"))
+	buf = append(buf, `,"level":`...)
+	buf = strconv.AppendQuote(buf, levelNames[level])
+	buf = append(buf, `,"module":`...)
+	buf = appendJSON(buf, module)
+	buf = append(buf, `,"gid":`...)
+	buf = strconv.AppendUint(buf, gid, 10)
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSON(buf, msg)
+	for i := 0; i < len(kv); i += 2 {
+		key, value := keyValue(kv, i)
+		buf = append(buf, ',')
+		buf = appendJSON(buf, key)
+		buf = append(buf, ':')
+		buf = appendJSON(buf, value)
+	}
+	buf = append(buf, '}', '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err := l.out.Write(buf)
+	return err
+}
+
+//keyValue return the key and value of pair start from i, a key without value is reported as EXTRA
+func keyValue(kv []interface{}, i int) (string, interface{}) {
+	if i+1 >= len(kv) {
+		return "EXTRA", kv[i]
+	}
+	if key, ok := kv[i].(string); ok {
+		return key, kv[i+1]
+	}
+	return fmt.Sprint(kv[i]), kv[i+1]
+}
+
+func appendJSON(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case error:
+		value = safeString(v.Error)
+	case fmt.Stringer:
+		value = safeString(v.String)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return append(buf, data...)
+}
+
+//safeString call f, recover from nil receivers of String and Error
+func safeString(f func() string) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = "<nil>"
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	LOG_FILE_SUFFIX = "_LOG.log"
+	LOG_TIME_FORMAT = "2006-01-02_15.04.05"
+	COMPRESS_SUFFIX = ".gz"
+)
+
+//RotateConfig of log file rotation
+type RotateConfig struct {
+	MaxSize    int64         //max MByte written to a file before rotated, 0 to disable
+	MaxAge     time.Duration //max duration a file is written before rotated, 0 to disable
+	MaxBackups int           //max count of rotated files kept, 0 to keep all
+	Compress   bool          //gzip rotated files
+}
+
+//RotateWriter write log to file in a directory, start a new file when current one is too large or too old
+type RotateWriter struct {
+	dir      string
+	cfg      RotateConfig
+	file     *os.File
+	size     int64
+	openTime time.Time
+	lock     sync.Mutex
+	wg       sync.WaitGroup //compress and cleanup of rotated files
+}
+
+//NewRotateWriter open a new log file in dir
+func NewRotateWriter(dir string, cfg RotateConfig) (*RotateWriter, error) {
+	if !strings.HasSuffix(dir, string(os.PathSeparator)) && !strings.HasSuffix(dir, "/") {
+		dir += string(os.PathSeparator)
+	}
+	w := &RotateWriter{dir: dir, cfg: cfg}
+	if err := w.open(time.Now()); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotateWriter) open(now time.Time) error {
+	if fi, err := os.Stat(w.dir); err == nil {
+		if !fi.IsDir() {
+			return fmt.Errorf("open %s: not a directory", w.dir)
+		}
+	} else if os.IsNotExist(err) {
+		if err := os.MkdirAll(w.dir, 0766); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+	file, err := os.OpenFile(w.dir+now.Format(LOG_TIME_FORMAT)+LOG_FILE_SUFFIX, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file = file
+	w.size = fi.Size()
+	w.openTime = now
+	return nil
+}
+
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.needRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "rotate log file error: %s\n", err)
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotateWriter) needRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.cfg.MaxSize > 0 && w.size+n > w.cfg.MaxSize*BYTE_TO_MB {
+		return true
+	}
+	return w.cfg.MaxAge > 0 && time.Since(w.openTime) >= w.cfg.MaxAge
+}
+
+//Rotate close current file and start a new one
+func (w *RotateWriter) Rotate() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+func (w *RotateWriter) rotate() error {
+	now := time.Now()
+	if now.Format(LOG_TIME_FORMAT) == w.openTime.Format(LOG_TIME_FORMAT) {
+		//new file would be the same as current one
+		return nil
+	}
+	old := w.file
+	if err := w.open(now); err != nil {
+		return err
+	}
+	name, current := old.Name(), w.file.Name()
+	if err := old.Close(); err != nil {
+		return err
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		if w.cfg.Compress {
+			if err := compressFile(name); err != nil {
+				fmt.Fprintf(os.Stderr, "compress log file %s error: %s\n", name, err)
+			}
+		}
+		w.cleanup(current)
+	}()
+	return nil
+}
+
+//cleanup remove the oldest rotated files beyond MaxBackups
+func (w *RotateWriter) cleanup(current string) {
+	if w.cfg.MaxBackups <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(w.dir + "*" + LOG_FILE_SUFFIX + "*")
+	if err != nil {
+		return
+	}
+	backups := make([]string, 0, len(matches))
+	for _, name := range matches {
+		if filepath.Base(name) != filepath.Base(current) {
+			backups = append(backups, name)
+		}
+	}
+	//names start with time, so sorted by time
+	sort.Strings(backups)
+	for i := 0; i < len(backups)-w.cfg.MaxBackups; i++ {
+		os.Remove(backups[i])
+	}
+}
+
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(name+COMPRESS_SUFFIX, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(name + COMPRESS_SUFFIX)
+		return err
+	}
+	return os.Remove(name)
+}
+
+//Size return the size of current file
+func (w *RotateWriter) Size() int64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.size
+}
+
+//Name return the path of current file
+func (w *RotateWriter) Name() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.file == nil {
+		return ""
+	}
+	return w.file.Name()
+}
+
+//Close current file and wait rotated files to be compressed
+func (w *RotateWriter) Close() error {
+	w.lock.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.lock.Unlock()
+	w.wg.Wait()
+	return err
+}
@@ -20,10 +20,10 @@ package consensus
 
 import (
 	"github.com/ontio/ontology-eventbus/actor"
-	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/consensus/solo"
 	"github.com/polynetwork/poly/consensus/vbft"
+	"github.com/polynetwork/poly/core/signature"
 )
 
 type ConsensusService interface {
@@ -37,7 +37,7 @@ const (
 	CONSENSUS_VBFT = "vbft"
 )
 
-func NewConsensusService(consensusType string, account *account.Account, txpool *actor.PID, ledger *actor.PID, p2p *actor.PID) (ConsensusService, error) {
+func NewConsensusService(consensusType string, signer signature.VrfSigner, txpool *actor.PID, ledger *actor.PID, p2p *actor.PID) (ConsensusService, error) {
 	if consensusType == "" {
 		consensusType = CONSENSUS_SOLO
 	}
@@ -45,9 +45,9 @@ func NewConsensusService(consensusType string, account *account.Account, txpool
 	var err error
 	switch consensusType {
 	case CONSENSUS_SOLO:
-		consensus, err = solo.NewSoloService(account, txpool)
+		consensus, err = solo.NewSoloService(signer, txpool)
 	case CONSENSUS_VBFT:
-		consensus, err = vbft.NewVbftServer(account, txpool, p2p)
+		consensus, err = vbft.NewVbftServer(signer, txpool, p2p)
 	}
 	log.Infof("ConsensusType:%s", consensusType)
 	return consensus, err
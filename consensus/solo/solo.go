@@ -25,7 +25,6 @@ import (
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-eventbus/actor"
-	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
@@ -44,7 +43,7 @@ import (
 const ContextVersion uint32 = 0
 
 type SoloService struct {
-	Account          *account.Account
+	Signer           signature.Signer
 	poolActor        *actorTypes.TxPoolActor
 	incrValidator    *increment.IncrementValidator
 	existCh          chan interface{}
@@ -53,9 +52,9 @@ type SoloService struct {
 	sub              *events.ActorSubscriber
 }
 
-func NewSoloService(bkAccount *account.Account, txpool *actor.PID) (*SoloService, error) {
+func NewSoloService(bkSigner signature.Signer, txpool *actor.PID) (*SoloService, error) {
 	service := &SoloService{
-		Account:          bkAccount,
+		Signer:           bkSigner,
 		poolActor:        &actorTypes.TxPoolActor{Pool: txpool},
 		incrValidator:    increment.NewIncrementValidator(20),
 		genBlockInterval: time.Duration(config.DefConfig.Genesis.SOLO.GenBlockTime) * time.Second,
@@ -160,7 +159,7 @@ func (self *SoloService) genBlock() error {
 
 func (self *SoloService) makeBlock() (*types.Block, error) {
 	log.Debug()
-	owner := self.Account.PublicKey
+	owner := self.Signer.PubKey()
 	nextBookkeeper, err := types.AddressFromBookkeepers([]keypair.PublicKey{owner})
 	if err != nil {
 		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
@@ -214,7 +213,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 
 	blockHash := block.Hash()
 
-	sig, err := signature.Sign(self.Account, blockHash[:])
+	sig, err := signature.Sign(self.Signer, blockHash[:])
 	if err != nil {
 		return nil, fmt.Errorf("[Signature],Sign error:%s.", err)
 	}
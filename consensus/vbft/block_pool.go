@@ -675,6 +675,24 @@ func (pool *BlockPool) setBlockSealed(block *Block, forEmpty bool, sigdata bool)
 	return nil
 }
 
+// getEndorseSigs returns the endorse signatures of block received from each endorser
+func (pool *BlockPool) getEndorseSigs(blkNum uint32) map[uint32][][]byte {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	sigs := make(map[uint32][][]byte)
+	c := pool.candidateBlocks[blkNum]
+	if c == nil {
+		return sigs
+	}
+	for endorser, eSigs := range c.EndorseSigs {
+		for _, eSig := range eSigs {
+			sigs[endorser] = append(sigs[endorser], eSig.Signature)
+		}
+	}
+	return sigs
+}
+
 func (pool *BlockPool) getSealedBlock(blockNum uint32) (*Block, common.Uint256) {
 	pool.lock.RLock()
 	defer pool.lock.RUnlock()
@@ -61,6 +61,8 @@ type VbftBlockInfo struct {
 	VrfProof           []byte       `json:"vrf_proof"`
 	LastConfigBlockNum uint32       `json:"last_config_block_num"`
 	NewChainConfig     *ChainConfig `json:"new_chain_config"`
+	PrevBlockSigners   []uint32     `json:"prev_block_signers,omitempty"`
+	PrevBlockSigData   [][]byte     `json:"prev_block_sig_data,omitempty"`
 }
 
 const (
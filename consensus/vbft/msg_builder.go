@@ -22,14 +22,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
-	"github.com/polynetwork/poly/core/ledger"
 	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/core/types"
 )
@@ -192,7 +190,7 @@ func (self *Server) constructBlock(blkNum uint32, prevBlkHash common.Uint256, tx
 	}
 	txRoot := common.ComputeMerkleRoot(txHash)
 
-	blockRoot := ledger.DefLedger.GetBlockRootWithPreBlockHashes(blkNum-1, []common.Uint256{lastBlock.Block.Header.PrevBlockHash, prevBlkHash})
+	blockRoot := self.ledger.GetBlockRootWithPreBlockHashes(blkNum-1, []common.Uint256{lastBlock.Block.Header.PrevBlockHash, prevBlkHash})
 	crossStateRoot, err := self.blockPool.getCrossStatesRoot(blkNum - 1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to GetCrossStatesRoot: %s,blkNum:%d", err, (blkNum - 1))
@@ -216,15 +214,18 @@ func (self *Server) constructBlock(blkNum uint32, prevBlkHash common.Uint256, tx
 		Header:       blkHeader,
 		Transactions: txs,
 	}
+	return blk, nil
+}
+
+func (self *Server) signBlock(blk *types.Block) error {
 	blkHash := blk.Hash()
-	sig, err := signature.Sign(self.account, blkHash[:])
+	sig, err := signature.Sign(self.signer, blkHash[:])
 	if err != nil {
-		return nil, fmt.Errorf("sign block failed, block hash:%s, error: %s", blkHash.ToHexString(), err)
+		return fmt.Errorf("sign block failed, block hash:%s, error: %s", blkHash.ToHexString(), err)
 	}
-	blkHeader.Bookkeepers = []keypair.PublicKey{self.account.PublicKey}
-	blkHeader.SigData = [][]byte{sig}
-
-	return blk, nil
+	blk.Header.Bookkeepers = []keypair.PublicKey{self.signer.PubKey()}
+	blk.Header.SigData = [][]byte{sig}
+	return nil
 }
 
 func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types.Transaction, chainconfig *vconfig.ChainConfig) (*blockProposalMsg, error) {
@@ -233,12 +234,12 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 	if prevBlk == nil {
 		return nil, fmt.Errorf("failed to get prevBlock (%d)", blkNum-1)
 	}
-	blocktimestamp := uint32(time.Now().Unix())
+	blocktimestamp := uint32(self.clock.Now().Unix())
 	if prevBlk.Block.Header.Timestamp >= blocktimestamp {
 		blocktimestamp = prevBlk.Block.Header.Timestamp + 1
 	}
 
-	vrfValue, vrfProof, err := computeVrf(self.account.PrivateKey, blkNum, prevBlk.getVrfValue())
+	vrfValue, vrfProof, err := computeVrf(self.signer, blkNum, prevBlk.getVrfValue())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vrf and proof: %s", err)
 	}
@@ -267,12 +268,19 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 		}
 		lastConfigBlkNum = blkNum
 	}
+	var prevBlkSigners []uint32
+	var prevBlkSigData [][]byte
+	if peerPerformanceEnabled(blkNum) {
+		prevBlkSigners, prevBlkSigData = self.getPrevBlockSigners(prevBlk, prevBlkHash)
+	}
 	vbftBlkInfo := &vconfig.VbftBlockInfo{
 		Proposer:           self.Index,
 		VrfValue:           vrfValue,
 		VrfProof:           vrfProof,
 		LastConfigBlockNum: lastConfigBlkNum,
 		NewChainConfig:     chainconfig,
+		PrevBlockSigners:   prevBlkSigners,
+		PrevBlockSigData:   prevBlkSigData,
 	}
 	consensusPayload, err := json.Marshal(vbftBlkInfo)
 	if err != nil {
@@ -287,6 +295,15 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 	if err != nil {
 		return nil, fmt.Errorf("failed to constuct blk: %s", err)
 	}
+	if err := self.proposalGuard.checkAndRecord(blkNum, self.config.View, blk.Hash(), emptyBlk.Hash()); err != nil {
+		return nil, fmt.Errorf("refuse to sign proposal: %s", err)
+	}
+	if err := self.signBlock(emptyBlk); err != nil {
+		return nil, fmt.Errorf("failed to sign empty block: %s", err)
+	}
+	if err := self.signBlock(blk); err != nil {
+		return nil, fmt.Errorf("failed to sign blk: %s", err)
+	}
 
 	msg := &blockProposalMsg{
 		Block: &Block{
@@ -319,7 +336,7 @@ func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty boo
 		proposerSig = proposal.Block.EmptyBlock.Header.SigData[0]
 		blkHash = proposal.Block.EmptyBlock.Hash()
 	}
-	endorserSig, err = signature.Sign(self.account, blkHash[:])
+	endorserSig, err = signature.Sign(self.signer, blkHash[:])
 	if err != nil {
 		return nil, fmt.Errorf("endorser failed to sign block. hash:%x, err: %s", blkHash, err)
 	}
@@ -357,7 +374,7 @@ func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*b
 		proposerSig = proposal.Block.EmptyBlock.Header.SigData[0]
 		blkHash = proposal.Block.EmptyBlock.Hash()
 	}
-	committerSig, err = signature.Sign(self.account, blkHash[:])
+	committerSig, err = signature.Sign(self.signer, blkHash[:])
 	if err != nil {
 		return nil, fmt.Errorf("endorser failed to sign block. hash:%x, caused by: %s", blkHash, err)
 	}
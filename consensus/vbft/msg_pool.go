@@ -125,7 +125,11 @@ func (pool *MsgPool) AddMsg(msg ConsensusMsg, msgHash common.Uint256) error {
 	// TODO: limit #history rounds to historyLen
 	// Note: we accept msg for future rounds
 
-	return pool.rounds[blkNum].addMsg(msg, msgHash)
+	if err := pool.rounds[blkNum].addMsg(msg, msgHash); err != nil {
+		return err
+	}
+	pool.server.history.addMsg(msg)
+	return nil
 }
 
 func (pool *MsgPool) DropMsg(msg ConsensusMsg) {
@@ -192,6 +192,17 @@ func (self *Server) getHighestRankProposal(blockNum uint32, proposals []*blockPr
 //  call this method with metaLock locked
 //
 func (self *Server) buildParticipantConfig(blkNum uint32, block *Block, chainCfg *vconfig.ChainConfig) (*BlockParticipantConfig, error) {
+	cfg, err := calcParticipantConfig(blkNum, block, chainCfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("server %d, blkNum: %d, state: %d, participants config: %v, %v, %v", self.Index, blkNum,
+		self.getState(), cfg.Proposers, cfg.Endorsers, cfg.Committers)
+
+	return cfg, nil
+}
+
+func calcParticipantConfig(blkNum uint32, block *Block, chainCfg *vconfig.ChainConfig) (*BlockParticipantConfig, error) {
 
 	if blkNum == 0 {
 		return nil, fmt.Errorf("not participant config for genesis block")
@@ -225,9 +236,6 @@ func (self *Server) buildParticipantConfig(blkNum uint32, block *Block, chainCfg
 	if uint32(len(cfg.Committers)) < 2*chainCfg.C {
 		return nil, fmt.Errorf("cfg.Committers length less than double chainCfg.C:%d,%d", uint32(len(cfg.Committers)), chainCfg.C)
 	}
-	log.Infof("server %d, blkNum: %d, state: %d, participants config: %v, %v, %v", self.Index, blkNum,
-		self.getState(), cfg.Proposers, cfg.Endorsers, cfg.Committers)
-
 	return cfg, nil
 }
 
@@ -407,14 +415,14 @@ func (self *Server) sendToPeer(peerIdx uint32, data []byte) error {
 	}
 	msg := &p2pmsg.ConsensusPayload{
 		Data:  data,
-		Owner: self.account.PublicKey,
+		Owner: self.signer.PubKey(),
 	}
 
 	buf := new(bytes.Buffer)
 	if err := msg.SerializeUnsigned(buf); err != nil {
 		return fmt.Errorf("failed to serialize consensus msg: %s", err)
 	}
-	msg.Signature, _ = signature.Sign(self.account, buf.Bytes())
+	msg.Signature, _ = signature.Sign(self.signer, buf.Bytes())
 
 	cons := msgpack.NewConsensus(msg)
 	p2pid, present := self.peerPool.getP2pId(peerIdx)
@@ -437,14 +445,14 @@ func (self *Server) broadcast(msg ConsensusMsg) error {
 func (self *Server) broadcastToAll(data []byte) error {
 	msg := &p2pmsg.ConsensusPayload{
 		Data:  data,
-		Owner: self.account.PublicKey,
+		Owner: self.signer.PubKey(),
 	}
 
 	buf := new(bytes.Buffer)
 	if err := msg.SerializeUnsigned(buf); err != nil {
 		return fmt.Errorf("failed to serialize consensus msg: %s", err)
 	}
-	msg.Signature, _ = signature.Sign(self.account, buf.Bytes())
+	msg.Signature, _ = signature.Sign(self.signer, buf.Bytes())
 
 	self.p2p.Broadcast(msg)
 	return nil
@@ -67,6 +67,7 @@ func constructServer() *Server {
 		currentParticipantConfig: blockparticipantconfig,
 		config:                   chainconfig,
 		chainStore:               chainstore,
+		clock:                    systemClock{},
 	}
 	return server
 }
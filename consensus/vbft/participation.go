@@ -0,0 +1,317 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+)
+
+// participationTracker accumulates the participation of consensus peers in the blocks of current view.
+// Proposals of a block are checked against its first selected proposer, endorsements against the
+// signers recorded in the consensus payload of the next block.
+type participationTracker struct {
+	lock             sync.Mutex
+	lastConfigBlkNum uint32
+	countedBlkNum    uint32
+	chainConfig      *vconfig.ChainConfig
+	performances     map[uint32]*node_manager.PeerPerformance
+}
+
+func newParticipationTracker() *participationTracker {
+	return &participationTracker{}
+}
+
+// peerPerformanceEnabled returns if block blkNum records the signers of prev block, and the governance
+// transaction of the block commits the peer performance of its view
+func peerPerformanceEnabled(blkNum uint32) bool {
+	return blkNum >= config.GetPeerPerformanceHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+// getPeerPerformance returns the performance of peers in the blocks of current view before blkNum-1.
+// The result only depends on sealed blocks, so all peers compute the same governance transaction.
+func (self *Server) getPeerPerformance(blkNum uint32) ([]*node_manager.PeerPerformance, error) {
+	tracker := self.participation
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if err := self.countParticipationLocked(blkNum); err != nil {
+		return nil, err
+	}
+	performances := make([]*node_manager.PeerPerformance, 0, len(tracker.performances))
+	for _, p := range tracker.performances {
+		performance := *p
+		performances = append(performances, &performance)
+	}
+	sort.Slice(performances, func(i, j int) bool {
+		return performances[i].PeerPubkey < performances[j].PeerPubkey
+	})
+	return performances, nil
+}
+
+// advanceParticipation counts the blocks persisted, to keep the scan of view change short
+func (self *Server) advanceParticipation(blkNum uint32) error {
+	tracker := self.participation
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	return self.countParticipationLocked(blkNum)
+}
+
+func (self *Server) countParticipationLocked(blkNum uint32) error {
+	tracker := self.participation
+	if blkNum < 2 {
+		return fmt.Errorf("no participation before block %d", blkNum)
+	}
+	prevBlk, _ := self.blockPool.getSealedBlock(blkNum - 1)
+	if prevBlk == nil {
+		return fmt.Errorf("failed to get prevBlock (%d)", blkNum-1)
+	}
+	lastConfigBlkNum := prevBlk.getLastConfigBlockNum()
+	if tracker.chainConfig == nil || tracker.lastConfigBlkNum != lastConfigBlkNum || tracker.countedBlkNum+2 > blkNum {
+		if err := self.resetParticipationLocked(lastConfigBlkNum); err != nil {
+			return err
+		}
+	}
+	for blk := tracker.countedBlkNum + 1; blk+2 <= blkNum; blk++ {
+		if err := self.countBlockParticipationLocked(blk); err != nil {
+			return fmt.Errorf("failed to count participation of block %d: %s", blk, err)
+		}
+		tracker.countedBlkNum = blk
+	}
+	return nil
+}
+
+func (self *Server) resetParticipationLocked(lastConfigBlkNum uint32) error {
+	tracker := self.participation
+	configBlk, _ := self.blockPool.getSealedBlock(lastConfigBlkNum)
+	if configBlk == nil {
+		return fmt.Errorf("failed to get config block (%d)", lastConfigBlkNum)
+	}
+	chainConfig := configBlk.getNewChainConfig()
+	if chainConfig == nil {
+		return fmt.Errorf("no chain config in block %d", lastConfigBlkNum)
+	}
+
+	tracker.lastConfigBlkNum = lastConfigBlkNum
+	tracker.countedBlkNum = lastConfigBlkNum
+	tracker.chainConfig = chainConfig
+	tracker.performances = make(map[uint32]*node_manager.PeerPerformance)
+	for _, peer := range chainConfig.Peers {
+		tracker.performances[peer.Index] = &node_manager.PeerPerformance{PeerPubkey: peer.ID}
+	}
+	return nil
+}
+
+func (self *Server) countBlockParticipationLocked(blkNum uint32) error {
+	tracker := self.participation
+	parent, _ := self.blockPool.getSealedBlock(blkNum - 1)
+	blk, _ := self.blockPool.getSealedBlock(blkNum)
+	next, _ := self.blockPool.getSealedBlock(blkNum + 1)
+	if parent == nil || blk == nil || next == nil {
+		return fmt.Errorf("failed to get sealed blocks")
+	}
+	cfg, err := calcParticipantConfig(blkNum, parent, tracker.chainConfig)
+	if err != nil {
+		return err
+	}
+
+	proposer := blk.getProposer()
+	if p, present := tracker.performances[proposer]; present {
+		p.ProposedBlocks++
+	}
+	if cfg.Proposers[0] != proposer {
+		if p, present := tracker.performances[cfg.Proposers[0]]; present {
+			p.MissedProposals++
+		}
+	}
+
+	// signers are recorded since performance tracking, skip endorsements of blocks before it
+	if len(next.Info.PrevBlockSigners) == 0 {
+		return nil
+	}
+	signers := make(map[uint32]bool)
+	for _, idx := range next.Info.PrevBlockSigners {
+		signers[idx] = true
+	}
+	for _, endorser := range cfg.Endorsers {
+		if endorser == proposer {
+			continue
+		}
+		p, present := tracker.performances[endorser]
+		if !present {
+			continue
+		}
+		if signers[endorser] {
+			p.EndorsedBlocks++
+		} else {
+			p.MissedEndorsements++
+		}
+	}
+	return nil
+}
+
+// getPrevBlockSigners returns the signers of prev block in ascending order, with their signatures on it.
+// Signatures sealed in prev block are merged with endorsements received after sealing, header signatures
+// are not part of block hash and every peer seals the block with the signatures it has received.
+func (self *Server) getPrevBlockSigners(prevBlk *Block, prevBlkHash common.Uint256) ([]uint32, [][]byte) {
+	candidates := self.blockPool.getEndorseSigs(prevBlk.getBlockNum())
+	header := prevBlk.Block.Header
+	for i, pk := range header.Bookkeepers {
+		if i >= len(header.SigData) {
+			break
+		}
+		if idx, present := self.peerPool.GetPeerIndex(vconfig.PubkeyID(pk)); present {
+			candidates[idx] = append([][]byte{header.SigData[i]}, candidates[idx]...)
+		}
+	}
+
+	sigs := make(map[uint32][]byte)
+	for idx, sigData := range candidates {
+		pk := self.peerPool.GetPeerPubKey(idx)
+		if pk == nil {
+			continue
+		}
+		for _, sig := range sigData {
+			if err := signature.Verify(pk, prevBlkHash[:], sig); err == nil {
+				sigs[idx] = sig
+				break
+			}
+		}
+	}
+	signers := make([]uint32, 0, len(sigs))
+	for idx := range sigs {
+		signers = append(signers, idx)
+	}
+	sort.Slice(signers, func(i, j int) bool { return signers[i] < signers[j] })
+	sigData := make([][]byte, 0, len(signers))
+	for _, idx := range signers {
+		sigData = append(sigData, sigs[idx])
+	}
+	return signers, sigData
+}
+
+// verifyPrevBlockSigners checks the signers of prev block recorded by proposer are distinct known peers with
+// valid signatures on prev block. Every peer seals prev block with the signatures it has received, so the
+// recorded signers have to reach the quorum sealed locally, a proposer can not skip the accounting with
+// an empty list. Blocks before peer performance height record no signers.
+func (self *Server) verifyPrevBlockSigners(blk *Block) error {
+	if !peerPerformanceEnabled(blk.getBlockNum()) {
+		if len(blk.Info.PrevBlockSigners) > 0 || len(blk.Info.PrevBlockSigData) > 0 {
+			return fmt.Errorf("prev block signers recorded before peer performance height")
+		}
+		return nil
+	}
+	prevBlk, prevBlkHash := self.blockPool.getSealedBlock(blk.getBlockNum() - 1)
+	if prevBlk == nil {
+		return fmt.Errorf("failed to get prevBlock (%d)", blk.getBlockNum()-1)
+	}
+	signers := blk.Info.PrevBlockSigners
+	if len(signers) != len(blk.Info.PrevBlockSigData) {
+		return fmt.Errorf("%d signers with %d signatures", len(signers), len(blk.Info.PrevBlockSigData))
+	}
+	for i, idx := range signers {
+		if i > 0 && signers[i-1] >= idx {
+			return fmt.Errorf("signers not in ascending order: %v", signers)
+		}
+		pk := self.peerPool.GetPeerPubKey(idx)
+		if pk == nil {
+			return fmt.Errorf("unknown signer %d", idx)
+		}
+		if err := signature.Verify(pk, prevBlkHash[:], blk.Info.PrevBlockSigData[i]); err != nil {
+			return fmt.Errorf("invalid signature of signer %d: %s", idx, err)
+		}
+	}
+
+	quorum := int(self.config.C) + 1
+	sealed := 0
+	for _, pk := range prevBlk.Block.Header.Bookkeepers {
+		if _, present := self.peerPool.GetPeerIndex(vconfig.PubkeyID(pk)); present {
+			sealed++
+		}
+	}
+	if sealed < quorum {
+		quorum = sealed
+	}
+	if len(signers) < quorum {
+		return fmt.Errorf("%d signers of prev block %d recorded, quorum %d", len(signers), prevBlk.getBlockNum(), quorum)
+	}
+	return nil
+}
+
+// omitsSelfEndorsement returns whether blk leaves out this peer from the signers of prev block, which this peer
+// has endorsed and sealed. The peer does not endorse such proposal, so a proposer dropping the endorsers can not
+// get its proposal endorsed by them. The proposal is kept, since the proposer may just seal prev block before
+// receiving the signature of this peer, and the peer still commits it once endorsed by others.
+func (self *Server) omitsSelfEndorsement(blk *Block) bool {
+	if !peerPerformanceEnabled(blk.getBlockNum()) {
+		return false
+	}
+	prevBlk, _ := self.blockPool.getSealedBlock(blk.getBlockNum() - 1)
+	if prevBlk == nil {
+		return false
+	}
+	for _, idx := range blk.Info.PrevBlockSigners {
+		if idx == self.Index {
+			return false
+		}
+	}
+	endorsers, err := self.getBlockEndorsers(prevBlk)
+	if err != nil || !endorsers[self.Index] {
+		return false
+	}
+	for _, pk := range prevBlk.Block.Header.Bookkeepers {
+		if idx, present := self.peerPool.GetPeerIndex(vconfig.PubkeyID(pk)); present && idx == self.Index {
+			return true
+		}
+	}
+	return false
+}
+
+// getBlockEndorsers returns the endorsers selected for block, whose endorsements are accounted
+func (self *Server) getBlockEndorsers(blk *Block) (map[uint32]bool, error) {
+	endorsers := make(map[uint32]bool)
+	blkNum := blk.getBlockNum()
+	if blkNum == 0 {
+		return endorsers, nil
+	}
+	parent, _ := self.blockPool.getSealedBlock(blkNum - 1)
+	if parent == nil {
+		return nil, fmt.Errorf("failed to get block (%d)", blkNum-1)
+	}
+	configBlk, _ := self.blockPool.getSealedBlock(blk.getLastConfigBlockNum())
+	if configBlk == nil || configBlk.getNewChainConfig() == nil {
+		return nil, fmt.Errorf("failed to get chain config of block %d", blkNum)
+	}
+	cfg, err := calcParticipantConfig(blkNum, parent, configBlk.getNewChainConfig())
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range cfg.Endorsers {
+		endorsers[idx] = true
+	}
+	return endorsers, nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// withPrevBlockSigners returns a copy of blk recording signers of prev block with their signatures
+func withPrevBlockSigners(blk *Block, signers []uint32, sigData [][]byte) *Block {
+	info := *blk.Info
+	info.PrevBlockSigners, info.PrevBlockSigData = signers, sigData
+	return &Block{Block: blk.Block, EmptyBlock: blk.EmptyBlock, Info: &info}
+}
+
+func TestPrevBlockSigners(t *testing.T) {
+	cluster := newSimCluster(t, simConfig{nodes: 4, seed: 11})
+	defer cluster.stop()
+	cluster.waitHeight(cluster.nodes, 4, 10*time.Minute)
+
+	node := cluster.nodes[0]
+	blk, _ := node.server.blockPool.getSealedBlock(3)
+	if !assert.NotNil(t, blk) {
+		return
+	}
+	signers, sigData := blk.Info.PrevBlockSigners, blk.Info.PrevBlockSigData
+	quorum := int(node.server.config.C) + 1
+	if !assert.True(t, len(signers) >= quorum) {
+		return
+	}
+	assert.Nil(t, node.server.verifyPrevBlockSigners(blk))
+
+	// signers under the quorum sealed locally
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, signers[:1], sigData[:1])))
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, nil, nil)))
+	// signers out of order, or signatures not matching signers
+	reversed, reversedSigs := make([]uint32, 0), make([][]byte, 0)
+	for i := len(signers) - 1; i >= 0; i-- {
+		reversed, reversedSigs = append(reversed, signers[i]), append(reversedSigs, sigData[i])
+	}
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, reversed, reversedSigs)))
+	swapped := append([][]byte{sigData[1], sigData[0]}, sigData[2:]...)
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, signers, swapped)))
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, signers, sigData[1:])))
+	// unknown signer
+	unknown := append(append([]uint32{}, signers[:len(signers)-1]...), 99)
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, unknown, sigData)))
+
+	// a peer refuses to endorse proposals leaving out its own endorsement of prev block
+	omitted := 0
+	for _, n := range cluster.nodes {
+		assert.False(t, n.server.omitsSelfEndorsement(blk))
+		others, otherSigs := make([]uint32, 0), make([][]byte, 0)
+		for i, idx := range signers {
+			if idx != n.index {
+				others, otherSigs = append(others, idx), append(otherSigs, sigData[i])
+			}
+		}
+		if n.server.omitsSelfEndorsement(withPrevBlockSigners(blk, others, otherSigs)) {
+			omitted++
+		}
+	}
+	assert.True(t, omitted > 0)
+
+	// blocks before peer performance height record no signers
+	networkId := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkId
+	}()
+	assert.NotNil(t, node.server.verifyPrevBlockSigners(blk))
+	assert.Nil(t, node.server.verifyPrevBlockSigners(withPrevBlockSigners(blk, nil, nil)))
+	for _, n := range cluster.nodes {
+		assert.False(t, n.server.omitsSelfEndorsement(withPrevBlockSigners(blk, nil, nil)))
+	}
+}
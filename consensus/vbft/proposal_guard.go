@@ -0,0 +1,178 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+)
+
+// PROPOSAL_GUARD_FILE keeps the signed proposals under the ledger dir, so a restarted proposer does not
+// sign a conflicting proposal of the height and view it signed before
+const PROPOSAL_GUARD_FILE = "proposal_guard.json"
+
+type proposalKey struct {
+	blkNum uint32
+	view   uint32
+}
+
+type signedProposal struct {
+	blkHash   common.Uint256
+	emptyHash common.Uint256
+}
+
+// proposalRecord is a signed proposal in the guard file
+type proposalRecord struct {
+	BlkNum    uint32 `json:"blkNum"`
+	View      uint32 `json:"view"`
+	BlkHash   string `json:"blkHash"`
+	EmptyHash string `json:"emptyHash"`
+}
+
+// proposalGuard keeps the proposals signed by local signer, and refuse to sign
+// another different proposal with same block number and chain config view.
+// Signing two conflicting proposals is equivocation of the proposer.
+// Records are written to path before signing if path is not empty.
+type proposalGuard struct {
+	lock      sync.Mutex
+	path      string
+	proposals map[proposalKey]*signedProposal
+}
+
+func newProposalGuard(path string) (*proposalGuard, error) {
+	self := &proposalGuard{
+		path:      path,
+		proposals: make(map[proposalKey]*signedProposal),
+	}
+	if path == "" {
+		return self, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return self, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read proposal guard %s: %s", path, err)
+	}
+	var records []*proposalRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse proposal guard %s: %s", path, err)
+	}
+	for _, r := range records {
+		blkHash, err := common.Uint256FromHexString(r.BlkHash)
+		if err != nil {
+			return nil, fmt.Errorf("parse proposal guard %s: %s", path, err)
+		}
+		emptyHash, err := common.Uint256FromHexString(r.EmptyHash)
+		if err != nil {
+			return nil, fmt.Errorf("parse proposal guard %s: %s", path, err)
+		}
+		self.proposals[proposalKey{blkNum: r.BlkNum, view: r.View}] = &signedProposal{
+			blkHash:   blkHash,
+			emptyHash: emptyHash,
+		}
+	}
+	return self, nil
+}
+
+func (self *proposalGuard) checkAndRecord(blkNum, view uint32, blkHash, emptyHash common.Uint256) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	key := proposalKey{blkNum: blkNum, view: view}
+	if p, present := self.proposals[key]; present {
+		if p.blkHash == blkHash && p.emptyHash == emptyHash {
+			return nil
+		}
+		return fmt.Errorf("already signed proposal %s for block %d view %d",
+			p.blkHash.ToHexString(), blkNum, view)
+	}
+	self.proposals[key] = &signedProposal{
+		blkHash:   blkHash,
+		emptyHash: emptyHash,
+	}
+	if err := self.save(); err != nil {
+		delete(self.proposals, key)
+		return err
+	}
+	return nil
+}
+
+// prune drops records of blocks not higher than sealed block number
+func (self *proposalGuard) prune(sealedBlkNum uint32) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	pruned := false
+	for key := range self.proposals {
+		if key.blkNum <= sealedBlkNum {
+			delete(self.proposals, key)
+			pruned = true
+		}
+	}
+	if pruned {
+		if err := self.save(); err != nil {
+			log.Errorf("prune proposal guard: %s", err)
+		}
+	}
+}
+
+// save replaces the guard file with current records, the file is synced before it replaces the old one
+func (self *proposalGuard) save() error {
+	if self.path == "" {
+		return nil
+	}
+	records := make([]*proposalRecord, 0, len(self.proposals))
+	for key, p := range self.proposals {
+		records = append(records, &proposalRecord{
+			BlkNum:    key.blkNum,
+			View:      key.view,
+			BlkHash:   p.blkHash.ToHexString(),
+			EmptyHash: p.emptyHash.ToHexString(),
+		})
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("marshal proposal guard: %s", err)
+	}
+	tmp := self.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("write proposal guard %s: %s", tmp, err)
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write proposal guard %s: %s", tmp, err)
+	}
+	if err := os.Rename(tmp, self.path); err != nil {
+		return fmt.Errorf("write proposal guard %s: %s", self.path, err)
+	}
+	return nil
+}
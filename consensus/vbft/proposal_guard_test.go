@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+)
+
+func TestProposalGuard(t *testing.T) {
+	guard, err := newProposalGuard("")
+	if err != nil {
+		t.Fatalf("new guard: %s", err)
+	}
+	blkHash := common.Uint256{1}
+	emptyHash := common.Uint256{2}
+	if err := guard.checkAndRecord(10, 1, blkHash, emptyHash); err != nil {
+		t.Fatalf("first proposal refused: %s", err)
+	}
+	if err := guard.checkAndRecord(10, 1, blkHash, emptyHash); err != nil {
+		t.Fatalf("same proposal refused: %s", err)
+	}
+	if err := guard.checkAndRecord(10, 1, common.Uint256{3}, emptyHash); err == nil {
+		t.Fatalf("conflicting proposal accepted")
+	}
+	if err := guard.checkAndRecord(10, 2, common.Uint256{3}, emptyHash); err != nil {
+		t.Fatalf("proposal of new view refused: %s", err)
+	}
+	if err := guard.checkAndRecord(11, 1, common.Uint256{3}, emptyHash); err != nil {
+		t.Fatalf("proposal of new block refused: %s", err)
+	}
+
+	guard.prune(10)
+	if len(guard.proposals) != 1 {
+		t.Fatalf("prune failed, remained %d", len(guard.proposals))
+	}
+	if err := guard.checkAndRecord(11, 1, common.Uint256{4}, emptyHash); err == nil {
+		t.Fatalf("conflicting proposal accepted after prune")
+	}
+}
+
+func TestProposalGuardReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proposal_guard")
+	if err != nil {
+		t.Fatalf("temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, PROPOSAL_GUARD_FILE)
+
+	guard, err := newProposalGuard(path)
+	if err != nil {
+		t.Fatalf("new guard: %s", err)
+	}
+	emptyHash := common.Uint256{2}
+	if err := guard.checkAndRecord(10, 1, common.Uint256{1}, emptyHash); err != nil {
+		t.Fatalf("first proposal refused: %s", err)
+	}
+	if err := guard.checkAndRecord(11, 1, common.Uint256{3}, emptyHash); err != nil {
+		t.Fatalf("proposal of new block refused: %s", err)
+	}
+
+	// restarted signer must keep refusing conflicting proposals
+	restarted, err := newProposalGuard(path)
+	if err != nil {
+		t.Fatalf("reload guard: %s", err)
+	}
+	if err := restarted.checkAndRecord(10, 1, common.Uint256{4}, emptyHash); err == nil {
+		t.Fatalf("conflicting proposal accepted after restart")
+	}
+	if err := restarted.checkAndRecord(10, 1, common.Uint256{1}, emptyHash); err != nil {
+		t.Fatalf("same proposal refused after restart: %s", err)
+	}
+
+	restarted.prune(10)
+	pruned, err := newProposalGuard(path)
+	if err != nil {
+		t.Fatalf("reload pruned guard: %s", err)
+	}
+	if len(pruned.proposals) != 1 {
+		t.Fatalf("prune not persisted, remained %d", len(pruned.proposals))
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+)
+
+const (
+	ROUND_HISTORY_LEN    = 64  // blocks kept in round history
+	MAX_ROUND_EVENTS_LEN = 512 // events kept of each block
+)
+
+// type of events in round history
+const (
+	RoundEventStart      = "start"
+	RoundEventProposal   = "proposal"
+	RoundEventEndorse    = "endorse"
+	RoundEventCommit     = "commit"
+	RoundEventTimer      = "timer"
+	RoundEventViewChange = "viewchange"
+	RoundEventSealed     = "sealed"
+	RoundEventCatchUp    = "catchup"
+)
+
+var timerEventNames = map[TimerEventType]string{
+	EventProposeBlockTimeout:      "ProposeBlockTimeout",
+	EventProposalBackoff:          "ProposalBackoff",
+	EventRandomBackoff:            "RandomBackoff",
+	EventPropose2ndBlockTimeout:   "Propose2ndBlockTimeout",
+	EventEndorseBlockTimeout:      "EndorseBlockTimeout",
+	EventEndorseEmptyBlockTimeout: "EndorseEmptyBlockTimeout",
+	EventCommitBlockTimeout:       "CommitBlockTimeout",
+	EventPeerHeartbeat:            "PeerHeartbeat",
+	EventTxPool:                   "TxPool",
+	EventTxBlockTimeout:           "TxBlockTimeout",
+}
+
+func (t TimerEventType) String() string {
+	if name, ok := timerEventNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TimerEventType(%d)", int(t))
+}
+
+// RoundEvent is a consensus message, timer event or view change of a block round
+type RoundEvent struct {
+	Time     time.Time
+	Type     string
+	Peer     uint32 // proposer, endorser or committer of message
+	Proposer uint32 // proposer of the endorsed, committed or sealed proposal
+	ForEmpty bool   `json:",omitempty"`
+	Hash     string `json:",omitempty"` // hash of the proposed, endorsed, committed or sealed block
+	Timer    string `json:",omitempty"`
+	View     uint32 `json:",omitempty"`
+	Reason   string `json:",omitempty"`
+}
+
+// RoundRecord is the history of a block round
+type RoundRecord struct {
+	BlockNum   uint32
+	View       uint32
+	Proposers  []uint32 `json:",omitempty"`
+	Endorsers  []uint32 `json:",omitempty"`
+	Committers []uint32 `json:",omitempty"`
+	Events     []*RoundEvent
+	Dropped    uint32 `json:",omitempty"` // events not kept beyond MAX_ROUND_EVENTS_LEN
+}
+
+// RoundTrace is the round history exported for offline analysis
+type RoundTrace struct {
+	Node   uint32
+	Time   time.Time
+	Rounds []*RoundRecord
+}
+
+// roundHistory keeps the records of latest blocks, methods are no-op on nil history
+type roundHistory struct {
+	lock     sync.RWMutex
+	capacity int
+	rounds   map[uint32]*RoundRecord // indexed by BlockNum
+}
+
+func newRoundHistory(capacity int) *roundHistory {
+	return &roundHistory{
+		capacity: capacity,
+		rounds:   make(map[uint32]*RoundRecord),
+	}
+}
+
+// latest created server, whose round history is queried
+var theRoundHistoryServer atomic.Value
+
+func setRoundHistoryServer(server *Server) {
+	theRoundHistoryServer.Store(server)
+}
+
+// GetRoundTrace return the round history of latest count blocks, all kept blocks if count is 0
+func GetRoundTrace(count uint32) *RoundTrace {
+	trace := &RoundTrace{Time: time.Now(), Rounds: []*RoundRecord{}}
+	if server, ok := theRoundHistoryServer.Load().(*Server); ok {
+		trace.Node = server.Index
+		trace.Rounds = server.history.snapshot(count)
+	}
+	return trace
+}
+
+// getRound return the record of blkNum, creating it if not exist, caller should hold the lock
+func (h *roundHistory) getRound(blkNum uint32) *RoundRecord {
+	if r, present := h.rounds[blkNum]; present {
+		return r
+	}
+	if len(h.rounds) >= h.capacity {
+		oldest := blkNum
+		for n := range h.rounds {
+			if n < oldest {
+				oldest = n
+			}
+		}
+		if oldest == blkNum {
+			// older than all kept blocks
+			return nil
+		}
+		delete(h.rounds, oldest)
+	}
+	r := &RoundRecord{BlockNum: blkNum}
+	h.rounds[blkNum] = r
+	return r
+}
+
+func (h *roundHistory) addEvent(blkNum uint32, evt *RoundEvent) {
+	if h == nil {
+		return
+	}
+	evt.Time = time.Now()
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	r := h.getRound(blkNum)
+	if r == nil {
+		return
+	}
+	if len(r.Events) >= MAX_ROUND_EVENTS_LEN {
+		r.Dropped++
+		return
+	}
+	r.Events = append(r.Events, evt)
+}
+
+func (h *roundHistory) addMsg(msg ConsensusMsg) {
+	if h == nil {
+		return
+	}
+	switch m := msg.(type) {
+	case *blockProposalMsg:
+		hash := m.Block.Block.Hash()
+		h.addEvent(m.GetBlockNum(), &RoundEvent{
+			Type:     RoundEventProposal,
+			Peer:     m.Block.getProposer(),
+			Proposer: m.Block.getProposer(),
+			Hash:     hash.ToHexString(),
+		})
+	case *blockEndorseMsg:
+		h.addEvent(m.GetBlockNum(), &RoundEvent{
+			Type:     RoundEventEndorse,
+			Peer:     m.Endorser,
+			Proposer: m.EndorsedProposer,
+			ForEmpty: m.EndorseForEmpty,
+			Hash:     m.EndorsedBlockHash.ToHexString(),
+		})
+	case *blockCommitMsg:
+		h.addEvent(m.GetBlockNum(), &RoundEvent{
+			Type:     RoundEventCommit,
+			Peer:     m.Committer,
+			Proposer: m.BlockProposer,
+			ForEmpty: m.CommitForEmpty,
+			Hash:     m.CommitBlockHash.ToHexString(),
+		})
+	}
+}
+
+func (h *roundHistory) addTimerEvent(evt *TimerEvent) {
+	if h == nil || evt.evtType == EventPeerHeartbeat {
+		// blockNum of heartbeat event is peer index
+		return
+	}
+	h.addEvent(evt.blockNum, &RoundEvent{
+		Type:  RoundEventTimer,
+		Timer: evt.evtType.String(),
+	})
+}
+
+// startRound record the participants of new round
+func (h *roundHistory) startRound(cfg *BlockParticipantConfig) {
+	if h == nil || cfg == nil {
+		return
+	}
+	h.lock.Lock()
+	r := h.getRound(cfg.BlockNum)
+	if r != nil {
+		if cfg.ChainConfig != nil {
+			r.View = cfg.ChainConfig.View
+		}
+		r.Proposers = append([]uint32{}, cfg.Proposers...)
+		r.Endorsers = append([]uint32{}, cfg.Endorsers...)
+		r.Committers = append([]uint32{}, cfg.Committers...)
+	}
+	h.lock.Unlock()
+	h.addEvent(cfg.BlockNum, &RoundEvent{Type: RoundEventStart})
+}
+
+func (h *roundHistory) viewChange(blkNum uint32, view uint32, reason string) {
+	h.addEvent(blkNum, &RoundEvent{
+		Type:   RoundEventViewChange,
+		View:   view,
+		Reason: reason,
+	})
+}
+
+// catchUp record that consensus of blkNum is caught up from the messages received in fastforwarding
+func (h *roundHistory) catchUp(blkNum uint32) {
+	h.addEvent(blkNum, &RoundEvent{Type: RoundEventCatchUp})
+}
+
+func (h *roundHistory) sealed(block *Block, empty bool, hash string) {
+	h.addEvent(block.getBlockNum(), &RoundEvent{
+		Type:     RoundEventSealed,
+		Proposer: block.getProposer(),
+		ForEmpty: empty,
+		Hash:     hash,
+	})
+}
+
+// snapshot copy the records of latest count blocks in order of BlockNum
+func (h *roundHistory) snapshot(count uint32) []*RoundRecord {
+	records := make([]*RoundRecord, 0)
+	if h == nil {
+		return records
+	}
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	for _, r := range h.rounds {
+		record := *r
+		record.Events = make([]*RoundEvent, 0, len(r.Events))
+		for _, evt := range r.Events {
+			e := *evt
+			record.Events = append(record.Events, &e)
+		}
+		records = append(records, &record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].BlockNum < records[j].BlockNum
+	})
+	if count > 0 && int(count) < len(records) {
+		records = records[len(records)-int(count):]
+	}
+	return records
+}
+
+// diffChainConfigPeers describe the peers added and removed by new chain config
+func diffChainConfigPeers(old, new *vconfig.ChainConfig) string {
+	oldPeers := make(map[uint32]bool)
+	if old != nil {
+		for _, p := range old.Peers {
+			oldPeers[p.Index] = true
+		}
+	}
+	added := make([]uint32, 0)
+	for _, p := range new.Peers {
+		if !oldPeers[p.Index] {
+			added = append(added, p.Index)
+		}
+		delete(oldPeers, p.Index)
+	}
+	removed := make([]uint32, 0)
+	for idx := range oldPeers {
+		removed = append(removed, idx)
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i] < removed[j] })
+	return fmt.Sprintf("peers added %v, removed %v", added, removed)
+}
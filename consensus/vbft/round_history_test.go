@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+)
+
+func TestRoundHistory(t *testing.T) {
+	h := newRoundHistory(2)
+	h.startRound(&BlockParticipantConfig{
+		BlockNum:    1,
+		ChainConfig: &vconfig.ChainConfig{View: 3},
+		Proposers:   []uint32{1, 2},
+		Endorsers:   []uint32{2, 3},
+		Committers:  []uint32{3, 4},
+	})
+	h.addMsg(&blockEndorseMsg{Endorser: 2, EndorsedProposer: 1, BlockNum: 1, EndorsedBlockHash: common.UINT256_EMPTY})
+	h.addMsg(&blockCommitMsg{Committer: 3, BlockProposer: 1, BlockNum: 1, CommitForEmpty: true})
+	h.addTimerEvent(&TimerEvent{evtType: EventEndorseBlockTimeout, blockNum: 1})
+	h.addTimerEvent(&TimerEvent{evtType: EventPeerHeartbeat, blockNum: 4})
+
+	records := h.snapshot(0)
+	if len(records) != 1 {
+		t.Fatalf("expect 1 round, got %d", len(records))
+	}
+	r := records[0]
+	if r.BlockNum != 1 || r.View != 3 || len(r.Committers) != 2 {
+		t.Fatalf("invalid round record: %+v", r)
+	}
+	if len(r.Events) != 4 {
+		t.Fatalf("expect 4 events, got %d", len(r.Events))
+	}
+	if r.Events[1].Type != RoundEventEndorse || r.Events[1].Peer != 2 || r.Events[1].Proposer != 1 {
+		t.Fatalf("invalid endorse event: %+v", r.Events[1])
+	}
+	if r.Events[2].Type != RoundEventCommit || !r.Events[2].ForEmpty {
+		t.Fatalf("invalid commit event: %+v", r.Events[2])
+	}
+	if r.Events[3].Timer != "EndorseBlockTimeout" {
+		t.Fatalf("invalid timer event: %+v", r.Events[3])
+	}
+
+	// oldest round is dropped
+	h.viewChange(2, 4, "test")
+	h.viewChange(3, 4, "test")
+	h.viewChange(1, 4, "test")
+	records = h.snapshot(0)
+	if len(records) != 2 || records[0].BlockNum != 2 || records[1].BlockNum != 3 {
+		t.Fatalf("invalid rounds kept: %d", len(records))
+	}
+	if records = h.snapshot(1); len(records) != 1 || records[0].BlockNum != 3 {
+		t.Fatalf("invalid latest round")
+	}
+
+	var nilHistory *roundHistory
+	nilHistory.addMsg(&blockCommitMsg{BlockNum: 1})
+	if len(nilHistory.snapshot(0)) != 0 {
+		t.Fatalf("nil history should be empty")
+	}
+}
+
+func TestDiffChainConfigPeers(t *testing.T) {
+	old := &vconfig.ChainConfig{Peers: []*vconfig.PeerConfig{{Index: 1}, {Index: 2}}}
+	new := &vconfig.ChainConfig{Peers: []*vconfig.PeerConfig{{Index: 2}, {Index: 3}}}
+	if diff := diffChainConfigPeers(old, new); diff != "peers added [3], removed [1]" {
+		t.Fatalf("invalid diff: %s", diff)
+	}
+}
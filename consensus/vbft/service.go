@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"time"
@@ -29,14 +30,17 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/vrf"
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology-eventbus/eventhub"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	actorTypes "github.com/polynetwork/poly/consensus/actor"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/ledger"
 	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/events"
 	"github.com/polynetwork/poly/events/message"
@@ -88,10 +92,13 @@ type p2pMsgPayload struct {
 
 type Server struct {
 	Index         uint32
-	account       *account.Account
+	signer        signature.VrfSigner
+	proposalGuard *proposalGuard
+	participation *participationTracker
 	poolActor     *actorTypes.TxPoolActor
 	p2p           *actorTypes.P2PActor
 	ledger        *ledger.Ledger
+	clock         Clock
 	incrValidator *increment.IncrementValidator
 	pid           *actor.PID
 
@@ -111,10 +118,11 @@ type Server struct {
 	config                   *vconfig.ChainConfig
 	currentParticipantConfig *BlockParticipantConfig
 
-	chainStore *ChainStore // block store
-	msgPool    *MsgPool    // consensus msg pool
-	blockPool  *BlockPool  // received block proposals
-	peerPool   *PeerPool   // consensus peers
+	chainStore *ChainStore   // block store
+	msgPool    *MsgPool      // consensus msg pool
+	blockPool  *BlockPool    // received block proposals
+	history    *roundHistory // round history of latest blocks
+	peerPool   *PeerPool     // consensus peers
 	syncer     *Syncer
 	stateMgr   *StateMgr
 	timer      *EventTimer
@@ -129,27 +137,45 @@ type Server struct {
 	quitWg     sync.WaitGroup
 }
 
-func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, error) {
+func NewVbftServer(signer signature.VrfSigner, txpool, p2p *actor.PID) (*Server, error) {
+	guardPath := filepath.Join(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName, PROPOSAL_GUARD_FILE)
+	return newVbftServer("consensus_vbft", signer, txpool, p2p, ledger.DefLedger, events.DefEvtHub, systemClock{}, guardPath)
+}
+
+// newVbftServer creates vbft server on ledger db, the server actor is spawned with name,
+// and subscribes block complete event from evtHub. Consensus timers are created from clock.
+// Signed proposals are kept in guardPath, or only in memory if guardPath is empty.
+func newVbftServer(name string, signer signature.VrfSigner, txpool, p2p *actor.PID, db *ledger.Ledger,
+	evtHub *eventhub.EventHub, clock Clock, guardPath string) (*Server, error) {
+	guard, err := newProposalGuard(guardPath)
+	if err != nil {
+		return nil, err
+	}
 	server := &Server{
 		msgHistoryDuration: 64,
-		account:            account,
+		signer:             signer,
+		proposalGuard:      guard,
+		participation:      newParticipationTracker(),
 		poolActor:          &actorTypes.TxPoolActor{Pool: txpool},
 		p2p:                &actorTypes.P2PActor{P2P: p2p},
-		ledger:             ledger.DefLedger,
+		ledger:             db,
+		clock:              clock,
 		incrValidator:      increment.NewIncrementValidator(20),
+		history:            newRoundHistory(ROUND_HISTORY_LEN),
 	}
 	server.stateMgr = newStateMgr(server)
+	setRoundHistoryServer(server)
 
 	props := actor.FromProducer(func() actor.Actor {
 		return server
 	})
 
-	pid, err := actor.SpawnNamed(props, "consensus_vbft")
+	pid, err := actor.SpawnNamed(props, name)
 	if err != nil {
 		return nil, err
 	}
 	server.pid = pid
-	server.sub = events.NewActorSubscriber(pid)
+	server.sub = events.NewActorSubscriber(pid, evtHub)
 
 	if err := server.initialize(); err != nil {
 		return nil, fmt.Errorf("vbft server start failed: %s", err)
@@ -212,6 +238,10 @@ func (self *Server) handleBlockPersistCompleted(block *types.Block) {
 	}
 	self.completedBlockNum = block.Header.Height
 	self.incrValidator.AddBlock(block)
+	self.proposalGuard.prune(block.Header.Height)
+	if err := self.advanceParticipation(block.Header.Height + 1); err != nil {
+		log.Debugf("server %d, advance participation at block %d: %s", self.Index, block.Header.Height, err)
+	}
 	if self.nonConsensusNode() {
 		self.chainStore.ReloadFromLedger()
 		self.metaLock.Lock()
@@ -221,8 +251,14 @@ func (self *Server) handleBlockPersistCompleted(block *types.Block) {
 		self.metaLock.Unlock()
 	}
 
-	if self.checkNeedUpdateChainConfig(self.completedBlockNum) || self.checkUpdateChainConfig(self.completedBlockNum) {
-		err := self.updateChainConfig()
+	reason := ""
+	if self.checkNeedUpdateChainConfig(self.completedBlockNum) {
+		reason = "max blocks of view reached"
+	} else if self.checkUpdateChainConfig(self.completedBlockNum) {
+		reason = "chain config updated by governance"
+	}
+	if reason != "" {
+		err := self.updateChainConfig(reason)
 		if err != nil {
 			log.Errorf("updateChainConfig failed:%s", err)
 		}
@@ -323,7 +359,7 @@ func (self *Server) nonConsensusNode() bool {
 }
 
 //updateChainCofig
-func (self *Server) updateChainConfig() error {
+func (self *Server) updateChainConfig(reason string) error {
 	block, _ := self.blockPool.getSealedBlock(self.completedBlockNum)
 	if block == nil {
 		return fmt.Errorf("GetBlockInfo failed,block is nil:%d", self.completedBlockNum)
@@ -332,6 +368,8 @@ func (self *Server) updateChainConfig() error {
 		return fmt.Errorf("GetNewChainConfig nil,%d", self.completedBlockNum)
 	}
 	log.Infof("updateChainConfig blkNum:%d", self.completedBlockNum)
+	self.history.viewChange(self.completedBlockNum+1, block.Info.NewChainConfig.View,
+		fmt.Sprintf("%s in block %d, %s", reason, self.completedBlockNum, diffChainConfigPeers(self.config, block.Info.NewChainConfig)))
 	self.metaLock.Lock()
 	self.config = block.Info.NewChainConfig
 	self.LastConfigBlockNum = block.getLastConfigBlockNum()
@@ -345,7 +383,7 @@ func (self *Server) updateChainConfig() error {
 	// 2. remove nonparticipation consensus node
 	// 3. update statemgr peers
 	// 4. reset remove peer connections, create new connections with new peers
-	pubkey := vconfig.PubkeyID(self.account.PublicKey)
+	pubkey := vconfig.PubkeyID(self.signer.PubKey())
 	peermap := make(map[uint32]string)
 	for _, p := range self.config.Peers {
 		peermap[p.Index] = p.ID
@@ -406,7 +444,7 @@ func (self *Server) initialize() error {
 	// TODO: load config from chain
 
 	// TODO: configurable log
-	selfNodeId := vconfig.PubkeyID(self.account.PublicKey)
+	selfNodeId := vconfig.PubkeyID(self.signer.PubKey())
 	log.Infof("server: %s starting", selfNodeId)
 
 	store, err := OpenBlockStore(self.ledger, self.pid)
@@ -455,7 +493,7 @@ func (self *Server) initialize() error {
 	}
 
 	//index equal math.MaxUint32  is noconsensus node
-	id := vconfig.PubkeyID(self.account.PublicKey)
+	id := vconfig.PubkeyID(self.signer.PubKey())
 	index, present := self.peerPool.GetPeerIndex(id)
 	if present {
 		self.Index = index
@@ -494,7 +532,11 @@ func (self *Server) initialize() error {
 
 func (self *Server) start() error {
 	// check if server pubkey support VRF
-	if !vrf.ValidatePrivateKey(self.account.PrivateKey) || !vrf.ValidatePublicKey(self.account.PublicKey) {
+	// private key of remote signer is not accessible, only check its public key
+	if acc, ok := self.signer.(*account.Account); ok && !vrf.ValidatePrivateKey(acc.PrivateKey) {
+		return fmt.Errorf("server %d consensus start failed: invalid account key for VRF", self.Index)
+	}
+	if !vrf.ValidatePublicKey(self.signer.PubKey()) {
 		return fmt.Errorf("server %d consensus start failed: invalid account key for VRF", self.Index)
 	}
 
@@ -654,6 +696,7 @@ func (self *Server) startNewRound() error {
 		log.Errorf("startNewRound error:%s", err)
 		return err
 	}
+	self.history.startRound(self.currentParticipantConfig)
 	// check proposals in msgpool
 	var proposal *blockProposalMsg
 	if proposals := self.msgPool.GetProposalMsgs(blkNum); len(proposals) > 0 {
@@ -836,6 +879,12 @@ func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash com
 			}
 		} else if msgBlkNum < self.GetCurrentBlockNo() {
 			if msgBlkNum <= self.GetCommittedBlockNo() {
+				if msgBlkNum == self.GetCommittedBlockNo() {
+					// keep endorsements of last sealed block, its signers are recorded in next proposal
+					if err := self.blockPool.newBlockEndorsement(pMsg); err != nil {
+						log.Debugf("server %d, add endorsement of sealed block %d: %s", self.Index, msgBlkNum, err)
+					}
+				}
 				if msgBlkNum+MAX_SYNCING_CHECK_BLK_NUM < self.GetCommittedBlockNo() {
 					log.Infof("server %d get endorse msg for block %d, from %d, current committed %d",
 						self.Index, msgBlkNum, pMsg.Endorser, self.GetCommittedBlockNo())
@@ -1087,7 +1136,7 @@ func (self *Server) processProposalMsg(msg *blockProposalMsg) {
 
 	prevBlockTimestamp := blk.Block.Header.Timestamp
 	currentBlockTimestamp := msg.Block.Block.Header.Timestamp
-	if currentBlockTimestamp <= prevBlockTimestamp || currentBlockTimestamp > uint32(time.Now().Add(time.Minute*10).Unix()) {
+	if currentBlockTimestamp <= prevBlockTimestamp || currentBlockTimestamp > uint32(self.clock.Now().Add(time.Minute*10).Unix()) {
 		log.Errorf("BlockPrposalMessage check  blocknum:%d,prevBlockTimestamp:%d,currentBlockTimestamp:%d", msg.GetBlockNum(), prevBlockTimestamp, currentBlockTimestamp)
 		self.msgPool.DropMsg(msg)
 		return
@@ -1108,7 +1157,21 @@ func (self *Server) processProposalMsg(msg *blockProposalMsg) {
 		return
 	}
 
+	if err := self.verifyPrevBlockSigners(msg.Block); err != nil {
+		log.Errorf("server %d failed to verify prev block signers of block %d proposal from %d: %s",
+			self.Index, msgBlkNum, msg.Block.getProposer(), err)
+		self.msgPool.DropMsg(msg)
+		return
+	}
+
 	txs := msg.Block.Block.Transactions
+	// the governance transaction commits the peer performance of view, it has to be the one computed locally
+	if peerPerformanceEnabled(msgBlkNum) && self.checkNeedUpdateChainConfig(msgBlkNum) && self.nonSystxs(txs, msgBlkNum) {
+		log.Errorf("server %d failed to verify governance transaction of block %d proposal from %d",
+			self.Index, msgBlkNum, msg.Block.getProposer())
+		self.msgPool.DropMsg(msg)
+		return
+	}
 	if len(txs) > 0 && self.nonSystxs(txs, msgBlkNum) {
 		height := uint32(msgBlkNum) - 1
 		start, end := self.incrValidator.BlockRange()
@@ -1672,6 +1735,7 @@ func (self *Server) actionLoop() {
 }
 
 func (self *Server) processTimerEvent(evt *TimerEvent) error {
+	self.history.addTimerEvent(evt)
 	switch evt.evtType {
 	case EventProposalBackoff:
 		// 1. if endorsed, return
@@ -1931,6 +1995,10 @@ func (self *Server) endorseBlock(proposal *blockProposalMsg, forEmpty bool) erro
 		if self.blockPool.endorseFailed(blkNum, self.config.C) {
 			forEmpty = true
 			log.Errorf("server %d, endorsing %d, changed from true to false", self.Index, blkNum)
+		} else if self.omitsSelfEndorsement(proposal.Block) {
+			forEmpty = true
+			log.Errorf("server %d, endorsing %d, proposal from %d omits self in prev block signers",
+				self.Index, blkNum, proposal.Block.getProposer())
 		}
 	}
 
@@ -2102,6 +2170,7 @@ func (self *Server) sealBlock(block *Block, empty bool, sigdata bool) error {
 	prevBlkHash := block.getPrevBlockHash()
 	log.Infof("server %d, sealed block %d, proposer %d, prevhash: %s, hash: %s", self.Index,
 		sealedBlkNum, block.getProposer(), prevBlkHash.ToHexString(), h.ToHexString())
+	self.history.sealed(block, empty, h.ToHexString())
 
 	// broadcast to other modules
 	// TODO: block committed, update tx pool, notify block-listeners
@@ -2150,14 +2219,40 @@ func (self *Server) msgSendLoop() {
 	}
 }
 
-//creategovernaceTransaction invoke governance native contract commit_pos
-func (self *Server) createGovernaceTransaction(blkNum uint32) *types.Transaction {
+//creategovernaceTransaction invoke governance native contract commit_pos, with peer performance of current view
+//since peer performance height
+func (self *Server) createGovernaceTransaction(blkNum uint32) (*types.Transaction, error) {
+	args := common.NewZeroCopySink(nil)
+	if peerPerformanceEnabled(blkNum) {
+		performances, err := self.getPeerPerformance(blkNum)
+		if err != nil {
+			return nil, fmt.Errorf("getPeerPerformance failed: %s", err)
+		}
+		param := &node_manager.CommitDposParam{Performances: performances}
+		param.Serialization(args)
+	}
 	contractInvokeParam := &states.ContractInvokeParam{Address: utils.NodeManagerContractAddress,
-		Method: node_manager.COMMIT_DPOS, Args: []byte{}}
+		Method: node_manager.COMMIT_DPOS, Args: args.Bytes()}
 	invokeCode := new(common.ZeroCopySink)
 	contractInvokeParam.Serialization(invokeCode)
 	tx := genesis.NewInvokeTransaction(invokeCode.Bytes(), blkNum)
-	return tx
+	return tx, nil
+}
+
+//signGovernaceTransaction signs governance transaction as proposer, commit_pos only takes the peer performance
+//of transactions signed by consensus peers
+func (self *Server) signGovernaceTransaction(tx *types.Transaction) (*types.Transaction, error) {
+	txHash := tx.Hash()
+	sigData, err := signature.Sign(self.signer, txHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("sign governance transaction: %s", err)
+	}
+	tx.Sigs = []types.Sig{{PubKeys: []keypair.PublicKey{self.signer.PubKey()}, M: 1, SigData: [][]byte{sigData}}}
+	sink := common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, err
+	}
+	return types.TransactionFromRawBytes(sink.Bytes())
 }
 
 //checkNeedUpdateChainConfig use blockcount
@@ -2176,7 +2271,7 @@ func (self *Server) checkNeedUpdateChainConfig(blockNum uint32) bool {
 
 //checkUpdateChainConfig query leveldb check is force update
 func (self *Server) checkUpdateChainConfig(blkNum uint32) bool {
-	force, err := isUpdate(self.blockPool.getExecWriteSet(blkNum-1), self.config.View)
+	force, err := isUpdate(self.blockPool.getExecWriteSet(blkNum-1), self.ledger, self.config.View)
 	if err != nil {
 		log.Errorf("checkUpdateChainConfig err:%s", err)
 		return false
@@ -2204,7 +2299,11 @@ func (self *Server) nonSystxs(sysTxs []*types.Transaction, blkNum uint32) bool {
 			log.Errorf("nonSystxs invoke is nil,blocknum:%d", blkNum)
 			return true
 		}
-		tx := self.createGovernaceTransaction(blkNum)
+		tx, err := self.createGovernaceTransaction(blkNum)
+		if err != nil {
+			log.Errorf("nonSystxs createGovernaceTransaction failed, blocknum:%d, err:%s", blkNum, err)
+			return true
+		}
 		if bytes.Compare(invoke.Code, tx.Payload.(*payload.InvokeCode).Code) == 0 {
 			return false
 		}
@@ -2226,13 +2325,34 @@ func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
 	cfg := &vconfig.ChainConfig{}
 	cfg = nil
 	if self.checkNeedUpdateChainConfig(blkNum) || self.checkUpdateChainConfig(blkNum) {
-		chainconfig, err := getChainConfig(self.blockPool.getExecWriteSet(blkNum-1), blkNum)
+		memdb := self.blockPool.getExecWriteSet(blkNum - 1)
+		//peers demoted by performance policy leave consensus in new view
+		var demoted map[string]bool
+		if self.checkNeedUpdateChainConfig(blkNum) && peerPerformanceEnabled(blkNum) {
+			performances, err := self.getPeerPerformance(blkNum)
+			if err != nil {
+				return fmt.Errorf("getPeerPerformance failed:%s", err)
+			}
+			demoted, err = getDemotedPeers(memdb, self.ledger, performances)
+			if err != nil {
+				return fmt.Errorf("getDemotedPeers failed:%s", err)
+			}
+		}
+		chainconfig, err := getChainConfig(memdb, self.ledger, blkNum, demoted)
 		if err != nil {
 			return fmt.Errorf("getChainConfig failed:%s", err)
 		}
 		//add transaction invoke governance native commit_pos contract
 		if self.checkNeedUpdateChainConfig(blkNum) {
-			tx := self.createGovernaceTransaction(blkNum)
+			tx, err := self.createGovernaceTransaction(blkNum)
+			if err != nil {
+				return fmt.Errorf("createGovernaceTransaction failed:%s", err)
+			}
+			if peerPerformanceEnabled(blkNum) {
+				if tx, err = self.signGovernaceTransaction(tx); err != nil {
+					return fmt.Errorf("signGovernaceTransaction failed:%s", err)
+				}
+			}
 			sysTxs = append(sysTxs, tx)
 			chainconfig.View++
 		}
@@ -2409,6 +2529,7 @@ func (self *Server) catchConsensus(blkNum uint32) error {
 	if !self.isEndorser(blkNum, self.Index) && !self.isCommitter(blkNum, self.Index) {
 		return nil
 	}
+	self.history.catchUp(blkNum)
 
 	proposals := make(map[uint32]*blockProposalMsg)
 	pMsgs := self.msgPool.GetProposalMsgs(blkNum)
@@ -0,0 +1,644 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/ec"
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology-eventbus/eventhub"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/constants"
+	"github.com/polynetwork/poly/common/log"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	ontErrors "github.com/polynetwork/poly/errors"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	p2pmsg "github.com/polynetwork/poly/p2pserver/message/types"
+	txpool "github.com/polynetwork/poly/txnpool/common"
+)
+
+// simulation runs several vbft servers in process, each with its own ledger and
+// txpool, connected by simNetwork and driven by simClock.
+//
+// Keys, network latency, drops and partitions are derived from the seed, but the
+// servers still run concurrently, so scenarios check safety and liveness
+// rather than exact block hashes.
+
+var simActorSeq uint32
+
+func simActorName(kind string) string {
+	return fmt.Sprintf("vbft_sim_%s_%d", kind, atomic.AddUint32(&simActorSeq, 1))
+}
+
+type simConfig struct {
+	nodes              int
+	seed               int64
+	maxBlockChangeView uint32
+	step               time.Duration // virtual time advanced each step
+	realStep           time.Duration // real time waited each step for servers to process
+}
+
+type simNode struct {
+	index   uint32 // peer index in chain config
+	id      uint64 // p2p id
+	account *account.Account
+	ledger  *ledger.Ledger
+	pool    *simTxPool
+	server  *Server
+	p2p     *actor.PID
+	halted  int32
+}
+
+func (self *simNode) deliver(payload *p2pmsg.ConsensusPayload) {
+	if atomic.LoadInt32(&self.halted) != 0 {
+		return
+	}
+	if err := payload.Verify(); err != nil {
+		return
+	}
+	self.server.pid.Tell(payload)
+}
+
+func (self *simNode) height() uint32 {
+	return self.server.GetCommittedBlockNo()
+}
+
+func (self *simNode) view() uint32 {
+	self.server.metaLock.RLock()
+	defer self.server.metaLock.RUnlock()
+	return self.server.config.View
+}
+
+// simTxPool answers txpool requests of vbft server, every transaction submitted
+// is considered valid and kept until it is saved in ledger
+type simTxPool struct {
+	lock   sync.Mutex
+	ledger *ledger.Ledger
+	txs    []*types.Transaction
+}
+
+func (self *simTxPool) add(tx *types.Transaction) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.txs = append(self.txs, tx)
+}
+
+func (self *simTxPool) pending() []*txpool.TXEntry {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	txs := self.txs[:0]
+	entries := make([]*txpool.TXEntry, 0)
+	for _, tx := range self.txs {
+		if ok, _ := self.ledger.IsContainTransaction(tx.Hash()); ok {
+			continue
+		}
+		txs = append(txs, tx)
+		entries = append(entries, &txpool.TXEntry{Tx: tx})
+	}
+	self.txs = txs
+	return entries
+}
+
+func (self *simTxPool) Receive(context actor.Context) {
+	switch msg := context.Message().(type) {
+	case *txpool.GetTxnPoolReq:
+		context.Sender().Request(&txpool.GetTxnPoolRsp{TxnPool: self.pending()}, context.Self())
+	case *txpool.VerifyBlockReq:
+		rsp := &txpool.VerifyBlockRsp{TxnPool: make([]*txpool.VerifyTxResult, 0, len(msg.Txs))}
+		for _, tx := range msg.Txs {
+			rsp.TxnPool = append(rsp.TxnPool, &txpool.VerifyTxResult{Height: msg.Height, Tx: tx, ErrCode: ontErrors.ErrNoError})
+		}
+		context.Sender().Request(rsp, context.Self())
+	}
+}
+
+type simCluster struct {
+	t         *testing.T
+	cfg       simConfig
+	clock     *simClock
+	net       *simNetwork
+	nodes     []*simNode
+	dataDir   string
+	logLevel  int
+	networkId uint32
+	nonce     uint32
+}
+
+// simAccount derives the key of node from seed, so a scenario always runs with same peers
+func simAccount(seed int64, i int) *account.Account {
+	curve := elliptic.P256()
+	h := sha256.Sum256([]byte(fmt.Sprintf("vbft simulation %d %d", seed, i)))
+	d := new(big.Int).SetBytes(h[:])
+	d.Mod(d, new(big.Int).Sub(curve.Params().N, big.NewInt(1)))
+	d.Add(d, big.NewInt(1))
+
+	pri := &ecdsa.PrivateKey{D: d}
+	pri.PublicKey.Curve = curve
+	pri.PublicKey.X, pri.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+	pub := &ec.PublicKey{Algorithm: ec.ECDSA, PublicKey: &pri.PublicKey}
+	return &account.Account{
+		PrivateKey: &ec.PrivateKey{Algorithm: ec.ECDSA, PrivateKey: pri},
+		PublicKey:  pub,
+		Address:    types.AddressFromPubKey(pub),
+		SigScheme:  s.SHA256withECDSA,
+	}
+}
+
+func newSimCluster(t *testing.T, cfg simConfig) *simCluster {
+	if cfg.nodes == 0 {
+		cfg.nodes = 4
+	}
+	if cfg.maxBlockChangeView == 0 {
+		cfg.maxBlockChangeView = 60000
+	}
+	if cfg.step == 0 {
+		cfg.step = 100 * time.Millisecond
+	}
+	if cfg.realStep == 0 {
+		cfg.realStep = 2 * time.Millisecond
+	}
+	dataDir, err := ioutil.TempDir("", "vbft_sim")
+	if err != nil {
+		t.Fatalf("create data dir: %s", err)
+	}
+	clock := newSimClock(time.Unix(int64(constants.GENESIS_BLOCK_TIMESTAMP), 0).Add(time.Hour))
+	cluster := &simCluster{
+		t:         t,
+		cfg:       cfg,
+		clock:     clock,
+		net:       newSimNetwork(clock, cfg.seed),
+		dataDir:   dataDir,
+		logLevel:  log.Log.GetDebugLevel(),
+		networkId: config.DefConfig.P2PNode.NetworkId,
+	}
+	log.Log.SetDebugLevel(log.FatalLog)
+	// nodes run on solo net, where all heights of forks are reached
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	// governance contract of the chain, registered by native/service in node
+	native.Contracts[utils.NodeManagerContractAddress] = node_manager.RegisterNodeManagerContract
+
+	vbftCfg := &config.VBFTConfig{
+		BlockMsgDelay:        5000,
+		HashMsgDelay:         5000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   cfg.maxBlockChangeView,
+		VrfValue:             config.PolarisConfig.VBFT.VrfValue,
+		VrfProof:             config.PolarisConfig.VBFT.VrfProof,
+	}
+	bookkeepers := make([]keypair.PublicKey, 0, cfg.nodes)
+	for i := 0; i < cfg.nodes; i++ {
+		acc := simAccount(cfg.seed, i)
+		node := &simNode{
+			index:   uint32(i + 1),
+			id:      uint64(i + 1),
+			account: acc,
+		}
+		cluster.nodes = append(cluster.nodes, node)
+		cluster.net.nodes[node.id] = node
+		bookkeepers = append(bookkeepers, acc.PubKey())
+		vbftCfg.Peers = append(vbftCfg.Peers, &config.VBFTPeerInfo{
+			Index:      node.index,
+			PeerPubkey: vconfig.PubkeyID(acc.PubKey()),
+			Address:    acc.Address.ToBase58(),
+		})
+	}
+
+	genesisCfg := config.DefConfig.Genesis
+	config.DefConfig.Genesis = &config.GenesisConfig{ConsensusType: config.CONSENSUS_TYPE_VBFT, VBFT: vbftCfg}
+	defer func() {
+		config.DefConfig.Genesis = genesisCfg
+	}()
+	for _, node := range cluster.nodes {
+		if err := cluster.initNode(node, bookkeepers); err != nil {
+			cluster.stop()
+			t.Fatalf("init node %d: %s", node.index, err)
+		}
+	}
+	for _, node := range cluster.nodes {
+		if err := node.server.Start(); err != nil {
+			cluster.stop()
+			t.Fatalf("start node %d: %s", node.index, err)
+		}
+	}
+	return cluster
+}
+
+func (self *simCluster) initNode(node *simNode, bookkeepers []keypair.PublicKey) error {
+	genesisBlock, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	if err != nil {
+		return fmt.Errorf("build genesis block: %s", err)
+	}
+	node.ledger, err = ledger.NewLedger(filepath.Join(self.dataDir, fmt.Sprintf("node%d", node.index)))
+	if err != nil {
+		return err
+	}
+	if err := node.ledger.Init(bookkeepers, genesisBlock); err != nil {
+		return err
+	}
+
+	node.pool = &simTxPool{ledger: node.ledger}
+	poolPid, err := actor.SpawnNamed(actor.FromProducer(func() actor.Actor {
+		return node.pool
+	}), simActorName("txpool"))
+	if err != nil {
+		return err
+	}
+	node.p2p, err = actor.SpawnNamed(actor.FromProducer(func() actor.Actor {
+		return &simP2P{net: self.net, id: node.id}
+	}), simActorName("p2p"))
+	if err != nil {
+		return err
+	}
+	node.server, err = newVbftServer(simActorName("consensus"), node.account, poolPid, node.p2p, node.ledger,
+		eventhub.GlobalEventHub, self.clock, "")
+	return err
+}
+
+func (self *simCluster) stop() {
+	for _, node := range self.nodes {
+		atomic.StoreInt32(&node.halted, 1)
+		if node.server != nil {
+			node.server.stop()
+			node.server.pid.Stop()
+		}
+		if node.p2p != nil {
+			node.p2p.Stop()
+		}
+		if node.ledger != nil {
+			node.ledger.Close()
+		}
+	}
+	os.RemoveAll(self.dataDir)
+	log.Log.SetDebugLevel(self.logLevel)
+	config.DefConfig.P2PNode.NetworkId = self.networkId
+}
+
+// run advances virtual time by d, step by step
+func (self *simCluster) run(d time.Duration) {
+	for elapsed := time.Duration(0); elapsed < d; elapsed += self.cfg.step {
+		self.clock.advance(self.cfg.step)
+		time.Sleep(self.cfg.realStep)
+	}
+}
+
+// runUntil advances virtual time until cond is met, or timeout
+func (self *simCluster) runUntil(timeout time.Duration, cond func() bool) bool {
+	for elapsed := time.Duration(0); elapsed < timeout; elapsed += self.cfg.step {
+		if cond() {
+			return true
+		}
+		self.clock.advance(self.cfg.step)
+		time.Sleep(self.cfg.realStep)
+	}
+	return cond()
+}
+
+// waitHeight checks liveness, all nodes should reach height in timeout of virtual time
+func (self *simCluster) waitHeight(nodes []*simNode, height uint32, timeout time.Duration) {
+	if !self.reachHeight(nodes, height, timeout) {
+		self.dump()
+		self.t.Fatalf("nodes failed to reach height %d in %s: %s", height, timeout, self.heights())
+	}
+}
+
+// reachHeight returns whether all nodes reach height in timeout of virtual time
+func (self *simCluster) reachHeight(nodes []*simNode, height uint32, timeout time.Duration) bool {
+	return self.runUntil(timeout, func() bool {
+		for _, node := range nodes {
+			if node.height() < height {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (self *simCluster) heights() string {
+	buf := new(bytes.Buffer)
+	for _, node := range self.nodes {
+		fmt.Fprintf(buf, "node %d: %d, ", node.index, node.height())
+	}
+	return buf.String()
+}
+
+func (self *simCluster) maxHeight() uint32 {
+	height := uint32(0)
+	for _, node := range self.nodes {
+		if h := node.height(); h > height {
+			height = h
+		}
+	}
+	return height
+}
+
+// waitView checks that all nodes moved to chain config view
+func (self *simCluster) waitView(view uint32, timeout time.Duration) {
+	ok := self.runUntil(timeout, func() bool {
+		for _, node := range self.nodes {
+			if node.view() < view {
+				return false
+			}
+		}
+		return true
+	})
+	if !ok {
+		self.dump()
+		self.t.Fatalf("nodes failed to reach view %d in %s: %s", view, timeout, self.heights())
+	}
+}
+
+// viewChangeReason returns the reason of change to view recorded in round history of node
+func viewChangeReason(node *simNode, view uint32) string {
+	for _, r := range node.server.history.snapshot(ROUND_HISTORY_LEN) {
+		for _, evt := range r.Events {
+			if evt.Type == RoundEventViewChange && evt.View == view {
+				return evt.Reason
+			}
+		}
+	}
+	return ""
+}
+
+// hasRoundEvent returns whether an event of evtType is recorded in round history of blkNum of node
+func hasRoundEvent(node *simNode, blkNum uint32, evtType string) bool {
+	for _, r := range node.server.history.snapshot(ROUND_HISTORY_LEN) {
+		if r.BlockNum != blkNum {
+			continue
+		}
+		for _, evt := range r.Events {
+			if evt.Type == evtType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dump logs latest rounds of all nodes, to tell where consensus stalled
+func (self *simCluster) dump() {
+	for _, node := range self.nodes {
+		for _, r := range node.server.history.snapshot(2) {
+			events := make([]string, 0, len(r.Events))
+			for _, evt := range r.Events {
+				events = append(events, fmt.Sprintf("%s(%d)", evt.Type, evt.Peer))
+			}
+			self.t.Logf("node %d block %d view %d proposers %v: %v", node.index, r.BlockNum, r.View, r.Proposers, events)
+		}
+	}
+}
+
+// checkSafety verifies that no two nodes have different blocks at same height
+func (self *simCluster) checkSafety() {
+	if fork := self.findFork(); fork != "" {
+		self.t.Fatalf(fork)
+	}
+}
+
+// findFork describes the first height where two nodes have different blocks, empty if no fork
+func (self *simCluster) findFork() string {
+	for h := uint32(1); ; h++ {
+		var hash common.Uint256
+		var owner *simNode
+		checked := 0
+		for _, node := range self.nodes {
+			if node.ledger.GetCurrentBlockHeight() < h {
+				continue
+			}
+			checked++
+			blkHash := node.ledger.GetBlockHash(h)
+			if owner == nil {
+				hash, owner = blkHash, node
+			} else if blkHash != hash {
+				return fmt.Sprintf("fork at height %d: node %d has %s, node %d has %s", h,
+					owner.index, hash.ToHexString(), node.index, blkHash.ToHexString())
+			}
+		}
+		if checked < 2 {
+			return ""
+		}
+	}
+}
+
+func (self *simCluster) submitTx(tx *types.Transaction) {
+	for _, node := range self.nodes {
+		node.pool.add(tx)
+	}
+}
+
+// commitDposTx builds governance transaction to go to next view, signed by the consensus operator of all nodes
+func (self *simCluster) commitDposTx() (*types.Transaction, error) {
+	param := &states.ContractInvokeParam{Address: utils.NodeManagerContractAddress, Method: node_manager.COMMIT_DPOS}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	self.nonce++
+	tx := genesis.NewInvokeTransaction(sink.Bytes(), self.nonce)
+
+	pubKeys := make([]keypair.PublicKey, 0, len(self.nodes))
+	for _, node := range self.nodes {
+		pubKeys = append(pubKeys, node.account.PubKey())
+	}
+	m := len(pubKeys) - (len(pubKeys)-1)/3
+	hash := tx.Hash()
+	sig := types.Sig{PubKeys: pubKeys, M: uint16(m)}
+	for _, node := range self.nodes[:m] {
+		data, err := signature.Sign(node.account, hash[:])
+		if err != nil {
+			return nil, err
+		}
+		sig.SigData = append(sig.SigData, data)
+	}
+	tx.Sigs = []types.Sig{sig}
+
+	sink = common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, err
+	}
+	return types.TransactionFromRawBytes(sink.Bytes())
+}
+
+// isolate cuts node off from all other nodes
+func (self *simCluster) isolate(node *simNode) {
+	others := make([]*simNode, 0, len(self.nodes))
+	for _, n := range self.nodes {
+		if n != node {
+			others = append(others, n)
+		}
+	}
+	self.net.setPartition(others)
+}
+
+// wrongParent makes proposer sign its proposals on a non-existing parent block for victims,
+// other nodes receive the original one
+func (self *simCluster) wrongParent(proposer *simNode, victims ...*simNode) simFilter {
+	return self.forgedProposals(proposer, victims, func(block *types.Block) error {
+		block.Header.PrevBlockHash = sha256.Sum256(block.Header.PrevBlockHash[:])
+		return nil
+	})
+}
+
+// equivocate makes proposer sign another proposal of the same height and view for victims,
+// other nodes receive the original one
+func (self *simCluster) equivocate(proposer *simNode, victims ...*simNode) simFilter {
+	return self.forgedProposals(proposer, victims, func(block *types.Block) error {
+		block.Header.Timestamp++
+		return nil
+	})
+}
+
+// omitSigners makes proposer record no signer of prev block but itself in its proposals for victims,
+// other nodes receive the original one
+func (self *simCluster) omitSigners(proposer *simNode, victims ...*simNode) simFilter {
+	return self.forgedProposals(proposer, victims, func(block *types.Block) error {
+		info := &vconfig.VbftBlockInfo{}
+		if err := json.Unmarshal(block.Header.ConsensusPayload, info); err != nil {
+			return err
+		}
+		signers, sigData := make([]uint32, 0), make([][]byte, 0)
+		for i, idx := range info.PrevBlockSigners {
+			if idx == proposer.index {
+				signers = append(signers, idx)
+				sigData = append(sigData, info.PrevBlockSigData[i])
+			}
+		}
+		info.PrevBlockSigners, info.PrevBlockSigData = signers, sigData
+		payload, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		block.Header.ConsensusPayload = payload
+		return nil
+	})
+}
+
+// forgedProposals replaces the proposals of proposer to victims with the ones rebuilt by forge
+func (self *simCluster) forgedProposals(proposer *simNode, victims []*simNode, forge func(block *types.Block) error) simFilter {
+	var lock sync.Mutex
+	forged := make(map[common.Uint256]*p2pmsg.ConsensusPayload)
+	return func(from, to *simNode, payload *p2pmsg.ConsensusPayload) *p2pmsg.ConsensusPayload {
+		if from != proposer || !containsSimNode(victims, to) {
+			return payload
+		}
+		msg, err := DeserializeVbftMsg(payload.Data)
+		if err != nil {
+			return payload
+		}
+		proposal, ok := msg.(*blockProposalMsg)
+		if !ok {
+			return payload
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		blkHash := proposal.Block.Block.Hash()
+		if p, present := forged[blkHash]; present {
+			return p
+		}
+		p, err := forgeProposal(proposer.account, proposal, forge)
+		if err != nil {
+			self.t.Errorf("forge proposal of block %d: %s", proposal.GetBlockNum(), err)
+			return payload
+		}
+		forged[blkHash] = p
+		return p
+	}
+}
+
+func containsSimNode(nodes []*simNode, node *simNode) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+// forgeProposal rebuilds the block of proposal with forge, and signs it again
+func forgeProposal(signer *account.Account, proposal *blockProposalMsg, forge func(block *types.Block) error) (*p2pmsg.ConsensusPayload, error) {
+	sink := common.NewZeroCopySink(nil)
+	if err := proposal.Block.Block.Serialization(sink); err != nil {
+		return nil, err
+	}
+	block, err := types.BlockFromRawBytes(sink.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if err := forge(block); err != nil {
+		return nil, err
+	}
+	sink = common.NewZeroCopySink(nil)
+	if err := block.Serialization(sink); err != nil {
+		return nil, err
+	}
+	if block, err = types.BlockFromRawBytes(sink.Bytes()); err != nil {
+		return nil, err
+	}
+	hash := block.Hash()
+	sig, err := signature.Sign(signer, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	block.Header.SigData = [][]byte{sig}
+
+	blk, err := initVbftBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	blk.EmptyBlock = proposal.Block.EmptyBlock
+	data, err := SerializeVbftMsg(&blockProposalMsg{Block: blk})
+	if err != nil {
+		return nil, err
+	}
+	payload := &p2pmsg.ConsensusPayload{
+		Data:  data,
+		Owner: signer.PubKey(),
+	}
+	buf := new(bytes.Buffer)
+	if err := payload.SerializeUnsigned(buf); err != nil {
+		return nil, err
+	}
+	if payload.Signature, err = signature.Sign(signer, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
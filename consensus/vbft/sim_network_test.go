@@ -0,0 +1,290 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"container/heap"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	netActor "github.com/polynetwork/poly/p2pserver/actor/server"
+	p2pmsg "github.com/polynetwork/poly/p2pserver/message/types"
+)
+
+// simClock is a virtual clock of simulation, time only moves forward by advance.
+// Timers due are fired in order of due time, each in its own goroutine as time.AfterFunc does.
+type simClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	seq    uint64
+	timers simTimerQueue
+}
+
+type simTimer struct {
+	clock *simClock
+	due   time.Time
+	seq   uint64
+	f     func()
+	index int
+}
+
+type simTimerQueue []*simTimer
+
+func (tq simTimerQueue) Len() int {
+	return len(tq)
+}
+
+func (tq simTimerQueue) Less(i, j int) bool {
+	if tq[i].due.Equal(tq[j].due) {
+		return tq[i].seq < tq[j].seq
+	}
+	return tq[i].due.Before(tq[j].due)
+}
+
+func (tq simTimerQueue) Swap(i, j int) {
+	tq[i], tq[j] = tq[j], tq[i]
+	tq[i].index = i
+	tq[j].index = j
+}
+
+func (tq *simTimerQueue) Push(x interface{}) {
+	t := x.(*simTimer)
+	t.index = len(*tq)
+	*tq = append(*tq, t)
+}
+
+func (tq *simTimerQueue) Pop() interface{} {
+	old := *tq
+	n := len(old)
+	t := old[n-1]
+	t.index = -1
+	*tq = old[0 : n-1]
+	return t
+}
+
+func newSimClock(start time.Time) *simClock {
+	return &simClock{now: start}
+}
+
+func (self *simClock) Now() time.Time {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.now
+}
+
+func (self *simClock) AfterFunc(d time.Duration, f func()) Timer {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	t := &simTimer{clock: self, f: f, index: -1}
+	self.schedule(t, d)
+	return t
+}
+
+func (self *simClock) schedule(t *simTimer, d time.Duration) {
+	self.seq++
+	t.seq = self.seq
+	t.due = self.now.Add(d)
+	heap.Push(&self.timers, t)
+}
+
+// advance moves the clock forward by d, and fires all timers due in the period
+func (self *simClock) advance(d time.Duration) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	end := self.now.Add(d)
+	for len(self.timers) > 0 && !self.timers[0].due.After(end) {
+		t := heap.Pop(&self.timers).(*simTimer)
+		self.now = t.due
+		go t.f()
+	}
+	self.now = end
+}
+
+func (self *simTimer) Stop() bool {
+	self.clock.lock.Lock()
+	defer self.clock.lock.Unlock()
+
+	if self.index < 0 {
+		return false
+	}
+	heap.Remove(&self.clock.timers, self.index)
+	return true
+}
+
+func (self *simTimer) Reset(d time.Duration) bool {
+	self.clock.lock.Lock()
+	defer self.clock.lock.Unlock()
+
+	active := self.index >= 0
+	if active {
+		heap.Remove(&self.clock.timers, self.index)
+	}
+	self.clock.schedule(self, d)
+	return active
+}
+
+// simFilter intercepts consensus payload sent from one node to another, the payload
+// returned is delivered instead, or the message is dropped if nil returned.
+type simFilter func(from, to *simNode, payload *p2pmsg.ConsensusPayload) *p2pmsg.ConsensusPayload
+
+// simNetwork delivers consensus payloads between simulated nodes with latency,
+// message drops, partitions and filters. All random decisions are taken from
+// a seeded source, so the fault schedule of a scenario is reproducible.
+type simNetwork struct {
+	lock       sync.Mutex
+	clock      *simClock
+	rand       *rand.Rand
+	nodes      map[uint64]*simNode
+	minLatency time.Duration
+	maxLatency time.Duration
+	dropRate   float64
+	partition  map[uint64]int
+	filters    []simFilter
+	sent       uint64
+	dropped    uint64
+}
+
+func newSimNetwork(clock *simClock, seed int64) *simNetwork {
+	return &simNetwork{
+		clock:      clock,
+		rand:       rand.New(rand.NewSource(seed)),
+		nodes:      make(map[uint64]*simNode),
+		minLatency: 10 * time.Millisecond,
+		maxLatency: 10 * time.Millisecond,
+		partition:  make(map[uint64]int),
+	}
+}
+
+func (self *simNetwork) setLatency(min, max time.Duration) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.minLatency, self.maxLatency = min, max
+}
+
+func (self *simNetwork) setDropRate(rate float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.dropRate = rate
+}
+
+// setPartition splits nodes into groups, nodes can only reach nodes in the same group.
+// Nodes not listed in any group stay in a group of their own.
+func (self *simNetwork) setPartition(groups ...[]*simNode) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.partition = make(map[uint64]int)
+	for id := range self.nodes {
+		self.partition[id] = -int(id)
+	}
+	for i, group := range groups {
+		for _, node := range group {
+			self.partition[node.id] = i + 1
+		}
+	}
+}
+
+// heal removes network partition
+func (self *simNetwork) heal() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.partition = make(map[uint64]int)
+}
+
+func (self *simNetwork) addFilter(filter simFilter) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.filters = append(self.filters, filter)
+}
+
+func (self *simNetwork) connected(from, to uint64) bool {
+	return self.partition[from] == self.partition[to]
+}
+
+func (self *simNetwork) send(from uint64, to []uint64, payload *p2pmsg.ConsensusPayload) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	src := self.nodes[from]
+	for _, id := range to {
+		dst := self.nodes[id]
+		if id == from || dst == nil {
+			continue
+		}
+		self.sent++
+		msg := payload
+		for _, filter := range self.filters {
+			if msg = filter(src, dst, msg); msg == nil {
+				break
+			}
+		}
+		// always draw the drop decision to keep random sequence independent of partitions
+		drop := self.rand.Float64() < self.dropRate
+		latency := self.minLatency
+		if self.maxLatency > self.minLatency {
+			latency += time.Duration(self.rand.Int63n(int64(self.maxLatency - self.minLatency)))
+		}
+		if msg == nil || drop || !self.connected(from, id) {
+			self.dropped++
+			continue
+		}
+		p := *msg
+		p.PeerId = from
+		self.clock.AfterFunc(latency, func() {
+			dst.deliver(&p)
+		})
+	}
+}
+
+func (self *simNetwork) ids() []uint64 {
+	ids := make([]uint64, 0, len(self.nodes))
+	for id := range self.nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// simP2P is the p2p actor of simulated node, consensus messages told by vbft server
+// are sent to simulated network
+type simP2P struct {
+	net *simNetwork
+	id  uint64
+}
+
+func (self *simP2P) Receive(context actor.Context) {
+	switch msg := context.Message().(type) {
+	case *p2pmsg.ConsensusPayload:
+		self.net.lock.Lock()
+		ids := self.net.ids()
+		self.net.lock.Unlock()
+		self.net.send(self.id, ids, msg)
+	case *netActor.TransmitConsensusMsgReq:
+		if cons, ok := msg.Msg.(*p2pmsg.Consensus); ok {
+			self.net.send(self.id, []uint64{msg.Target}, &cons.Cons)
+		}
+	}
+}
@@ -19,7 +19,9 @@
 package vbft
 
 import (
+	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/polynetwork/poly/common/log"
@@ -44,6 +46,33 @@ const (
 	SyncingCheck     // potentially lost syncing
 )
 
+var serverStateNames = map[ServerState]string{
+	Init:             "Init",
+	LocalConfigured:  "LocalConfigured",
+	Configured:       "Configured",
+	Syncing:          "Syncing",
+	WaitNetworkReady: "WaitNetworkReady",
+	SyncReady:        "SyncReady",
+	Synced:           "Synced",
+	SyncingCheck:     "SyncingCheck",
+}
+
+func (state ServerState) String() string {
+	if name, ok := serverStateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("ServerState(%d)", int(state))
+}
+
+//state of the running server, -1 if no server is running
+var runningState int32 = -1
+
+//GetServerState return the state of the running server, false if vbft is not running
+func GetServerState() (ServerState, bool) {
+	state := atomic.LoadInt32(&runningState)
+	return ServerState(state), state >= 0
+}
+
 func isReady(state ServerState) bool {
 	return state >= SyncReady
 }
@@ -83,7 +112,7 @@ type StateMgr struct {
 	StateEventC      chan *StateEvent
 	peers            map[uint32]*PeerState
 
-	liveTicker             *time.Timer
+	liveTicker             Timer
 	lastTickChainHeight    uint32
 	lastBlockSyncReqHeight uint32
 }
@@ -102,8 +131,14 @@ func (self *StateMgr) getState() ServerState {
 	return self.currentState
 }
 
+func (self *StateMgr) setState(state ServerState) {
+	self.currentState = state
+	atomic.StoreInt32(&runningState, int32(state))
+}
+
 func (self *StateMgr) run() {
-	self.liveTicker = time.AfterFunc(peerHandshakeTimeout*5, func() {
+	atomic.StoreInt32(&runningState, int32(self.currentState))
+	self.liveTicker = self.server.clock.AfterFunc(peerHandshakeTimeout*5, func() {
 		self.StateEventC <- &StateEvent{
 			Type:     LiveTick,
 			blockNum: self.server.GetCommittedBlockNo(),
@@ -121,11 +156,11 @@ func (self *StateMgr) run() {
 			switch evt.Type {
 			case ConfigLoaded:
 				if self.currentState == Init {
-					self.currentState = LocalConfigured
+					self.setState(LocalConfigured)
 				}
 			case SyncReadyTimeout:
 				if self.currentState == SyncReady {
-					self.currentState = Synced
+					self.setState(Synced)
 					if evt.blockNum == self.server.GetCurrentBlockNo() {
 						self.server.startNewRound()
 					}
@@ -138,12 +173,12 @@ func (self *StateMgr) run() {
 					v := self.getSyncedChainConfigView()
 					if v == self.server.config.View && self.currentState < Syncing {
 						log.Infof("server %d, start syncing", self.server.Index)
-						self.currentState = Syncing
+						self.setState(Syncing)
 					} else if v > self.server.config.View {
 						// update ChainConfig
 						log.Errorf("todo: chain config changed, need update chain config from peers")
 						// TODO: fetch config from neighbours, update chain config
-						self.currentState = LocalConfigured
+						self.setState(LocalConfigured)
 					}
 				}
 			case UpdatePeerState:
@@ -171,6 +206,7 @@ func (self *StateMgr) run() {
 
 		case <-self.server.quitC:
 			log.Infof("server %d, state mgr quit", self.server.Index)
+			atomic.StoreInt32(&runningState, -1)
 			return
 		}
 	}
@@ -207,7 +243,7 @@ func (self *StateMgr) onPeerUpdate(peerState *PeerState) error {
 			self.server.Index, self.currentState, peerIdx, len(self.peers), v, self.server.config.View)
 
 		if v == self.server.config.View {
-			self.currentState = Syncing
+			self.setState(Syncing)
 		}
 	case Configured:
 	case Syncing:
@@ -273,7 +309,7 @@ func (self *StateMgr) onPeerDisconnected(peerIdx uint32) error {
 	// start another connection if necessary
 	if self.currentState == Synced || self.currentState == SyncingCheck {
 		if self.server.peerPool.getActivePeerCount() < self.getMinActivePeerCount() {
-			self.currentState = WaitNetworkReady
+			self.setState(WaitNetworkReady)
 		}
 	}
 
@@ -363,11 +399,11 @@ func (self *StateMgr) isSyncedReady() bool {
 
 func (self *StateMgr) setSyncedReady() error {
 	prevState := self.currentState
-	self.currentState = SyncReady
+	self.setState(SyncReady)
 	if prevState <= SyncReady {
 		log.Infof("server %d start sync ready", self.server.Index)
 		blkNum := self.server.GetCurrentBlockNo()
-		time.AfterFunc(self.syncReadyTimeout, func() {
+		self.server.clock.AfterFunc(self.syncReadyTimeout, func() {
 			self.StateEventC <- &StateEvent{
 				Type:     SyncReadyTimeout,
 				blockNum: blkNum,
@@ -405,7 +441,7 @@ func (self *StateMgr) checkStartSyncing(startBlkNum uint32, forceSync bool) erro
 	}
 
 	if maxCommitted > startBlkNum || forceSync {
-		self.currentState = Syncing
+		self.setState(Syncing)
 		startBlkNum = self.server.GetCommittedBlockNo() + 1
 
 		if maxCommitted > self.server.syncer.getCurrentTargetBlockNum() {
@@ -420,7 +456,7 @@ func (self *StateMgr) checkStartSyncing(startBlkNum uint32, forceSync bool) erro
 		}
 	} else if self.currentState == Synced {
 		log.Infof("server %d, start syncing check %v, %d", self.server.Index, peers, self.server.GetCurrentBlockNo())
-		self.currentState = SyncingCheck
+		self.setState(SyncingCheck)
 	}
 
 	return nil
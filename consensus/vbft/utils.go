@@ -25,7 +25,6 @@ import (
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/vrf"
-	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/consensus/vbft/config"
@@ -38,14 +37,14 @@ import (
 	nutils "github.com/polynetwork/poly/native/service/utils"
 )
 
-func SignMsg(account *account.Account, msg ConsensusMsg) ([]byte, error) {
+func SignMsg(signer signature.Signer, msg ConsensusMsg) ([]byte, error) {
 
 	data, err := msg.Serialize()
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal msg when signing: %s", err)
 	}
 
-	return signature.Sign(account, data)
+	return signature.Sign(signer, data)
 }
 
 func hashData(data []byte) common.Uint256 {
@@ -95,7 +94,7 @@ type vrfData struct {
 	PrevVrf  []byte `json:"prev_vrf"`
 }
 
-func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
+func computeVrf(signer signature.VrfSigner, blkNum uint32, prevVrf []byte) ([]byte, []byte, error) {
 	data, err := json.Marshal(&vrfData{
 		BlockNum: blkNum,
 		PrevVrf:  prevVrf,
@@ -104,7 +103,7 @@ func computeVrf(sk keypair.PrivateKey, blkNum uint32, prevVrf []byte) ([]byte, [
 		return nil, nil, fmt.Errorf("computeVrf failed to marshal vrfData: %s", err)
 	}
 
-	return vrf.Vrf(sk, data)
+	return signer.Vrf(data)
 }
 
 func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byte) error {
@@ -125,8 +124,8 @@ func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byt
 	}
 	return nil
 }
-func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
-	data, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, []byte(node_manager.VBFT_CONFIG))
+func GetVbftConfigInfo(memdb *overlaydb.MemDB, backend *ledger.Ledger) (*config.VBFTConfig, error) {
+	data, err := GetStorageValue(memdb, backend, nutils.NodeManagerContractAddress, []byte(node_manager.VBFT_CONFIG))
 	if err != nil {
 		return nil, err
 	}
@@ -145,14 +144,14 @@ func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
 	return chainconfig, nil
 }
 
-func GetPeersConfig(memdb *overlaydb.MemDB) ([]*config.VBFTPeerInfo, error) {
-	goveranceview, err := GetGovernanceView(memdb)
+func GetPeersConfig(memdb *overlaydb.MemDB, backend *ledger.Ledger) ([]*config.VBFTPeerInfo, error) {
+	goveranceview, err := GetGovernanceView(memdb, backend)
 	if err != nil {
 		return nil, err
 	}
 	viewBytes := nutils.GetUint32Bytes(goveranceview.View)
 	key := append([]byte(node_manager.PEER_POOL), viewBytes...)
-	data, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, key)
+	data, err := GetStorageValue(memdb, backend, nutils.NodeManagerContractAddress, key)
 	if err != nil {
 		return nil, err
 	}
@@ -176,8 +175,95 @@ func GetPeersConfig(memdb *overlaydb.MemDB) ([]*config.VBFTPeerInfo, error) {
 	return peerstakes, nil
 }
 
-func isUpdate(memdb *overlaydb.MemDB, view uint32) (bool, error) {
-	goveranceview, err := GetGovernanceView(memdb)
+func getPeerPoolMap(memdb *overlaydb.MemDB, backend *ledger.Ledger, view uint32) (*node_manager.PeerPoolMap, error) {
+	viewBytes := nutils.GetUint32Bytes(view)
+	key := append([]byte(node_manager.PEER_POOL), viewBytes...)
+	data, err := GetStorageValue(memdb, backend, nutils.NodeManagerContractAddress, key)
+	if err != nil {
+		return nil, err
+	}
+	peerMap := &node_manager.PeerPoolMap{
+		PeerPoolMap: make(map[string]*node_manager.PeerPoolItem),
+	}
+	err = peerMap.Deserialization(common.NewZeroCopySource(data))
+	if err != nil {
+		return nil, err
+	}
+	return peerMap, nil
+}
+
+func getPerformancePolicy(memdb *overlaydb.MemDB, backend *ledger.Ledger) (*node_manager.PerformancePolicy, error) {
+	data, err := GetStorageValue(memdb, backend, nutils.NodeManagerContractAddress, []byte(node_manager.PERFORMANCE_POLICY))
+	if err == scommon.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policy := new(node_manager.PerformancePolicy)
+	err = policy.Deserialization(common.NewZeroCopySource(data))
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func getPeerPerformanceMap(memdb *overlaydb.MemDB, backend *ledger.Ledger, view uint32) (*node_manager.PeerPerformanceMap, error) {
+	performanceMap := &node_manager.PeerPerformanceMap{
+		PeerPerformanceMap: make(map[string]*node_manager.PeerPerformance),
+	}
+	viewBytes := nutils.GetUint32Bytes(view)
+	key := append([]byte(node_manager.PEER_PERFORMANCE), viewBytes...)
+	data, err := GetStorageValue(memdb, backend, nutils.NodeManagerContractAddress, key)
+	if err == scommon.ErrNotFound {
+		return performanceMap, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = performanceMap.Deserialization(common.NewZeroCopySource(data))
+	if err != nil {
+		return nil, err
+	}
+	return performanceMap, nil
+}
+
+// getDemotedPeers evaluates peer performance of current view the same way as node_manager commitDpos
+func getDemotedPeers(memdb *overlaydb.MemDB, backend *ledger.Ledger, performances []*node_manager.PeerPerformance) (map[string]bool, error) {
+	policy, err := getPerformancePolicy(memdb, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get performance policy: %s", err)
+	}
+	if policy == nil || policy.OfflineEpochs == 0 {
+		return nil, nil
+	}
+	goveranceview, err := GetGovernanceView(memdb, backend)
+	if err != nil {
+		return nil, err
+	}
+	last := &node_manager.PeerPerformanceMap{
+		PeerPerformanceMap: make(map[string]*node_manager.PeerPerformance),
+	}
+	if goveranceview.View > 1 {
+		last, err = getPeerPerformanceMap(memdb, backend, goveranceview.View-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get peer performance of view %d: %s", goveranceview.View-1, err)
+		}
+	}
+	peerMap, err := getPeerPoolMap(memdb, backend, goveranceview.View)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peer pool of view %d: %s", goveranceview.View, err)
+	}
+	_, peers := node_manager.EvaluatePeerPerformance(policy, last, performances, peerMap)
+	demoted := make(map[string]bool)
+	for _, peer := range peers {
+		demoted[peer] = true
+	}
+	return demoted, nil
+}
+
+func isUpdate(memdb *overlaydb.MemDB, backend *ledger.Ledger, view uint32) (bool, error) {
+	goveranceview, err := GetGovernanceView(memdb, backend)
 	if err != nil {
 		return false, err
 	}
@@ -211,8 +297,8 @@ func GetStorageValue(memdb *overlaydb.MemDB, backend *ledger.Ledger, addr common
 	return
 }
 
-func GetGovernanceView(memdb *overlaydb.MemDB) (*node_manager.GovernanceView, error) {
-	value, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW))
+func GetGovernanceView(memdb *overlaydb.MemDB, backend *ledger.Ledger) (*node_manager.GovernanceView, error) {
+	value, err := GetStorageValue(memdb, backend, nutils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW))
 	if err != nil {
 		return nil, err
 	}
@@ -224,17 +310,23 @@ func GetGovernanceView(memdb *overlaydb.MemDB) (*node_manager.GovernanceView, er
 	return governanceView, nil
 }
 
-func getChainConfig(memdb *overlaydb.MemDB, blkNum uint32) (*vconfig.ChainConfig, error) {
-	config, err := GetVbftConfigInfo(memdb)
+func getChainConfig(memdb *overlaydb.MemDB, backend *ledger.Ledger, blkNum uint32, demoted map[string]bool) (*vconfig.ChainConfig, error) {
+	config, err := GetVbftConfigInfo(memdb, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chainconfig from leveldb: %s", err)
 	}
 
-	peersinfo, err := GetPeersConfig(memdb)
+	peers, err := GetPeersConfig(memdb, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get peersinfo from leveldb: %s", err)
 	}
-	goverview, err := GetGovernanceView(memdb)
+	peersinfo := peers[:0]
+	for _, peer := range peers {
+		if !demoted[peer.PeerPubkey] {
+			peersinfo = append(peersinfo, peer)
+		}
+	}
+	goverview, err := GetGovernanceView(memdb, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get governanceview failed:%s", err)
 	}
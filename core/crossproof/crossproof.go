@@ -0,0 +1,191 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package crossproof verifies the self contained bundles poly serves for its cross chain messages,
+// following the checks the cross chain manager contract of a destination chain makes
+package crossproof
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+)
+
+// Bundle proves a ToMerkleValue made at some poly height
+type Bundle struct {
+	Value     []byte // serialized ToMerkleValue
+	AuditPath []byte // merkle path of Value to the cross state root of Header
+	Header    []byte // signed header of the next block, which carries the cross state root
+	// signed config headers after the trusted epoch up to the epoch signing Header, in height order
+	EpochHeaders [][]byte
+}
+
+type bundleJSON struct {
+	Value        string   `json:"value"`
+	AuditPath    string   `json:"auditPath"`
+	Header       string   `json:"header"`
+	EpochHeaders []string `json:"epochHeaders"`
+}
+
+// MarshalJSON encodes the bundle fields as hex strings
+func (this *Bundle) MarshalJSON() ([]byte, error) {
+	epochHeaders := make([]string, len(this.EpochHeaders))
+	for i, h := range this.EpochHeaders {
+		epochHeaders[i] = hex.EncodeToString(h)
+	}
+	return json.Marshal(&bundleJSON{
+		Value:        hex.EncodeToString(this.Value),
+		AuditPath:    hex.EncodeToString(this.AuditPath),
+		Header:       hex.EncodeToString(this.Header),
+		EpochHeaders: epochHeaders,
+	})
+}
+
+// UnmarshalJSON decodes a bundle returned by the getcrossstatesbundle rpc
+func (this *Bundle) UnmarshalJSON(data []byte) error {
+	var aux bundleJSON
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	if this.Value, err = hex.DecodeString(aux.Value); err != nil {
+		return fmt.Errorf("Bundle, decode value error: %v", err)
+	}
+	if this.AuditPath, err = hex.DecodeString(aux.AuditPath); err != nil {
+		return fmt.Errorf("Bundle, decode audit path error: %v", err)
+	}
+	if this.Header, err = hex.DecodeString(aux.Header); err != nil {
+		return fmt.Errorf("Bundle, decode header error: %v", err)
+	}
+	this.EpochHeaders = make([][]byte, len(aux.EpochHeaders))
+	for i, h := range aux.EpochHeaders {
+		if this.EpochHeaders[i], err = hex.DecodeString(h); err != nil {
+			return fmt.Errorf("Bundle, decode epoch header %d error: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Epoch is the consensus set trusted to sign the headers above StartHeight
+type Epoch struct {
+	StartHeight uint32
+	Keepers     []keypair.PublicKey
+}
+
+// EpochFromHeader returns the epoch started by a config header, the header itself is not verified
+func EpochFromHeader(rawHeader []byte) (*Epoch, error) {
+	header, err := types.HeaderFromRawBytes(rawHeader)
+	if err != nil {
+		return nil, fmt.Errorf("EpochFromHeader, deserialize header error: %v", err)
+	}
+	return epochFromHeader(header)
+}
+
+func epochFromHeader(header *types.Header) (*Epoch, error) {
+	blkInfo, err := vconfig.VbftBlock(header)
+	if err != nil {
+		return nil, fmt.Errorf("epochFromHeader, unmarshal block info of height %d error: %v", header.Height, err)
+	}
+	if blkInfo.NewChainConfig == nil {
+		return nil, fmt.Errorf("epochFromHeader, header of height %d is not a config header", header.Height)
+	}
+	keepers := make([]keypair.PublicKey, 0, len(blkInfo.NewChainConfig.Peers))
+	for _, p := range blkInfo.NewChainConfig.Peers {
+		pk, err := vconfig.Pubkey(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("epochFromHeader, parse peer %s error: %v", p.ID, err)
+		}
+		keepers = append(keepers, pk)
+	}
+	return &Epoch{StartHeight: header.Height, Keepers: keepers}, nil
+}
+
+// VerifyHeader checks header is signed by a quorum of the epoch keepers
+func (this *Epoch) VerifyHeader(header *types.Header) error {
+	if header.Height <= this.StartHeight {
+		return fmt.Errorf("VerifyHeader, header height %d is not above epoch start height %d", header.Height, this.StartHeight)
+	}
+	keepers := make(map[string]bool, len(this.Keepers))
+	for _, k := range this.Keepers {
+		keepers[vconfig.PubkeyID(k)] = true
+	}
+	m := len(keepers) - (len(keepers)-1)/3
+	if len(header.Bookkeepers) < m {
+		return fmt.Errorf("VerifyHeader, header of height %d has %d bookkeepers, need %d", header.Height, len(header.Bookkeepers), m)
+	}
+	used := make(map[string]bool, len(header.Bookkeepers))
+	for _, bookkeeper := range header.Bookkeepers {
+		id := vconfig.PubkeyID(bookkeeper)
+		if !keepers[id] || used[id] {
+			return fmt.Errorf("VerifyHeader, invalid bookkeeper %s of height %d", id, header.Height)
+		}
+		used[id] = true
+	}
+	hash := header.Hash()
+	if err := signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData); err != nil {
+		return fmt.Errorf("VerifyHeader, VerifyMultiSignature of height %d error: %v", header.Height, err)
+	}
+	return nil
+}
+
+// Verify checks the bundle end to end against the trusted epoch: it walks the epoch headers,
+// verifies the signed header and proves the value to its cross state root.
+// It returns the proved ToMerkleValue and the epoch signing the header, which callers may trust from then on.
+func Verify(bundle *Bundle, trusted *Epoch) (*scom.ToMerkleValue, *Epoch, error) {
+	epoch := trusted
+	for i, raw := range bundle.EpochHeaders {
+		header, err := types.HeaderFromRawBytes(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Verify, deserialize epoch header %d error: %v", i, err)
+		}
+		if err = epoch.VerifyHeader(header); err != nil {
+			return nil, nil, fmt.Errorf("Verify, epoch header %d: %v", i, err)
+		}
+		if epoch, err = epochFromHeader(header); err != nil {
+			return nil, nil, fmt.Errorf("Verify, epoch header %d: %v", i, err)
+		}
+	}
+
+	header, err := types.HeaderFromRawBytes(bundle.Header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Verify, deserialize header error: %v", err)
+	}
+	if err = epoch.VerifyHeader(header); err != nil {
+		return nil, nil, fmt.Errorf("Verify, %v", err)
+	}
+	value, err := merkle.MerkleProve(bundle.AuditPath, header.CrossStateRoot[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("Verify, merkle.MerkleProve error: %v", err)
+	}
+	if !bytes.Equal(value, bundle.Value) {
+		return nil, nil, fmt.Errorf("Verify, proved value %x is not the bundle value %x", value, bundle.Value)
+	}
+	merkleValue := new(scom.ToMerkleValue)
+	if err = merkleValue.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, nil, fmt.Errorf("Verify, deserialize ToMerkleValue error: %v", err)
+	}
+	return merkleValue, epoch, nil
+}
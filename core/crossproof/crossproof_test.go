@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package crossproof
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAccounts(n int) []*account.Account {
+	accs := make([]*account.Account, n)
+	for i := range accs {
+		accs[i] = account.NewAccount("")
+	}
+	return accs
+}
+
+func newHeader(t *testing.T, height uint32, crossStateRoot common.Uint256, config []*account.Account, signers []*account.Account) *types.Header {
+	blkInfo := &vconfig.VbftBlockInfo{}
+	if config != nil {
+		blkInfo.NewChainConfig = &vconfig.ChainConfig{N: uint32(len(config))}
+		for i, acc := range config {
+			blkInfo.NewChainConfig.Peers = append(blkInfo.NewChainConfig.Peers,
+				&vconfig.PeerConfig{Index: uint32(i), ID: vconfig.PubkeyID(acc.PublicKey)})
+		}
+	}
+	payload, err := json.Marshal(blkInfo)
+	assert.Nil(t, err)
+	header := &types.Header{
+		Height:           height,
+		CrossStateRoot:   crossStateRoot,
+		ConsensusPayload: payload,
+	}
+	hash := header.Hash()
+	for _, acc := range signers {
+		sig, err := signature.Sign(acc, hash[:])
+		assert.Nil(t, err)
+		header.Bookkeepers = append(header.Bookkeepers, acc.PublicKey)
+		header.SigData = append(header.SigData, sig)
+	}
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	epoch0, epoch1 := newAccounts(4), newAccounts(4)
+	genesis := newHeader(t, 0, common.UINT256_EMPTY, epoch0, nil)
+	configHeader := newHeader(t, 10, common.UINT256_EMPTY, epoch1, epoch0[:3])
+
+	sink := common.NewZeroCopySink(nil)
+	(&scom.ToMerkleValue{
+		TxHash:      []byte{1},
+		FromChainID: 2,
+		MakeTxParam: &scom.MakeTxParam{TxHash: []byte{2}, CrossChainID: []byte{3}, ToChainID: 3, Method: "unlock"},
+	}).Serialization(sink)
+	value := sink.Bytes()
+	hashes := []common.Uint256{merkle.HashLeaf([]byte("other")), merkle.HashLeaf(value), merkle.HashLeaf([]byte("another"))}
+	path, err := merkle.MerkleLeafPath(value, hashes)
+	assert.Nil(t, err)
+	header := newHeader(t, 20, merkle.TreeHasher{}.HashFullTreeWithLeafHash(hashes), nil, epoch1[1:])
+
+	trusted, err := EpochFromHeader(genesis.ToArray())
+	assert.Nil(t, err)
+	bundle := &Bundle{
+		Value:        value,
+		AuditPath:    path,
+		Header:       header.ToArray(),
+		EpochHeaders: [][]byte{configHeader.ToArray()},
+	}
+	raw, err := json.Marshal(bundle)
+	assert.Nil(t, err)
+	decoded := new(Bundle)
+	assert.Nil(t, json.Unmarshal(raw, decoded))
+	assert.Equal(t, bundle, decoded)
+
+	merkleValue, epoch, err := Verify(decoded, trusted)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), merkleValue.FromChainID)
+	assert.Equal(t, "unlock", merkleValue.MakeTxParam.Method)
+	assert.Equal(t, uint32(10), epoch.StartHeight)
+	assert.Equal(t, []keypair.PublicKey{epoch1[0].PublicKey, epoch1[1].PublicKey, epoch1[2].PublicKey, epoch1[3].PublicKey}, epoch.Keepers)
+
+	// the header is signed by an epoch the destination chain does not know yet
+	_, _, err = Verify(&Bundle{Value: value, AuditPath: path, Header: header.ToArray()}, trusted)
+	assert.NotNil(t, err)
+
+	// too few signers
+	bundle.Header = newHeader(t, 20, header.CrossStateRoot, nil, epoch1[2:]).ToArray()
+	_, _, err = Verify(bundle, trusted)
+	assert.NotNil(t, err)
+
+	// value not in the cross states of the header
+	bundle.Header = header.ToArray()
+	bundle.Value = []byte("other")
+	_, _, err = Verify(bundle, trusted)
+	assert.NotNil(t, err)
+}
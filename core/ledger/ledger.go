@@ -26,10 +26,12 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/store/ledgerstore"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native/event"
 	cstate "github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/native/trace"
 )
 
 var DefLedger *Ledger
@@ -181,6 +183,14 @@ func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecRe
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) SimulateTransaction(tx *types.Transaction, height uint32) (*trace.Result, error) {
+	return self.ldgStore.SimulateTransaction(tx, height)
+}
+
+func (self *Ledger) GetStoreError() error {
+	return self.ldgStore.GetStoreError()
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
@@ -189,6 +199,14 @@ func (self *Ledger) GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify
 	return self.ldgStore.GetEventNotifyByBlock(height)
 }
 
+func (self *Ledger) GetEventLogs(filter *scom.EventLogFilter) ([]*scom.EventLog, []byte, error) {
+	return self.ldgStore.GetEventLogs(filter)
+}
+
+func (self *Ledger) GetEventLogIndexGap() *scom.HeightRange {
+	return self.ldgStore.GetEventLogIndexGap()
+}
+
 func (self *Ledger) Close() error {
 	return self.ldgStore.Close()
 }
@@ -25,14 +25,9 @@ import (
 	s "github.com/ontio/ontology-crypto/signature"
 )
 
-// Sign returns the signature of data using privKey
+// Sign returns the signature of data using signer
 func Sign(signer Signer, data []byte) ([]byte, error) {
-	signature, err := s.Sign(signer.Scheme(), signer.PrivKey(), data, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return s.Serialize(signature)
+	return signer.Sign(data)
 }
 
 // Verify check the signature of data using pubKey
@@ -24,12 +24,26 @@ import (
 )
 
 // Signer is the abstract interface of user's information(Keys) for signing data.
+// The private key may live in process (account.Account) or behind an external
+// signing daemon (account.RemoteSigner).
+//
+// Signer has no PrivKey method since a remote signer never exposes its key,
+// callers requiring the private key should take *account.Account instead.
 type Signer interface {
-	//get signer's private key
-	PrivKey() keypair.PrivateKey
-
 	//get signer's public key
 	PubKey() keypair.PublicKey
 
 	Scheme() signature.SignatureScheme
+
+	// sign data and return the serialized signature
+	Sign(data []byte) ([]byte, error)
+}
+
+// VrfSigner is a Signer which is also able to compute vrf value and proof,
+// required by vbft block proposers.
+type VrfSigner interface {
+	Signer
+
+	// compute vrf value and proof of data
+	Vrf(data []byte) ([]byte, []byte, error)
 }
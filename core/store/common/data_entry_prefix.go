@@ -27,6 +27,7 @@ const (
 	DATA_HEADER                            = 0x01 //Block hash => block hash key prefix
 	DATA_TRANSACTION                       = 0x02 //Transction hash = > transaction key prefix
 	DATA_STATE_MERKLE_ROOT                 = 0x21 // block height => write set hash + state merkle root
+	DATA_STATE_UNDO                        = 0x24 // block height => values of the write set keys before the block
 
 	// Transaction
 	ST_BOOKKEEPER DataEntryPrefix = 0x03 //BookKeeper state key prefix
@@ -45,6 +46,8 @@ const (
 	SYS_STATE_MERKLE_TREE  DataEntryPrefix = 0x20 // state merkle tree root key prefix
 	SYS_CROSS_STATES       DataEntryPrefix = 0x22
 	SYS_CROSS_STATES_HASH  DataEntryPrefix = 0x23
+	SYS_EVENT_LOG_INDEX    DataEntryPrefix = 0x16 //Height the event log index is built to
 
 	EVENT_NOTIFY DataEntryPrefix = 0x14 //Event notify key prefix
+	IX_EVENT_LOG DataEntryPrefix = 0x15 //Event log index key prefix
 )
@@ -27,6 +27,8 @@ import (
 
 var ErrNotFound = errors.New("not found")
 
+var ErrReadOnly = errors.New("store is read only")
+
 //Store iterator for iterate store
 type StoreIterator interface {
 	Next() bool //Next item. If item available return true, otherwise return false
@@ -84,8 +86,8 @@ type MemoryCacheStore interface {
 
 //EventStore save event notify
 type EventStore interface {
-	//SaveEventNotifyByTx save event notify gen by smart contract execution
-	SaveEventNotifyByTx(txHash common.Uint256, notify *event.ExecuteNotify) error
+	//SaveEventNotifyByTx save event notify gen by smart contract execution in block of height
+	SaveEventNotifyByTx(height uint32, txHash common.Uint256, notify *event.ExecuteNotify) error
 	//Save transaction hashes which have event notify gen
 	SaveEventNotifyByBlock(height uint32, txHashs []common.Uint256) error
 	//GetEventNotifyByTx return event notify by transaction hash
@@ -116,3 +118,29 @@ func (e *StateItem) Copy() *StateItem {
 	c := *e
 	return &c
 }
+
+//EventLogFilter select the event logs returned by the event log index
+type EventLogFilter struct {
+	FromHeight  uint32          //First block height, inclusive
+	ToHeight    uint32          //Last block height, inclusive
+	Contract    *common.Address //Contract address which emitted the event, nil for any
+	Name        string          //First state of the event, empty for any
+	FromChainID *uint64         //Chain id in the second state of the event, nil for any
+	ToChainID   *uint64         //Chain id in the third state of the event, nil for any
+	Limit       int             //Max number of logs returned
+	Cursor      []byte          //Position returned by the previous page, nil for the first page
+}
+
+//HeightRange is the block heights from From to To, inclusive
+type HeightRange struct {
+	From uint32
+	To   uint32
+}
+
+//EventLog is an event notify located by the event log index
+type EventLog struct {
+	Height uint32
+	TxHash common.Uint256
+	Index  uint32 //Index of the notify in the transaction
+	Notify *event.NotifyEventInfo
+}
@@ -29,12 +29,33 @@ import (
 	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/native/event"
+	"github.com/syndtr/goleveldb/leveldb"
+	"math"
+	"sync"
+)
+
+const (
+	eventLogByHeight    byte = 'h' //Index of all event logs
+	eventLogByContract  byte = 'c' //Index by contract address
+	eventLogByName      byte = 'n' //Index by first state of the event
+	eventLogByFromChain byte = 'f' //Index by chain id in the second state of the event
+	eventLogByToChain   byte = 't' //Index by chain id in the third state of the event
+
+	eventLogPosLen       = 4 + common.UINT256_SIZE + 4 //Height, transaction hash and notify index
+	defaultEventLogLimit = 100
+	maxEventLogLimit     = 1000
+	maxEventLogScan      = 10000 //Max index entries scanned by one GetEventLogs
+	eventLogRebuildBatch = 1000  //Blocks indexed per commit when rebuilding the index
 )
 
 //Saving event notifies gen by smart contract execution
 type EventStore struct {
-	dbDir string                     //Store path
-	store *leveldbstore.LevelDBStore //Store handler
+	dbDir     string                     //Store path
+	store     *leveldbstore.LevelDBStore //Store handler
+	indexGap  *scom.HeightRange          //Heights not indexed yet while the event log index is rebuilt
+	gapLock   sync.RWMutex
+	closing   chan struct{}
+	rebuildWg sync.WaitGroup
 }
 
 //NewEventStore return event store instance
@@ -44,8 +65,9 @@ func NewEventStore(dbDir string) (*EventStore, error) {
 		return nil, err
 	}
 	return &EventStore{
-		dbDir: dbDir,
-		store: store,
+		dbDir:   dbDir,
+		store:   store,
+		closing: make(chan struct{}),
 	}, nil
 }
 
@@ -54,14 +76,17 @@ func (this *EventStore) NewBatch() {
 	this.store.NewBatch()
 }
 
-//SaveEventNotifyByTx persist event notify by transaction hash
-func (this *EventStore) SaveEventNotifyByTx(txHash common.Uint256, notify *event.ExecuteNotify) error {
+//SaveEventNotifyByTx persist event notify by transaction hash, and index its event logs
+func (this *EventStore) SaveEventNotifyByTx(height uint32, txHash common.Uint256, notify *event.ExecuteNotify) error {
 	result, err := json.Marshal(notify)
 	if err != nil {
 		return fmt.Errorf("json.Marshal error %s", err)
 	}
 	key := this.getEventNotifyByTxKey(txHash)
 	this.store.BatchPut(key, result)
+	for _, key := range getEventLogIndexKeys(height, txHash, notify) {
+		this.store.BatchPut(key, []byte{})
+	}
 	return nil
 }
 
@@ -139,8 +164,10 @@ func (this *EventStore) CommitTo() error {
 	return this.store.BatchCommit()
 }
 
-//Close event store
+//Close event store, stopping the rebuilding of event log index
 func (this *EventStore) Close() error {
+	close(this.closing)
+	this.rebuildWg.Wait()
 	return this.store.Close()
 }
 
@@ -165,6 +192,9 @@ func (this *EventStore) SaveCurrentBlock(height uint32, blockHash common.Uint256
 	blockHash.Serialize(value)
 	serialization.WriteUint32(value, height)
 	this.store.BatchPut(key, value.Bytes())
+	if this.GetEventLogIndexGap() == nil {
+		this.store.BatchPut(getEventLogIndexHeightKey(), heightBytes(height))
+	}
 
 	return nil
 }
@@ -189,6 +219,213 @@ func (this *EventStore) GetCurrentBlock() (common.Uint256, uint32, error) {
 	return blockHash, height, nil
 }
 
+//GetEventLogs return the event logs matching filter in height order, and the cursor of the next page.
+//A nil cursor means there are no more logs
+func (this *EventStore) GetEventLogs(filter *scom.EventLogFilter) ([]*scom.EventLog, []byte, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultEventLogLimit
+	} else if limit > maxEventLogLimit {
+		limit = maxEventLogLimit
+	}
+	logs := make([]*scom.EventLog, 0)
+	if filter.ToHeight < filter.FromHeight {
+		return logs, nil, nil
+	}
+	prefix := this.getEventLogFilterPrefix(filter)
+	start := append(append([]byte{}, prefix...), heightBytes(filter.FromHeight)...)
+	if filter.Cursor != nil {
+		if len(filter.Cursor) != eventLogPosLen {
+			return nil, nil, fmt.Errorf("invalid cursor length %d", len(filter.Cursor))
+		}
+		cursor := append(append(append([]byte{}, prefix...), filter.Cursor...), 0)
+		if bytes.Compare(cursor, start) > 0 {
+			start = cursor
+		}
+	}
+	var end []byte
+	if filter.ToHeight < math.MaxUint32 {
+		end = append(append([]byte{}, prefix...), heightBytes(filter.ToHeight+1)...)
+	}
+
+	iter := this.store.NewRangeIterator(start, end)
+	defer iter.Release()
+	notifies := make(map[common.Uint256]*event.ExecuteNotify)
+	scanned := 0
+	var next []byte
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, prefix) || len(key) != len(prefix)+eventLogPosLen {
+			break
+		}
+		pos := key[len(prefix):]
+		eventLog, err := this.getEventLog(pos, notifies)
+		if err != nil {
+			return nil, nil, err
+		}
+		if eventLog != nil && matchEventLog(filter, eventLog.Notify) {
+			logs = append(logs, eventLog)
+		}
+		scanned++
+		if len(logs) == limit || scanned == maxEventLogScan {
+			next = append([]byte{}, pos...)
+			break
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, nil, err
+	}
+	return logs, next, nil
+}
+
+//RebuildEventLogIndex start indexing the event notifies saved before the event log index existed in background,
+//up to the current block of event store. The blocks saved meanwhile are indexed as usual
+func (this *EventStore) RebuildEventLogIndex() error {
+	_, current, err := this.GetCurrentBlock()
+	if err == scom.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("GetCurrentBlock error %s", err)
+	}
+	start := uint32(0)
+	built, err := this.getEventLogIndexHeight()
+	if err == nil {
+		if built >= current {
+			return nil
+		}
+		start = built + 1
+	} else if err != scom.ErrNotFound {
+		return fmt.Errorf("getEventLogIndexHeight error %s", err)
+	}
+	this.gapLock.Lock()
+	this.indexGap = &scom.HeightRange{From: start, To: current}
+	this.gapLock.Unlock()
+	this.rebuildWg.Add(1)
+	go func() {
+		defer this.rebuildWg.Done()
+		if err := this.rebuildEventLogIndex(start, current); err != nil {
+			log.Errorf("RebuildEventLogIndex error %s", err)
+		}
+	}()
+	return nil
+}
+
+//GetEventLogIndexGap return the heights whose event logs are not indexed yet, nil if the index is complete
+func (this *EventStore) GetEventLogIndexGap() *scom.HeightRange {
+	this.gapLock.RLock()
+	defer this.gapLock.RUnlock()
+	if this.indexGap == nil {
+		return nil
+	}
+	gap := *this.indexGap
+	return &gap
+}
+
+func (this *EventStore) rebuildEventLogIndex(start, end uint32) error {
+	log.Infof("RebuildEventLogIndex from height %d to %d", start, end)
+	batch := new(leveldb.Batch)
+	for height := start; height <= end; height++ {
+		notifies, err := this.GetEventNotifyByBlock(height)
+		if err != nil && err != scom.ErrNotFound {
+			return fmt.Errorf("GetEventNotifyByBlock height:%d error %s", height, err)
+		}
+		for _, notify := range notifies {
+			for _, key := range getEventLogIndexKeys(height, notify.TxHash, notify) {
+				batch.Put(key, []byte{})
+			}
+		}
+		if (height-start+1)%eventLogRebuildBatch != 0 && height != end {
+			continue
+		}
+		batch.Put(getEventLogIndexHeightKey(), heightBytes(height))
+		if err := this.store.BatchWrite(batch); err != nil {
+			return fmt.Errorf("BatchWrite height:%d error %s", height, err)
+		}
+		batch.Reset()
+		log.Infof("RebuildEventLogIndex height %d/%d", height, end)
+		this.gapLock.Lock()
+		if height == end {
+			this.indexGap = nil
+		} else {
+			this.indexGap.From = height + 1
+		}
+		this.gapLock.Unlock()
+		select {
+		case <-this.closing:
+			return nil
+		default:
+		}
+	}
+	return nil
+}
+
+func getEventLogIndexKeys(height uint32, txHash common.Uint256, notify *event.ExecuteNotify) [][]byte {
+	keys := make([][]byte, 0)
+	for i, n := range notify.Notify {
+		pos := getEventLogPos(height, txHash, uint32(i))
+		for _, prefix := range getEventLogIndexPrefixes(n) {
+			keys = append(keys, append(prefix, pos...))
+		}
+	}
+	return keys
+}
+
+func getEventLogIndexHeightKey() []byte {
+	return []byte{byte(scom.SYS_EVENT_LOG_INDEX)}
+}
+
+func (this *EventStore) getEventLogIndexHeight() (uint32, error) {
+	data, err := this.store.Get(getEventLogIndexHeightKey())
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 4 {
+		return 0, fmt.Errorf("invalid event log index height length %d", len(data))
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func (this *EventStore) getEventLog(pos []byte, notifies map[common.Uint256]*event.ExecuteNotify) (*scom.EventLog, error) {
+	height := binary.BigEndian.Uint32(pos[:4])
+	var txHash common.Uint256
+	copy(txHash[:], pos[4:4+common.UINT256_SIZE])
+	index := binary.BigEndian.Uint32(pos[4+common.UINT256_SIZE:])
+	notify, ok := notifies[txHash]
+	if !ok {
+		var err error
+		notify, err = this.GetEventNotifyByTx(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("GetEventNotifyByTx %s error %s", txHash.ToHexString(), err)
+		}
+		notifies[txHash] = notify
+	}
+	if int(index) >= len(notify.Notify) {
+		return nil, nil
+	}
+	return &scom.EventLog{
+		Height: height,
+		TxHash: txHash,
+		Index:  index,
+		Notify: notify.Notify[index],
+	}, nil
+}
+
+//getEventLogFilterPrefix pick the index which scan the event logs of filter
+func (this *EventStore) getEventLogFilterPrefix(filter *scom.EventLogFilter) []byte {
+	switch {
+	case filter.Name != "" && len(filter.Name) <= math.MaxUint8:
+		return getEventLogIndexPrefix(eventLogByName, getEventLogName(filter.Name))
+	case filter.FromChainID != nil:
+		return getEventLogIndexPrefix(eventLogByFromChain, chainIDBytes(*filter.FromChainID))
+	case filter.ToChainID != nil:
+		return getEventLogIndexPrefix(eventLogByToChain, chainIDBytes(*filter.ToChainID))
+	case filter.Contract != nil:
+		return getEventLogIndexPrefix(eventLogByContract, filter.Contract[:])
+	}
+	return getEventLogIndexPrefix(eventLogByHeight, nil)
+}
+
 func (this *EventStore) getCurrentBlockKey() []byte {
 	return []byte{byte(scom.SYS_CURRENT_BLOCK)}
 }
@@ -207,3 +444,101 @@ func (this *EventStore) getEventNotifyByTxKey(txHash common.Uint256) []byte {
 	copy(key[1:], data)
 	return key
 }
+
+func getEventLogIndexPrefixes(n *event.NotifyEventInfo) [][]byte {
+	prefixes := [][]byte{
+		getEventLogIndexPrefix(eventLogByHeight, nil),
+		getEventLogIndexPrefix(eventLogByContract, n.ContractAddress[:]),
+	}
+	states, _ := n.States.([]interface{})
+	if len(states) == 0 {
+		return prefixes
+	}
+	if name, ok := states[0].(string); ok && name != "" && len(name) <= math.MaxUint8 {
+		prefixes = append(prefixes, getEventLogIndexPrefix(eventLogByName, getEventLogName(name)))
+	}
+	if id, ok := stateUint64(states, 1); ok {
+		prefixes = append(prefixes, getEventLogIndexPrefix(eventLogByFromChain, chainIDBytes(id)))
+	}
+	if id, ok := stateUint64(states, 2); ok {
+		prefixes = append(prefixes, getEventLogIndexPrefix(eventLogByToChain, chainIDBytes(id)))
+	}
+	return prefixes
+}
+
+func getEventLogIndexPrefix(kind byte, field []byte) []byte {
+	key := make([]byte, 0, 2+len(field)+eventLogPosLen)
+	key = append(key, byte(scom.IX_EVENT_LOG), kind)
+	return append(key, field...)
+}
+
+func getEventLogName(name string) []byte {
+	return append([]byte{byte(len(name))}, name...)
+}
+
+func getEventLogPos(height uint32, txHash common.Uint256, index uint32) []byte {
+	pos := make([]byte, eventLogPosLen)
+	binary.BigEndian.PutUint32(pos, height)
+	copy(pos[4:], txHash[:])
+	binary.BigEndian.PutUint32(pos[4+common.UINT256_SIZE:], index)
+	return pos
+}
+
+func matchEventLog(filter *scom.EventLogFilter, n *event.NotifyEventInfo) bool {
+	if filter.Contract != nil && n.ContractAddress != *filter.Contract {
+		return false
+	}
+	states, _ := n.States.([]interface{})
+	if filter.Name != "" {
+		if len(states) == 0 {
+			return false
+		}
+		if name, ok := states[0].(string); !ok || name != filter.Name {
+			return false
+		}
+	}
+	if filter.FromChainID != nil {
+		if id, ok := stateUint64(states, 1); !ok || id != *filter.FromChainID {
+			return false
+		}
+	}
+	if filter.ToChainID != nil {
+		if id, ok := stateUint64(states, 2); !ok || id != *filter.ToChainID {
+			return false
+		}
+	}
+	return true
+}
+
+//stateUint64 return the chain id in states, which is a float64 once the notify is read back from store
+func stateUint64(states []interface{}, i int) (uint64, bool) {
+	if len(states) <= i {
+		return 0, false
+	}
+	switch v := states[i].(type) {
+	case float64:
+		if v < 0 || v != math.Trunc(v) || v >= math.MaxUint64 {
+			return 0, false
+		}
+		return uint64(v), true
+	case uint64:
+		return v, true
+	case uint32:
+		return uint64(v), true
+	case int:
+		return uint64(v), v >= 0
+	}
+	return 0, false
+}
+
+func heightBytes(height uint32) []byte {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, height)
+	return data
+}
+
+func chainIDBytes(id uint64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, id)
+	return data
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ledgerstore
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func saveTestEvents(t *testing.T, store *EventStore, index bool) common.Address {
+	contract := common.AddressFromVmCode([]byte("contract"))
+	other := common.AddressFromVmCode([]byte("other"))
+	store.NewBatch()
+	for height := uint32(1); height <= 10; height++ {
+		txHash := common.Uint256(sha256.Sum256([]byte{byte(height)}))
+		notify := &event.ExecuteNotify{
+			TxHash: txHash,
+			State:  event.CONTRACT_STATE_SUCCESS,
+			Notify: []*event.NotifyEventInfo{
+				{ContractAddress: contract, States: []interface{}{"makeProof", uint64(height % 2), uint64(2)}},
+				{ContractAddress: other, States: []interface{}{"other", uint64(3)}},
+			},
+		}
+		if index {
+			assert.Nil(t, store.SaveEventNotifyByTx(height, txHash, notify))
+		} else {
+			data, err := json.Marshal(notify)
+			assert.Nil(t, err)
+			store.store.BatchPut(store.getEventNotifyByTxKey(txHash), data)
+		}
+		assert.Nil(t, store.SaveEventNotifyByBlock(height, []common.Uint256{txHash}))
+		if index {
+			assert.Nil(t, store.SaveCurrentBlock(height, txHash))
+		} else {
+			key := store.getCurrentBlockKey()
+			value := append(txHash.ToArray(), 0, 0, 0, 0)
+			value[common.UINT256_SIZE] = byte(height)
+			store.store.BatchPut(key, value)
+		}
+	}
+	assert.Nil(t, store.CommitTo())
+	return contract
+}
+
+func TestGetEventLogs(t *testing.T) {
+	store, err := NewEventStore("test/event")
+	assert.Nil(t, err)
+	defer store.Close()
+	contract := saveTestEvents(t, store, true)
+
+	logs, cursor, err := store.GetEventLogs(&scom.EventLogFilter{FromHeight: 3, ToHeight: 6})
+	assert.Nil(t, err)
+	assert.Nil(t, cursor)
+	assert.Equal(t, 8, len(logs))
+	assert.Equal(t, uint32(3), logs[0].Height)
+	assert.Equal(t, uint32(6), logs[7].Height)
+
+	logs, _, err = store.GetEventLogs(&scom.EventLogFilter{ToHeight: 10, Contract: &contract})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(logs))
+
+	fromChainID := uint64(1)
+	filter := &scom.EventLogFilter{ToHeight: 10, Name: "makeProof", FromChainID: &fromChainID, Limit: 2}
+	logs, cursor, err = store.GetEventLogs(filter)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, []uint32{1, 3}, []uint32{logs[0].Height, logs[1].Height})
+	assert.NotNil(t, cursor)
+	filter.Cursor = cursor
+	filter.Limit = 10
+	logs, cursor, err = store.GetEventLogs(filter)
+	assert.Nil(t, err)
+	assert.Nil(t, cursor)
+	assert.Equal(t, []uint32{5, 7, 9}, []uint32{logs[0].Height, logs[1].Height, logs[2].Height})
+
+	toChainID := uint64(3)
+	logs, _, err = store.GetEventLogs(&scom.EventLogFilter{ToHeight: 10, ToChainID: &toChainID})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(logs))
+}
+
+func TestRebuildEventLogIndex(t *testing.T) {
+	store, err := NewEventStore("test/event_rebuild")
+	assert.Nil(t, err)
+	defer store.Close()
+	saveTestEvents(t, store, false)
+
+	logs, _, err := store.GetEventLogs(&scom.EventLogFilter{ToHeight: 10, Name: "other"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(logs))
+
+	assert.Nil(t, store.RebuildEventLogIndex())
+	store.rebuildWg.Wait()
+	assert.Nil(t, store.GetEventLogIndexGap())
+	logs, _, err = store.GetEventLogs(&scom.EventLogFilter{ToHeight: 10, Name: "other"})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, len(logs))
+	height, err := store.getEventLogIndexHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	// the index height is not moved by the blocks saved while rebuilding
+	store.indexGap = &scom.HeightRange{From: 5, To: 10}
+	store.NewBatch()
+	assert.Nil(t, store.SaveCurrentBlock(11, common.Uint256{}))
+	assert.Nil(t, store.CommitTo())
+	height, err = store.getEventLogIndexHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+	assert.Equal(t, &scom.HeightRange{From: 5, To: 10}, store.GetEventLogIndexGap())
+}
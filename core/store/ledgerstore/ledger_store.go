@@ -47,6 +47,7 @@ import (
 	cstates "github.com/polynetwork/poly/native/states"
 	sstate "github.com/polynetwork/poly/native/states"
 	"github.com/polynetwork/poly/native/storage"
+	"github.com/polynetwork/poly/native/trace"
 )
 
 const (
@@ -75,6 +76,7 @@ type LedgerStoreImp struct {
 	savingBlockSemaphore chan bool
 	vbftPeerInfoheader   map[string]uint32 //pubInfo save pubkey,peerindex
 	vbftPeerInfoblock    map[string]uint32 //pubInfo save pubkey,peerindex
+	storeErr             error             //error of last failed block commit
 	lock                 sync.RWMutex
 }
 
@@ -229,6 +231,12 @@ func (this *LedgerStoreImp) init() error {
 	if err != nil {
 		return fmt.Errorf("loadHeaderIndexList error %s", err)
 	}
+	if config.DefConfig.Common.EnableEventLog {
+		err = this.eventStore.RebuildEventLogIndex()
+		if err != nil {
+			return fmt.Errorf("RebuildEventLogIndex error %s", err)
+		}
+	}
 	err = this.recoverStore()
 	if err != nil {
 		return fmt.Errorf("recoverStore error %s", err)
@@ -659,7 +667,7 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 	blockHeight := block.Header.Height
 
 	for _, notify := range result.Notify {
-		err := SaveNotify(this.eventStore, notify.TxHash, notify)
+		err := SaveNotify(this.eventStore, blockHeight, notify.TxHash, notify)
 		if err != nil {
 			return fmt.Errorf("SaveNotify error %s", err)
 		}
@@ -687,6 +695,13 @@ func (this *LedgerStoreImp) saveBlockToStateStore(block *types.Block, result sto
 
 	log.Debugf("the state transition hash of block %d is:%s", blockHeight, result.Hash.ToHexString())
 
+	if keep := uint32(config.DefConfig.Common.StateUndoBlocks); keep > 0 {
+		err = this.stateStore.AddStateUndo(blockHeight, keep, result.WriteSet)
+		if err != nil {
+			return fmt.Errorf("AddStateUndo error %s", err)
+		}
+	}
+
 	result.WriteSet.ForEach(func(key, val []byte) {
 		if len(val) == 0 {
 			this.stateStore.BatchDeleteRawKey(key)
@@ -751,6 +766,26 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 			block.Header.Height, blockRoot.ToHexString(), block.Header.BlockRoot.ToHexString())
 	}
 
+	err := this.commitBlock(block, result)
+	this.setStoreError(err)
+	if err != nil {
+		return err
+	}
+	this.setCurrentBlock(blockHeight, blockHash)
+
+	if events.DefActorPublisher != nil {
+		events.DefActorPublisher.Publish(
+			message.TOPIC_SAVE_BLOCK_COMPLETE,
+			&message.SaveBlockCompleteMsg{
+				Block: block,
+			})
+	}
+	return nil
+}
+
+//commitBlock write block, state and event of block to their stores
+func (this *LedgerStoreImp) commitBlock(block *types.Block, result store.ExecuteResult) error {
+	blockHeight := block.Header.Height
 	this.blockStore.NewBatch()
 	this.stateStore.NewBatch()
 	this.eventStore.NewBatch()
@@ -779,18 +814,23 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 	if err != nil {
 		return fmt.Errorf("stateStore.CommitTo height:%d error %s", blockHeight, err)
 	}
-	this.setCurrentBlock(blockHeight, blockHash)
-
-	if events.DefActorPublisher != nil {
-		events.DefActorPublisher.Publish(
-			message.TOPIC_SAVE_BLOCK_COMPLETE,
-			&message.SaveBlockCompleteMsg{
-				Block: block,
-			})
-	}
 	return nil
 }
 
+//setStoreError record the error of last block commit, nil clear it
+func (this *LedgerStoreImp) setStoreError(err error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.storeErr = err
+}
+
+//GetStoreError return the error of last failed block commit, nil if last commit succeeded
+func (this *LedgerStoreImp) GetStoreError() error {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.storeErr
+}
+
 //saveBlock do the job of execution samrt contract and commit block to store.
 func (this *LedgerStoreImp) saveBlock(block *types.Block, stateMerkleRoot common.Uint256) error {
 	blockHeight := block.Header.Height
@@ -884,6 +924,82 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*cstates.
 	return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Result: common.ToHexString(res.([]byte)), Notify: service.GetNotify()}, nil
 }
 
+//SimulateTransaction execute transaction against the state of block at height without commit, tracing its execution.
+//height 0 is for current block, the states of the last Common.StateUndoBlocks blocks are restored from their undo records
+func (this *LedgerStoreImp) SimulateTransaction(tx *types.Transaction, height uint32) (*trace.Result, error) {
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil, fmt.Errorf("SimulateTransaction, transaction payload type error")
+	}
+	keep := uint32(config.DefConfig.Common.StateUndoBlocks)
+	if height != 0 && keep == 0 {
+		return nil, fmt.Errorf("SimulateTransaction, states of previous blocks are not kept, only height 0 is accepted")
+	}
+	//blocks saved during the simulation are not seen by the snapshot
+	snapshot, err := this.stateStore.NewSnapshot()
+	if err != nil {
+		return nil, fmt.Errorf("SimulateTransaction, snapshot state store error: %v", err)
+	}
+	defer snapshot.Close()
+	_, current, err := snapshot.GetCurrentBlock()
+	if err != nil {
+		return nil, fmt.Errorf("SimulateTransaction, get current block error: %v", err)
+	}
+	if height == 0 {
+		height = current
+	}
+	if height > current {
+		return nil, fmt.Errorf("SimulateTransaction, height %d is beyond current height %d", height, current)
+	}
+	if current-height > keep {
+		return nil, fmt.Errorf("SimulateTransaction, states of only the last %d blocks are kept, current height %d", keep, current)
+	}
+	hash := this.GetBlockHash(height)
+	block, err := this.GetBlockByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("SimulateTransaction, get block of height %d error: %v", height, err)
+	}
+	overlay, err := snapshot.NewHistoryOverlayDB(height, current)
+	if err != nil {
+		return nil, fmt.Errorf("SimulateTransaction, %v, current height %d", err, current)
+	}
+	tracer := trace.NewTracer()
+	cache := storage.NewCacheDB(overlay)
+	cache.SetTracer(tracer)
+	service, err := native.NewNativeService(cache, tx, block.Header.Timestamp, block.Header.Height,
+		hash, block.Header.ChainID, invoke.Code, true)
+	if err != nil {
+		return nil, fmt.Errorf("SimulateTransaction, NewNativeService error: %v", err)
+	}
+	service.SetTracer(tracer)
+	res, err := service.Invoke()
+
+	result := &trace.Result{
+		State:   event.CONTRACT_STATE_SUCCESS,
+		Height:  block.Header.Height,
+		Call:    tracer.Root(),
+		Failure: tracer.Failure(),
+	}
+	if err != nil {
+		result.State = event.CONTRACT_STATE_FAIL
+		result.Error = err.Error()
+		return result, nil
+	}
+	if data, ok := res.([]byte); ok {
+		result.Result = common.ToHexString(data)
+	}
+	for _, notify := range service.GetNotify() {
+		result.Notify = append(result.Notify, trace.NewNotify(notify))
+	}
+	for _, crossHash := range service.GetCrossHashes() {
+		result.CrossHashes = append(result.CrossHashes, crossHash.ToHexString())
+	}
+	cache.ForEachChange(func(key, before, after []byte) {
+		result.StateDiff = append(result.StateDiff, trace.NewStateChange(key, before, after))
+	})
+	return result, nil
+}
+
 //IsContainBlock return whether the block is in store
 func (this *LedgerStoreImp) IsContainBlock(blockHash common.Uint256) (bool, error) {
 	return this.blockStore.ContainBlock(blockHash)
@@ -904,6 +1020,12 @@ func (this *LedgerStoreImp) GetBlockRootWithPreBlockHashes(startHeight uint32, p
 		log.Errorf("this.currBlockHeight= %d, startHeight= %d, len(preBlockHashes)= %d\n", this.currBlockHeight, startHeight, len(preBlockHashes))
 		return common.UINT256_EMPTY
 	}
+	// the ledger is behind consensus, such as a node halted on a block it failed to seal. The block
+	// at currBlockHeight+1 is not in preBlockHashes then and the start index below would underflow
+	if this.currBlockHeight+1 < startHeight {
+		log.Errorf("this.currBlockHeight= %d, startHeight= %d, ledger is behind consensus\n", this.currBlockHeight, startHeight)
+		return common.UINT256_EMPTY
+	}
 
 	needs := preBlockHashes[this.currBlockHeight+1-startHeight:]
 	return this.stateStore.GetBlockRootWithPreBlockHashes(needs)
@@ -978,6 +1100,17 @@ func (this *LedgerStoreImp) GetEventNotifyByBlock(height uint32) ([]*event.Execu
 	return this.eventStore.GetEventNotifyByBlock(height)
 }
 
+//GetEventLogs return the event logs matching filter and the cursor of the next page. Wrap function of EventStore.GetEventLogs
+func (this *LedgerStoreImp) GetEventLogs(filter *scom.EventLogFilter) ([]*scom.EventLog, []byte, error) {
+	return this.eventStore.GetEventLogs(filter)
+}
+
+//GetEventLogIndexGap return the heights whose event logs are not indexed yet, nil if the index is complete.
+//Wrap function of EventStore.GetEventLogIndexGap
+func (this *LedgerStoreImp) GetEventLogIndexGap() *scom.HeightRange {
+	return this.eventStore.GetEventLogIndexGap()
+}
+
 //Close ledger store.
 func (this *LedgerStoreImp) Close() error {
 	err := this.blockStore.Close()
@@ -22,10 +22,14 @@ import (
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -137,3 +141,42 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestGetBlockRootWithPreBlockHashes(t *testing.T) {
+	ledger := &LedgerStoreImp{stateStore: testStateStore, currBlockHeight: 10}
+	hashes := []common.Uint256{{1}, {2}, {3}}
+
+	// consensus is far behind ledger
+	if root := ledger.GetBlockRootWithPreBlockHashes(5, hashes); root != common.UINT256_EMPTY {
+		t.Errorf("TestGetBlockRootWithPreBlockHashes failed root %x != empty", root)
+		return
+	}
+	// ledger is behind consensus, the block at height 11 is missing from the hashes
+	if root := ledger.GetBlockRootWithPreBlockHashes(12, hashes); root != common.UINT256_EMPTY {
+		t.Errorf("TestGetBlockRootWithPreBlockHashes failed root %x != empty", root)
+		return
+	}
+	expect := testStateStore.GetBlockRootWithPreBlockHashes(hashes[1:])
+	if root := ledger.GetBlockRootWithPreBlockHashes(10, hashes); root != expect {
+		t.Errorf("TestGetBlockRootWithPreBlockHashes failed root %x != %x", root, expect)
+		return
+	}
+	expect = testStateStore.GetBlockRootWithPreBlockHashes(hashes)
+	if root := ledger.GetBlockRootWithPreBlockHashes(11, hashes); root != expect {
+		t.Errorf("TestGetBlockRootWithPreBlockHashes failed root %x != %x", root, expect)
+		return
+	}
+}
+
+func TestSimulateTransactionHeight(t *testing.T) {
+	tx := &types.Transaction{Payload: &payload.InvokeCode{}}
+	keep := config.DefConfig.Common.StateUndoBlocks
+	defer func() { config.DefConfig.Common.StateUndoBlocks = keep }()
+
+	config.DefConfig.Common.StateUndoBlocks = 0
+	_, err := testLedgerStore.SimulateTransaction(tx, 1)
+	if err == nil || !strings.Contains(err.Error(), "only height 0 is accepted") {
+		t.Errorf("TestSimulateTransactionHeight failed error %v", err)
+		return
+	}
+}
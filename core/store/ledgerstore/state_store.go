@@ -38,6 +38,7 @@ var (
 	BOOKKEEPER = []byte("Bookkeeper") //Bookkeeper store key
 )
 
+
 //StateStore saving the data of ledger states. Like balance of account, and the execution result of smart contract
 type StateStore struct {
 	dbDir                string                    //Store file path
@@ -269,6 +270,83 @@ func (self *StateStore) GetCrossStates(height uint32) (hashes []common.Uint256,
 	return
 }
 
+//AddStateUndo save the values overwritten by the write set of block at height, so the state before the block
+//can be restored. The record of block keep before is dropped
+func (self *StateStore) AddStateUndo(height, keep uint32, writeSet *overlaydb.MemDB) error {
+	sink := common.NewZeroCopySink(nil)
+	var err error
+	writeSet.ForEach(func(key, _ []byte) {
+		if err != nil {
+			return
+		}
+		val, e := self.store.Get(key)
+		if e != nil && e != scom.ErrNotFound {
+			err = e
+			return
+		}
+		sink.WriteVarBytes(key)
+		sink.WriteBool(e == nil)
+		if e == nil {
+			sink.WriteVarBytes(val)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	self.store.BatchPut(genStateUndoKey(height), sink.Bytes())
+	if height > keep {
+		self.store.BatchDelete(genStateUndoKey(height - keep))
+	}
+	return nil
+}
+
+//NewSnapshot return a read only state store of the states at the time it is taken, blocks saved later
+//are not seen by it. The snapshot should be closed after use
+func (self *StateStore) NewSnapshot() (*StateStore, error) {
+	store, ok := self.store.(*leveldbstore.LevelDBStore)
+	if !ok {
+		return nil, fmt.Errorf("snapshot is not supported by the store")
+	}
+	snapshot, err := store.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &StateStore{store: snapshot}, nil
+}
+
+//NewHistoryOverlayDB return an overlay db of the state at height, restored by undoing the blocks after it to current, the
+//current block of the store. Reads of a live store see blocks saved meanwhile, so use a snapshot
+func (self *StateStore) NewHistoryOverlayDB(height, current uint32) (*overlaydb.OverlayDB, error) {
+	overlay := self.NewOverlayDB()
+	for h := current; h > height; h-- {
+		data, err := self.store.Get(genStateUndoKey(h))
+		if err == scom.ErrNotFound {
+			return nil, fmt.Errorf("state of height %d is not kept", height)
+		}
+		if err != nil {
+			return nil, err
+		}
+		source := common.NewZeroCopySource(data)
+		for source.Len() > 0 {
+			key, eof := source.NextVarBytes()
+			exist, e := source.NextBool()
+			if eof || e {
+				return nil, fmt.Errorf("undo record of height %d: %s", h, io.ErrUnexpectedEOF)
+			}
+			if !exist {
+				overlay.Delete(key)
+				continue
+			}
+			val, eof := source.NextVarBytes()
+			if eof {
+				return nil, fmt.Errorf("undo record of height %d: %s", h, io.ErrUnexpectedEOF)
+			}
+			overlay.Put(key, val)
+		}
+	}
+	return overlay, nil
+}
+
 //AddBlockMerkleTreeRoot add a new tree root
 func (self *StateStore) AddBlockMerkleTreeRoot(preBlockHash common.Uint256) error {
 	key := self.genBlockMerkleTreeKey()
@@ -462,6 +540,13 @@ func (self *StateStore) genStateMerkleRootKey(height uint32) []byte {
 	return key
 }
 
+func genStateUndoKey(height uint32) []byte {
+	key := make([]byte, 5, 5)
+	key[0] = byte(scom.DATA_STATE_UNDO)
+	binary.LittleEndian.PutUint32(key[1:], height)
+	return key
+}
+
 //ClearAll clear all data in state store
 func (self *StateStore) ClearAll() error {
 	self.store.NewBatch()
@@ -479,6 +564,8 @@ func (self *StateStore) ClearAll() error {
 
 //Close state store
 func (self *StateStore) Close() error {
-	self.merkleHashStore.Close()
+	if self.merkleHashStore != nil {
+		self.merkleHashStore.Close()
+	}
 	return self.store.Close()
 }
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/merkle"
 	"github.com/stretchr/testify/assert"
 )
@@ -71,3 +72,80 @@ func TestStateMerkleRoot(t *testing.T) {
 	}
 
 }
+
+func TestStateUndo(t *testing.T) {
+	db := NewMemStateStore(0)
+	commit := func(height uint32, kvs map[string]string) {
+		writeSet := overlaydb.NewMemDB(0, 0)
+		for k, v := range kvs {
+			if v == "" {
+				writeSet.Delete([]byte(k))
+			} else {
+				writeSet.Put([]byte(k), []byte(v))
+			}
+		}
+		db.NewBatch()
+		assert.Nil(t, db.AddStateUndo(height, 3, writeSet))
+		writeSet.ForEach(func(key, val []byte) {
+			if len(val) == 0 {
+				db.BatchDeleteRawKey(key)
+			} else {
+				db.BatchPutRawKeyVal(key, val)
+			}
+		})
+		assert.Nil(t, db.SaveCurrentBlock(height, common.Uint256{}))
+		assert.Nil(t, db.CommitTo())
+	}
+	commit(1, map[string]string{"a": "1", "b": "1"})
+	commit(2, map[string]string{"a": "2", "c": "2"})
+	commit(3, map[string]string{"b": "", "c": "3"})
+
+	expects := map[uint32]map[string]string{
+		3: {"a": "2", "b": "", "c": "3"},
+		2: {"a": "2", "b": "1", "c": "2"},
+		1: {"a": "1", "b": "1", "c": ""},
+		0: {"a": "", "b": "", "c": ""},
+	}
+	for height, expect := range expects {
+		overlay, err := db.NewHistoryOverlayDB(height, 3)
+		assert.Nil(t, err)
+		for k, v := range expect {
+			val, err := overlay.Get([]byte(k))
+			assert.Nil(t, err)
+			assert.Equal(t, v, string(val), "height %d key %s", height, k)
+		}
+	}
+
+	// blocks saved after the snapshot is taken are not seen by it
+	snapshot, err := db.NewSnapshot()
+	assert.Nil(t, err)
+	commit(4, map[string]string{"a": "4"})
+	_, current, err := snapshot.GetCurrentBlock()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), current)
+	overlay, err := snapshot.NewHistoryOverlayDB(2, current)
+	assert.Nil(t, err)
+	val, err := overlay.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", string(val))
+	val, err = snapshot.store.Get([]byte("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2", string(val))
+	assert.Nil(t, snapshot.Close())
+	_, err = snapshot.NewHistoryOverlayDB(2, 3)
+	assert.NotNil(t, err)
+
+	// only the records of the last 3 blocks are kept
+	_, err = db.NewHistoryOverlayDB(0, 4)
+	assert.NotNil(t, err)
+	_, err = db.NewHistoryOverlayDB(1, 4)
+	assert.Nil(t, err)
+
+	db.NewBatch()
+	db.BatchDeleteRawKey(genStateUndoKey(2))
+	assert.Nil(t, db.CommitTo())
+	_, err = db.NewHistoryOverlayDB(1, 4)
+	assert.NotNil(t, err)
+	_, err = db.NewHistoryOverlayDB(2, 4)
+	assert.Nil(t, err)
+}
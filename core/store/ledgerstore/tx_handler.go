@@ -50,11 +50,11 @@ func (self *StateStore) HandleInvokeTransaction(store store.LedgerStore, overlay
 	return service.GetCrossHashes(), nil
 }
 
-func SaveNotify(eventStore scommon.EventStore, txHash common.Uint256, notify *event.ExecuteNotify) error {
+func SaveNotify(eventStore scommon.EventStore, height uint32, txHash common.Uint256, notify *event.ExecuteNotify) error {
 	if !config.DefConfig.Common.EnableEventLog {
 		return nil
 	}
-	if err := eventStore.SaveEventNotifyByTx(txHash, notify); err != nil {
+	if err := eventStore.SaveEventNotifyByTx(height, txHash, notify); err != nil {
 		return fmt.Errorf("SaveEventNotifyByTx error %s", err)
 	}
 	event.PushSmartCodeEvent(txHash, 0, event.EVENT_NOTIFY, notify)
@@ -144,6 +144,11 @@ func (self *LevelDBStore) BatchCommit() error {
 	return nil
 }
 
+//BatchWrite write a batch built apart from the commit batch to leveldb
+func (self *LevelDBStore) BatchWrite(batch *leveldb.Batch) error {
+	return self.db.Write(batch, nil)
+}
+
 //Close leveldb
 func (self *LevelDBStore) Close() error {
 	err := self.db.Close()
@@ -157,3 +162,77 @@ func (self *LevelDBStore) NewIterator(prefix []byte) common.StoreIterator {
 
 	return iter
 }
+
+//NewRangeIterator return a iterator of leveldb over the keys in [start, limit)
+func (self *LevelDBStore) NewRangeIterator(start, limit []byte) common.StoreIterator {
+	return self.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+}
+
+//LevelDBSnapshot is a read only view of LevelDBStore at the time it is taken
+type LevelDBSnapshot struct {
+	snapshot *leveldb.Snapshot
+}
+
+//NewSnapshot return a snapshot of leveldb, which should be closed after use
+func (self *LevelDBStore) NewSnapshot() (*LevelDBSnapshot, error) {
+	snapshot, err := self.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBSnapshot{snapshot: snapshot}, nil
+}
+
+//Put is not supported by snapshot
+func (self *LevelDBSnapshot) Put(key []byte, value []byte) error {
+	return common.ErrReadOnly
+}
+
+//Get the value of a key from snapshot
+func (self *LevelDBSnapshot) Get(key []byte) ([]byte, error) {
+	dat, err := self.snapshot.Get(key, nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+	return dat, nil
+}
+
+//Has return whether the key is exist in snapshot
+func (self *LevelDBSnapshot) Has(key []byte) (bool, error) {
+	return self.snapshot.Has(key, nil)
+}
+
+//Delete is not supported by snapshot
+func (self *LevelDBSnapshot) Delete(key []byte) error {
+	return common.ErrReadOnly
+}
+
+//NewBatch does nothing, the batch of snapshot can't be committed
+func (self *LevelDBSnapshot) NewBatch() {
+}
+
+//BatchPut does nothing, the batch of snapshot can't be committed
+func (self *LevelDBSnapshot) BatchPut(key []byte, value []byte) {
+}
+
+//BatchDelete does nothing, the batch of snapshot can't be committed
+func (self *LevelDBSnapshot) BatchDelete(key []byte) {
+}
+
+//BatchCommit is not supported by snapshot
+func (self *LevelDBSnapshot) BatchCommit() error {
+	return common.ErrReadOnly
+}
+
+//Close release snapshot
+func (self *LevelDBSnapshot) Close() error {
+	self.snapshot.Release()
+	return nil
+}
+
+//NewIterator return a iterator of snapshot with the key prefix
+func (self *LevelDBSnapshot) NewIterator(prefix []byte) common.StoreIterator {
+	return self.snapshot.NewIterator(util.BytesPrefix(prefix), nil)
+}
@@ -22,10 +22,12 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native/event"
 	cstates "github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/native/trace"
 )
 
 type ExecuteResult struct {
@@ -65,6 +67,10 @@ type LedgerStore interface {
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
+	SimulateTransaction(tx *types.Transaction, height uint32) (*trace.Result, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
+	GetEventLogs(filter *scom.EventLogFilter) ([]*scom.EventLog, []byte, error)
+	GetEventLogIndexGap() *scom.HeightRange
+	GetStoreError() error
 }
@@ -44,6 +44,8 @@ require (
 	github.com/zeebo/assert v1.3.0
 	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
 	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2
+	google.golang.org/grpc v1.37.0
+	google.golang.org/protobuf v1.26.0
 	gotest.tools v2.2.0+incompatible
 )
 
@@ -21,9 +21,11 @@ package actor
 import (
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/ledger"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native/event"
 	cstate "github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/native/trace"
 )
 
 const (
@@ -87,6 +89,16 @@ func PreExecuteContract(tx *types.Transaction) (*cstate.PreExecResult, error) {
 	return ledger.DefLedger.PreExecuteContract(tx)
 }
 
+//GetStoreError from ledger
+func GetStoreError() error {
+	return ledger.DefLedger.GetStoreError()
+}
+
+//SimulateTransaction from ledger
+func SimulateTransaction(tx *types.Transaction, height uint32) (*trace.Result, error) {
+	return ledger.DefLedger.SimulateTransaction(tx, height)
+}
+
 //GetEventNotifyByTxHash from ledger
 func GetEventNotifyByTxHash(txHash common.Uint256) (*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByTx(txHash)
@@ -97,6 +109,16 @@ func GetEventNotifyByHeight(height uint32) ([]*event.ExecuteNotify, error) {
 	return ledger.DefLedger.GetEventNotifyByBlock(height)
 }
 
+//GetEventLogs from ledger
+func GetEventLogs(filter *scom.EventLogFilter) ([]*scom.EventLog, []byte, error) {
+	return ledger.DefLedger.GetEventLogs(filter)
+}
+
+//GetEventLogIndexGap from ledger
+func GetEventLogIndexGap() *scom.HeightRange {
+	return ledger.DefLedger.GetEventLogIndexGap()
+}
+
 //GetMerkleProof from ledger
 func GetMerkleProof(proofHeight uint32, rootHeight uint32) ([]byte, error) {
 	return ledger.DefLedger.GetMerkleProof(proofHeight, rootHeight)
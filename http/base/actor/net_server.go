@@ -79,6 +79,24 @@ func GetNeighborAddrs() []common.PeerAddr {
 	return r.Addrs
 }
 
+//GetBestPeerHeight from netSever actor
+func GetBestPeerHeight() (uint32, error) {
+	if netServerPid == nil {
+		return 0, nil
+	}
+	future := netServerPid.RequestFuture(&ac.GetBestPeerHeightReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return 0, err
+	}
+	r, ok := result.(*ac.GetBestPeerHeightRsp)
+	if !ok {
+		return 0, errors.New("fail")
+	}
+	return r.Height, nil
+}
+
 //GetConnectionState from netSever actor
 func GetConnectionState() (uint32, error) {
 	if netServerPid == nil {
@@ -0,0 +1,312 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package auth provides access control of the methods served by rpc, restful, websocket and grpc servers
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	SCHEME_BEARER = "bearer" //Authorization: Bearer <token>
+	//Authorization: HMAC-SHA256 <name>:<unix timestamp>:<nonce>:<hex of HMAC-SHA256(token,
+	//"<unix timestamp>\n<nonce>\n<http method>\n<path with query>\n<body>")>
+	SCHEME_HMAC = "hmac"
+
+	ALL_METHODS = "*"
+	//max difference between the timestamp of a hmac signature and local time, limits replaying
+	MAX_CLOCK_SKEW = 5 * time.Minute
+	//max length of the nonce of a hmac signature
+	MAX_NONCE_LEN = 64
+)
+
+//methods protected if Protected of config is empty, the admin methods of local rpc
+var DefaultProtected = []string{"startconsensus", "stopconsensus", "setdebuginfo"}
+
+//credential granted to requests from trusted listeners, such as the unix socket of local rpc
+var TrustedCredential = &Credential{Name: "trusted", Methods: []string{ALL_METHODS}}
+
+//authenticator of the servers, nil if no auth file configured, then DefaultProtected methods are only
+//allowed to trusted listeners and loopback clients, other methods are allowed to all
+var DefAuthenticator *Authenticator
+
+type Credential struct {
+	Name    string   //unique name, identifies the credential in hmac signatures and logs
+	Scheme  string   //SCHEME_BEARER or SCHEME_HMAC
+	Token   string   //bearer token, or secret of hmac signatures
+	Methods []string //protected methods allowed, ALL_METHODS for all
+}
+
+//Config is the content of auth file
+type Config struct {
+	Protected   []string //methods requiring a credential, DefaultProtected if empty
+	Credentials []*Credential
+}
+
+type Authenticator struct {
+	protected   map[string]bool
+	credentials []*Credential
+	byName      map[string]*Credential
+	allowed     map[*Credential]map[string]bool
+
+	nonceLock sync.Mutex
+	nonces    map[string]time.Time //credential name and nonce of accepted hmac signatures -> expire time
+	lastPrune time.Time
+}
+
+//Init load auth file at path as DefAuthenticator, only the admin methods are limited to local requests if path is empty
+func Init(path string) error {
+	if path == "" {
+		return nil
+	}
+	authenticator, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	DefAuthenticator = authenticator
+	return nil
+}
+
+//LoadFile load authenticator from json auth file
+func LoadFile(path string) (*Authenticator, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadFile, read %s error: %v", path, err)
+	}
+	config := new(Config)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("LoadFile, unmarshal %s error: %v", path, err)
+	}
+	return NewAuthenticator(config)
+}
+
+func NewAuthenticator(config *Config) (*Authenticator, error) {
+	protected := config.Protected
+	if len(protected) == 0 {
+		protected = DefaultProtected
+	}
+	this := &Authenticator{
+		protected: make(map[string]bool),
+		byName:    make(map[string]*Credential),
+		allowed:   make(map[*Credential]map[string]bool),
+		nonces:    make(map[string]time.Time),
+	}
+	for _, method := range protected {
+		this.protected[strings.ToLower(method)] = true
+	}
+	for _, cred := range config.Credentials {
+		if cred.Name == "" || cred.Token == "" {
+			return nil, fmt.Errorf("NewAuthenticator, credential without name or token")
+		}
+		if _, ok := this.byName[cred.Name]; ok {
+			return nil, fmt.Errorf("NewAuthenticator, duplicated credential %s", cred.Name)
+		}
+		cred.Scheme = strings.ToLower(cred.Scheme)
+		if cred.Scheme != SCHEME_BEARER && cred.Scheme != SCHEME_HMAC {
+			return nil, fmt.Errorf("NewAuthenticator, unknown scheme %s of credential %s", cred.Scheme, cred.Name)
+		}
+		this.credentials = append(this.credentials, cred)
+		this.byName[cred.Name] = cred
+		this.allowed[cred] = make(map[string]bool)
+		for _, method := range cred.Methods {
+			this.allowed[cred][strings.ToLower(method)] = true
+		}
+	}
+	return this, nil
+}
+
+//Protected return whether method requires a credential, DefaultProtected methods if this is nil
+func (this *Authenticator) Protected(method string) bool {
+	method = strings.ToLower(method)
+	if this == nil {
+		for _, protected := range DefaultProtected {
+			if method == protected {
+				return true
+			}
+		}
+		return false
+	}
+	return this.protected[method]
+}
+
+//Allow return whether cred, nil if not authenticated, may call method
+func (this *Authenticator) Allow(cred *Credential, method string) bool {
+	if !this.Protected(method) {
+		return true
+	}
+	if cred == TrustedCredential {
+		return true
+	}
+	if cred == nil || this == nil {
+		return false
+	}
+	allowed := this.allowed[cred]
+	return allowed[ALL_METHODS] || allowed[strings.ToLower(method)]
+}
+
+//Authenticate check the Authorization header of request with body, nil credential if the header is absent.
+//Without auth file, requests from loopback clients are trusted
+func (this *Authenticator) Authenticate(r *http.Request, body []byte) (*Credential, error) {
+	if IsTrusted(r.Context()) || (this == nil && IsLoopback(r.RemoteAddr)) {
+		return TrustedCredential, nil
+	}
+	return this.AuthenticateHeader(r.Header.Get("Authorization"), r.Method, r.URL.RequestURI(), body, time.Now())
+}
+
+//AuthenticateHeader check authorization header value of request to method and path with body at now,
+//nil credential if authorization is empty
+func (this *Authenticator) AuthenticateHeader(authorization, method, path string, body []byte,
+	now time.Time) (*Credential, error) {
+	if this == nil || authorization == "" {
+		return nil, nil
+	}
+	fields := strings.SplitN(strings.TrimSpace(authorization), " ", 2)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("AuthenticateHeader, invalid authorization")
+	}
+	switch strings.ToLower(fields[0]) {
+	case SCHEME_BEARER:
+		token := []byte(strings.TrimSpace(fields[1]))
+		for _, cred := range this.credentials {
+			if cred.Scheme == SCHEME_BEARER && subtle.ConstantTimeCompare(token, []byte(cred.Token)) == 1 {
+				return cred, nil
+			}
+		}
+		return nil, fmt.Errorf("AuthenticateHeader, unknown bearer token")
+	case "hmac-sha256":
+		return this.authenticateHmac(strings.TrimSpace(fields[1]), method, path, body, now)
+	}
+	return nil, fmt.Errorf("AuthenticateHeader, unknown scheme %s", fields[0])
+}
+
+func (this *Authenticator) authenticateHmac(value, method, path string, body []byte, now time.Time) (*Credential, error) {
+	fields := strings.Split(value, ":")
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("authenticateHmac, invalid signature")
+	}
+	cred, ok := this.byName[fields[0]]
+	if !ok || cred.Scheme != SCHEME_HMAC {
+		return nil, fmt.Errorf("authenticateHmac, unknown credential %s", fields[0])
+	}
+	timestamp, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("authenticateHmac, invalid timestamp %s", fields[1])
+	}
+	skew := now.Sub(time.Unix(timestamp, 0))
+	if skew > MAX_CLOCK_SKEW || skew < -MAX_CLOCK_SKEW {
+		return nil, fmt.Errorf("authenticateHmac, timestamp %d expired", timestamp)
+	}
+	nonce := fields[2]
+	if nonce == "" || len(nonce) > MAX_NONCE_LEN {
+		return nil, fmt.Errorf("authenticateHmac, invalid nonce")
+	}
+	sig, err := hex.DecodeString(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("authenticateHmac, invalid signature hex")
+	}
+	if !hmac.Equal(sig, Sign(cred.Token, timestamp, nonce, method, path, body)) {
+		return nil, fmt.Errorf("authenticateHmac, signature of %s mismatch", cred.Name)
+	}
+	if !this.useNonce(cred.Name, nonce, time.Unix(timestamp, 0).Add(MAX_CLOCK_SKEW), now) {
+		return nil, fmt.Errorf("authenticateHmac, nonce %s of %s reused", nonce, cred.Name)
+	}
+	return cred, nil
+}
+
+//useNonce record nonce of the credential name until expire, return false if it is already used.
+//A nonce only needs to be kept until its timestamp is out of MAX_CLOCK_SKEW.
+func (this *Authenticator) useNonce(name, nonce string, expire, now time.Time) bool {
+	this.nonceLock.Lock()
+	defer this.nonceLock.Unlock()
+
+	if now.Sub(this.lastPrune) > MAX_CLOCK_SKEW {
+		for key, t := range this.nonces {
+			if t.Before(now) {
+				delete(this.nonces, key)
+			}
+		}
+		this.lastPrune = now
+	}
+	key := name + ":" + nonce
+	if t, ok := this.nonces[key]; ok && !t.Before(now) {
+		return false
+	}
+	this.nonces[key] = expire
+	return true
+}
+
+//Sign return the hmac signature with secret of the request to method and path with body, at timestamp with nonce
+func Sign(secret string, timestamp int64, nonce, method, path string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(strings.ToUpper(method)))
+	mac.Write([]byte("\n"))
+	mac.Write([]byte(path))
+	mac.Write([]byte("\n"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+//HmacAuthorization return the Authorization header value signing the request to method and path with body,
+//by the hmac credential name and secret. nonce must not be reused by the credential within MAX_CLOCK_SKEW
+func HmacAuthorization(name, secret string, timestamp int64, nonce, method, path string, body []byte) string {
+	return fmt.Sprintf("HMAC-SHA256 %s:%d:%s:%s", name, timestamp, nonce,
+		hex.EncodeToString(Sign(secret, timestamp, nonce, method, path, body)))
+}
+
+type trustedKey struct{}
+
+//Trusted mark the requests served by h as from a trusted listener
+func Trusted(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), trustedKey{}, true)))
+	})
+}
+
+//IsLoopback return whether the remote address host:port is a loopback address
+func IsLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+//IsTrusted return whether the request of ctx is from a trusted listener
+func IsTrusted(ctx context.Context) bool {
+	trusted, _ := ctx.Value(trustedKey{}).(bool)
+	return trusted
+}
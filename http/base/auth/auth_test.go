@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	authenticator, err := NewAuthenticator(&Config{
+		Protected: []string{"startconsensus", "stopconsensus", "sendrawtransaction"},
+		Credentials: []*Credential{
+			{Name: "ops", Scheme: "bearer", Token: "ops-token", Methods: []string{ALL_METHODS}},
+			{Name: "relayer", Scheme: "hmac", Token: "relayer-secret", Methods: []string{"sendrawtransaction"}},
+		},
+	})
+	assert.Nil(t, err)
+	return authenticator
+}
+
+func TestBearer(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	cred, err := authenticator.AuthenticateHeader("Bearer ops-token", "POST", "/", nil, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, "ops", cred.Name)
+	assert.True(t, authenticator.Allow(cred, "stopconsensus"))
+
+	_, err = authenticator.AuthenticateHeader("Bearer other", "POST", "/", nil, time.Now())
+	assert.NotNil(t, err)
+	//hmac secret is not a bearer token
+	_, err = authenticator.AuthenticateHeader("Bearer relayer-secret", "POST", "/", nil, time.Now())
+	assert.NotNil(t, err)
+
+	cred, err = authenticator.AuthenticateHeader("", "POST", "/", nil, time.Now())
+	assert.Nil(t, err)
+	assert.Nil(t, cred)
+	assert.False(t, authenticator.Allow(nil, "startconsensus"))
+	assert.True(t, authenticator.Allow(nil, "getblock"))
+}
+
+func TestHmac(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	body := []byte(`{"jsonrpc":"2.0","method":"sendrawtransaction","params":["00"],"id":1}`)
+	now := time.Now()
+	sign := func(name, secret string, timestamp int64, nonce, method, path string) string {
+		return HmacAuthorization(name, secret, timestamp, nonce, method, path, body)
+	}
+
+	cred, err := authenticator.AuthenticateHeader(sign("relayer", "relayer-secret", now.Unix(), "n1", "POST", "/"), "POST", "/", body, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "relayer", cred.Name)
+	assert.True(t, authenticator.Allow(cred, "sendrawtransaction"))
+	assert.False(t, authenticator.Allow(cred, "stopconsensus"))
+
+	_, err = authenticator.AuthenticateHeader(sign("relayer", "relayer-secret", now.Unix(), "n2", "POST", "/"), "POST", "/", []byte("{}"), now)
+	assert.NotNil(t, err)
+	_, err = authenticator.AuthenticateHeader(sign("relayer", "wrong", now.Unix(), "n3", "POST", "/"), "POST", "/", body, now)
+	assert.NotNil(t, err)
+	expired := now.Add(-MAX_CLOCK_SKEW - time.Second).Unix()
+	_, err = authenticator.AuthenticateHeader(sign("relayer", "relayer-secret", expired, "n4", "POST", "/"), "POST", "/", body, now)
+	assert.NotNil(t, err)
+	_, err = authenticator.AuthenticateHeader(sign("ops", "ops-token", now.Unix(), "n5", "POST", "/"), "POST", "/", body, now)
+	assert.NotNil(t, err)
+	_, err = authenticator.AuthenticateHeader(sign("relayer", "relayer-secret", now.Unix(), "", "POST", "/"), "POST", "/", body, now)
+	assert.NotNil(t, err)
+
+	//signature is bound to method and path
+	_, err = authenticator.AuthenticateHeader(sign("relayer", "relayer-secret", now.Unix(), "n6", "GET", "/api/v1/block/height"),
+		"GET", "/api/v1/block/hash/1", body, now)
+	assert.NotNil(t, err)
+	_, err = authenticator.AuthenticateHeader(sign("relayer", "relayer-secret", now.Unix(), "n7", "GET", "/"), "POST", "/", body, now)
+	assert.NotNil(t, err)
+}
+
+func TestHmacNonce(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	now := time.Now()
+	authorization := HmacAuthorization("relayer", "relayer-secret", now.Unix(), "n1", "GET", "/", nil)
+
+	_, err := authenticator.AuthenticateHeader(authorization, "GET", "/", nil, now)
+	assert.Nil(t, err)
+	//replayed within MAX_CLOCK_SKEW
+	_, err = authenticator.AuthenticateHeader(authorization, "GET", "/", nil, now.Add(time.Minute))
+	assert.NotNil(t, err)
+	//nonces are per credential
+	authenticator.credentials = append(authenticator.credentials, &Credential{Name: "other", Scheme: SCHEME_HMAC, Token: "s"})
+	authenticator.byName["other"] = authenticator.credentials[len(authenticator.credentials)-1]
+	_, err = authenticator.AuthenticateHeader(HmacAuthorization("other", "s", now.Unix(), "n1", "GET", "/", nil), "GET", "/", nil, now)
+	assert.Nil(t, err)
+
+	//expired nonces are pruned, the replayed signature is refused by its timestamp
+	later := now.Add(2*MAX_CLOCK_SKEW + time.Second)
+	_, err = authenticator.AuthenticateHeader(HmacAuthorization("relayer", "relayer-secret", later.Unix(), "n2", "GET", "/", nil), "GET", "/", nil, later)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(authenticator.nonces))
+	_, err = authenticator.AuthenticateHeader(authorization, "GET", "/", nil, later)
+	assert.NotNil(t, err)
+}
+
+func TestConfig(t *testing.T) {
+	authenticator, err := NewAuthenticator(&Config{})
+	assert.Nil(t, err)
+	for _, method := range DefaultProtected {
+		assert.True(t, authenticator.Protected(method))
+	}
+	assert.False(t, authenticator.Protected("sendrawtransaction"))
+
+	_, err = NewAuthenticator(&Config{Credentials: []*Credential{{Name: "a", Scheme: "basic", Token: "t"}}})
+	assert.NotNil(t, err)
+	_, err = NewAuthenticator(&Config{Credentials: []*Credential{{Name: "a", Scheme: "bearer"}}})
+	assert.NotNil(t, err)
+	_, err = NewAuthenticator(&Config{Credentials: []*Credential{
+		{Name: "a", Scheme: "bearer", Token: "t1"},
+		{Name: "a", Scheme: "hmac", Token: "t2"},
+	}})
+	assert.NotNil(t, err)
+
+	// without auth file, admin methods are only allowed to loopback clients
+	var none *Authenticator
+	assert.False(t, none.Allow(nil, "stopconsensus"))
+	assert.True(t, none.Allow(nil, "sendrawtransaction"))
+	r, err := http.NewRequest("POST", "/local", nil)
+	assert.Nil(t, err)
+	r.RemoteAddr = "127.0.0.1:50000"
+	cred, err := none.Authenticate(r, nil)
+	assert.Nil(t, err)
+	assert.True(t, none.Allow(cred, "stopconsensus"))
+	r.RemoteAddr = "10.0.0.1:50000"
+	cred, err = none.Authenticate(r, nil)
+	assert.Nil(t, err)
+	assert.False(t, none.Allow(cred, "stopconsensus"))
+}
+
+func TestTrusted(t *testing.T) {
+	authenticator := newTestAuthenticator(t)
+	var cred *Credential
+	handler := Trusted(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cred, _ = authenticator.Authenticate(r, nil)
+	}))
+	r, err := http.NewRequest("POST", "/local", nil)
+	assert.Nil(t, err)
+	handler.ServeHTTP(nil, r)
+	assert.Equal(t, TrustedCredential, cred)
+	assert.True(t, authenticator.Allow(cred, "stopconsensus"))
+	assert.False(t, IsTrusted(context.Background()))
+}
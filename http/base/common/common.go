@@ -20,13 +20,25 @@
 package common
 
 import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/crossproof"
+	scom "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/types"
 	ontErrors "github.com/polynetwork/poly/errors"
 	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/merkle"
 	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
 	cstate "github.com/polynetwork/poly/native/states"
 )
 
@@ -66,6 +78,20 @@ type NotifyEventInfo struct {
 	States          interface{}
 }
 
+type EventLog struct {
+	Height          uint32
+	TxHash          string
+	Index           uint32
+	ContractAddress string
+	States          interface{}
+}
+
+type EventLogs struct {
+	Logs      []EventLog
+	Cursor    string
+	Unindexed *scom.HeightRange `json:",omitempty"` //Heights whose event logs are missing while the index is rebuilt
+}
+
 type TxAttributeInfo struct {
 	Usage types.TransactionAttributeUsage
 	Data  string
@@ -141,6 +167,22 @@ type ConsensusInfo struct {
 	// TODO
 }
 
+type PeerPerformanceInfo struct {
+	View         uint32                          // governance view the performances recorded for
+	Policy       *node_manager.PerformancePolicy // current demotion policy, nil if not set
+	Performances []*node_manager.PeerPerformance
+}
+
+type RelayerRewardInfo struct {
+	Address         string
+	ChainID         uint64
+	HeadersSynced   uint64
+	ProofsDelivered uint64
+	Earned          string
+	Claimed         string
+	Claimable       string
+}
+
 type TXNAttrInfo struct {
 	Height  uint32
 	Type    int
@@ -162,6 +204,15 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 	return contractAddrs, ExecuteNotify{txhash, obj.State, obj.GasConsumed, evts}
 }
 
+func GetEventLogs(logs []*scom.EventLog, cursor []byte, unindexed *scom.HeightRange) EventLogs {
+	evts := make([]EventLog, 0, len(logs))
+	for _, v := range logs {
+		evts = append(evts, EventLog{v.Height, v.TxHash.ToHexString(), v.Index,
+			v.Notify.ContractAddress.ToHexString(), v.Notify.States})
+	}
+	return EventLogs{evts, hex.EncodeToString(cursor), unindexed}
+}
+
 func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
 	evts := []NotifyEventInfo{}
 	for _, v := range obj.Notify {
@@ -276,3 +327,123 @@ func GetAddress(str string) (common.Address, error) {
 	}
 	return address, err
 }
+
+//GetPeerPerformanceInfo return peer performances recorded at the end of view, and latest recorded view if view is 0
+func GetPeerPerformanceInfo(view uint32) (*PeerPerformanceInfo, error) {
+	contract := nutils.NodeManagerContractAddress
+	if view == 0 {
+		value, err := bactor.GetStorageItem(contract, []byte(node_manager.GOVERNANCE_VIEW))
+		if err != nil {
+			return nil, fmt.Errorf("get governance view error: %s", err)
+		}
+		governanceView := new(node_manager.GovernanceView)
+		if err := governanceView.Deserialization(common.NewZeroCopySource(value)); err != nil {
+			return nil, fmt.Errorf("deserialize governance view error: %s", err)
+		}
+		view = governanceView.View - 1
+	}
+	info := &PeerPerformanceInfo{
+		View:         view,
+		Performances: make([]*node_manager.PeerPerformance, 0),
+	}
+
+	value, err := bactor.GetStorageItem(contract, []byte(node_manager.PERFORMANCE_POLICY))
+	if err != nil && err != scom.ErrNotFound {
+		return nil, fmt.Errorf("get performance policy error: %s", err)
+	}
+	if err == nil {
+		info.Policy = new(node_manager.PerformancePolicy)
+		if err := info.Policy.Deserialization(common.NewZeroCopySource(value)); err != nil {
+			return nil, fmt.Errorf("deserialize performance policy error: %s", err)
+		}
+	}
+
+	key := append([]byte(node_manager.PEER_PERFORMANCE), nutils.GetUint32Bytes(view)...)
+	value, err = bactor.GetStorageItem(contract, key)
+	if err == scom.ErrNotFound {
+		return info, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get peer performance error: %s", err)
+	}
+	performanceMap := new(node_manager.PeerPerformanceMap)
+	if err := performanceMap.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("deserialize peer performance error: %s", err)
+	}
+	for _, v := range performanceMap.PeerPerformanceMap {
+		info.Performances = append(info.Performances, v)
+	}
+	sort.Slice(info.Performances, func(i, j int) bool {
+		return info.Performances[i].PeerPubkey < info.Performances[j].PeerPubkey
+	})
+	return info, nil
+}
+
+//GetRelayerRewardInfo return work and claimable fee of relayer for a chain
+func GetRelayerRewardInfo(relayer common.Address, chainID uint64) (*RelayerRewardInfo, error) {
+	reward := &relayer_manager.RelayerReward{
+		Address: relayer,
+		ChainID: chainID,
+		Earned:  new(big.Int),
+		Claimed: new(big.Int),
+	}
+	key := append(append([]byte(relayer_manager.RELAYER_REWARD), relayer[:]...), nutils.GetUint64Bytes(chainID)...)
+	value, err := bactor.GetStorageItem(nutils.RelayerManagerContractAddress, key)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, fmt.Errorf("get relayer reward error: %s", err)
+	}
+	if err == nil {
+		if err := reward.Deserialization(common.NewZeroCopySource(value)); err != nil {
+			return nil, fmt.Errorf("deserialize relayer reward error: %s", err)
+		}
+	}
+	return &RelayerRewardInfo{
+		Address:         reward.Address.ToBase58(),
+		ChainID:         reward.ChainID,
+		HeadersSynced:   reward.HeadersSynced,
+		ProofsDelivered: reward.ProofsDelivered,
+		Earned:          reward.Earned.String(),
+		Claimed:         reward.Claimed.String(),
+		Claimable:       reward.Claimable().String(),
+	}, nil
+}
+
+// GetCrossStatesBundle collects the proof of the cross state stored under key at height, with the
+// config headers a destination chain trusting the epoch started at trustedHeight needs to verify it
+func GetCrossStatesBundle(height uint32, key []byte, trustedHeight uint32) (*crossproof.Bundle, error) {
+	path, err := bactor.GetCrossStatesProof(height, key)
+	if err != nil {
+		return nil, fmt.Errorf("GetCrossStatesProof error: %s", err)
+	}
+	// cross states of a block are committed to by the header of the next block
+	header, err := bactor.GetHeaderByHeight(height + 1)
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderByHeight %d error: %s", height+1, err)
+	}
+	value, err := merkle.MerkleProve(path, header.CrossStateRoot[:])
+	if err != nil {
+		return nil, fmt.Errorf("MerkleProve error: %s", err)
+	}
+	bundle := &crossproof.Bundle{
+		Value:     value,
+		AuditPath: path,
+		Header:    header.ToArray(),
+	}
+	// header h is signed by the epoch of its last config block k, config block k itself records the
+	// config block before it, walk back from the epoch signing the header
+	for cur := header; cur.Height > 0; {
+		blkInfo, err := vconfig.VbftBlock(cur)
+		if err != nil {
+			return nil, fmt.Errorf("VbftBlock of height %d error: %s", cur.Height, err)
+		}
+		k := blkInfo.LastConfigBlockNum
+		if k <= trustedHeight || k >= cur.Height {
+			break
+		}
+		if cur, err = bactor.GetHeaderByHeight(k); err != nil {
+			return nil, fmt.Errorf("GetHeaderByHeight %d error: %s", k, err)
+		}
+		bundle.EpochHeaders = append([][]byte{cur.ToArray()}, bundle.EpochHeaders...)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/crossproof"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/signature"
+	cstates "github.com/polynetwork/poly/core/states"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/merkle"
+	"github.com/polynetwork/poly/native"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEpoch(n int) []*account.Account {
+	accs := make([]*account.Account, n)
+	for i := range accs {
+		accs[i] = account.NewAccount("")
+	}
+	return accs
+}
+
+func chainConfig(epoch []*account.Account) *vconfig.ChainConfig {
+	cfg := &vconfig.ChainConfig{N: uint32(len(epoch)), C: uint32(len(epoch)-1) / 3}
+	for i, acc := range epoch {
+		cfg.Peers = append(cfg.Peers, &vconfig.PeerConfig{Index: uint32(i + 1), ID: vconfig.PubkeyID(acc.PublicKey)})
+	}
+	return cfg
+}
+
+// submitBlock appends a block signed by signers to ledger, with the cross state value stored under key
+func submitBlock(t *testing.T, ldg *ledger.Ledger, signers []*account.Account, blkInfo *vconfig.VbftBlockInfo,
+	key, value []byte) {
+	prev, err := ldg.GetHeaderByHeight(ldg.GetCurrentBlockHeight())
+	assert.Nil(t, err)
+	crossStateRoot, err := ldg.GetCrossStateRoot(prev.Height)
+	assert.Nil(t, err)
+	payload, err := json.Marshal(blkInfo)
+	assert.Nil(t, err)
+	prevHash := prev.Hash()
+	block := &types.Block{Header: &types.Header{
+		PrevBlockHash:    prevHash,
+		CrossStateRoot:   crossStateRoot,
+		BlockRoot:        ldg.GetBlockRootWithPreBlockHashes(prev.Height+1, []common.Uint256{prevHash}),
+		Timestamp:        prev.Timestamp + 1,
+		Height:           prev.Height + 1,
+		ConsensusPayload: payload,
+	}}
+	hash := block.Hash()
+	for _, acc := range signers {
+		sig, err := signature.Sign(acc, hash[:])
+		assert.Nil(t, err)
+		block.Header.Bookkeepers = append(block.Header.Bookkeepers, acc.PublicKey)
+		block.Header.SigData = append(block.Header.SigData, sig)
+	}
+
+	result, err := ldg.ExecuteBlock(block)
+	assert.Nil(t, err)
+	if key != nil {
+		result.WriteSet.Put(append([]byte{byte(scom.ST_STORAGE)}, key...), cstates.GenRawStorageItem(value))
+		result.CrossHashes = []common.Uint256{merkle.HashLeaf([]byte("other")), merkle.HashLeaf(value)}
+		result.CrossStatesRoot = merkle.TreeHasher{}.HashFullTreeWithLeafHash(result.CrossHashes)
+	}
+	assert.Nil(t, ldg.SubmitBlock(block, result))
+}
+
+func TestGetCrossStatesBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cross_states_bundle")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	epoch0, epoch1, epoch2 := newEpoch(4), newEpoch(4), newEpoch(4)
+	bookkeepers := make([]keypair.PublicKey, 0, len(epoch0))
+	vbftCfg := &config.VBFTConfig{
+		VrfValue: config.PolarisConfig.VBFT.VrfValue,
+		VrfProof: config.PolarisConfig.VBFT.VrfProof,
+	}
+	for i, acc := range epoch0 {
+		bookkeepers = append(bookkeepers, acc.PublicKey)
+		vbftCfg.Peers = append(vbftCfg.Peers, &config.VBFTPeerInfo{
+			Index:      uint32(i + 1),
+			PeerPubkey: vconfig.PubkeyID(acc.PublicKey),
+			Address:    acc.Address.ToBase58(),
+		})
+	}
+	genesisCfg := config.DefConfig.Genesis
+	config.DefConfig.Genesis = &config.GenesisConfig{ConsensusType: config.CONSENSUS_TYPE_VBFT, VBFT: vbftCfg}
+	defer func() {
+		config.DefConfig.Genesis = genesisCfg
+	}()
+	native.Contracts[nutils.NodeManagerContractAddress] = node_manager.RegisterNodeManagerContract
+	genesisBlock, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	assert.Nil(t, err)
+	ldg, err := ledger.NewLedger(dir)
+	assert.Nil(t, err)
+	defer ldg.Close()
+	assert.Nil(t, ldg.Init(bookkeepers, genesisBlock))
+	defLedger := ledger.DefLedger
+	ledger.DefLedger = ldg
+	defer func() {
+		ledger.DefLedger = defLedger
+	}()
+
+	// epoch 1 starts at config block 2 and epoch 2 at config block 4, the cross state is stored in
+	// config block 4, the header committing to it is the first one signed by epoch 2
+	sink := common.NewZeroCopySink(nil)
+	(&ccmcom.ToMerkleValue{
+		TxHash:      []byte{1},
+		FromChainID: 2,
+		MakeTxParam: &ccmcom.MakeTxParam{TxHash: []byte{2}, CrossChainID: []byte{3}, ToChainID: 3, Method: "unlock"},
+	}).Serialization(sink)
+	key, value := []byte("cross state"), sink.Bytes()
+	submitBlock(t, ldg, epoch0[:3], &vconfig.VbftBlockInfo{}, nil, nil)
+	submitBlock(t, ldg, epoch0[:3], &vconfig.VbftBlockInfo{NewChainConfig: chainConfig(epoch1)}, nil, nil)
+	submitBlock(t, ldg, epoch1[:3], &vconfig.VbftBlockInfo{LastConfigBlockNum: 2}, nil, nil)
+	submitBlock(t, ldg, epoch1[:3], &vconfig.VbftBlockInfo{LastConfigBlockNum: 2, NewChainConfig: chainConfig(epoch2)},
+		key, value)
+	submitBlock(t, ldg, epoch2[:3], &vconfig.VbftBlockInfo{LastConfigBlockNum: 4}, nil, nil)
+
+	trusted, err := crossproof.EpochFromHeader(genesisBlock.Header.ToArray())
+	assert.Nil(t, err)
+	bundle, err := GetCrossStatesBundle(4, key, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(bundle.EpochHeaders))
+	merkleValue, epoch, err := crossproof.Verify(bundle, trusted)
+	assert.Nil(t, err)
+	assert.Equal(t, "unlock", merkleValue.MakeTxParam.Method)
+	assert.Equal(t, uint32(4), epoch.StartHeight)
+
+	// destination chain already trusts epoch 1
+	trusted, err = crossproof.EpochFromHeader(bundle.EpochHeaders[0])
+	assert.Nil(t, err)
+	bundle, err = GetCrossStatesBundle(4, key, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(bundle.EpochHeaders))
+	_, epoch, err = crossproof.Verify(bundle, trusted)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(4), epoch.StartHeight)
+}
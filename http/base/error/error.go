@@ -27,6 +27,7 @@ const (
 	SERVICE_CEILING    int64 = 41002
 	ILLEGAL_DATAFORMAT int64 = 41003
 	INVALID_VERSION    int64 = 41004
+	UNAUTHORIZED       int64 = 41005
 
 	INVALID_METHOD int64 = 42001
 	INVALID_PARAMS int64 = 42002
@@ -45,12 +46,43 @@ const (
 	PRE_EXEC_ERROR  int64 = 47002
 )
 
+// JSON-RPC 2.0 error codes, other errors keep the codes above as application defined codes
+const (
+	JSONRPC_PARSE_ERROR      int64 = -32700
+	JSONRPC_INVALID_REQUEST  int64 = -32600
+	JSONRPC_METHOD_NOT_FOUND int64 = -32601
+	JSONRPC_INVALID_PARAMS   int64 = -32602
+	JSONRPC_INTERNAL_ERROR   int64 = -32603
+)
+
+var JsonRpcErrMap = map[int64]string{
+	JSONRPC_PARSE_ERROR:      "Parse error",
+	JSONRPC_INVALID_REQUEST:  "Invalid Request",
+	JSONRPC_METHOD_NOT_FOUND: "Method not found",
+	JSONRPC_INVALID_PARAMS:   "Invalid params",
+	JSONRPC_INTERNAL_ERROR:   "Internal error",
+}
+
+// JsonRpcCode returns the JSON-RPC 2.0 error code of errcode
+func JsonRpcCode(errcode int64) int64 {
+	switch errcode {
+	case INVALID_METHOD:
+		return JSONRPC_METHOD_NOT_FOUND
+	case INVALID_PARAMS:
+		return JSONRPC_INVALID_PARAMS
+	case INTERNAL_ERROR:
+		return JSONRPC_INTERNAL_ERROR
+	}
+	return errcode
+}
+
 var ErrMap = map[int64]string{
 	SUCCESS:            "SUCCESS",
 	SESSION_EXPIRED:    "SESSION EXPIRED",
 	SERVICE_CEILING:    "SERVICE CEILING",
 	ILLEGAL_DATAFORMAT: "ILLEGAL DATAFORMAT",
 	INVALID_VERSION:    "INVALID VERSION",
+	UNAUTHORIZED:       "UNAUTHORIZED",
 
 	INVALID_METHOD: "INVALID METHOD",
 	INVALID_PARAMS: "INVALID PARAMS",
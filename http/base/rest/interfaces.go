@@ -21,14 +21,9 @@ package rest
 import (
 	"encoding/hex"
 	"github.com/polynetwork/poly/common"
-	"github.com/polynetwork/poly/common/config"
-	"github.com/polynetwork/poly/common/log"
-	scom "github.com/polynetwork/poly/core/store/common"
-	"github.com/polynetwork/poly/core/types"
-	ontErrors "github.com/polynetwork/poly/errors"
-	bactor "github.com/polynetwork/poly/http/base/actor"
 	bcomn "github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/http/base/service"
 	"strconv"
 )
 
@@ -39,27 +34,57 @@ type ApiServer interface {
 	Stop()
 }
 
+func responseError(err *service.Error) map[string]interface{} {
+	resp := ResponsePack(err.Code)
+	resp["Result"] = err.Desc
+	return resp
+}
+
+func getHeight(cmd map[string]interface{}, key string) (uint32, bool) {
+	param, ok := cmd[key].(string)
+	if !ok || len(param) == 0 {
+		return 0, false
+	}
+	height, err := strconv.ParseUint(param, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(height), true
+}
+
+func getHash(cmd map[string]interface{}, key string) (common.Uint256, bool) {
+	str, ok := cmd[key].(string)
+	if !ok || len(str) == 0 {
+		return common.UINT256_EMPTY, false
+	}
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return common.UINT256_EMPTY, false
+	}
+	return hash, true
+}
+
 // get node verison
 func GetNodeVersion(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
-	resp["Result"] = config.Version
+	resp["Result"] = service.GetNodeVersion()
 	return resp
 }
 
 // get networkid
 func GetNetworkId(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
-	resp["Result"] = config.DefConfig.P2PNode.NetworkId
+	resp["Result"] = service.GetNetworkId()
 	return resp
 }
 
 //get connection node count
 func GetConnectionCount(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	count, err := bactor.GetConnectionCnt()
+	count, err := service.GetConnectionCount()
 	if err != nil {
-		return ResponsePack(berr.INTERNAL_ERROR)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
 	resp["Result"] = count
 	return resp
 }
@@ -67,168 +92,102 @@ func GetConnectionCount(cmd map[string]interface{}) map[string]interface{} {
 //get block height
 func GetBlockHeight(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
-	height := bactor.GetCurrentBlockHeight()
-	resp["Result"] = height
+	resp["Result"] = service.GetBlockHeight()
 	return resp
 }
 
 //get block hash by height
 func GetBlockHash(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	param, ok := cmd["Height"].(string)
-	if !ok || len(param) == 0 {
+	height, ok := getHeight(cmd, "Height")
+	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	height, err := strconv.ParseInt(param, 10, 64)
+	hash, err := service.GetBlockHash(height)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	hash := bactor.GetBlockHashFromStore(uint32(height))
-	if hash == common.UINT256_EMPTY {
-		return ResponsePack(berr.UNKNOWN_BLOCK)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
 	resp["Result"] = hash.ToHexString()
 	return resp
 }
 
-func getBlock(hash common.Uint256, getTxBytes bool) (interface{}, int64) {
-	block, err := bactor.GetBlockFromStore(hash)
+func blockResponse(cmd map[string]interface{}, hash common.Uint256) map[string]interface{} {
+	block, err := service.GetBlockByHash(hash)
 	if err != nil {
-		return nil, berr.UNKNOWN_BLOCK
-	}
-	if block == nil {
-		return nil, berr.UNKNOWN_BLOCK
-	}
-	if block.Header == nil {
-		return nil, berr.UNKNOWN_BLOCK
+		return responseError(err)
 	}
-	if getTxBytes {
-		return common.ToHexString(block.ToArray()), berr.SUCCESS
+	resp := ResponsePack(berr.SUCCESS)
+	if raw, ok := cmd["Raw"].(string); ok && raw == "1" {
+		resp["Result"] = common.ToHexString(block.ToArray())
+	} else {
+		resp["Result"] = bcomn.GetBlockInfo(block)
 	}
-	return bcomn.GetBlockInfo(block), berr.SUCCESS
+	return resp
 }
 
 //get block by hash
 func GetBlockByHash(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	str := cmd["Hash"].(string)
-	if len(str) == 0 {
+	hash, ok := getHash(cmd, "Hash")
+	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	var getTxBytes = false
-	if raw, ok := cmd["Raw"].(string); ok && raw == "1" {
-		getTxBytes = true
+	return blockResponse(cmd, hash)
+}
+
+//get block by height
+func GetBlockByHeight(cmd map[string]interface{}) map[string]interface{} {
+	height, ok := getHeight(cmd, "Height")
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	var hash common.Uint256
-	hash, err := common.Uint256FromHexString(str)
+	hash, err := service.GetBlockHash(height)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
+		return responseError(err)
 	}
-	resp["Result"], resp["Error"] = getBlock(hash, getTxBytes)
-	return resp
+	return blockResponse(cmd, hash)
 }
 
 //get block height by transaction hash
 func GetBlockHeightByTxHash(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	str, ok := cmd["Hash"].(string)
-	if !ok || len(str) == 0 {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	hash, err := common.Uint256FromHexString(str)
-	if err != nil {
+	hash, ok := getHash(cmd, "Hash")
+	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	height, tx, err := bactor.GetTxnWithHeightByTxHash(hash)
+	height, err := service.GetBlockHeightByTxHash(hash)
 	if err != nil {
-		return ResponsePack(berr.INTERNAL_ERROR)
-	}
-	if tx == nil {
-		return ResponsePack(berr.INVALID_PARAMS)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
 	resp["Result"] = height
 	return resp
 }
 
 //get block transaction hashes by height
 func GetBlockTxsByHeight(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-
-	param, ok := cmd["Height"].(string)
-	if !ok || len(param) == 0 {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	height, err := strconv.ParseInt(param, 10, 64)
-	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	index := uint32(height)
-	hash := bactor.GetBlockHashFromStore(index)
-	if hash == common.UINT256_EMPTY {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	block, err := bactor.GetBlockFromStore(hash)
-	if err != nil {
-		return ResponsePack(berr.UNKNOWN_BLOCK)
-	}
-	resp["Result"] = bcomn.GetBlockTransactions(block)
-	return resp
-}
-
-//get block by height
-func GetBlockByHeight(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-
-	param, ok := cmd["Height"].(string)
+	height, ok := getHeight(cmd, "Height")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	if len(param) == 0 {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	var getTxBytes = false
-	if raw, ok := cmd["Raw"].(string); ok && raw == "1" {
-		getTxBytes = true
-	}
-	height, err := strconv.ParseInt(param, 10, 64)
+	block, err := service.GetBlockByHeight(height)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	index := uint32(height)
-	block, err := bactor.GetBlockByHeight(index)
-	if err != nil || block == nil {
-		return ResponsePack(berr.UNKNOWN_BLOCK)
-	}
-	if getTxBytes {
-		resp["Result"] = common.ToHexString(block.ToArray())
-	} else {
-		resp["Result"] = bcomn.GetBlockInfo(block)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = bcomn.GetBlockTransactions(block)
 	return resp
 }
 
 //get transaction by hash
 func GetTransactionByHash(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-
-	str, ok := cmd["Hash"].(string)
+	hash, ok := getHash(cmd, "Hash")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	hash, err := common.Uint256FromHexString(str)
+	tx, height, err := service.GetTransaction(hash)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	height, tx, err := bactor.GetTxnWithHeightByTxHash(hash)
-	if tx == nil {
-		return ResponsePack(berr.UNKNOWN_TRANSACTION)
-	}
-	if err != nil {
-		return ResponsePack(berr.UNKNOWN_TRANSACTION)
-	}
-	if tx == nil {
-		return ResponsePack(berr.UNKNOWN_TRANSACTION)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
 	if raw, ok := cmd["Raw"].(string); ok && raw == "1" {
 		resp["Result"] = common.ToHexString(tx.Raw)
 		return resp
@@ -241,71 +200,41 @@ func GetTransactionByHash(cmd map[string]interface{}) map[string]interface{} {
 
 //send raw transaction
 func SendRawTransaction(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-
 	str, ok := cmd["Data"].(string)
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	bys, err := common.HexToBytes(str)
+	raw, err := common.HexToBytes(str)
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-
-	txn, err := types.TransactionFromRawBytes(bys)
-	if err != nil {
-		return ResponsePack(berr.INVALID_TRANSACTION)
-	}
-	var hash common.Uint256
-	hash = txn.Hash()
-	log.Debugf("SendRawTransaction recv %s", hash.ToHexString())
-	if txn.TxType == types.Invoke || txn.TxType == types.Deploy {
-		if preExec, ok := cmd["PreExec"].(string); ok && preExec == "1" {
-			rst, err := bactor.PreExecuteContract(txn)
-			if err != nil {
-				log.Infof("PreExec: ", err)
-				resp = ResponsePack(berr.SMARTCODE_ERROR)
-				resp["Result"] = err.Error()
-				return resp
-			}
-			resp["Result"] = bcomn.ConvertPreExecuteResult(rst)
-			return resp
-		}
-	}
-	log.Debugf("SendRawTransaction send to txpool %s", hash.ToHexString())
-	if errCode, desc := bcomn.SendTxToPool(txn); errCode != ontErrors.ErrNoError {
-		resp["Error"] = int64(errCode)
-		resp["Result"] = desc
-		log.Warnf("SendRawTransaction verified %s error: %s", hash.ToHexString(), desc)
+	preExec, _ := cmd["PreExec"].(string)
+	hash, result, serr := service.SendRawTransaction(raw, preExec == "1")
+	if serr != nil {
+		return responseError(serr)
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	if result != nil {
+		resp["Result"] = bcomn.ConvertPreExecuteResult(result)
 		return resp
 	}
-	log.Debugf("SendRawTransaction verified %s", hash.ToHexString())
 	resp["Result"] = hash.ToHexString()
 	return resp
 }
 
 //get smartcontract event by height
 func GetSmartCodeEventTxsByHeight(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-
-	param, ok := cmd["Height"].(string)
+	height, ok := getHeight(cmd, "Height")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	if len(param) == 0 {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	height, err := strconv.ParseInt(param, 10, 64)
+	eventInfos, err := service.GetEventsByHeight(height)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
+		return responseError(err)
 	}
-	index := uint32(height)
-	eventInfos, err := bactor.GetEventNotifyByHeight(index)
-	if err != nil {
-		if scom.ErrNotFound == err {
-			return ResponsePack(berr.SUCCESS)
-		}
-		return ResponsePack(berr.INTERNAL_ERROR)
+	resp := ResponsePack(berr.SUCCESS)
+	if eventInfos == nil {
+		return resp
 	}
 	eInfos := make([]*bcomn.ExecuteNotify, 0, len(eventInfos))
 	for _, eventInfo := range eventInfos {
@@ -318,29 +247,17 @@ func GetSmartCodeEventTxsByHeight(cmd map[string]interface{}) map[string]interfa
 
 //get smartcontract event by transaction hash
 func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{} {
-	if !config.DefConfig.Common.EnableEventLog {
-		return ResponsePack(berr.INVALID_METHOD)
-	}
-
-	resp := ResponsePack(berr.SUCCESS)
-
-	str, ok := cmd["Hash"].(string)
+	hash, ok := getHash(cmd, "Hash")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	hash, err := common.Uint256FromHexString(str)
+	eventInfo, err := service.GetEventsByTxHash(hash)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	eventInfo, err := bactor.GetEventNotifyByTxHash(hash)
-	if err != nil {
-		if scom.ErrNotFound == err {
-			return ResponsePack(berr.SUCCESS)
-		}
-		return ResponsePack(berr.INTERNAL_ERROR)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
 	if eventInfo == nil {
-		return ResponsePack(berr.INVALID_TRANSACTION)
+		return resp
 	}
 	_, notify := bcomn.GetExecuteNotify(eventInfo)
 	resp["Result"] = notify
@@ -349,7 +266,6 @@ func GetSmartCodeEventByTxHash(cmd map[string]interface{}) map[string]interface{
 
 //get storage from contract
 func GetStorage(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
 	str, ok := cmd["Hash"].(string)
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
@@ -358,82 +274,66 @@ func GetStorage(cmd map[string]interface{}) map[string]interface{} {
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	key := cmd["Key"].(string)
+	key, ok := cmd["Key"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
 	item, err := common.HexToBytes(key)
 	if err != nil {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	value, err := bactor.GetStorageItem(address, item)
-	if err != nil {
-		if err == scom.ErrNotFound {
-			return ResponsePack(berr.SUCCESS)
-		}
-		return ResponsePack(berr.INTERNAL_ERROR)
+	value, serr := service.GetStorage(address, item)
+	if serr != nil {
+		return responseError(serr)
+	}
+	resp := ResponsePack(berr.SUCCESS)
+	if value != nil {
+		resp["Result"] = common.ToHexString(value)
 	}
-	resp["Result"] = common.ToHexString(value)
 	return resp
 }
 
 //get merkle proof by transaction hash
 func GetMerkleProof(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	bhStr, ok := cmd["BlockHeight"].(string)
+	height, ok := getHeight(cmd, "BlockHeight")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	rhStr, ok := cmd["RootHeight"].(string)
+	rootHeight, ok := getHeight(cmd, "RootHeight")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	height, err := strconv.ParseInt(bhStr, 10, 64)
+	proof, err := service.GetMerkleProof(height, rootHeight)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	rootHeight, err := strconv.ParseInt(rhStr, 10, 64)
-	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
-	}
-	if height >= rootHeight || height == 0 {
-		ResponsePack(berr.INVALID_PARAMS)
+		return responseError(err)
 	}
-	proof, err := bactor.GetMerkleProof(uint32(height), uint32(rootHeight))
-	if err != nil {
-		ResponsePack(berr.INTERNAL_ERROR)
-	}
-	resp["Result"] = bcomn.MerkleProof{"MerkleProof", hex.EncodeToString(proof)}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = bcomn.MerkleProof{Type: "MerkleProof", AuditPath: hex.EncodeToString(proof)}
 	return resp
 }
 
 //get memory pool transaction count
 func GetMemPoolTxCount(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	count, err := bactor.GetTxnCount()
+	count, err := service.GetMemPoolTxCount()
 	if err != nil {
-		return ResponsePack(berr.INTERNAL_ERROR)
+		return responseError(err)
 	}
+	resp := ResponsePack(berr.SUCCESS)
 	resp["Result"] = count
 	return resp
 }
 
 //get memory poll transaction state
 func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
-	resp := ResponsePack(berr.SUCCESS)
-	str, ok := cmd["Hash"].(string)
+	hash, ok := getHash(cmd, "Hash")
 	if !ok {
 		return ResponsePack(berr.INVALID_PARAMS)
 	}
-	hash, err := common.Uint256FromHexString(str)
+	info, err := service.GetMemPoolTxState(hash)
 	if err != nil {
-		return ResponsePack(berr.INVALID_PARAMS)
+		return responseError(err)
 	}
-	txEntry, err := bactor.GetTxFromPool(hash)
-	if err != nil {
-		return ResponsePack(berr.UNKNOWN_TRANSACTION)
-	}
-	attrs := []bcomn.TXNAttrInfo{}
-	for _, t := range txEntry.Attrs {
-		attrs = append(attrs, bcomn.TXNAttrInfo{t.Height, int(t.Type), int(t.ErrCode)})
-	}
-	resp["Result"] = bcomn.TXNEntryInfo{attrs}
+	resp := ResponsePack(berr.SUCCESS)
+	resp["Result"] = info
 	return resp
 }
@@ -20,23 +20,23 @@ package rpc
 
 import (
 	"encoding/hex"
-	"fmt"
 
 	"github.com/polynetwork/poly/common"
-	"github.com/polynetwork/poly/common/config"
-	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/consensus/vbft"
 	scom "github.com/polynetwork/poly/core/store/common"
-	"github.com/polynetwork/poly/core/types"
-	ontErrors "github.com/polynetwork/poly/errors"
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	bcomn "github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/http/base/service"
 )
 
+func responseError(err *service.Error) map[string]interface{} {
+	return responsePack(err.Code, err.Desc)
+}
+
 //get best block hash
 func GetBestBlockHash(params []interface{}) map[string]interface{} {
-	hash := bactor.CurrentBlockHash()
+	hash := service.GetBestBlockHash()
 	return responseSuccess(hash.ToHexString())
 }
 
@@ -48,19 +48,20 @@ func GetBlock(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
-	var err error
 	var hash common.Uint256
 	switch (params[0]).(type) {
 	// block height
 	case float64:
 		index := uint32(params[0].(float64))
-		hash = bactor.GetBlockHashFromStore(index)
-		if hash == common.UINT256_EMPTY {
+		var serr *service.Error
+		hash, serr = service.GetBlockHash(index)
+		if serr != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
 		// block hash
 	case string:
 		str := params[0].(string)
+		var err error
 		hash, err = common.Uint256FromHexString(str)
 		if err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
@@ -68,9 +69,9 @@ func GetBlock(params []interface{}) map[string]interface{} {
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	block, err := bactor.GetBlockFromStore(hash)
-	if err != nil {
-		return responsePack(berr.UNKNOWN_BLOCK, "unknown block")
+	block, serr := service.GetBlockByHash(hash)
+	if serr != nil {
+		return responseError(serr)
 	}
 	if len(params) >= 2 {
 		switch (params[1]).(type) {
@@ -88,7 +89,7 @@ func GetBlock(params []interface{}) map[string]interface{} {
 
 //get block height
 func GetBlockCount(params []interface{}) map[string]interface{} {
-	height := bactor.GetCurrentBlockHeight()
+	height := service.GetBlockHeight()
 	return responseSuccess(height + 1)
 }
 
@@ -107,9 +108,9 @@ func GetCrossStateRoot(params []interface{}) map[string]interface{} {
 	// block height
 	case float64:
 		height := uint32(params[0].(float64))
-		result, err := bactor.GetCrossStateRoot(height)
+		result, err := service.GetCrossStateRoot(height)
 		if err != nil {
-			return responsePack(berr.UNKNOWN_BLOCK, err.Error())
+			return responseError(err)
 		}
 		return responseSuccess(result)
 	default:
@@ -128,9 +129,9 @@ func GetBlockHash(params []interface{}) map[string]interface{} {
 	switch params[0].(type) {
 	case float64:
 		height := uint32(params[0].(float64))
-		hash := bactor.GetBlockHashFromStore(height)
-		if hash == common.UINT256_EMPTY {
-			return responsePack(berr.UNKNOWN_BLOCK, "")
+		hash, err := service.GetBlockHash(height)
+		if err != nil {
+			return responseError(err)
 		}
 		return responseSuccess(hash.ToHexString())
 	default:
@@ -140,10 +141,9 @@ func GetBlockHash(params []interface{}) map[string]interface{} {
 
 //get node connection count
 func GetConnectionCount(params []interface{}) map[string]interface{} {
-	count, err := bactor.GetConnectionCnt()
+	count, err := service.GetConnectionCount()
 	if err != nil {
-		log.Errorf("GetConnectionCount error:%s", err)
-		return responsePack(berr.INTERNAL_ERROR, false)
+		return responsePack(err.Code, false)
 	}
 	return responseSuccess(count)
 }
@@ -162,9 +162,9 @@ func GetRawMemPool(params []interface{}) map[string]interface{} {
 
 //get memory pool transaction count
 func GetMemPoolTxCount(params []interface{}) map[string]interface{} {
-	count, err := bactor.GetTxnCount()
+	count, err := service.GetMemPoolTxCount()
 	if err != nil {
-		return responsePack(berr.INTERNAL_ERROR, nil)
+		return responsePack(err.Code, nil)
 	}
 	return responseSuccess(count)
 }
@@ -181,15 +181,10 @@ func GetMemPoolTxState(params []interface{}) map[string]interface{} {
 		if err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
-		txEntry, err := bactor.GetTxFromPool(hash)
-		if err != nil {
-			return responsePack(berr.UNKNOWN_TRANSACTION, "unknown transaction")
-		}
-		attrs := []bcomn.TXNAttrInfo{}
-		for _, t := range txEntry.Attrs {
-			attrs = append(attrs, bcomn.TXNAttrInfo{t.Height, int(t.Type), int(t.ErrCode)})
+		info, serr := service.GetMemPoolTxState(hash)
+		if serr != nil {
+			return responseError(serr)
 		}
-		info := bcomn.TXNEntryInfo{attrs}
 		return responseSuccess(info)
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -203,24 +198,18 @@ func GetRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
-	var tx *types.Transaction
-	var height uint32
-	switch params[0].(type) {
-	case string:
-		str := params[0].(string)
-		hash, err := common.Uint256FromHexString(str)
-		if err != nil {
-			return responsePack(berr.INVALID_PARAMS, "")
-		}
-		h, t, err := bactor.GetTxnWithHeightByTxHash(hash)
-		if err != nil {
-			return responsePack(berr.UNKNOWN_TRANSACTION, fmt.Sprintf("unknown transaction:%s", err))
-		}
-		height = h
-		tx = t
-	default:
+	str, ok := params[0].(string)
+	if !ok {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	tx, height, serr := service.GetTransaction(hash)
+	if serr != nil {
+		return responseError(serr)
+	}
 
 	if len(params) >= 2 {
 		switch (params[1]).(type) {
@@ -270,78 +259,138 @@ func GetStorage(params []interface{}) map[string]interface{} {
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	value, err := bactor.GetStorageItem(address, key)
+	value, err := service.GetStorage(address, key)
 	if err != nil {
-		if err == scom.ErrNotFound {
-			return responseSuccess(nil)
-		}
-		return responsePack(berr.INVALID_PARAMS, "")
+		return responseError(err)
+	}
+	if value == nil {
+		return responseSuccess(nil)
 	}
 	return responseSuccess(common.ToHexString(value))
 }
 
-//send raw transaction
-// A JSON example for sendrawtransaction method as following:
-//   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
-func SendRawTransaction(params []interface{}) map[string]interface{} {
-	if len(params) < 1 {
+//get participation of consensus peers recorded by governance at the end of a view
+// A JSON example for getpeerperformance method as following, view is optional, latest recorded view if omitted:
+//   {"jsonrpc": "2.0", "method": "getpeerperformance", "params": [3], "id": 0}
+func GetPeerPerformance(params []interface{}) map[string]interface{} {
+	var view uint32
+	if len(params) > 0 {
+		switch params[0].(type) {
+		case float64:
+			view = uint32(params[0].(float64))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	info, err := service.GetPeerPerformance(view)
+	if err != nil {
+		return responseError(err)
+	}
+	return responseSuccess(info)
+}
+
+//get relayer work and claimable fee for a chain
+// A JSON example for getrelayerreward method as following:
+//   {"jsonrpc": "2.0", "method": "getrelayerreward", "params": ["relayer address in base58 or hex", 2], "id": 0}
+func GetRelayerReward(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
-	var hash common.Uint256
+	var address common.Address
+	var chainID uint64
 	switch params[0].(type) {
 	case string:
-		str := params[0].(string)
-		raw, err := common.HexToBytes(str)
+		var err error
+		address, err = bcomn.GetAddress(params[0].(string))
 		if err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
-		txn, err := types.TransactionFromRawBytes(raw)
-		if err != nil {
-			return responsePack(berr.INVALID_TRANSACTION, "")
-		}
-		hash = txn.Hash()
-		log.Debugf("SendRawTransaction recv %s", hash.ToHexString())
-		if txn.TxType == types.Invoke {
-			if len(params) > 1 {
-				preExec, ok := params[1].(float64)
-				if ok && preExec == 1 {
-					result, err := bactor.PreExecuteContract(txn)
-					if err != nil {
-						log.Infof("PreExec: ", err)
-						return responsePack(berr.SMARTCODE_ERROR, err.Error())
-					}
-					return responseSuccess(bcomn.ConvertPreExecuteResult(result))
-				}
-			}
-		}
-
-		log.Debugf("SendRawTransaction send to txpool %s", hash.ToHexString())
-		if errCode, desc := bcomn.SendTxToPool(txn); errCode != ontErrors.ErrNoError {
-			log.Warnf("SendRawTransaction verified %s error: %s", hash.ToHexString(), desc)
-			return responsePack(int64(errCode), desc)
-		}
-		log.Debugf("SendRawTransaction verified %s", hash.ToHexString())
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	switch params[1].(type) {
+	case float64:
+		chainID = uint64(params[1].(float64))
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	info, err := service.GetRelayerReward(address, chainID)
+	if err != nil {
+		return responseError(err)
+	}
+	return responseSuccess(info)
+}
+
+//send raw transaction
+// A JSON example for sendrawtransaction method as following:
+//   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
+func SendRawTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	raw, err := common.HexToBytes(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	preExec := false
+	if len(params) > 1 {
+		flag, ok := params[1].(float64)
+		preExec = ok && flag == 1
+	}
+	hash, result, serr := service.SendRawTransaction(raw, preExec)
+	if serr != nil {
+		return responseError(serr)
+	}
+	if result != nil {
+		return responseSuccess(bcomn.ConvertPreExecuteResult(result))
+	}
 	return responseSuccess(hash.ToHexString())
 }
 
+//simulate raw transaction against the state at height, current block if absent, returning the trace of execution
+func SimulateTransaction(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	raw, err := common.HexToBytes(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var height uint32
+	if len(params) > 1 && params[1] != nil {
+		h, ok := params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		height = uint32(h)
+	}
+	result, serr := service.SimulateTransaction(raw, height)
+	if serr != nil {
+		return responseError(serr)
+	}
+	return responseSuccess(result)
+}
+
 //get node version
 func GetNodeVersion(params []interface{}) map[string]interface{} {
-	return responseSuccess(config.Version)
+	return responseSuccess(service.GetNodeVersion())
 }
 
 // get networkid
 func GetNetworkId(params []interface{}) map[string]interface{} {
-	return responseSuccess(config.DefConfig.P2PNode.NetworkId)
+	return responseSuccess(service.GetNetworkId())
 }
 
 //get smartconstract event
 func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
-	if !config.DefConfig.Common.EnableEventLog {
-		return responsePack(berr.INVALID_METHOD, "")
-	}
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
@@ -350,12 +399,12 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	// block height
 	case float64:
 		height := uint32(params[0].(float64))
-		eventInfos, err := bactor.GetEventNotifyByHeight(height)
+		eventInfos, err := service.GetEventsByHeight(height)
 		if err != nil {
-			if err == scom.ErrNotFound {
-				return responseSuccess(nil)
-			}
-			return responsePack(berr.INTERNAL_ERROR, "")
+			return responseError(err)
+		}
+		if eventInfos == nil {
+			return responseSuccess(nil)
 		}
 		eInfos := make([]*bcomn.ExecuteNotify, 0, len(eventInfos))
 		for _, eventInfo := range eventInfos {
@@ -370,19 +419,81 @@ func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 		if err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
-		eventInfo, err := bactor.GetEventNotifyByTxHash(hash)
-		if err != nil {
-			if scom.ErrNotFound == err {
-				return responseSuccess(nil)
-			}
-			return responsePack(berr.INTERNAL_ERROR, "")
+		eventInfo, serr := service.GetEventsByTxHash(hash)
+		if serr != nil {
+			return responseError(serr)
+		}
+		if eventInfo == nil {
+			return responseSuccess(nil)
 		}
 		_, notify := bcomn.GetExecuteNotify(eventInfo)
 		return responseSuccess(notify)
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	return responsePack(berr.INVALID_PARAMS, "")
+}
+
+//get event logs by height range, contract, event name and chain ids
+func GetEventLogs(params []interface{}) map[string]interface{} {
+	filter := &scom.EventLogFilter{}
+	for i, param := range params {
+		if param == nil {
+			continue
+		}
+		var ok bool
+		switch i {
+		case 0, 1, 4, 5, 6:
+			var num float64
+			if num, ok = param.(float64); !ok || num < 0 {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			switch i {
+			case 0:
+				filter.FromHeight = uint32(num)
+			case 1:
+				filter.ToHeight = uint32(num)
+			case 4:
+				id := uint64(num)
+				filter.FromChainID = &id
+			case 5:
+				id := uint64(num)
+				filter.ToChainID = &id
+			case 6:
+				filter.Limit = int(num)
+			}
+		case 2:
+			var str string
+			if str, ok = param.(string); !ok {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			contract, err := common.AddressFromHexString(str)
+			if err != nil {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			filter.Contract = &contract
+		case 3:
+			if filter.Name, ok = param.(string); !ok {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+		case 7:
+			var str string
+			if str, ok = param.(string); !ok {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			cursor, err := hex.DecodeString(str)
+			if err != nil {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			if len(cursor) > 0 {
+				filter.Cursor = cursor
+			}
+		}
+	}
+	logs, cursor, err := service.GetEventLogs(filter)
+	if err != nil {
+		return responseError(err)
+	}
+	return responseSuccess(bcomn.GetEventLogs(logs, cursor, service.GetUnindexedEventLogs(filter)))
 }
 
 //get block height by transaction hash
@@ -399,9 +510,9 @@ func GetBlockHeightByTxHash(params []interface{}) map[string]interface{} {
 		if err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
-		height, _, err := bactor.GetTxnWithHeightByTxHash(hash)
-		if err != nil {
-			return responsePack(berr.INVALID_PARAMS, "")
+		height, serr := service.GetBlockHeightByTxHash(hash)
+		if serr != nil {
+			return responseError(serr)
 		}
 		return responseSuccess(height)
 	default:
@@ -423,14 +534,11 @@ func GetMerkleProof(params []interface{}) map[string]interface{} {
 	if !ok {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	if height >= rootHeight || height == 0 {
-		responsePack(berr.INVALID_PARAMS, fmt.Sprintf("Cannot get proof of block hash at height: %d when the block root is at height: %d", uint32(height), uint32(rootHeight)))
-	}
-	proof, err := bactor.GetMerkleProof(uint32(height), uint32(rootHeight))
+	proof, err := service.GetMerkleProof(uint32(height), uint32(rootHeight))
 	if err != nil {
-		return responsePack(berr.INTERNAL_ERROR, "")
+		return responseError(err)
 	}
-	return responseSuccess(bcomn.MerkleProof{"MerkleProof", hex.EncodeToString(proof)})
+	return responseSuccess(bcomn.MerkleProof{Type: "MerkleProof", AuditPath: hex.EncodeToString(proof)})
 }
 
 //get cross chain state proof
@@ -450,11 +558,45 @@ func GetCrossStatesProof(params []interface{}) map[string]interface{} {
 	if err != nil {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	proof, err := bactor.GetCrossStatesProof(uint32(height), key)
+	proof, serr := service.GetCrossStatesProof(uint32(height), key)
+	if serr != nil {
+		return responseError(serr)
+	}
+	return responseSuccess(bcomn.MerkleProof{Type: "CrossStatesProof", AuditPath: hex.EncodeToString(proof)})
+}
+
+//get a self contained proof of cross chain state, params are height, key and the optional
+//height of the config block starting the epoch the destination chain trusts
+func GetCrossStatesBundle(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	height, ok := params[0].(float64)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[1].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	key, err := hex.DecodeString(str)
 	if err != nil {
-		return responsePack(berr.INTERNAL_ERROR, err.Error())
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	// without a trusted epoch only the header is returned
+	trustedHeight := uint32(height) + 1
+	if len(params) > 2 {
+		trusted, ok := params[2].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		trustedHeight = uint32(trusted)
+	}
+	bundle, serr := service.GetCrossStatesBundle(uint32(height), key, trustedHeight)
+	if serr != nil {
+		return responseError(serr)
 	}
-	return responseSuccess(bcomn.MerkleProof{"CrossStatesProof", hex.EncodeToString(proof)})
+	return responseSuccess(bundle)
 }
 
 func GetHeaderByHeight(params []interface{}) map[string]interface{} {
@@ -465,9 +607,9 @@ func GetHeaderByHeight(params []interface{}) map[string]interface{} {
 	if !ok {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
-	header, err := bactor.GetHeaderByHeight(uint32(height))
+	header, err := service.GetHeaderByHeight(uint32(height))
 	if err != nil {
-		return responsePack(berr.INTERNAL_ERROR, err.Error())
+		return responseError(err)
 	}
 
 	return responseSuccess(hex.EncodeToString(header.ToArray()))
@@ -481,13 +623,9 @@ func GetBlockTxsByHeight(params []interface{}) map[string]interface{} {
 	switch params[0].(type) {
 	case float64:
 		height := uint32(params[0].(float64))
-		hash := bactor.GetBlockHashFromStore(height)
-		if hash == common.UINT256_EMPTY {
-			return responsePack(berr.INVALID_PARAMS, "")
-		}
-		block, err := bactor.GetBlockFromStore(hash)
+		block, err := service.GetBlockByHeight(height)
 		if err != nil {
-			return responsePack(berr.UNKNOWN_BLOCK, "")
+			return responseError(err)
 		}
 		return responseSuccess(bcomn.GetBlockTransactions(block))
 	default:
@@ -505,9 +643,9 @@ func GetStateMerkleRoot(params []interface{}) map[string]interface{} {
 	// block height
 	case float64:
 		index := uint32(params[0].(float64))
-		root, err := bactor.GetStateMerkleRoot(index)
+		root, err := service.GetStateMerkleRoot(index)
 		if err != nil {
-			return responsePack(berr.INTERNAL_ERROR, err.Error())
+			return responseError(err)
 		}
 
 		return responseSuccess(root.ToHexString())
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/vbft"
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	"github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
@@ -107,18 +108,52 @@ func StopConsensus(params []interface{}) map[string]interface{} {
 	return responsePack(berr.SUCCESS, true)
 }
 
+//SetDebugInfo set the log level, of the module like consensus/vbft if given,
+//level is a number or name like debug, level -1 make the module use the global level again
 func SetDebugInfo(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	var level int
 	switch params[0].(type) {
 	case float64:
-		level := params[0].(float64)
-		if err := log.Log.SetDebugLevel(int(level)); err != nil {
+		level = int(params[0].(float64))
+	case string:
+		var err error
+		if level, err = log.ParseLevel(params[0].(string)); err != nil {
 			return responsePack(berr.INVALID_PARAMS, "")
 		}
 	default:
 		return responsePack(berr.INVALID_PARAMS, "")
 	}
+	module := ""
+	if len(params) > 1 && params[1] != nil {
+		str, ok := params[1].(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		module = str
+	}
+	if module != "" {
+		if err := log.Log.SetModuleLevel(module, level); err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	} else if err := log.Log.SetDebugLevel(level); err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
 	return responsePack(berr.SUCCESS, true)
 }
+
+//GetRoundHistory return the vbft round history of latest count blocks, all kept blocks if count is 0 or omitted
+func GetRoundHistory(params []interface{}) map[string]interface{} {
+	count := uint32(0)
+	if len(params) > 0 && params[0] != nil {
+		switch params[0].(type) {
+		case float64:
+			count = uint32(params[0].(float64))
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	return responseSuccess(vbft.GetRoundTrace(count))
+}
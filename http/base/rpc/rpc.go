@@ -20,6 +20,7 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -28,29 +29,35 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/auth"
 	berr "github.com/polynetwork/poly/http/base/error"
 )
 
-func init() {
-	mainMux.m = make(map[string]func([]interface{}) map[string]interface{})
-}
-
-//an instance of the multiplexer
-var mainMux ServeMux
+//an instance of the multiplexer, serving the json rpc server
+var mainMux = NewServeMux()
 
 //multiplexer that keeps track of every function to be called on specific rpc call
 type ServeMux struct {
 	sync.RWMutex
 	m               map[string]func([]interface{}) map[string]interface{}
+	paramNames      map[string][]string
 	defaultFunction func(http.ResponseWriter, *http.Request)
+	auth            *auth.Authenticator //nil if methods are not access controlled
+}
+
+func NewServeMux() *ServeMux {
+	return &ServeMux{
+		m:          make(map[string]func([]interface{}) map[string]interface{}),
+		paramNames: make(map[string][]string),
+	}
 }
 
-//a function to register functions to be called for specific rpc calls
-func HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}) {
-	mainMux.Lock()
-	defer mainMux.Unlock()
-	mainMux.m[pattern] = handler
+//a function to register functions to be called for specific rpc calls,
+//paramNames are the names of the positional params, which allow calling the method with object params
+func HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}, paramNames ...string) {
+	mainMux.HandleFunc(pattern, handler, paramNames...)
 }
 
 //a function to be called if the request is not a HTTP JSON RPC call
@@ -58,22 +65,51 @@ func SetDefaultFunc(def func(http.ResponseWriter, *http.Request)) {
 	mainMux.defaultFunction = def
 }
 
+//SetAuthenticator set the access control of the methods of json rpc server
+func SetAuthenticator(authenticator *auth.Authenticator) {
+	mainMux.SetAuthenticator(authenticator)
+}
+
+func (this *ServeMux) HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}, paramNames ...string) {
+	this.Lock()
+	defer this.Unlock()
+	this.m[pattern] = handler
+	this.paramNames[pattern] = paramNames
+}
+
+func (this *ServeMux) SetAuthenticator(authenticator *auth.Authenticator) {
+	this.Lock()
+	defer this.Unlock()
+	this.auth = authenticator
+}
+
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  json.RawMessage `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Id      json.RawMessage `json:"id"`
+}
+
 // this is the function that should be called in order to answer an rpc call
 // should be registered like "http.HandleFunc("/", httpjsonrpc.Handle)"
 func Handle(w http.ResponseWriter, r *http.Request) {
-	mainMux.RLock()
-	defer mainMux.RUnlock()
+	mainMux.ServeHTTP(w, r)
+}
+
+func (this *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	this.RLock()
+	defer this.RUnlock()
 	if r.Method == "OPTIONS" {
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		w.Header().Set("content-type", "application/json;charset=utf-8")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		return
 	}
 	//JSON RPC commands should be POSTs
 	if r.Method != "POST" {
-		if mainMux.defaultFunction != nil {
+		if this.defaultFunction != nil {
 			log.Info("HTTP JSON RPC Handle - Method!=\"POST\"")
-			mainMux.defaultFunction(w, r)
+			this.defaultFunction(w, r)
 			return
 		} else {
 			log.Warn("HTTP JSON RPC Handle - Method!=\"POST\"")
@@ -83,9 +119,9 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 
 	//check if there is Request Body to read
 	if r.Body == nil {
-		if mainMux.defaultFunction != nil {
+		if this.defaultFunction != nil {
 			log.Info("HTTP JSON RPC Handle - Request body is nil")
-			mainMux.defaultFunction(w, r)
+			this.defaultFunction(w, r)
 			return
 		} else {
 			log.Warn("HTTP JSON RPC Handle - Request body is nil")
@@ -100,61 +136,208 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 		log.Error("HTTP JSON RPC Handle - ioutil.ReadAll: ", err)
 		return
 	}
-	request := make(map[string]interface{})
-	err = json.Unmarshal(body, &request)
+	cred, err := this.auth.Authenticate(r, body)
 	if err != nil {
-		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
-		return
+		log.Warnf("HTTP JSON RPC Handle - authenticate %s error: %s", r.RemoteAddr, err)
 	}
-	if request["method"] == nil {
-		log.Error("HTTP JSON RPC Handle - method not found: ")
-		return
+	writeResponse(w, this.handleBody(body, cred))
+}
+
+// handleBody answers a single request or a batch of cred, it returns nil when nothing is to be answered
+func (this *ServeMux) handleBody(body []byte, cred *auth.Credential) interface{} {
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		log.Error("HTTP JSON RPC Handle - invalid json")
+		return errorResponse(nil, berr.JSONRPC_PARSE_ERROR, nil)
 	}
-	method, ok := request["method"].(string)
-	if !ok {
+	if len(body) == 0 || body[0] != '[' {
+		return this.handleRequest(body, cred)
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+		return errorResponse(nil, berr.JSONRPC_INVALID_REQUEST, nil)
+	}
+	responses := make([]interface{}, 0, len(batch))
+	for _, raw := range batch {
+		if response := this.handleRequest(raw, cred); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// handleRequest answers one request object, it returns nil for notifications
+func (this *ServeMux) handleRequest(raw json.RawMessage, cred *auth.Credential) interface{} {
+	req := new(request)
+	if err := json.Unmarshal(raw, req); err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		return errorResponse(nil, berr.JSONRPC_INVALID_REQUEST, nil)
+	}
+	//a request without id is a notification, which is never answered, not even with an error,
+	//unless the legacy envelope is used
+	notification := req.Id == nil && !legacyEnvelope()
+	var method string
+	if err := json.Unmarshal(req.Method, &method); err != nil || method == "" {
 		log.Error("HTTP JSON RPC Handle - method is not string: ")
-		return
+		if notification {
+			return nil
+		}
+		return errorResponse(req.Id, berr.JSONRPC_INVALID_REQUEST, nil)
 	}
 	//get the corresponding function
-	function, ok := mainMux.m[method]
-	if ok {
-		response := function(request["params"].([]interface{}))
-		data, err := json.Marshal(map[string]interface{}{
+	function, ok := this.m[method]
+	if !ok {
+		//if the function does not exist
+		log.Warn("HTTP JSON RPC Handle - No function to call for ", method)
+		if notification {
+			return nil
+		}
+		return errorResponse(req.Id, berr.JSONRPC_METHOD_NOT_FOUND, "The called method was not found on the server")
+	}
+	params, err := positionalParams(req.Params, this.paramNames[method])
+	if err != nil {
+		if notification {
+			return nil
+		}
+		return errorResponse(req.Id, berr.JSONRPC_INVALID_PARAMS, err.Error())
+	}
+	var response map[string]interface{}
+	if this.auth.Allow(cred, method) {
+		response = call(method, function, params)
+	} else {
+		log.Warnf("HTTP JSON RPC Handle - %s not allowed", method)
+		response = responsePack(berr.UNAUTHORIZED, "")
+	}
+	if notification {
+		return nil
+	}
+	if legacyEnvelope() {
+		return map[string]interface{}{
 			"jsonrpc": "2.0",
 			"error":   response["error"],
 			"desc":    response["desc"],
 			"result":  response["result"],
-			"id":      request["id"],
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
+			"id":      req.Id,
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
-	} else {
-		//if the function does not exist
-		log.Warn("HTTP JSON RPC Handle - No function to call for ", request["method"])
-		data, err := json.Marshal(map[string]interface{}{
-			"error": berr.INVALID_METHOD,
-			"result": map[string]interface{}{
-				"code":    -32601,
-				"message": "Method not found",
-				"data":    "The called method was not found on the server",
+	}
+	if errcode, _ := response["error"].(int64); errcode != berr.SUCCESS {
+		code := berr.JsonRpcCode(errcode)
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"error": map[string]interface{}{
+				"code":    code,
+				"message": response["desc"],
+				"data":    response["result"],
 			},
-			"id": request["id"],
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
+			"id": idOrNull(req.Id),
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
 	}
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"result":  response["result"],
+		"id":      req.Id,
+	}
+}
+
+// positionalParams returns the params handlers take, object params are ordered by the registered names
+func positionalParams(raw json.RawMessage, names []string) ([]interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return []interface{}{}, nil
+	}
+	switch raw[0] {
+	case '[':
+		params := make([]interface{}, 0)
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		return params, nil
+	case '{':
+		named := make(map[string]interface{})
+		if err := json.Unmarshal(raw, &named); err != nil {
+			return nil, err
+		}
+		params := make([]interface{}, len(names))
+		n := 0
+		for i, name := range names {
+			if v, ok := named[name]; ok {
+				params[i] = v
+				n = i + 1
+				delete(named, name)
+			}
+		}
+		for name := range named {
+			return nil, fmt.Errorf("unknown param %s", name)
+		}
+		return params[:n], nil
+	}
+	return nil, fmt.Errorf("params must be an array or an object")
+}
+
+// call runs the handler, turning a panic into an internal error
+func call(method string, function func([]interface{}) map[string]interface{}, params []interface{}) (response map[string]interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("HTTP JSON RPC Handle - %s panic: %v", method, r)
+			response = responsePack(berr.INTERNAL_ERROR, "")
+		}
+	}()
+	return function(params)
+}
+
+func legacyEnvelope() bool {
+	return config.DefConfig.Rpc != nil && config.DefConfig.Rpc.LegacyEnvelope
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func errorResponse(id json.RawMessage, code int64, data interface{}) map[string]interface{} {
+	if legacyEnvelope() {
+		errcode := berr.INVALID_PARAMS
+		if code == berr.JSONRPC_METHOD_NOT_FOUND {
+			errcode = berr.INVALID_METHOD
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"error":   errcode,
+			"desc":    berr.ErrMap[errcode],
+			"result":  data,
+			"id":      id,
+		}
+	}
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": berr.JsonRpcErrMap[code],
+			"data":    data,
+		},
+		"id": idOrNull(id),
+	}
+}
+
+func writeResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("content-type", "application/json;charset=utf-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
+		return
+	}
+	w.Write(data)
 }
 
 // Call sends RPC request to server
@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/http/base/auth"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	HandleFunc("testadd", func(params []interface{}) map[string]interface{} {
+		if len(params) < 2 {
+			return responsePack(berr.INVALID_PARAMS, nil)
+		}
+		a, ok := params[0].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		b, ok := params[1].(float64)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		return responseSuccess(a + b)
+	}, "a", "b")
+	HandleFunc("testpanic", func(params []interface{}) map[string]interface{} {
+		return responseSuccess(params[5])
+	})
+}
+
+func handle(t *testing.T, body string) interface{} {
+	response := mainMux.handleBody([]byte(body), nil)
+	if response == nil {
+		return nil
+	}
+	data, err := json.Marshal(response)
+	assert.Nil(t, err)
+	var decoded interface{}
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func errorCode(response interface{}) float64 {
+	return response.(map[string]interface{})["error"].(map[string]interface{})["code"].(float64)
+}
+
+func TestHandleBody(t *testing.T) {
+	response := handle(t, `{"jsonrpc":"2.0","method":"testadd","params":[1,2],"id":1}`)
+	assert.Equal(t, map[string]interface{}{"jsonrpc": "2.0", "result": float64(3), "id": float64(1)}, response)
+
+	response = handle(t, `{"jsonrpc":"2.0","method":"testadd","params":{"b":2,"a":5},"id":"x"}`)
+	assert.Equal(t, float64(7), response.(map[string]interface{})["result"])
+
+	assert.Equal(t, float64(berr.JSONRPC_INVALID_PARAMS), errorCode(handle(t, `{"jsonrpc":"2.0","method":"testadd","params":{"c":1},"id":1}`)))
+	assert.Equal(t, float64(berr.JSONRPC_INVALID_PARAMS), errorCode(handle(t, `{"jsonrpc":"2.0","method":"testadd","id":1}`)))
+	assert.Equal(t, float64(berr.JSONRPC_INTERNAL_ERROR), errorCode(handle(t, `{"jsonrpc":"2.0","method":"testpanic","id":1}`)))
+	assert.Equal(t, float64(berr.JSONRPC_METHOD_NOT_FOUND), errorCode(handle(t, `{"jsonrpc":"2.0","method":"none","id":1}`)))
+	assert.Equal(t, float64(berr.JSONRPC_INVALID_REQUEST), errorCode(handle(t, `{"jsonrpc":"2.0","method":1,"id":1}`)))
+	assert.Equal(t, float64(berr.JSONRPC_PARSE_ERROR), errorCode(handle(t, `{"jsonrpc":"2.0",`)))
+	assert.Equal(t, float64(berr.JSONRPC_INVALID_REQUEST), errorCode(handle(t, `[]`)))
+	assert.Nil(t, handle(t, `{"jsonrpc":"2.0","method":"testadd","params":[1,2]}`))
+
+	// notifications are not answered even if they fail
+	assert.Nil(t, handle(t, `{"jsonrpc":"2.0","method":"none"}`))
+	assert.Nil(t, handle(t, `{"jsonrpc":"2.0","method":1}`))
+	assert.Nil(t, handle(t, `{"jsonrpc":"2.0","method":"testadd","params":{"c":1}}`))
+	assert.Nil(t, handle(t, `{"jsonrpc":"2.0","method":"testpanic"}`))
+}
+
+func TestHandleBatch(t *testing.T) {
+	response := handle(t, `[
+		{"jsonrpc":"2.0","method":"testadd","params":[1,2],"id":1},
+		{"jsonrpc":"2.0","method":"testadd","params":[1,2]},
+		1,
+		{"jsonrpc":"2.0","method":"none","id":3}
+	]`)
+	responses := response.([]interface{})
+	assert.Equal(t, 3, len(responses))
+	assert.Equal(t, float64(3), responses[0].(map[string]interface{})["result"])
+	assert.Equal(t, float64(berr.JSONRPC_INVALID_REQUEST), errorCode(responses[1]))
+	assert.Equal(t, float64(berr.JSONRPC_METHOD_NOT_FOUND), errorCode(responses[2]))
+
+	assert.Nil(t, handle(t, `[{"jsonrpc":"2.0","method":"testadd","params":[1,2]}]`))
+	assert.Nil(t, handle(t, `[
+		{"jsonrpc":"2.0","method":"none"},
+		{"jsonrpc":"2.0","method":1},
+		{"jsonrpc":"2.0","method":"testadd","params":{"c":1}}
+	]`))
+
+	response = handle(t, `[
+		{"jsonrpc":"2.0","method":"none"},
+		{"jsonrpc":"2.0","method":"none","id":2}
+	]`)
+	responses = response.([]interface{})
+	assert.Equal(t, 1, len(responses))
+	assert.Equal(t, float64(2), responses[0].(map[string]interface{})["id"])
+}
+
+func TestHandleLegacyEnvelope(t *testing.T) {
+	config.DefConfig.Rpc.LegacyEnvelope = true
+	defer func() { config.DefConfig.Rpc.LegacyEnvelope = false }()
+
+	response := handle(t, `{"jsonrpc":"2.0","method":"testadd","params":[1],"id":1}`)
+	assert.Equal(t, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"error":   float64(berr.INVALID_PARAMS),
+		"desc":    berr.ErrMap[berr.INVALID_PARAMS],
+		"result":  nil,
+		"id":      float64(1),
+	}, response)
+}
+
+func TestHandleAuth(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(&auth.Config{
+		Protected:   []string{"testadd"},
+		Credentials: []*auth.Credential{{Name: "ops", Scheme: auth.SCHEME_BEARER, Token: "token", Methods: []string{"testadd"}}},
+	})
+	assert.Nil(t, err)
+	mux := NewServeMux()
+	mux.HandleFunc("testadd", mainMux.m["testadd"], "a", "b")
+	mux.SetAuthenticator(authenticator)
+
+	body := `{"jsonrpc":"2.0","method":"testadd","params":[1,2],"id":1}`
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), `"code":41005`)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer token")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), `"result":3`)
+}
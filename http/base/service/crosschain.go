@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/crossproof"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+)
+
+//GetCrossStateRoot return the root of cross chain states of block at height
+func GetCrossStateRoot(height uint32) (common.Uint256, *Error) {
+	root, err := bactor.GetCrossStateRoot(height)
+	if err != nil {
+		return common.UINT256_EMPTY, newError(berr.UNKNOWN_BLOCK, err.Error())
+	}
+	return root, nil
+}
+
+//GetCrossStatesProof return the audit path of cross chain state stored under key at height
+func GetCrossStatesProof(height uint32, key []byte) ([]byte, *Error) {
+	proof, err := bactor.GetCrossStatesProof(height, key)
+	if err != nil {
+		return nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return proof, nil
+}
+
+//GetCrossStatesBundle return a self contained proof of cross chain state stored under key at height,
+//for a destination chain trusting the epoch started at trustedHeight
+func GetCrossStatesBundle(height uint32, key []byte, trustedHeight uint32) (*crossproof.Bundle, *Error) {
+	bundle, err := bcomn.GetCrossStatesBundle(height, key, trustedHeight)
+	if err != nil {
+		return nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return bundle, nil
+}
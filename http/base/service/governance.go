@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+)
+
+//GetPeerPerformance return participation of consensus peers recorded at the end of view,
+//latest recorded view if view is 0
+func GetPeerPerformance(view uint32) (*bcomn.PeerPerformanceInfo, *Error) {
+	info, err := bcomn.GetPeerPerformanceInfo(view)
+	if err != nil {
+		log.Errorf("GetPeerPerformance error:%s", err)
+		return nil, newError(berr.INTERNAL_ERROR, "")
+	}
+	return info, nil
+}
+
+//GetRelayerReward return work and claimable fee of relayer for a chain
+func GetRelayerReward(address common.Address, chainID uint64) (*bcomn.RelayerRewardInfo, *Error) {
+	info, err := bcomn.GetRelayerRewardInfo(address, chainID)
+	if err != nil {
+		log.Errorf("GetRelayerReward error:%s", err)
+		return nil, newError(berr.INTERNAL_ERROR, "")
+	}
+	return info, nil
+}
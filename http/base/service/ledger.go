@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/native/event"
+)
+
+//GetBlockHeight return the height of current block
+func GetBlockHeight() uint32 {
+	return bactor.GetCurrentBlockHeight()
+}
+
+//GetBestBlockHash return the hash of current block
+func GetBestBlockHash() common.Uint256 {
+	return bactor.CurrentBlockHash()
+}
+
+//GetBlockHash return the hash of block at height
+func GetBlockHash(height uint32) (common.Uint256, *Error) {
+	hash := bactor.GetBlockHashFromStore(height)
+	if hash == common.UINT256_EMPTY {
+		return common.UINT256_EMPTY, newError(berr.UNKNOWN_BLOCK, "")
+	}
+	return hash, nil
+}
+
+//GetBlockByHash return block by block hash
+func GetBlockByHash(hash common.Uint256) (*types.Block, *Error) {
+	block, err := bactor.GetBlockFromStore(hash)
+	if err != nil || block == nil || block.Header == nil {
+		return nil, newError(berr.UNKNOWN_BLOCK, "unknown block")
+	}
+	return block, nil
+}
+
+//GetBlockByHeight return block at height
+func GetBlockByHeight(height uint32) (*types.Block, *Error) {
+	hash, err := GetBlockHash(height)
+	if err != nil {
+		return nil, err
+	}
+	return GetBlockByHash(hash)
+}
+
+//GetHeaderByHeight return block header at height
+func GetHeaderByHeight(height uint32) (*types.Header, *Error) {
+	header, err := bactor.GetHeaderByHeight(height)
+	if err != nil {
+		return nil, newError(berr.UNKNOWN_BLOCK, err.Error())
+	}
+	return header, nil
+}
+
+//GetTransaction return transaction and the height of block it packed in by transaction hash
+func GetTransaction(hash common.Uint256) (*types.Transaction, uint32, *Error) {
+	height, tx, err := bactor.GetTxnWithHeightByTxHash(hash)
+	if err != nil {
+		return nil, 0, newError(berr.UNKNOWN_TRANSACTION, fmt.Sprintf("unknown transaction:%s", err))
+	}
+	if tx == nil {
+		return nil, 0, newError(berr.UNKNOWN_TRANSACTION, "unknown transaction")
+	}
+	return tx, height, nil
+}
+
+//GetBlockHeightByTxHash return the height of block the transaction packed in
+func GetBlockHeightByTxHash(hash common.Uint256) (uint32, *Error) {
+	_, height, err := GetTransaction(hash)
+	return height, err
+}
+
+//GetStorage return the value of key in contract storage, nil if not found
+func GetStorage(address common.Address, key []byte) ([]byte, *Error) {
+	value, err := bactor.GetStorageItem(address, key)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return value, nil
+}
+
+//GetStateMerkleRoot return the state merkle root of block at height
+func GetStateMerkleRoot(height uint32) (common.Uint256, *Error) {
+	root, err := bactor.GetStateMerkleRoot(height)
+	if err != nil {
+		return common.UINT256_EMPTY, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return root, nil
+}
+
+//GetMerkleProof return the audit path proving the block hash at height by the block root at rootHeight
+func GetMerkleProof(height, rootHeight uint32) ([]byte, *Error) {
+	if height >= rootHeight || height == 0 {
+		return nil, newError(berr.INVALID_PARAMS, fmt.Sprintf("Cannot get proof of block hash at height: %d when the block root is at height: %d", height, rootHeight))
+	}
+	proof, err := bactor.GetMerkleProof(height, rootHeight)
+	if err != nil {
+		return nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return proof, nil
+}
+
+//GetEventsByHeight return the event notifies of transactions in block at height
+func GetEventsByHeight(height uint32) ([]*event.ExecuteNotify, *Error) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return nil, newError(berr.INVALID_METHOD, "")
+	}
+	notifies, err := bactor.GetEventNotifyByHeight(height)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return notifies, nil
+}
+
+//GetEventsByTxHash return the event notify of transaction, nil if not found
+func GetEventsByTxHash(hash common.Uint256) (*event.ExecuteNotify, *Error) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return nil, newError(berr.INVALID_METHOD, "")
+	}
+	notify, err := bactor.GetEventNotifyByTxHash(hash)
+	if err != nil {
+		if err == scom.ErrNotFound {
+			return nil, nil
+		}
+		return nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return notify, nil
+}
+
+//GetEventLogs return the event logs matching filter and the cursor of the next page,
+//a ToHeight of 0 is the current block height
+func GetEventLogs(filter *scom.EventLogFilter) ([]*scom.EventLog, []byte, *Error) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return nil, nil, newError(berr.INVALID_METHOD, "")
+	}
+	if filter.ToHeight == 0 {
+		filter.ToHeight = bactor.GetCurrentBlockHeight()
+	}
+	logs, cursor, err := bactor.GetEventLogs(filter)
+	if err != nil {
+		return nil, nil, newError(berr.INTERNAL_ERROR, err.Error())
+	}
+	return logs, cursor, nil
+}
+
+//GetUnindexedEventLogs return the heights within filter whose event logs are not indexed yet while the index is
+//rebuilt, so the event logs returned for them are incomplete, nil if all are indexed
+func GetUnindexedEventLogs(filter *scom.EventLogFilter) *scom.HeightRange {
+	gap := bactor.GetEventLogIndexGap()
+	if gap == nil || gap.From > filter.ToHeight || gap.To < filter.FromHeight {
+		return nil
+	}
+	if gap.From < filter.FromHeight {
+		gap.From = filter.FromHeight
+	}
+	if gap.To > filter.ToHeight {
+		gap.To = filter.ToHeight
+	}
+	return gap
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	berr "github.com/polynetwork/poly/http/base/error"
+)
+
+//GetNodeVersion return the version of node
+func GetNodeVersion() string {
+	return config.Version
+}
+
+//GetNetworkId return the network id of node
+func GetNetworkId() uint32 {
+	return config.DefConfig.P2PNode.NetworkId
+}
+
+//GetConnectionCount return the count of connected peers
+func GetConnectionCount() (uint32, *Error) {
+	count, err := bactor.GetConnectionCnt()
+	if err != nil {
+		log.Errorf("GetConnectionCount error:%s", err)
+		return 0, newError(berr.INTERNAL_ERROR, "")
+	}
+	return count, nil
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package service implements the queries shared by the rpc, restful, websocket and grpc servers,
+// the servers only parse their requests and format the results
+package service
+
+import (
+	berr "github.com/polynetwork/poly/http/base/error"
+)
+
+// Error of a query, Code is one of the error codes of http/base/error
+type Error struct {
+	Code int64
+	Desc string
+}
+
+func (this *Error) Error() string {
+	if this.Desc != "" {
+		return this.Desc
+	}
+	return berr.ErrMap[this.Code]
+}
+
+func newError(code int64, desc string) *Error {
+	return &Error{Code: code, Desc: desc}
+}
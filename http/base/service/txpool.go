@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/types"
+	ontErrors "github.com/polynetwork/poly/errors"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+	cstate "github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/native/trace"
+)
+
+//SendRawTransaction send the serialized transaction to txpool and return its hash,
+//the transaction is only executed against current state and not sent if preExec
+func SendRawTransaction(raw []byte, preExec bool) (common.Uint256, *cstate.PreExecResult, *Error) {
+	txn, err := types.TransactionFromRawBytes(raw)
+	if err != nil {
+		return common.UINT256_EMPTY, nil, newError(berr.INVALID_TRANSACTION, "")
+	}
+	hash := txn.Hash()
+	log.Debugf("SendRawTransaction recv %s", hash.ToHexString())
+	if preExec && (txn.TxType == types.Invoke || txn.TxType == types.Deploy) {
+		result, err := bactor.PreExecuteContract(txn)
+		if err != nil {
+			log.Infof("PreExec: %s", err)
+			return hash, nil, newError(berr.SMARTCODE_ERROR, err.Error())
+		}
+		return hash, result, nil
+	}
+	log.Debugf("SendRawTransaction send to txpool %s", hash.ToHexString())
+	if errCode, desc := bcomn.SendTxToPool(txn); errCode != ontErrors.ErrNoError {
+		log.Warnf("SendRawTransaction verified %s error: %s", hash.ToHexString(), desc)
+		return hash, nil, newError(int64(errCode), desc)
+	}
+	log.Debugf("SendRawTransaction verified %s", hash.ToHexString())
+	return hash, nil, nil
+}
+
+//SimulateTransaction execute the serialized transaction against the state at height without sending it,
+//returning the trace of execution, a failed execution is traced but not an error
+func SimulateTransaction(raw []byte, height uint32) (*trace.Result, *Error) {
+	txn, err := types.TransactionFromRawBytes(raw)
+	if err != nil {
+		return nil, newError(berr.INVALID_TRANSACTION, "")
+	}
+	if txn.TxType != types.Invoke {
+		return nil, newError(berr.INVALID_TRANSACTION, "only invoke transaction can be simulated")
+	}
+	if height != 0 && config.DefConfig.Common.StateUndoBlocks == 0 {
+		return nil, newError(berr.INVALID_PARAMS, "states of previous blocks are not kept, only height 0 is accepted")
+	}
+	if current := bactor.GetCurrentBlockHeight(); height > current {
+		return nil, newError(berr.INVALID_PARAMS, fmt.Sprintf("height %d is beyond current height %d", height, current))
+	}
+	result, err := bactor.SimulateTransaction(txn, height)
+	if err != nil {
+		return nil, newError(berr.SMARTCODE_ERROR, err.Error())
+	}
+	return result, nil
+}
+
+//GetMemPoolTxCount return the count of transactions in txpool
+func GetMemPoolTxCount() ([]uint32, *Error) {
+	count, err := bactor.GetTxnCount()
+	if err != nil {
+		return nil, newError(berr.INTERNAL_ERROR, "")
+	}
+	return count, nil
+}
+
+//GetMemPoolTxState return the verify results of transaction in txpool
+func GetMemPoolTxState(hash common.Uint256) (*bcomn.TXNEntryInfo, *Error) {
+	txEntry, err := bactor.GetTxFromPool(hash)
+	if err != nil {
+		return nil, newError(berr.UNKNOWN_TRANSACTION, "unknown transaction")
+	}
+	attrs := []bcomn.TXNAttrInfo{}
+	for _, t := range txEntry.Attrs {
+		attrs = append(attrs, bcomn.TXNAttrInfo{Height: t.Height, Type: int(t.Type), ErrCode: int(t.ErrCode)})
+	}
+	return &bcomn.TXNEntryInfo{State: attrs}, nil
+}
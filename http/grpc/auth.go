@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/auth"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+//methodName return the lower cased method name of grpc full method, as the rpc methods are named
+func methodName(fullMethod string) string {
+	return strings.ToLower(fullMethod[strings.LastIndex(fullMethod, "/")+1:])
+}
+
+//RequestBody return the body hmac signatures of grpc requests sign, the deterministic protobuf encoding of req
+func RequestBody(req interface{}) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, nil
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
+//authorize check the authorization metadata of ctx for method with request message req, hmac signatures
+//sign a POST request to the full method path with the body returned by RequestBody
+func authorize(ctx context.Context, authenticator *auth.Authenticator, fullMethod string, req interface{}) error {
+	method := methodName(fullMethod)
+	if !authenticator.Protected(method) {
+		return nil
+	}
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	body, err := RequestBody(req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "encode request error: %s", err)
+	}
+	cred, err := authenticator.AuthenticateHeader(authorization, "POST", fullMethod, body, time.Now())
+	if err != nil {
+		log.Warnf("grpc authenticate error: %s", err)
+	}
+	if cred == nil {
+		return status.Errorf(codes.Unauthenticated, "%s requires authorization", method)
+	}
+	if !authenticator.Allow(cred, method) {
+		return status.Errorf(codes.PermissionDenied, "%s not allowed for %s", method, cred.Name)
+	}
+	return nil
+}
+
+func unaryAuthInterceptor(authenticator *auth.Authenticator) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, authenticator, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+//authStream authorize a protected server streaming call when its request message is received
+type authStream struct {
+	grpclib.ServerStream
+	authenticator *auth.Authenticator
+	fullMethod    string
+	authorized    bool
+}
+
+func (this *authStream) RecvMsg(m interface{}) error {
+	if err := this.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if this.authorized {
+		return nil
+	}
+	if err := authorize(this.Context(), this.authenticator, this.fullMethod, m); err != nil {
+		return err
+	}
+	this.authorized = true
+	return nil
+}
+
+func streamAuthInterceptor(authenticator *auth.Authenticator) grpclib.StreamServerInterceptor {
+	return func(srv interface{}, ss grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		if !authenticator.Protected(methodName(info.FullMethod)) {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authStream{ServerStream: ss, authenticator: authenticator, fullMethod: info.FullMethod})
+	}
+}
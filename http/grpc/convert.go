@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"encoding/json"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common/log"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"github.com/polynetwork/poly/native/event"
+)
+
+func convertHeader(header *types.Header) *pb.Header {
+	hash := header.Hash()
+	bookkeepers := make([][]byte, 0, len(header.Bookkeepers))
+	for _, pubKey := range header.Bookkeepers {
+		bookkeepers = append(bookkeepers, keypair.SerializePublicKey(pubKey))
+	}
+	return &pb.Header{
+		Version:          header.Version,
+		ChainId:          header.ChainID,
+		PrevBlockHash:    header.PrevBlockHash.ToHexString(),
+		TransactionsRoot: header.TransactionsRoot.ToHexString(),
+		CrossStateRoot:   header.CrossStateRoot.ToHexString(),
+		BlockRoot:        header.BlockRoot.ToHexString(),
+		Timestamp:        header.Timestamp,
+		Height:           header.Height,
+		ConsensusData:    header.ConsensusData,
+		ConsensusPayload: header.ConsensusPayload,
+		NextBookkeeper:   header.NextBookkeeper.ToBase58(),
+		Bookkeepers:      bookkeepers,
+		SigData:          header.SigData,
+		Hash:             hash.ToHexString(),
+	}
+}
+
+//convertBlock convert block with hashes of its transactions, and the serialized block if raw
+func convertBlock(block *types.Block, raw bool) *pb.Block {
+	result := &pb.Block{
+		Header:       convertHeader(block.Header),
+		Transactions: make([]string, 0, len(block.Transactions)),
+	}
+	for _, tx := range block.Transactions {
+		hash := tx.Hash()
+		result.Transactions = append(result.Transactions, hash.ToHexString())
+	}
+	if raw {
+		result.Raw = block.ToArray()
+	}
+	return result
+}
+
+func convertTransaction(tx *types.Transaction, height uint32) *pb.Transaction {
+	hash := tx.Hash()
+	return &pb.Transaction{
+		Hash:    hash.ToHexString(),
+		Height:  height,
+		Version: uint32(tx.Version),
+		TxType:  uint32(tx.TxType),
+		Nonce:   tx.Nonce,
+		ChainId: tx.ChainID,
+		Payer:   tx.Payer.ToBase58(),
+		Raw:     tx.Raw,
+	}
+}
+
+//encodeStates encode states of event notify to json, as the rpc and restful servers return them
+func encodeStates(states interface{}) string {
+	data, err := json.Marshal(states)
+	if err != nil {
+		log.Warnf("grpc encodeStates error:%s", err)
+		return ""
+	}
+	return string(data)
+}
+
+func convertNotifyEvents(notify []*event.NotifyEventInfo) []*pb.NotifyEvent {
+	result := make([]*pb.NotifyEvent, 0, len(notify))
+	for _, n := range notify {
+		result = append(result, &pb.NotifyEvent{
+			ContractAddress: n.ContractAddress.ToHexString(),
+			States:          encodeStates(n.States),
+		})
+	}
+	return result
+}
+
+func convertExecuteNotify(notify *event.ExecuteNotify) *pb.ExecuteNotify {
+	return &pb.ExecuteNotify{
+		TxHash:      notify.TxHash.ToHexString(),
+		State:       uint32(notify.State),
+		GasConsumed: notify.GasConsumed,
+		Notify:      convertNotifyEvents(notify.Notify),
+	}
+}
+
+func convertEventLog(l *scom.EventLog) *pb.EventLog {
+	return &pb.EventLog{
+		Height:          l.Height,
+		TxHash:          l.TxHash.ToHexString(),
+		Index:           l.Index,
+		ContractAddress: l.Notify.ContractAddress.ToHexString(),
+		States:          encodeStates(l.Notify.States),
+	}
+}
+
+func convertEventLogFilter(req *pb.EventLogFilter) (*scom.EventLogFilter, error) {
+	filter := &scom.EventLogFilter{
+		FromHeight: req.FromHeight,
+		ToHeight:   req.ToHeight,
+		Name:       req.Name,
+		Limit:      int(req.Limit),
+		Cursor:     req.Cursor,
+	}
+	if req.Contract != "" {
+		address, err := parseAddress(req.Contract)
+		if err != nil {
+			return nil, err
+		}
+		filter.Contract = &address
+	}
+	if req.FromChainId != nil {
+		id := req.FromChainId.Id
+		filter.FromChainID = &id
+	}
+	if req.ToChainId != nil {
+		id := req.ToChainId.Id
+		filter.ToChainID = &id
+	}
+	return filter, nil
+}
+
+func convertPeerPerformance(info *bcomn.PeerPerformanceInfo) *pb.PeerPerformanceInfo {
+	result := &pb.PeerPerformanceInfo{
+		View:         info.View,
+		Performances: make([]*pb.PeerPerformance, 0, len(info.Performances)),
+	}
+	if info.Policy != nil {
+		result.Policy = &pb.PerformancePolicy{
+			MinExpected:   info.Policy.MinExpected,
+			MaxMissedRate: info.Policy.MaxMissedRate,
+			OfflineEpochs: info.Policy.OfflineEpochs,
+		}
+	}
+	for _, p := range info.Performances {
+		result.Performances = append(result.Performances, &pb.PeerPerformance{
+			PeerPubkey:         p.PeerPubkey,
+			ProposedBlocks:     p.ProposedBlocks,
+			MissedProposals:    p.MissedProposals,
+			EndorsedBlocks:     p.EndorsedBlocks,
+			MissedEndorsements: p.MissedEndorsements,
+			OfflineEpochs:      p.OfflineEpochs,
+		})
+	}
+	return result
+}
@@ -0,0 +1,1032 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// PolyClient is the client API for Poly service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PolyClient interface {
+	// GetVersion returns the version of node
+	GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Version, error)
+	// GetNetworkId returns the network id of node
+	GetNetworkId(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NetworkId, error)
+	// GetConnectionCount returns the count of connected peers
+	GetConnectionCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConnectionCount, error)
+	// GetBlockHeight returns the height of current block
+	GetBlockHeight(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockHeight, error)
+	// GetBlockHash returns the hash of block at height
+	GetBlockHash(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Hash, error)
+	// GetBlock returns block by hash, or by height if hash is empty
+	GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// GetHeader returns block header at height
+	GetHeader(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Header, error)
+	// GetTransaction returns transaction by hash
+	GetTransaction(ctx context.Context, in *Hash, opts ...grpc.CallOption) (*Transaction, error)
+	// GetStorage returns the value of key in contract storage
+	GetStorage(ctx context.Context, in *StorageRequest, opts ...grpc.CallOption) (*Storage, error)
+	// GetStateMerkleRoot returns the state merkle root of block at height
+	GetStateMerkleRoot(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Hash, error)
+	// GetMerkleProof returns the audit path proving the block hash at height by the block root at root height
+	GetMerkleProof(ctx context.Context, in *MerkleProofRequest, opts ...grpc.CallOption) (*MerkleProof, error)
+	// GetSmartCodeEvents returns the event notifies of transactions in block at height
+	GetSmartCodeEvents(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*ExecuteNotifies, error)
+	// GetSmartCodeEvent returns the event notify of transaction
+	GetSmartCodeEvent(ctx context.Context, in *Hash, opts ...grpc.CallOption) (*ExecuteNotify, error)
+	// GetEventLogs returns a page of event logs matching filter
+	GetEventLogs(ctx context.Context, in *EventLogFilter, opts ...grpc.CallOption) (*EventLogs, error)
+	// SendRawTransaction sends transaction to txpool, or only executes it against current state if pre exec
+	SendRawTransaction(ctx context.Context, in *RawTransaction, opts ...grpc.CallOption) (*SendRawTransactionResult, error)
+	// GetMemPoolTxCount returns the count of transactions in txpool
+	GetMemPoolTxCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MemPoolTxCount, error)
+	// GetMemPoolTxState returns the verify results of transaction in txpool
+	GetMemPoolTxState(ctx context.Context, in *Hash, opts ...grpc.CallOption) (*MemPoolTxState, error)
+	// GetPeerPerformance returns participation of consensus peers recorded at the end of view
+	GetPeerPerformance(ctx context.Context, in *PeerPerformanceRequest, opts ...grpc.CallOption) (*PeerPerformanceInfo, error)
+	// GetRelayerReward returns work and claimable fee of relayer for a chain
+	GetRelayerReward(ctx context.Context, in *RelayerRewardRequest, opts ...grpc.CallOption) (*RelayerReward, error)
+	// GetCrossStateRoot returns the root of cross chain states of block at height
+	GetCrossStateRoot(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Hash, error)
+	// GetCrossStatesProof returns the audit path of cross chain state stored under key at height
+	GetCrossStatesProof(ctx context.Context, in *CrossStatesRequest, opts ...grpc.CallOption) (*MerkleProof, error)
+	// GetCrossStatesBundle returns a self contained proof of cross chain state stored under key at height
+	GetCrossStatesBundle(ctx context.Context, in *CrossStatesRequest, opts ...grpc.CallOption) (*CrossStatesBundle, error)
+	// SubscribeBlocks streams blocks from height, and new blocks once saved
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (Poly_SubscribeBlocksClient, error)
+	// SubscribeEvents streams event logs matching filter from height, and those of new blocks once saved
+	SubscribeEvents(ctx context.Context, in *EventLogFilter, opts ...grpc.CallOption) (Poly_SubscribeEventsClient, error)
+}
+
+type polyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPolyClient(cc grpc.ClientConnInterface) PolyClient {
+	return &polyClient{cc}
+}
+
+func (c *polyClient) GetVersion(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Version, error) {
+	out := new(Version)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetNetworkId(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NetworkId, error) {
+	out := new(NetworkId)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetNetworkId", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetConnectionCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ConnectionCount, error) {
+	out := new(ConnectionCount)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetConnectionCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetBlockHeight(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BlockHeight, error) {
+	out := new(BlockHeight)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetBlockHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetBlockHash(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Hash, error) {
+	out := new(Hash)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetBlockHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetBlock(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetHeader(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Header, error) {
+	out := new(Header)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetHeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetTransaction(ctx context.Context, in *Hash, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetStorage(ctx context.Context, in *StorageRequest, opts ...grpc.CallOption) (*Storage, error) {
+	out := new(Storage)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetStorage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetStateMerkleRoot(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Hash, error) {
+	out := new(Hash)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetStateMerkleRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetMerkleProof(ctx context.Context, in *MerkleProofRequest, opts ...grpc.CallOption) (*MerkleProof, error) {
+	out := new(MerkleProof)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetMerkleProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetSmartCodeEvents(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*ExecuteNotifies, error) {
+	out := new(ExecuteNotifies)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetSmartCodeEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetSmartCodeEvent(ctx context.Context, in *Hash, opts ...grpc.CallOption) (*ExecuteNotify, error) {
+	out := new(ExecuteNotify)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetSmartCodeEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetEventLogs(ctx context.Context, in *EventLogFilter, opts ...grpc.CallOption) (*EventLogs, error) {
+	out := new(EventLogs)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetEventLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) SendRawTransaction(ctx context.Context, in *RawTransaction, opts ...grpc.CallOption) (*SendRawTransactionResult, error) {
+	out := new(SendRawTransactionResult)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/SendRawTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetMemPoolTxCount(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MemPoolTxCount, error) {
+	out := new(MemPoolTxCount)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetMemPoolTxCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetMemPoolTxState(ctx context.Context, in *Hash, opts ...grpc.CallOption) (*MemPoolTxState, error) {
+	out := new(MemPoolTxState)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetMemPoolTxState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetPeerPerformance(ctx context.Context, in *PeerPerformanceRequest, opts ...grpc.CallOption) (*PeerPerformanceInfo, error) {
+	out := new(PeerPerformanceInfo)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetPeerPerformance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetRelayerReward(ctx context.Context, in *RelayerRewardRequest, opts ...grpc.CallOption) (*RelayerReward, error) {
+	out := new(RelayerReward)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetRelayerReward", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetCrossStateRoot(ctx context.Context, in *BlockHeight, opts ...grpc.CallOption) (*Hash, error) {
+	out := new(Hash)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetCrossStateRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetCrossStatesProof(ctx context.Context, in *CrossStatesRequest, opts ...grpc.CallOption) (*MerkleProof, error) {
+	out := new(MerkleProof)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetCrossStatesProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) GetCrossStatesBundle(ctx context.Context, in *CrossStatesRequest, opts ...grpc.CallOption) (*CrossStatesBundle, error) {
+	out := new(CrossStatesBundle)
+	err := c.cc.Invoke(ctx, "/polyrpc.Poly/GetCrossStatesBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *polyClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (Poly_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Poly_ServiceDesc.Streams[0], "/polyrpc.Poly/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &polySubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Poly_SubscribeBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type polySubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *polySubscribeBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *polyClient) SubscribeEvents(ctx context.Context, in *EventLogFilter, opts ...grpc.CallOption) (Poly_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Poly_ServiceDesc.Streams[1], "/polyrpc.Poly/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &polySubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Poly_SubscribeEventsClient interface {
+	Recv() (*EventLog, error)
+	grpc.ClientStream
+}
+
+type polySubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *polySubscribeEventsClient) Recv() (*EventLog, error) {
+	m := new(EventLog)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PolyServer is the server API for Poly service.
+// All implementations must embed UnimplementedPolyServer
+// for forward compatibility
+type PolyServer interface {
+	// GetVersion returns the version of node
+	GetVersion(context.Context, *Empty) (*Version, error)
+	// GetNetworkId returns the network id of node
+	GetNetworkId(context.Context, *Empty) (*NetworkId, error)
+	// GetConnectionCount returns the count of connected peers
+	GetConnectionCount(context.Context, *Empty) (*ConnectionCount, error)
+	// GetBlockHeight returns the height of current block
+	GetBlockHeight(context.Context, *Empty) (*BlockHeight, error)
+	// GetBlockHash returns the hash of block at height
+	GetBlockHash(context.Context, *BlockHeight) (*Hash, error)
+	// GetBlock returns block by hash, or by height if hash is empty
+	GetBlock(context.Context, *BlockRequest) (*Block, error)
+	// GetHeader returns block header at height
+	GetHeader(context.Context, *BlockHeight) (*Header, error)
+	// GetTransaction returns transaction by hash
+	GetTransaction(context.Context, *Hash) (*Transaction, error)
+	// GetStorage returns the value of key in contract storage
+	GetStorage(context.Context, *StorageRequest) (*Storage, error)
+	// GetStateMerkleRoot returns the state merkle root of block at height
+	GetStateMerkleRoot(context.Context, *BlockHeight) (*Hash, error)
+	// GetMerkleProof returns the audit path proving the block hash at height by the block root at root height
+	GetMerkleProof(context.Context, *MerkleProofRequest) (*MerkleProof, error)
+	// GetSmartCodeEvents returns the event notifies of transactions in block at height
+	GetSmartCodeEvents(context.Context, *BlockHeight) (*ExecuteNotifies, error)
+	// GetSmartCodeEvent returns the event notify of transaction
+	GetSmartCodeEvent(context.Context, *Hash) (*ExecuteNotify, error)
+	// GetEventLogs returns a page of event logs matching filter
+	GetEventLogs(context.Context, *EventLogFilter) (*EventLogs, error)
+	// SendRawTransaction sends transaction to txpool, or only executes it against current state if pre exec
+	SendRawTransaction(context.Context, *RawTransaction) (*SendRawTransactionResult, error)
+	// GetMemPoolTxCount returns the count of transactions in txpool
+	GetMemPoolTxCount(context.Context, *Empty) (*MemPoolTxCount, error)
+	// GetMemPoolTxState returns the verify results of transaction in txpool
+	GetMemPoolTxState(context.Context, *Hash) (*MemPoolTxState, error)
+	// GetPeerPerformance returns participation of consensus peers recorded at the end of view
+	GetPeerPerformance(context.Context, *PeerPerformanceRequest) (*PeerPerformanceInfo, error)
+	// GetRelayerReward returns work and claimable fee of relayer for a chain
+	GetRelayerReward(context.Context, *RelayerRewardRequest) (*RelayerReward, error)
+	// GetCrossStateRoot returns the root of cross chain states of block at height
+	GetCrossStateRoot(context.Context, *BlockHeight) (*Hash, error)
+	// GetCrossStatesProof returns the audit path of cross chain state stored under key at height
+	GetCrossStatesProof(context.Context, *CrossStatesRequest) (*MerkleProof, error)
+	// GetCrossStatesBundle returns a self contained proof of cross chain state stored under key at height
+	GetCrossStatesBundle(context.Context, *CrossStatesRequest) (*CrossStatesBundle, error)
+	// SubscribeBlocks streams blocks from height, and new blocks once saved
+	SubscribeBlocks(*SubscribeBlocksRequest, Poly_SubscribeBlocksServer) error
+	// SubscribeEvents streams event logs matching filter from height, and those of new blocks once saved
+	SubscribeEvents(*EventLogFilter, Poly_SubscribeEventsServer) error
+	mustEmbedUnimplementedPolyServer()
+}
+
+// UnimplementedPolyServer must be embedded to have forward compatible implementations.
+type UnimplementedPolyServer struct {
+}
+
+func (UnimplementedPolyServer) GetVersion(context.Context, *Empty) (*Version, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedPolyServer) GetNetworkId(context.Context, *Empty) (*NetworkId, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkId not implemented")
+}
+func (UnimplementedPolyServer) GetConnectionCount(context.Context, *Empty) (*ConnectionCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConnectionCount not implemented")
+}
+func (UnimplementedPolyServer) GetBlockHeight(context.Context, *Empty) (*BlockHeight, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHeight not implemented")
+}
+func (UnimplementedPolyServer) GetBlockHash(context.Context, *BlockHeight) (*Hash, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockHash not implemented")
+}
+func (UnimplementedPolyServer) GetBlock(context.Context, *BlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedPolyServer) GetHeader(context.Context, *BlockHeight) (*Header, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeader not implemented")
+}
+func (UnimplementedPolyServer) GetTransaction(context.Context, *Hash) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedPolyServer) GetStorage(context.Context, *StorageRequest) (*Storage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorage not implemented")
+}
+func (UnimplementedPolyServer) GetStateMerkleRoot(context.Context, *BlockHeight) (*Hash, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateMerkleRoot not implemented")
+}
+func (UnimplementedPolyServer) GetMerkleProof(context.Context, *MerkleProofRequest) (*MerkleProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMerkleProof not implemented")
+}
+func (UnimplementedPolyServer) GetSmartCodeEvents(context.Context, *BlockHeight) (*ExecuteNotifies, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSmartCodeEvents not implemented")
+}
+func (UnimplementedPolyServer) GetSmartCodeEvent(context.Context, *Hash) (*ExecuteNotify, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSmartCodeEvent not implemented")
+}
+func (UnimplementedPolyServer) GetEventLogs(context.Context, *EventLogFilter) (*EventLogs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventLogs not implemented")
+}
+func (UnimplementedPolyServer) SendRawTransaction(context.Context, *RawTransaction) (*SendRawTransactionResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendRawTransaction not implemented")
+}
+func (UnimplementedPolyServer) GetMemPoolTxCount(context.Context, *Empty) (*MemPoolTxCount, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemPoolTxCount not implemented")
+}
+func (UnimplementedPolyServer) GetMemPoolTxState(context.Context, *Hash) (*MemPoolTxState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemPoolTxState not implemented")
+}
+func (UnimplementedPolyServer) GetPeerPerformance(context.Context, *PeerPerformanceRequest) (*PeerPerformanceInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerPerformance not implemented")
+}
+func (UnimplementedPolyServer) GetRelayerReward(context.Context, *RelayerRewardRequest) (*RelayerReward, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRelayerReward not implemented")
+}
+func (UnimplementedPolyServer) GetCrossStateRoot(context.Context, *BlockHeight) (*Hash, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrossStateRoot not implemented")
+}
+func (UnimplementedPolyServer) GetCrossStatesProof(context.Context, *CrossStatesRequest) (*MerkleProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrossStatesProof not implemented")
+}
+func (UnimplementedPolyServer) GetCrossStatesBundle(context.Context, *CrossStatesRequest) (*CrossStatesBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrossStatesBundle not implemented")
+}
+func (UnimplementedPolyServer) SubscribeBlocks(*SubscribeBlocksRequest, Poly_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+func (UnimplementedPolyServer) SubscribeEvents(*EventLogFilter, Poly_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (UnimplementedPolyServer) mustEmbedUnimplementedPolyServer() {}
+
+// UnsafePolyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PolyServer will
+// result in compilation errors.
+type UnsafePolyServer interface {
+	mustEmbedUnimplementedPolyServer()
+}
+
+func RegisterPolyServer(s grpc.ServiceRegistrar, srv PolyServer) {
+	s.RegisterService(&Poly_ServiceDesc, srv)
+}
+
+func _Poly_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetVersion(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetNetworkId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetNetworkId(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetNetworkId",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetNetworkId(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetConnectionCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetConnectionCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetConnectionCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetConnectionCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetBlockHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetBlockHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetBlockHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetBlockHeight(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetBlockHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeight)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetBlockHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetBlockHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetBlockHash(ctx, req.(*BlockHeight))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetBlock(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeight)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetHeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetHeader(ctx, req.(*BlockHeight))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hash)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetTransaction(ctx, req.(*Hash))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetStorage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetStorage(ctx, req.(*StorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetStateMerkleRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeight)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetStateMerkleRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetStateMerkleRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetStateMerkleRoot(ctx, req.(*BlockHeight))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetMerkleProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MerkleProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetMerkleProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetMerkleProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetMerkleProof(ctx, req.(*MerkleProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetSmartCodeEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeight)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetSmartCodeEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetSmartCodeEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetSmartCodeEvents(ctx, req.(*BlockHeight))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetSmartCodeEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hash)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetSmartCodeEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetSmartCodeEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetSmartCodeEvent(ctx, req.(*Hash))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetEventLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventLogFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetEventLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetEventLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetEventLogs(ctx, req.(*EventLogFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_SendRawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RawTransaction)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).SendRawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/SendRawTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).SendRawTransaction(ctx, req.(*RawTransaction))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetMemPoolTxCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetMemPoolTxCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetMemPoolTxCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetMemPoolTxCount(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetMemPoolTxState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Hash)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetMemPoolTxState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetMemPoolTxState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetMemPoolTxState(ctx, req.(*Hash))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetPeerPerformance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerPerformanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetPeerPerformance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetPeerPerformance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetPeerPerformance(ctx, req.(*PeerPerformanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetRelayerReward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RelayerRewardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetRelayerReward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetRelayerReward",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetRelayerReward(ctx, req.(*RelayerRewardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetCrossStateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeight)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetCrossStateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetCrossStateRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetCrossStateRoot(ctx, req.(*BlockHeight))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetCrossStatesProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrossStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetCrossStatesProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetCrossStatesProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetCrossStatesProof(ctx, req.(*CrossStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_GetCrossStatesBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrossStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolyServer).GetCrossStatesBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/polyrpc.Poly/GetCrossStatesBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolyServer).GetCrossStatesBundle(ctx, req.(*CrossStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Poly_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PolyServer).SubscribeBlocks(m, &polySubscribeBlocksServer{stream})
+}
+
+type Poly_SubscribeBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type polySubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *polySubscribeBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Poly_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventLogFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PolyServer).SubscribeEvents(m, &polySubscribeEventsServer{stream})
+}
+
+type Poly_SubscribeEventsServer interface {
+	Send(*EventLog) error
+	grpc.ServerStream
+}
+
+type polySubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *polySubscribeEventsServer) Send(m *EventLog) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Poly_ServiceDesc is the grpc.ServiceDesc for Poly service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Poly_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "polyrpc.Poly",
+	HandlerType: (*PolyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVersion",
+			Handler:    _Poly_GetVersion_Handler,
+		},
+		{
+			MethodName: "GetNetworkId",
+			Handler:    _Poly_GetNetworkId_Handler,
+		},
+		{
+			MethodName: "GetConnectionCount",
+			Handler:    _Poly_GetConnectionCount_Handler,
+		},
+		{
+			MethodName: "GetBlockHeight",
+			Handler:    _Poly_GetBlockHeight_Handler,
+		},
+		{
+			MethodName: "GetBlockHash",
+			Handler:    _Poly_GetBlockHash_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Poly_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetHeader",
+			Handler:    _Poly_GetHeader_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Poly_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetStorage",
+			Handler:    _Poly_GetStorage_Handler,
+		},
+		{
+			MethodName: "GetStateMerkleRoot",
+			Handler:    _Poly_GetStateMerkleRoot_Handler,
+		},
+		{
+			MethodName: "GetMerkleProof",
+			Handler:    _Poly_GetMerkleProof_Handler,
+		},
+		{
+			MethodName: "GetSmartCodeEvents",
+			Handler:    _Poly_GetSmartCodeEvents_Handler,
+		},
+		{
+			MethodName: "GetSmartCodeEvent",
+			Handler:    _Poly_GetSmartCodeEvent_Handler,
+		},
+		{
+			MethodName: "GetEventLogs",
+			Handler:    _Poly_GetEventLogs_Handler,
+		},
+		{
+			MethodName: "SendRawTransaction",
+			Handler:    _Poly_SendRawTransaction_Handler,
+		},
+		{
+			MethodName: "GetMemPoolTxCount",
+			Handler:    _Poly_GetMemPoolTxCount_Handler,
+		},
+		{
+			MethodName: "GetMemPoolTxState",
+			Handler:    _Poly_GetMemPoolTxState_Handler,
+		},
+		{
+			MethodName: "GetPeerPerformance",
+			Handler:    _Poly_GetPeerPerformance_Handler,
+		},
+		{
+			MethodName: "GetRelayerReward",
+			Handler:    _Poly_GetRelayerReward_Handler,
+		},
+		{
+			MethodName: "GetCrossStateRoot",
+			Handler:    _Poly_GetCrossStateRoot_Handler,
+		},
+		{
+			MethodName: "GetCrossStatesProof",
+			Handler:    _Poly_GetCrossStatesProof_Handler,
+		},
+		{
+			MethodName: "GetCrossStatesBundle",
+			Handler:    _Poly_GetCrossStatesBundle_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _Poly_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Poly_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "poly.proto",
+}
@@ -0,0 +1,372 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package grpc provides the grpc server of node, like the rpc, restful and websocket servers
+// it's a thin adapter of http/base/service
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/polynetwork/poly/common"
+	cfg "github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/events/message"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/base/auth"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/http/base/service"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var server *grpclib.Server
+
+//StartServer start grpc server listening on the configured port
+func StartServer() error {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(int(cfg.DefConfig.Grpc.GrpcPort)))
+	if err != nil {
+		return fmt.Errorf("Listen error:%s", err)
+	}
+	poly := newPolyServer()
+	bactor.SubscribeEvent(message.TOPIC_SAVE_BLOCK_COMPLETE, func(v interface{}) {
+		poly.blocks.notify()
+	})
+	server = grpclib.NewServer(
+		grpclib.UnaryInterceptor(unaryAuthInterceptor(auth.DefAuthenticator)),
+		grpclib.StreamInterceptor(streamAuthInterceptor(auth.DefAuthenticator)),
+	)
+	pb.RegisterPolyServer(server, poly)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Errorf("grpc Serve error:%s", err)
+		}
+	}()
+	return nil
+}
+
+//Stop stop grpc server, closing the open subscriptions
+func Stop() {
+	if server != nil {
+		server.Stop()
+	}
+}
+
+type polyServer struct {
+	pb.UnimplementedPolyServer
+	blocks *blockNotifier
+}
+
+func newPolyServer() *polyServer {
+	return &polyServer{blocks: newBlockNotifier()}
+}
+
+//statusError convert service error to grpc status, the code of http/base/error is kept in message
+func statusError(err *service.Error) error {
+	return status.Errorf(statusCode(err.Code), "%s (%d)", err.Error(), err.Code)
+}
+
+func statusCode(code int64) codes.Code {
+	switch code {
+	case berr.INVALID_PARAMS, berr.INVALID_TRANSACTION:
+		return codes.InvalidArgument
+	case berr.UNKNOWN_TRANSACTION, berr.UNKNOWN_BLOCK, berr.UNKNOWN_CONTRACT:
+		return codes.NotFound
+	case berr.INVALID_METHOD:
+		return codes.FailedPrecondition
+	case berr.INTERNAL_ERROR:
+		return codes.Internal
+	case berr.SMARTCODE_ERROR:
+		return codes.Aborted
+	case berr.UNAUTHORIZED:
+		return codes.PermissionDenied
+	}
+	//rejected by txpool
+	return codes.FailedPrecondition
+}
+
+func parseHash(str string) (common.Uint256, error) {
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return common.UINT256_EMPTY, status.Errorf(codes.InvalidArgument, "invalid hash %s", str)
+	}
+	return hash, nil
+}
+
+func parseAddress(str string) (common.Address, error) {
+	address, err := bcomn.GetAddress(str)
+	if err != nil {
+		return common.ADDRESS_EMPTY, status.Errorf(codes.InvalidArgument, "invalid address %s", str)
+	}
+	return address, nil
+}
+
+func (this *polyServer) GetVersion(ctx context.Context, req *pb.Empty) (*pb.Version, error) {
+	return &pb.Version{Version: service.GetNodeVersion()}, nil
+}
+
+func (this *polyServer) GetNetworkId(ctx context.Context, req *pb.Empty) (*pb.NetworkId, error) {
+	return &pb.NetworkId{NetworkId: service.GetNetworkId()}, nil
+}
+
+func (this *polyServer) GetConnectionCount(ctx context.Context, req *pb.Empty) (*pb.ConnectionCount, error) {
+	count, err := service.GetConnectionCount()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.ConnectionCount{Count: count}, nil
+}
+
+func (this *polyServer) GetBlockHeight(ctx context.Context, req *pb.Empty) (*pb.BlockHeight, error) {
+	return &pb.BlockHeight{Height: service.GetBlockHeight()}, nil
+}
+
+func (this *polyServer) GetBlockHash(ctx context.Context, req *pb.BlockHeight) (*pb.Hash, error) {
+	hash, err := service.GetBlockHash(req.Height)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Hash{Hash: hash.ToHexString()}, nil
+}
+
+func (this *polyServer) GetBlock(ctx context.Context, req *pb.BlockRequest) (*pb.Block, error) {
+	if req.Hash != "" {
+		hash, err := parseHash(req.Hash)
+		if err != nil {
+			return nil, err
+		}
+		block, serr := service.GetBlockByHash(hash)
+		if serr != nil {
+			return nil, statusError(serr)
+		}
+		return convertBlock(block, req.Raw), nil
+	}
+	block, err := service.GetBlockByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return convertBlock(block, req.Raw), nil
+}
+
+func (this *polyServer) GetHeader(ctx context.Context, req *pb.BlockHeight) (*pb.Header, error) {
+	header, err := service.GetHeaderByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return convertHeader(header), nil
+}
+
+func (this *polyServer) GetTransaction(ctx context.Context, req *pb.Hash) (*pb.Transaction, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	tx, height, serr := service.GetTransaction(hash)
+	if serr != nil {
+		return nil, statusError(serr)
+	}
+	return convertTransaction(tx, height), nil
+}
+
+func (this *polyServer) GetStorage(ctx context.Context, req *pb.StorageRequest) (*pb.Storage, error) {
+	address, err := parseAddress(req.Contract)
+	if err != nil {
+		return nil, err
+	}
+	value, serr := service.GetStorage(address, req.Key)
+	if serr != nil {
+		return nil, statusError(serr)
+	}
+	return &pb.Storage{Found: value != nil, Value: value}, nil
+}
+
+func (this *polyServer) GetStateMerkleRoot(ctx context.Context, req *pb.BlockHeight) (*pb.Hash, error) {
+	root, err := service.GetStateMerkleRoot(req.Height)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Hash{Hash: root.ToHexString()}, nil
+}
+
+func (this *polyServer) GetMerkleProof(ctx context.Context, req *pb.MerkleProofRequest) (*pb.MerkleProof, error) {
+	proof, err := service.GetMerkleProof(req.Height, req.RootHeight)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.MerkleProof{AuditPath: proof}, nil
+}
+
+func (this *polyServer) GetSmartCodeEvents(ctx context.Context, req *pb.BlockHeight) (*pb.ExecuteNotifies, error) {
+	notifies, err := service.GetEventsByHeight(req.Height)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	result := &pb.ExecuteNotifies{Notifies: make([]*pb.ExecuteNotify, 0, len(notifies))}
+	for _, notify := range notifies {
+		result.Notifies = append(result.Notifies, convertExecuteNotify(notify))
+	}
+	return result, nil
+}
+
+func (this *polyServer) GetSmartCodeEvent(ctx context.Context, req *pb.Hash) (*pb.ExecuteNotify, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	notify, serr := service.GetEventsByTxHash(hash)
+	if serr != nil {
+		return nil, statusError(serr)
+	}
+	if notify == nil {
+		return nil, status.Errorf(codes.NotFound, "no event notify of transaction %s", req.Hash)
+	}
+	return convertExecuteNotify(notify), nil
+}
+
+func (this *polyServer) GetEventLogs(ctx context.Context, req *pb.EventLogFilter) (*pb.EventLogs, error) {
+	filter, err := convertEventLogFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	logs, cursor, serr := service.GetEventLogs(filter)
+	if serr != nil {
+		return nil, statusError(serr)
+	}
+	//the event logs of the heights in unindexed-heights header, as "from-to", are missing while the index is rebuilt
+	if gap := service.GetUnindexedEventLogs(filter); gap != nil {
+		_ = grpclib.SetHeader(ctx, metadata.Pairs("unindexed-heights", fmt.Sprintf("%d-%d", gap.From, gap.To)))
+	}
+	result := &pb.EventLogs{Logs: make([]*pb.EventLog, 0, len(logs)), Cursor: cursor}
+	for _, l := range logs {
+		result.Logs = append(result.Logs, convertEventLog(l))
+	}
+	return result, nil
+}
+
+func (this *polyServer) SendRawTransaction(ctx context.Context, req *pb.RawTransaction) (*pb.SendRawTransactionResult, error) {
+	hash, preResult, err := service.SendRawTransaction(req.Raw, req.PreExec)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	result := &pb.SendRawTransactionResult{Hash: hash.ToHexString()}
+	if preResult != nil {
+		result.PreExec = &pb.PreExecuteResult{
+			State:  uint32(preResult.State),
+			Result: encodeStates(preResult.Result),
+			Notify: convertNotifyEvents(preResult.Notify),
+		}
+	}
+	return result, nil
+}
+
+func (this *polyServer) GetMemPoolTxCount(ctx context.Context, req *pb.Empty) (*pb.MemPoolTxCount, error) {
+	count, err := service.GetMemPoolTxCount()
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.MemPoolTxCount{Count: count}, nil
+}
+
+func (this *polyServer) GetMemPoolTxState(ctx context.Context, req *pb.Hash) (*pb.MemPoolTxState, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	info, serr := service.GetMemPoolTxState(hash)
+	if serr != nil {
+		return nil, statusError(serr)
+	}
+	result := &pb.MemPoolTxState{State: make([]*pb.TxVerifyResult, 0, len(info.State))}
+	for _, s := range info.State {
+		result.State = append(result.State, &pb.TxVerifyResult{
+			Height:  s.Height,
+			Type:    int32(s.Type),
+			ErrCode: int32(s.ErrCode),
+		})
+	}
+	return result, nil
+}
+
+func (this *polyServer) GetPeerPerformance(ctx context.Context, req *pb.PeerPerformanceRequest) (*pb.PeerPerformanceInfo, error) {
+	info, err := service.GetPeerPerformance(req.View)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return convertPeerPerformance(info), nil
+}
+
+func (this *polyServer) GetRelayerReward(ctx context.Context, req *pb.RelayerRewardRequest) (*pb.RelayerReward, error) {
+	address, err := parseAddress(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	info, serr := service.GetRelayerReward(address, req.ChainId)
+	if serr != nil {
+		return nil, statusError(serr)
+	}
+	return &pb.RelayerReward{
+		Address:         info.Address,
+		ChainId:         info.ChainID,
+		HeadersSynced:   info.HeadersSynced,
+		ProofsDelivered: info.ProofsDelivered,
+		Earned:          info.Earned,
+		Claimed:         info.Claimed,
+		Claimable:       info.Claimable,
+	}, nil
+}
+
+func (this *polyServer) GetCrossStateRoot(ctx context.Context, req *pb.BlockHeight) (*pb.Hash, error) {
+	root, err := service.GetCrossStateRoot(req.Height)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Hash{Hash: root.ToHexString()}, nil
+}
+
+func (this *polyServer) GetCrossStatesProof(ctx context.Context, req *pb.CrossStatesRequest) (*pb.MerkleProof, error) {
+	proof, err := service.GetCrossStatesProof(req.Height, req.Key)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.MerkleProof{AuditPath: proof}, nil
+}
+
+func (this *polyServer) GetCrossStatesBundle(ctx context.Context, req *pb.CrossStatesRequest) (*pb.CrossStatesBundle, error) {
+	//without a trusted epoch only the header is returned
+	trustedHeight := req.Height + 1
+	if req.TrustedHeight != nil {
+		trustedHeight = req.TrustedHeight.Height
+	}
+	bundle, err := service.GetCrossStatesBundle(req.Height, req.Key, trustedHeight)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.CrossStatesBundle{
+		Value:        bundle.Value,
+		AuditPath:    bundle.AuditPath,
+		Header:       bundle.Header,
+		EpochHeaders: bundle.EpochHeaders,
+	}, nil
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common/config"
+	ontErrors "github.com/polynetwork/poly/errors"
+	"github.com/polynetwork/poly/http/base/auth"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/http/base/service"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestStatusError(t *testing.T) {
+	err := statusError(&service.Error{Code: berr.UNKNOWN_BLOCK})
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, s.Code())
+	assert.Equal(t, "UNKNOWN BLOCK (44003)", s.Message())
+
+	assert.Equal(t, codes.InvalidArgument, statusCode(berr.INVALID_PARAMS))
+	assert.Equal(t, codes.Internal, statusCode(berr.INTERNAL_ERROR))
+	assert.Equal(t, codes.FailedPrecondition, statusCode(int64(ontErrors.ErrDuplicatedTx)))
+}
+
+func TestEventLogFilter(t *testing.T) {
+	filter, err := convertEventLogFilter(&pb.EventLogFilter{
+		FromHeight:  1,
+		Contract:    utils.CrossChainManagerContractAddress.ToHexString(),
+		Name:        "makeProof",
+		FromChainId: &pb.ChainId{Id: 0},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), filter.FromHeight)
+	assert.Equal(t, utils.CrossChainManagerContractAddress, *filter.Contract)
+	assert.Equal(t, uint64(0), *filter.FromChainID)
+	assert.Nil(t, filter.ToChainID)
+
+	_, err = convertEventLogFilter(&pb.EventLogFilter{Contract: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBlockNotifier(t *testing.T) {
+	notifier := newBlockNotifier()
+	saved := notifier.wait()
+	select {
+	case <-saved:
+		t.Fatal("notified without block saved")
+	default:
+	}
+	notifier.notify()
+	select {
+	case <-saved:
+	case <-time.After(time.Second):
+		t.Fatal("not notified")
+	}
+	assert.NotEqual(t, saved, notifier.wait())
+}
+
+func TestGetVersion(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpclib.NewServer()
+	pb.RegisterPolyServer(server, newPolyServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpclib.DialContext(context.Background(), "bufnet",
+		grpclib.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpclib.WithInsecure())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	version, err := pb.NewPolyClient(conn).GetVersion(context.Background(), &pb.Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, config.Version, version.Version)
+
+	_, err = pb.NewPolyClient(conn).GetBlock(context.Background(), &pb.BlockRequest{Hash: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestResumeHeight(t *testing.T) {
+	next, err := resumeHeight(0, 2000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2001), next)
+	next, err = resumeHeight(1001, 2000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1001), next)
+	next, err = resumeHeight(2001, 2000)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2001), next)
+
+	_, err = resumeHeight(1000, 2000)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = resumeHeight(2002, 2000)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuthorize(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(&auth.Config{
+		Protected: []string{"sendrawtransaction"},
+		Credentials: []*auth.Credential{
+			{Name: "relayer", Scheme: "hmac", Token: "relayer-secret", Methods: []string{"sendrawtransaction"}},
+		},
+	})
+	assert.Nil(t, err)
+	fullMethod := "/polyrpc.Poly/SendRawTransaction"
+	signed := func(req *pb.RawTransaction, nonce string) context.Context {
+		body, err := RequestBody(req)
+		assert.Nil(t, err)
+		authorization := auth.HmacAuthorization("relayer", "relayer-secret", time.Now().Unix(), nonce,
+			"POST", fullMethod, body)
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+	}
+
+	req := &pb.RawTransaction{Raw: []byte{1, 2, 3}}
+	ctx := signed(req, "n1")
+	assert.Nil(t, authorize(ctx, authenticator, fullMethod, req))
+	// a captured signature is not replayed with another request
+	ctx = signed(req, "n2")
+	err = authorize(ctx, authenticator, fullMethod, &pb.RawTransaction{Raw: []byte{4, 5, 6}})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, authorize(signed(req, "n3"), authenticator, fullMethod, req))
+	err = authorize(context.Background(), authenticator, fullMethod, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Nil(t, authorize(context.Background(), authenticator, "/polyrpc.Poly/GetVersion", &pb.Empty{}))
+}
+
+func TestStreamAuthorize(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(&auth.Config{Protected: []string{"subscribeblocks"}})
+	assert.Nil(t, err)
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpclib.NewServer(grpclib.StreamInterceptor(streamAuthInterceptor(authenticator)))
+	pb.RegisterPolyServer(server, newPolyServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpclib.DialContext(context.Background(), "bufnet",
+		grpclib.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpclib.WithInsecure())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	stream, err := pb.NewPolyClient(conn).SubscribeBlocks(context.Background(), &pb.SubscribeBlocksRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
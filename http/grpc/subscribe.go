@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"sync"
+
+	"github.com/polynetwork/poly/http/base/service"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//MAX_RESUME_BLOCKS is the max number of saved blocks a subscription replays, as the websocket subscriptions
+const MAX_RESUME_BLOCKS = 1000
+
+//blockNotifier wakes up the subscriptions waiting for a new block
+type blockNotifier struct {
+	lock  sync.Mutex
+	saved chan struct{} //closed and replaced when a block saved
+}
+
+func newBlockNotifier() *blockNotifier {
+	return &blockNotifier{saved: make(chan struct{})}
+}
+
+func (this *blockNotifier) notify() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	close(this.saved)
+	this.saved = make(chan struct{})
+}
+
+//wait return a channel closed when the next block saved,
+//must be taken before reading current height to not miss a block
+func (this *blockNotifier) wait() <-chan struct{} {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.saved
+}
+
+//resumeHeight return the height a subscription from height starts at when current is the current block height,
+//the next block if from is 0. from must be within MAX_RESUME_BLOCKS blocks before the next block
+func resumeHeight(from, current uint32) (uint32, error) {
+	if from == 0 {
+		return current + 1, nil
+	}
+	if from > current+1 || current+1-from > MAX_RESUME_BLOCKS {
+		return 0, status.Errorf(codes.InvalidArgument, "from height %d should be within %d blocks before %d",
+			from, MAX_RESUME_BLOCKS, current+1)
+	}
+	return from, nil
+}
+
+//SubscribeBlocks send blocks from FromHeight, or from the next block if 0, as they are saved.
+//FromHeight is at most MAX_RESUME_BLOCKS blocks before the next block
+func (this *polyServer) SubscribeBlocks(req *pb.SubscribeBlocksRequest, stream pb.Poly_SubscribeBlocksServer) error {
+	next, err := resumeHeight(req.FromHeight, service.GetBlockHeight())
+	if err != nil {
+		return err
+	}
+	for {
+		saved := this.blocks.wait()
+		for ; next <= service.GetBlockHeight(); next++ {
+			block, err := service.GetBlockByHeight(next)
+			if err != nil {
+				return statusError(err)
+			}
+			if err := stream.Send(convertBlock(block, req.Raw)); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-saved:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+//SubscribeEvents send event logs matching filter from FromHeight, or from the next block if 0,
+//as the blocks are saved. FromHeight is at most MAX_RESUME_BLOCKS blocks before the next block,
+//ToHeight, Limit and Cursor of filter are ignored. It fails with Unavailable
+//if the heights to send are not indexed yet while the event log index is rebuilt
+func (this *polyServer) SubscribeEvents(req *pb.EventLogFilter, stream pb.Poly_SubscribeEventsServer) error {
+	filter, err := convertEventLogFilter(req)
+	if err != nil {
+		return err
+	}
+	filter.Limit = 0
+	next, err := resumeHeight(req.FromHeight, service.GetBlockHeight())
+	if err != nil {
+		return err
+	}
+	for {
+		saved := this.blocks.wait()
+		current := service.GetBlockHeight()
+		if next <= current {
+			filter.FromHeight, filter.ToHeight, filter.Cursor = next, current, nil
+			if gap := service.GetUnindexedEventLogs(filter); gap != nil {
+				return status.Errorf(codes.Unavailable, "event logs of heights %d-%d are not indexed yet",
+					gap.From, gap.To)
+			}
+			for {
+				logs, cursor, serr := service.GetEventLogs(filter)
+				if serr != nil {
+					return statusError(serr)
+				}
+				for _, l := range logs {
+					if err := stream.Send(convertEventLog(l)); err != nil {
+						return err
+					}
+				}
+				if cursor == nil {
+					break
+				}
+				filter.Cursor = cursor
+			}
+			next = current + 1
+		}
+		select {
+		case <-saved:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
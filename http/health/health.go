@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package health provides the /health and /ready endpoints of node,
+// /health fails only when node can not save blocks, /ready fails when node is not fit to serve requests
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/vbft"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	tcomn "github.com/polynetwork/poly/txnpool/common"
+)
+
+const (
+	HEALTH_PATH = "/health"
+	READY_PATH  = "/ready"
+)
+
+//Status of node reported by health and ready endpoints
+type Status struct {
+	Ready           bool
+	Reasons         []string `json:",omitempty"` //why node is not ready
+	CurrentHeight   uint32
+	BestPeerHeight  uint32
+	ConsensusState  string `json:",omitempty"` //state of vbft server, empty if vbft is not running
+	SinceLastBlock  uint32 //seconds since timestamp of current block
+	TxPoolCount     uint32
+	TxPoolCapacity  uint32
+	TxPoolUsage     uint32 //percent of txpool capacity in use
+	StoreError      string `json:",omitempty"`
+	consensusState  vbft.ServerState
+	consensusActive bool
+}
+
+//GetStatus collect the status of node
+func GetStatus() *Status {
+	status := &Status{
+		CurrentHeight:  bactor.GetCurrentBlockHeight(),
+		TxPoolCapacity: tcomn.MAX_CAPACITY,
+	}
+	if height, err := bactor.GetBestPeerHeight(); err == nil {
+		status.BestPeerHeight = height
+	}
+	if header, err := bactor.GetHeaderByHeight(status.CurrentHeight); err == nil && header != nil {
+		now := uint32(time.Now().Unix())
+		if now > header.Timestamp {
+			status.SinceLastBlock = now - header.Timestamp
+		}
+	}
+	if count, err := bactor.GetTxnCount(); err == nil && len(count) > 0 {
+		status.TxPoolCount = count[0]
+		status.TxPoolUsage = uint32(uint64(count[0]) * 100 / tcomn.MAX_CAPACITY)
+	}
+	if err := bactor.GetStoreError(); err != nil {
+		status.StoreError = err.Error()
+	}
+	status.consensusState, status.consensusActive = vbft.GetServerState()
+	if status.consensusActive {
+		status.ConsensusState = status.consensusState.String()
+	}
+	return status
+}
+
+//Check set Ready and Reasons of status against the thresholds of cfg
+func (this *Status) Check(cfg *config.HealthConfig) {
+	this.Reasons = nil
+	if this.StoreError != "" {
+		this.Reasons = append(this.Reasons, fmt.Sprintf("ledger store error: %s", this.StoreError))
+	}
+	if this.BestPeerHeight > this.CurrentHeight && this.BestPeerHeight-this.CurrentHeight > uint32(cfg.MaxBlockLag) {
+		this.Reasons = append(this.Reasons, fmt.Sprintf("block height %d is %d blocks behind best peer height %d",
+			this.CurrentHeight, this.BestPeerHeight-this.CurrentHeight, this.BestPeerHeight))
+	}
+	if cfg.MaxBlockAge > 0 && this.SinceLastBlock > uint32(cfg.MaxBlockAge) {
+		this.Reasons = append(this.Reasons, fmt.Sprintf("no block since %d seconds", this.SinceLastBlock))
+	}
+	if this.TxPoolUsage > uint32(cfg.MaxTxPoolUsage) {
+		this.Reasons = append(this.Reasons, fmt.Sprintf("txpool is %d%% full", this.TxPoolUsage))
+	}
+	if this.consensusActive && this.consensusState < vbft.SyncReady {
+		this.Reasons = append(this.Reasons, fmt.Sprintf("consensus state is %s", this.ConsensusState))
+	}
+	this.Ready = len(this.Reasons) == 0
+}
+
+//Health is handler of /health, respond 503 when ledger store fails to save blocks
+func Health(w http.ResponseWriter, r *http.Request) {
+	status := GetStatus()
+	status.Check(config.DefConfig.Health)
+	code := http.StatusOK
+	if status.StoreError != "" {
+		code = http.StatusServiceUnavailable
+	}
+	write(w, code, status)
+}
+
+//Ready is handler of /ready, respond 503 when any readiness check fails
+func Ready(w http.ResponseWriter, r *http.Request) {
+	status := GetStatus()
+	status.Check(config.DefConfig.Health)
+	code := http.StatusOK
+	if !status.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	write(w, code, status)
+}
+
+func write(w http.ResponseWriter, code int, status *Status) {
+	data, err := json.Marshal(status)
+	if err != nil {
+		log.Errorf("health write error:%s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(data)
+}
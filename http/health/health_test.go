@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package health
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/consensus/vbft"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	cfg := &config.HealthConfig{
+		MaxBlockLag:    10,
+		MaxBlockAge:    60,
+		MaxTxPoolUsage: 90,
+	}
+	status := &Status{CurrentHeight: 100, BestPeerHeight: 110, SinceLastBlock: 60, TxPoolUsage: 90}
+	status.Check(cfg)
+	assert.True(t, status.Ready)
+	assert.Empty(t, status.Reasons)
+
+	status.BestPeerHeight = 111
+	status.Check(cfg)
+	assert.False(t, status.Ready)
+	assert.Len(t, status.Reasons, 1)
+
+	status.BestPeerHeight = 0
+	status.SinceLastBlock = 61
+	status.TxPoolUsage = 91
+	status.Check(cfg)
+	assert.False(t, status.Ready)
+	assert.Len(t, status.Reasons, 2)
+
+	cfg.MaxBlockAge = 0
+	cfg.MaxTxPoolUsage = 100
+	status.Check(cfg)
+	assert.True(t, status.Ready)
+
+	status.StoreError = "disk full"
+	status.Check(cfg)
+	assert.False(t, status.Ready)
+	status.StoreError = ""
+
+	status.consensusActive = true
+	status.consensusState = vbft.Syncing
+	status.ConsensusState = status.consensusState.String()
+	status.Check(cfg)
+	assert.False(t, status.Ready)
+	assert.Equal(t, []string{"consensus state is Syncing"}, status.Reasons)
+
+	status.consensusState = vbft.SyncReady
+	status.Check(cfg)
+	assert.True(t, status.Ready)
+}
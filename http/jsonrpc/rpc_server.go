@@ -27,38 +27,49 @@ import (
 
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/auth"
 	"github.com/polynetwork/poly/http/base/rpc"
+	"github.com/polynetwork/poly/http/health"
 )
 
 func StartRPCServer() error {
 	log.Debug()
 	http.HandleFunc("/", rpc.Handle)
+	http.HandleFunc(health.HEALTH_PATH, health.Health)
+	http.HandleFunc(health.READY_PATH, health.Ready)
+	rpc.SetAuthenticator(auth.DefAuthenticator)
 
 	rpc.HandleFunc("getbestblockhash", rpc.GetBestBlockHash)
-	rpc.HandleFunc("getblock", rpc.GetBlock)
+	rpc.HandleFunc("getblock", rpc.GetBlock, "block", "verbose")
 	rpc.HandleFunc("getblockcount", rpc.GetBlockCount)
-	rpc.HandleFunc("getblockhash", rpc.GetBlockHash)
+	rpc.HandleFunc("getblockhash", rpc.GetBlockHash, "height")
 	rpc.HandleFunc("getlatestblockmsgssnap", rpc.GetLatestBlockMsgsSnap)
-	rpc.HandleFunc("getcrossstateroot", rpc.GetCrossStateRoot)
+	rpc.HandleFunc("getcrossstateroot", rpc.GetCrossStateRoot, "height")
 	rpc.HandleFunc("getconnectioncount", rpc.GetConnectionCount)
 	//HandleFunc("getrawmempool", GetRawMemPool)
 
-	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction)
-	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction)
-	rpc.HandleFunc("getstorage", rpc.GetStorage)
+	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction, "txHash", "verbose")
+	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction, "tx", "preExec")
+	rpc.HandleFunc("simulatetransaction", rpc.SimulateTransaction, "tx", "height")
+	rpc.HandleFunc("getstorage", rpc.GetStorage, "contract", "key")
+	rpc.HandleFunc("getpeerperformance", rpc.GetPeerPerformance, "view")
+	rpc.HandleFunc("getrelayerreward", rpc.GetRelayerReward, "address", "chainId")
 	rpc.HandleFunc("getversion", rpc.GetNodeVersion)
 	rpc.HandleFunc("getnetworkid", rpc.GetNetworkId)
 
 	rpc.HandleFunc("getmempooltxcount", rpc.GetMemPoolTxCount)
-	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
-	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
-	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash)
+	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState, "txHash")
+	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent, "heightOrTxHash")
+	rpc.HandleFunc("geteventlogs", rpc.GetEventLogs, "fromHeight", "toHeight", "contract", "name",
+		"fromChainId", "toChainId", "limit", "cursor")
+	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash, "txHash")
 
-	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
-	rpc.HandleFunc("getcrossstatesproof", rpc.GetCrossStatesProof)
-	rpc.HandleFunc("getheaderbyheight", rpc.GetHeaderByHeight)
-	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
-	rpc.HandleFunc("getstatemerkleroot", rpc.GetStateMerkleRoot)
+	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof, "height", "rootHeight")
+	rpc.HandleFunc("getcrossstatesproof", rpc.GetCrossStatesProof, "height", "key")
+	rpc.HandleFunc("getcrossstatesbundle", rpc.GetCrossStatesBundle, "height", "key", "trustedHeight")
+	rpc.HandleFunc("getheaderbyheight", rpc.GetHeaderByHeight, "height")
+	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight, "height")
+	rpc.HandleFunc("getstatemerkleroot", rpc.GetStateMerkleRoot, "height")
 
 	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), nil)
 	if err != nil {
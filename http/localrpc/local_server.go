@@ -20,12 +20,15 @@
 package localrpc
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"strconv"
 
-	"fmt"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/auth"
 	"github.com/polynetwork/poly/http/base/rpc"
 )
 
@@ -36,18 +39,54 @@ const (
 
 func StartLocalServer() error {
 	log.Debug()
-	http.HandleFunc(LOCAL_DIR, rpc.Handle)
+	mux := rpc.NewServeMux()
+	mux.HandleFunc("getneighbor", rpc.GetNeighbor)
+	mux.HandleFunc("getnodestate", rpc.GetNodeState)
+	mux.HandleFunc("startconsensus", rpc.StartConsensus)
+	mux.HandleFunc("stopconsensus", rpc.StopConsensus)
+	mux.HandleFunc("setdebuginfo", rpc.SetDebugInfo, "level", "module")
+	mux.HandleFunc("getroundhistory", rpc.GetRoundHistory, "count")
+	if auth.DefAuthenticator == nil {
+		log.Warnf("local rpc admin methods are only served to localhost without an auth file")
+	}
+	mux.SetAuthenticator(auth.DefAuthenticator)
 
-	rpc.HandleFunc("getneighbor", rpc.GetNeighbor)
-	rpc.HandleFunc("getnodestate", rpc.GetNodeState)
-	rpc.HandleFunc("startconsensus", rpc.StartConsensus)
-	rpc.HandleFunc("stopconsensus", rpc.StopConsensus)
-	rpc.HandleFunc("setdebuginfo", rpc.SetDebugInfo)
+	if path := cfg.DefConfig.Rpc.HttpLocalSocket; path != "" {
+		if err := startSocketServer(path, mux); err != nil {
+			return fmt.Errorf("startSocketServer error:%s", err)
+		}
+	}
 
+	serveMux := http.NewServeMux()
+	serveMux.Handle(LOCAL_DIR, mux)
 	// TODO: only listen to local host
-	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), nil)
+	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), serveMux)
 	if err != nil {
 		return fmt.Errorf("ListenAndServe error:%s", err)
 	}
 	return nil
 }
+
+//startSocketServer serve local rpc on unix socket at path, only accessible to the owner of node,
+//requests from which are trusted with all methods
+func startSocketServer(path string, mux *rpc.ServeMux) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s error:%s", path, err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("Listen error:%s", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("Chmod error:%s", err)
+	}
+	serveMux := http.NewServeMux()
+	serveMux.Handle(LOCAL_DIR, auth.Trusted(mux))
+	go func() {
+		if err := http.Serve(listener, serveMux); err != nil {
+			log.Errorf("local rpc socket Serve error:%s", err)
+		}
+	}()
+	return nil
+}
@@ -25,8 +25,10 @@ import (
 	"encoding/json"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/auth"
 	berr "github.com/polynetwork/poly/http/base/error"
 	"github.com/polynetwork/poly/http/base/rest"
+	"github.com/polynetwork/poly/http/health"
 	"golang.org/x/net/netutil"
 	"io/ioutil"
 	"net"
@@ -49,6 +51,7 @@ type restServer struct {
 	server   *http.Server
 	postMap  map[string]Action //post method map
 	getMap   map[string]Action //get method map
+	auth     *auth.Authenticator
 }
 
 const (
@@ -60,16 +63,10 @@ const (
 	GET_BLK_HASH          = "/api/v1/block/hash/:height"
 	GET_TX                = "/api/v1/transaction/:hash"
 	GET_STORAGE           = "/api/v1/storage/:hash/:key"
-	GET_BALANCE           = "/api/v1/balance/:addr"
-	GET_CONTRACT_STATE    = "/api/v1/contract/:hash"
 	GET_SMTCOCE_EVT_TXS   = "/api/v1/smartcode/event/transactions/:height"
 	GET_SMTCOCE_EVTS      = "/api/v1/smartcode/event/txhash/:hash"
 	GET_BLK_HGT_BY_TXHASH = "/api/v1/block/height/txhash/:hash"
 	GET_MERKLE_PROOF      = "/api/v1/merkleproof/:bheight/:rheight"
-	GET_GAS_PRICE         = "/api/v1/gasprice"
-	GET_ALLOWANCE         = "/api/v1/allowance/:asset/:from/:to"
-	GET_UNBOUNDONG        = "/api/v1/unboundong/:addr"
-	GET_GRANTONG          = "/api/v1/grantong/:addr"
 	GET_MEMPOOL_TXCOUNT   = "/api/v1/mempool/txcount"
 	GET_MEMPOOL_TXSTATE   = "/api/v1/mempool/txstate/:hash"
 	GET_VERSION           = "/api/v1/version"
@@ -80,12 +77,14 @@ const (
 
 //init restful server
 func InitRestServer() rest.ApiServer {
-	rt := &restServer{}
+	rt := &restServer{auth: auth.DefAuthenticator}
 
 	rt.router = NewRouter()
 	rt.registryMethod()
 	rt.initGetHandler()
 	rt.initPostHandler()
+	rt.router.Get(health.HEALTH_PATH, health.Health)
+	rt.router.Get(health.READY_PATH, health.Ready)
 	return rt
 }
 
@@ -168,8 +167,6 @@ func (this *restServer) getPath(url string) string {
 		return GET_BLK_BY_HASH
 	} else if strings.Contains(url, strings.TrimRight(GET_TX, ":hash")) {
 		return GET_TX
-	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_STATE, ":hash")) {
-		return GET_CONTRACT_STATE
 	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVT_TXS, ":height")) {
 		return GET_SMTCOCE_EVT_TXS
 	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVTS, ":hash")) {
@@ -178,16 +175,8 @@ func (this *restServer) getPath(url string) string {
 		return GET_BLK_HGT_BY_TXHASH
 	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE, ":hash/:key")) {
 		return GET_STORAGE
-	} else if strings.Contains(url, strings.TrimRight(GET_BALANCE, ":addr")) {
-		return GET_BALANCE
 	} else if strings.Contains(url, strings.TrimRight(GET_MERKLE_PROOF, ":bheight/:rheight")) {
 		return GET_MERKLE_PROOF
-	} else if strings.Contains(url, strings.TrimRight(GET_ALLOWANCE, ":asset/:from/:to")) {
-		return GET_ALLOWANCE
-	} else if strings.Contains(url, strings.TrimRight(GET_UNBOUNDONG, ":addr")) {
-		return GET_UNBOUNDONG
-	} else if strings.Contains(url, strings.TrimRight(GET_GRANTONG, ":addr")) {
-		return GET_GRANTONG
 	} else if strings.Contains(url, strings.TrimRight(GET_MEMPOOL_TXSTATE, ":hash")) {
 		return GET_MEMPOOL_TXSTATE
 	}
@@ -209,8 +198,6 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Height"] = getParam(r, "height")
 	case GET_TX:
 		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
-	case GET_CONTRACT_STATE:
-		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
 	case POST_RAW_TX:
 		req["PreExec"] = r.FormValue("preExec")
 	case GET_STORAGE:
@@ -221,17 +208,8 @@ func (this *restServer) getParams(r *http.Request, url string, req map[string]in
 		req["Hash"] = getParam(r, "hash")
 	case GET_BLK_HGT_BY_TXHASH:
 		req["Hash"] = getParam(r, "hash")
-	case GET_BALANCE:
-		req["Addr"] = getParam(r, "addr")
 	case GET_MERKLE_PROOF:
 		req["BlockHeight"], req["RootHeight"] = getParam(r, "bheight"), getParam(r, "rheight")
-	case GET_ALLOWANCE:
-		req["Asset"] = getParam(r, "asset")
-		req["From"], req["To"] = getParam(r, "from"), getParam(r, "to")
-	case GET_UNBOUNDONG:
-		req["Addr"] = getParam(r, "addr")
-	case GET_GRANTONG:
-		req["Addr"] = getParam(r, "addr")
 	case GET_MEMPOOL_TXSTATE:
 		req["Hash"] = getParam(r, "hash")
 	default:
@@ -250,8 +228,12 @@ func (this *restServer) initGetHandler() {
 
 			url := this.getPath(r.URL.Path)
 			if h, ok := this.getMap[url]; ok {
-				req = this.getParams(r, url, req)
-				resp = h.handler(req)
+				if this.allow(r, nil, h.name) {
+					req = this.getParams(r, url, req)
+					resp = h.handler(req)
+				} else {
+					resp = rest.ResponsePack(berr.UNAUTHORIZED)
+				}
 				resp["Action"] = h.name
 			} else {
 				resp = rest.ResponsePack(berr.INVALID_METHOD)
@@ -274,7 +256,10 @@ func (this *restServer) initPostHandler() {
 
 			url := this.getPath(r.URL.Path)
 			if h, ok := this.postMap[url]; ok {
-				if err := json.Unmarshal(body, &req); err == nil {
+				if !this.allow(r, body, h.name) {
+					resp = rest.ResponsePack(berr.UNAUTHORIZED)
+					resp["Action"] = h.name
+				} else if err := json.Unmarshal(body, &req); err == nil {
 					req = this.getParams(r, url, req)
 					resp = h.handler(req)
 					resp["Action"] = h.name
@@ -296,8 +281,21 @@ func (this *restServer) initPostHandler() {
 	}
 
 }
+
+//allow return whether the request with body may call action
+func (this *restServer) allow(r *http.Request, body []byte, action string) bool {
+	if !this.auth.Protected(action) {
+		return true
+	}
+	cred, err := this.auth.Authenticate(r, body)
+	if err != nil {
+		log.Warnf("restful authenticate %s error: %s", r.RemoteAddr, err)
+	}
+	return this.auth.Allow(cred, action)
+}
+
 func (this *restServer) write(w http.ResponseWriter, data []byte) {
-	w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
+	w.Header().Add("Access-Control-Allow-Headers", "Content-Type, Authorization")
 	w.Header().Set("content-type", "application/json;charset=utf-8")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(data)
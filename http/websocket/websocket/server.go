@@ -33,6 +33,8 @@ import (
 	"github.com/polynetwork/poly/common"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/base/auth"
 	Err "github.com/polynetwork/poly/http/base/error"
 	"github.com/polynetwork/poly/http/base/rest"
 	"github.com/polynetwork/poly/http/websocket/session"
@@ -53,11 +55,13 @@ type Handler struct {
 
 //subscribe event for client
 type subscribe struct {
-	ContractsFilter       []string `json:"ContractsFilter"`
-	SubscribeEvent        bool     `json:"SubscribeEvent"`
-	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
-	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
-	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+	ContractsFilter       []string       `json:"ContractsFilter"`
+	SubscribeEvent        bool           `json:"SubscribeEvent"`
+	SubscribeJsonBlock    bool           `json:"SubscribeJsonBlock"`
+	SubscribeRawBlock     bool           `json:"SubscribeRawBlock"`
+	SubscribeBlockTxHashs bool           `json:"SubscribeBlockTxHashs"`
+	Topics                []*TopicFilter `json:"Topics,omitempty"`
+	NextHeight            uint32         `json:"NextHeight,omitempty"` //next block height to push topics of
 }
 type WsServer struct {
 	sync.RWMutex
@@ -68,6 +72,8 @@ type WsServer struct {
 	ActionMap    map[string]Handler   //handler functions
 	TxHashMap    map[string]string    //key: txHash   value:sessionid
 	SubscribeMap map[string]subscribe //key: sessionId   value:subscribeInfo
+	topicLock    sync.Mutex           //serializes topic pushes
+	auth         *auth.Authenticator
 }
 
 //init websocket server
@@ -77,6 +83,7 @@ func InitWsServer() *WsServer {
 		SessionList:  session.NewSessionList(),
 		TxHashMap:    make(map[string]string),
 		SubscribeMap: make(map[string]subscribe),
+		auth:         auth.DefAuthenticator,
 	}
 	return ws
 }
@@ -165,6 +172,23 @@ func (self *WsServer) registryMethod() {
 				}
 			}
 		}
+		if v, ok := cmd["Topics"]; ok {
+			topics, err := parseTopics(v)
+			if err != nil {
+				return rest.ResponsePack(Err.INVALID_PARAMS)
+			}
+			sub.Topics = topics
+			// replay the events from the resume height, or push from the next block on
+			current := bactor.GetCurrentBlockHeight()
+			sub.NextHeight = current + 1
+			if from, ok := cmd["FromHeight"].(float64); ok {
+				if uint32(from) > current+1 || current+1-uint32(from) > MAX_RESUME_BLOCKS {
+					return rest.ResponsePack(Err.INVALID_PARAMS)
+				}
+				sub.NextHeight = uint32(from)
+				go self.PushTopics(current)
+			}
+		}
 		self.SubscribeMap[sessionId] = sub
 
 		resp["Action"] = "subscribe"
@@ -245,6 +269,11 @@ func (self *WsServer) checkSessionsTimeout(done chan bool) {
 }
 
 func (self *WsServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
+	//credential of the connection, authenticated by the Authorization header of upgrade request
+	cred, err := self.auth.Authenticate(r, nil)
+	if err != nil {
+		log.Warnf("websocket authenticate %s error: %s", r.RemoteAddr, err)
+	}
 	wsConn, err := self.Upgrader.Upgrade(w, r, nil)
 
 	if err != nil {
@@ -270,7 +299,7 @@ func (self *WsServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 	for {
 		_, bysMsg, err := wsConn.ReadMessage()
 		if err == nil {
-			if self.OnDataHandle(nsSession, bysMsg, r) {
+			if self.OnDataHandle(nsSession, bysMsg, cred) {
 				nsSession.UpdateActiveTime()
 			}
 			continue
@@ -294,7 +323,7 @@ func (self *WsServer) IsValidMsg(reqMsg map[string]interface{}) bool {
 	}
 	return true
 }
-func (self *WsServer) OnDataHandle(curSession *session.Session, bysMsg []byte, r *http.Request) bool {
+func (self *WsServer) OnDataHandle(curSession *session.Session, bysMsg []byte, cred *auth.Credential) bool {
 
 	var req = make(map[string]interface{})
 
@@ -316,6 +345,12 @@ func (self *WsServer) OnDataHandle(curSession *session.Session, bysMsg []byte, r
 		curSession.Send(marshalResp(resp))
 		return false
 	}
+	if !self.auth.Allow(cred, actionName) {
+		resp := rest.ResponsePack(Err.UNAUTHORIZED)
+		resp["Action"] = actionName
+		curSession.Send(marshalResp(resp))
+		return true
+	}
 	if !self.IsValidMsg(req) {
 		resp := rest.ResponsePack(Err.INVALID_PARAMS)
 		curSession.Send(marshalResp(resp))
@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	scom "github.com/polynetwork/poly/core/store/common"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	Err "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/http/base/rest"
+	"github.com/polynetwork/poly/native/event"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	hscom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// typed subscription topics, pushed once the block of the events is saved
+const (
+	TOPIC_MAKE_PROOF  = "makeProof"  // cross chain manager makeProof events
+	TOPIC_SYNC_HEADER = "syncHeader" // header sync progress of side chains
+	TOPIC_GOVERNANCE  = "governance" // events of the governance contracts
+	TOPIC_NOTIFY      = "notify"     // any notify, narrowed by the filter fields
+
+	MAX_RESUME_BLOCKS = 1000
+)
+
+var governanceContracts = map[common.Address]bool{
+	utils.SideChainManagerContractAddress: true,
+	utils.NodeManagerContractAddress:      true,
+	utils.RelayerManagerContractAddress:   true,
+	utils.Neo3StateManagerContractAddress: true,
+	utils.SignatureManagerContractAddress: true,
+	utils.ReplenishContractAddress:        true,
+}
+
+// TopicFilter selects notifies by topic and by the leading States fields,
+// which native contracts fill with method name, from chain id and to chain id
+type TopicFilter struct {
+	Topic       string  `json:"Topic"`
+	Contract    string  `json:"Contract,omitempty"`
+	Method      string  `json:"Method,omitempty"`
+	FromChainId *uint64 `json:"FromChainId,omitempty"`
+	ToChainId   *uint64 `json:"ToChainId,omitempty"`
+}
+
+func parseTopics(v interface{}) ([]*TopicFilter, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	topics := make([]*TopicFilter, 0)
+	if err = json.Unmarshal(data, &topics); err != nil {
+		return nil, err
+	}
+	for _, t := range topics {
+		switch t.Topic {
+		case TOPIC_MAKE_PROOF, TOPIC_SYNC_HEADER, TOPIC_GOVERNANCE, TOPIC_NOTIFY:
+		default:
+			return nil, fmt.Errorf("unknown topic %s", t.Topic)
+		}
+	}
+	return topics, nil
+}
+
+// Match reports whether notify belongs to the topic and passes the filter
+func (this *TopicFilter) Match(notify *event.NotifyEventInfo) bool {
+	states, _ := notify.States.([]interface{})
+	method := ""
+	if len(states) > 0 {
+		method, _ = states[0].(string)
+	}
+	switch this.Topic {
+	case TOPIC_MAKE_PROOF:
+		if notify.ContractAddress != utils.CrossChainManagerContractAddress || method != ccmcom.NOTIFY_MAKE_PROOF {
+			return false
+		}
+	case TOPIC_SYNC_HEADER:
+		if notify.ContractAddress != utils.HeaderSyncContractAddress || method != hscom.SYNC_HEADER_NAME {
+			return false
+		}
+	case TOPIC_GOVERNANCE:
+		if !governanceContracts[notify.ContractAddress] {
+			return false
+		}
+	}
+	if this.Contract != "" && this.Contract != notify.ContractAddress.ToHexString() {
+		return false
+	}
+	if this.Method != "" && this.Method != method {
+		return false
+	}
+	if this.FromChainId != nil && !stateEquals(states, 1, *this.FromChainId) {
+		return false
+	}
+	if this.ToChainId != nil && !stateEquals(states, 2, *this.ToChainId) {
+		return false
+	}
+	return true
+}
+
+// stateEquals compares a chain id in States, which is a float64 once the notify is read back from the event store
+func stateEquals(states []interface{}, i int, id uint64) bool {
+	if len(states) <= i {
+		return false
+	}
+	switch v := states[i].(type) {
+	case float64:
+		return v == float64(id)
+	case uint64:
+		return v == id
+	case uint32:
+		return uint64(v) == id
+	case int:
+		return v >= 0 && uint64(v) == id
+	}
+	return false
+}
+
+// PushTopics sends the events up to height to the sessions subscribing topics,
+// each session continues from the height its last push or resume ended at
+func (self *WsServer) PushTopics(height uint32) {
+	self.topicLock.Lock()
+	defer self.topicLock.Unlock()
+
+	self.RLock()
+	subs := make(map[string]subscribe)
+	for sid, sub := range self.SubscribeMap {
+		if len(sub.Topics) > 0 && sub.NextHeight <= height {
+			subs[sid] = sub
+		}
+	}
+	self.RUnlock()
+
+	blocks := make(map[uint32][]*event.ExecuteNotify)
+	for sid, sub := range subs {
+		s := self.SessionList.GetSessionById(sid)
+		if s == nil {
+			continue
+		}
+		next := sub.NextHeight
+		for ; next <= height; next++ {
+			notifies, ok := blocks[next]
+			if !ok {
+				var err error
+				notifies, err = bactor.GetEventNotifyByHeight(next)
+				if err != nil && err != scom.ErrNotFound {
+					log.Errorf("websocket PushTopics, GetEventNotifyByHeight %d error: %s", next, err)
+					break
+				}
+				blocks[next] = notifies
+			}
+			for _, n := range notifies {
+				if n.State != event.CONTRACT_STATE_SUCCESS {
+					continue
+				}
+				for _, notify := range n.Notify {
+					for _, t := range sub.Topics {
+						if t.Match(notify) {
+							s.Send(marshalResp(topicResp(t.Topic, next, n.TxHash, notify)))
+							break
+						}
+					}
+				}
+			}
+		}
+		self.Lock()
+		if cur, ok := self.SubscribeMap[sid]; ok && cur.NextHeight == sub.NextHeight {
+			cur.NextHeight = next
+			self.SubscribeMap[sid] = cur
+		}
+		self.Unlock()
+	}
+}
+
+func topicResp(topic string, height uint32, txHash common.Uint256, notify *event.NotifyEventInfo) map[string]interface{} {
+	resp := rest.ResponsePack(Err.SUCCESS)
+	resp["Action"] = "topicnotify"
+	resp["Result"] = map[string]interface{}{
+		"Topic":           topic,
+		"Height":          height,
+		"TxHash":          txHash.ToHexString(),
+		"ContractAddress": notify.ContractAddress.ToHexString(),
+		"States":          notify.States,
+	}
+	return resp
+}
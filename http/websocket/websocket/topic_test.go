@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicFilter(t *testing.T) {
+	var cmd map[string]interface{}
+	err := json.Unmarshal([]byte(`{"Topics":[{"Topic":"makeProof","ToChainId":2},{"Topic":"syncHeader","FromChainId":6},{"Topic":"governance"}]}`), &cmd)
+	assert.Nil(t, err)
+	topics, err := parseTopics(cmd["Topics"])
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(topics))
+
+	// States read back from the event store carry numbers as float64
+	makeProof := &event.NotifyEventInfo{
+		ContractAddress: utils.CrossChainManagerContractAddress,
+		States:          []interface{}{"makeProof", float64(6), float64(2), "hash", float64(10), "key"},
+	}
+	assert.True(t, topics[0].Match(makeProof))
+	makeProof.States = []interface{}{"makeProof", uint64(6), uint64(3), "hash", uint32(10), "key"}
+	assert.False(t, topics[0].Match(makeProof))
+	assert.False(t, topics[1].Match(makeProof))
+
+	syncHeader := &event.NotifyEventInfo{
+		ContractAddress: utils.HeaderSyncContractAddress,
+		States:          []interface{}{"syncHeader", uint64(6), uint32(100), "hash", uint32(10)},
+	}
+	assert.False(t, topics[0].Match(syncHeader))
+	assert.True(t, topics[1].Match(syncHeader))
+
+	governance := &event.NotifyEventInfo{ContractAddress: utils.NodeManagerContractAddress, States: []interface{}{"commitDpos"}}
+	assert.True(t, topics[2].Match(governance))
+	assert.False(t, topics[2].Match(syncHeader))
+
+	notify := &TopicFilter{Topic: TOPIC_NOTIFY, Contract: utils.NodeManagerContractAddress.ToHexString(), Method: "commitDpos"}
+	assert.True(t, notify.Match(governance))
+	notify.Method = "other"
+	assert.False(t, notify.Match(governance))
+
+	_, err = parseTopics([]interface{}{map[string]interface{}{"Topic": "unknown"}})
+	assert.NotNil(t, err)
+}
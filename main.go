@@ -42,8 +42,11 @@ import (
 	"github.com/polynetwork/poly/consensus"
 	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/events"
 	hserver "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/base/auth"
+	"github.com/polynetwork/poly/http/grpc"
 	"github.com/polynetwork/poly/http/jsonrpc"
 	"github.com/polynetwork/poly/http/localrpc"
 	"github.com/polynetwork/poly/http/nodeinfo"
@@ -82,7 +85,14 @@ func setupAPP() *cli.App {
 		//common setting
 		utils.ConfigFlag,
 		utils.LogLevelFlag,
+		utils.LogFormatFlag,
+		utils.LogModulesFlag,
+		utils.LogMaxSizeFlag,
+		utils.LogMaxAgeFlag,
+		utils.LogMaxBackupsFlag,
+		utils.DisableLogCompressFlag,
 		utils.DisableEventLogFlag,
+		utils.StateUndoBlocksFlag,
 		utils.DataDirFlag,
 		//account setting
 		utils.WalletFileFlag,
@@ -91,6 +101,7 @@ func setupAPP() *cli.App {
 		//consensus setting
 		utils.EnableConsensusFlag,
 		utils.MaxTxInBlockFlag,
+		utils.RemoteSignerFlag,
 		//txpool setting
 		utils.TxpoolPreExecDisableFlag,
 		utils.DisableBroadcastNetTxFlag,
@@ -111,8 +122,11 @@ func setupAPP() *cli.App {
 		//rpc setting
 		utils.RPCDisabledFlag,
 		utils.RPCPortFlag,
+		utils.RPCLegacyEnvelopeFlag,
 		utils.RPCLocalEnableFlag,
 		utils.RPCLocalProtFlag,
+		utils.RPCLocalSocketFlag,
+		utils.RPCAuthFileFlag,
 		//rest setting
 		utils.RestfulEnableFlag,
 		utils.RestfulPortFlag,
@@ -120,6 +134,13 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//grpc setting
+		utils.GrpcEnableFlag,
+		utils.GrpcPortFlag,
+		//health setting
+		utils.HealthMaxBlockLagFlag,
+		utils.HealthMaxBlockAgeFlag,
+		utils.HealthMaxTxPoolUsageFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -174,6 +195,11 @@ func startOntology(ctx *cli.Context) {
 		log.Errorf("initConsensus error:%s", err)
 		return
 	}
+	err = auth.Init(config.DefConfig.Rpc.AuthFile)
+	if err != nil {
+		log.Errorf("auth.Init error:%s", err)
+		return
+	}
 	err = initRpc(ctx)
 	if err != nil {
 		log.Errorf("initRpc error:%s", err)
@@ -186,6 +212,11 @@ func startOntology(ctx *cli.Context) {
 	}
 	initRestful(ctx)
 	initWs(ctx)
+	err = initGrpc(ctx)
+	if err != nil {
+		log.Errorf("initGrpc error:%s", err)
+		return
+	}
 	initNodeInfo(ctx, p2pSvr)
 
 	go logCurrBlockHeight()
@@ -196,7 +227,29 @@ func initLog(ctx *cli.Context) {
 	//init log module
 	logLevel := ctx.GlobalInt(utils.GetFlagName(utils.LogLevelFlag))
 	alog.InitLog(log.PATH)
-	log.InitLog(logLevel, log.PATH, log.Stdout)
+	logFile, err := log.NewRotateWriter(log.PATH, log.RotateConfig{
+		MaxSize:    int64(ctx.GlobalUint(utils.GetFlagName(utils.LogMaxSizeFlag))),
+		MaxAge:     time.Duration(ctx.GlobalUint(utils.GetFlagName(utils.LogMaxAgeFlag))) * time.Hour,
+		MaxBackups: int(ctx.GlobalUint(utils.GetFlagName(utils.LogMaxBackupsFlag))),
+		Compress:   !ctx.GlobalBool(utils.GetFlagName(utils.DisableLogCompressFlag)),
+	})
+	if err != nil {
+		cmd.PrintErrorMsg("open log file error:%s", err)
+		os.Exit(1)
+	}
+	log.InitLog(logLevel, logFile, log.Stdout)
+	if err := log.Log.SetFormat(ctx.GlobalString(utils.GetFlagName(utils.LogFormatFlag))); err != nil {
+		cmd.PrintErrorMsg("%s", err)
+		os.Exit(1)
+	}
+	modules, err := log.ParseModuleLevels(ctx.GlobalString(utils.GetFlagName(utils.LogModulesFlag)))
+	if err == nil {
+		err = log.Log.SetModuleLevels(modules)
+	}
+	if err != nil {
+		cmd.PrintErrorMsg("%s", err)
+		os.Exit(1)
+	}
 }
 
 func initConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
@@ -209,10 +262,13 @@ func initConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	return cfg, nil
 }
 
-func initAccount(ctx *cli.Context) (*account.Account, error) {
+func initAccount(ctx *cli.Context) (signature.VrfSigner, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
+	if config.DefConfig.Consensus.RemoteSigner != "" {
+		return initRemoteSigner()
+	}
 	walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
 	if walletFile == "" {
 		return nil, fmt.Errorf("Please config wallet file using --wallet flag")
@@ -236,6 +292,22 @@ func initAccount(ctx *cli.Context) (*account.Account, error) {
 	return acc, nil
 }
 
+func initRemoteSigner() (signature.VrfSigner, error) {
+	signer, err := account.NewRemoteSigner(config.DefConfig.Consensus.RemoteSigner, 0)
+	if err != nil {
+		return nil, fmt.Errorf("init remote signer error:%s", err)
+	}
+	log.Infof("Using remote signer:%s, account:%s", config.DefConfig.Consensus.RemoteSigner, signer.Address.ToBase58())
+
+	if config.DefConfig.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
+		curPk := hex.EncodeToString(keypair.SerializePublicKey(signer.PubKey()))
+		config.DefConfig.Genesis.SOLO.Bookkeepers = []string{curPk}
+	}
+
+	log.Infof("Remote signer init success")
+	return signer, nil
+}
+
 func initLedger(ctx *cli.Context) (*ledger.Ledger, error) {
 	events.Init() //Init event hub
 
@@ -309,7 +381,7 @@ func initP2PNode(ctx *cli.Context, txpoolSvr *proc.TXPoolServer) (*p2pserver.P2P
 	return p2p, p2pPID, nil
 }
 
-func initConsensus(ctx *cli.Context, p2pPid *actor.PID, txpoolSvr *proc.TXPoolServer, acc *account.Account) (consensus.ConsensusService, error) {
+func initConsensus(ctx *cli.Context, p2pPid *actor.PID, txpoolSvr *proc.TXPoolServer, acc signature.VrfSigner) (consensus.ConsensusService, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
@@ -396,6 +468,18 @@ func initWs(ctx *cli.Context) {
 	log.Infof("Ws init success")
 }
 
+func initGrpc(ctx *cli.Context) error {
+	if !config.DefConfig.Grpc.EnableGrpc {
+		return nil
+	}
+	err := grpc.StartServer()
+	if err != nil {
+		return fmt.Errorf("StartServer error:%s", err)
+	}
+	log.Infof("Grpc init success")
+	return nil
+}
+
 func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	if config.DefConfig.P2PNode.HttpInfoPort == 0 {
 		return
@@ -411,11 +495,6 @@ func logCurrBlockHeight() {
 		select {
 		case <-ticker.C:
 			log.Infof("CurrentBlockHeight = %d", ledger.DefLedger.GetCurrentBlockHeight())
-			isNeedNewFile := log.CheckIfNeedNewFile()
-			if isNeedNewFile {
-				log.ClosePrintLog()
-				log.InitLog(int(config.DefConfig.Common.LogLevel), log.PATH, log.Stdout)
-			}
 		}
 	}
 }
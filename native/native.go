@@ -34,6 +34,13 @@ type (
 	RegisterService func(native *NativeService)
 )
 
+// Tracer observes the invocations of native contracts, used to trace a simulated transaction
+type Tracer interface {
+	CaptureEnter(contract common.Address, method string, args []byte)
+	CaptureExit(result interface{}, err error)
+	CaptureNotify(notify *event.NotifyEventInfo)
+}
+
 var (
 	Contracts = make(map[common.Address]RegisterService)
 )
@@ -55,8 +62,10 @@ type NativeService struct {
 	time          uint32
 	blockHash     common.Uint256
 	crossHashes   []common.Uint256
+	syncedHeaders uint64
 	contexts      []common.Address
 	preExec       bool
+	tracer        Tracer
 }
 
 func NewNativeService(cacheDB *storage.CacheDB, tx *types.Transaction,
@@ -83,11 +92,26 @@ func (this *NativeService) Register(methodName string, handler Handler) {
 	this.serviceMap[methodName] = handler
 }
 
+// SetTracer sets the tracer observing the invocations and notifications of this service
+func (this *NativeService) SetTracer(tracer Tracer) {
+	this.tracer = tracer
+}
+
 func (this *NativeService) Invoke() (interface{}, error) {
 	invokeParam := new(states.ContractInvokeParam)
 	if err := invokeParam.Deserialization(common.NewZeroCopySource(this.input)); err != nil {
 		return nil, err
 	}
+	if this.tracer == nil {
+		return this.invoke(invokeParam)
+	}
+	this.tracer.CaptureEnter(invokeParam.Address, invokeParam.Method, invokeParam.Args)
+	result, err := this.invoke(invokeParam)
+	this.tracer.CaptureExit(result, err)
+	return result, err
+}
+
+func (this *NativeService) invoke(invokeParam *states.ContractInvokeParam) (interface{}, error) {
 	services, ok := Contracts[invokeParam.Address]
 	if !ok {
 		return false, fmt.Errorf("[Invoke] Native contract address %x haven't been registered.", invokeParam.Address)
@@ -192,6 +216,9 @@ func (this *NativeService) CheckWitness(address common.Address) bool {
 }
 
 func (this *NativeService) AddNotify(notify *event.NotifyEventInfo) {
+	if this.tracer != nil {
+		this.tracer.CaptureNotify(notify)
+	}
 	this.notifications = append(this.notifications, notify)
 }
 
@@ -223,6 +250,49 @@ func (this *NativeService) GetNotify() []*event.NotifyEventInfo {
 	return this.notifications
 }
 
+// SetInput replaces the input of current invocation, used by methods running other handlers on part of their input
+func (this *NativeService) SetInput(input []byte) {
+	this.input = input
+}
+
+// Snapshot is a point of native execution which Revert rolls back to
+type Snapshot struct {
+	cache         int
+	notifications int
+	crossHashes   int
+}
+
+// Snapshot records the cache, notifications and cross chain hashes made so far
+func (this *NativeService) Snapshot() *Snapshot {
+	return &Snapshot{
+		cache:         this.cacheDB.Snapshot(),
+		notifications: len(this.notifications),
+		crossHashes:   len(this.crossHashes),
+	}
+}
+
+// Revert drops the writes, notifications and cross chain hashes made after snapshot
+func (this *NativeService) Revert(snapshot *Snapshot) {
+	this.cacheDB.RevertToSnapshot(snapshot.cache)
+	this.notifications = this.notifications[:snapshot.notifications]
+	this.crossHashes = this.crossHashes[:snapshot.crossHashes]
+}
+
+// Release keeps everything made after snapshot, a snapshot is either reverted or released
+func (this *NativeService) Release(snapshot *Snapshot) {
+	this.cacheDB.ReleaseSnapshot(snapshot.cache)
+}
+
 func (this *NativeService) GetCrossHashes() []common.Uint256 {
 	return this.crossHashes
 }
+
+// AddSyncedHeaders counts side chain headers newly stored by current invocation
+func (this *NativeService) AddSyncedHeaders(count uint64) {
+	this.syncedHeaders += count
+}
+
+// GetSyncedHeaders returns the number of side chain headers newly stored by current invocation
+func (this *NativeService) GetSyncedHeaders() uint64 {
+	return this.syncedHeaders
+}
@@ -22,9 +22,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"sort"
 
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/polynetwork/poly/common"
@@ -51,6 +52,15 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 		return fmt.Errorf("MultiSign, getBtcMultiSignInfo error: %v", err)
 	}
 
+	replacement, err := getBtcTxReplaced(service, params.TxHash)
+	if err != nil {
+		return fmt.Errorf("MultiSign, %v", err)
+	}
+	if replacement != nil {
+		return fmt.Errorf("MultiSign, tx %s is replaced by %s", hex.EncodeToString(params.TxHash),
+			hex.EncodeToString(replacement))
+	}
+
 	_, ok := multiSignInfo.MultiSignInfo[params.Address]
 	if ok {
 		return fmt.Errorf("MultiSign, address %s already sign", params.Address)
@@ -65,7 +75,7 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 	if err != nil {
 		return fmt.Errorf("MultiSign, %v", err)
 	}
-	_, addrs, n, err := txscript.ExtractPkScriptAddrs(redeemScript, netParam)
+	addrs, keys, n, err := getVaultSigners(redeemScript, netParam)
 	if err != nil {
 		return fmt.Errorf("MultiSign, failed to extract pkscript addrs: %v", err)
 	}
@@ -93,7 +103,11 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 	if err != nil {
 		return fmt.Errorf("MultiSign, failed to get stxos: %v", err)
 	}
-	err = verifySigs(params.Signs, params.Address, addrs, redeemScript, mtx, pkScripts, amts)
+	if keys != nil {
+		err = verifyTaprootSigs(params.Signs, params.Address, keys, redeemScript, mtx, pkScripts, amts)
+	} else {
+		err = verifySigs(params.Signs, params.Address, addrs, redeemScript, mtx, pkScripts, amts)
+	}
 	if err != nil {
 		return fmt.Errorf("MultiSign, failed to verify: %v", err)
 	}
@@ -111,8 +125,9 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 				States:          []interface{}{"btcTxMultiSign", params.TxHash, multiSignInfo.MultiSignInfo},
 			})
 	} else {
-		err = addSigToTx(multiSignInfo, addrs, redeemScript, mtx, pkScripts)
-		if err != nil {
+		if keys != nil {
+			addTaprootSigToTx(multiSignInfo, keys, redeemScript, mtx)
+		} else if err = addSigToTx(multiSignInfo, addrs, redeemScript, mtx, pkScripts); err != nil {
 			return fmt.Errorf("MultiSign, failed to add sig to tx: %v", err)
 		}
 		var buf bytes.Buffer
@@ -121,29 +136,9 @@ func (this *BTCHandler) MultiSign(service *native.NativeService) error {
 			return fmt.Errorf("MultiSign, failed to encode msgtx to bytes: %v", err)
 		}
 
-		witScript, err := getLockScript(redeemScript, netParam)
-		if err != nil {
-			return fmt.Errorf("MultiSign, failed to get lock script: %v", err)
+		if err = addChangeUtxos(service, params.ChainID, params.RedeemKey, redeemScript, netParam, mtx); err != nil {
+			return fmt.Errorf("MultiSign, %v", err)
 		}
-		utxos, err := getUtxos(service, params.ChainID, params.RedeemKey)
-		if err != nil {
-			return fmt.Errorf("MultiSign, getUtxos error: %v", err)
-		}
-		txid := mtx.TxHash()
-		for i, v := range mtx.TxOut {
-			if bytes.Equal(witScript, v.PkScript) {
-				newUtxo := &Utxo{
-					Op: &OutPoint{
-						Hash:  txid[:],
-						Index: uint32(i),
-					},
-					Value:        uint64(v.Value),
-					ScriptPubkey: v.PkScript,
-				}
-				utxos.Utxos = append(utxos.Utxos, newUtxo)
-			}
-		}
-		putUtxos(service, params.ChainID, params.RedeemKey, utxos)
 		btcFromTxInfo, err := getBtcFromInfo(service, params.TxHash)
 		if err != nil {
 			return fmt.Errorf("MultiSign, failed to get from tx hash %s from cacheDB: %v",
@@ -236,6 +231,247 @@ func (this *BTCHandler) MakeTransaction(service *native.NativeService, param *cr
 	return nil
 }
 
+// ConsolidateUtxos merges the smallest utxos of a vault into one output paying to the vault, or to the
+// vault it is migrated to. The caller should have checked the approval of governance.
+func (this *BTCHandler) ConsolidateUtxos(service *native.NativeService) error {
+	params := new(ConsolidateUtxosParam)
+	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
+		return fmt.Errorf("ConsolidateUtxos, contract params deserialize error: %v", err)
+	}
+	if params.MaxInputs < 2 {
+		return fmt.Errorf("ConsolidateUtxos, at least 2 utxos to consolidate")
+	}
+	rk, err := hex.DecodeString(params.RedeemKey)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, hex.DecodeString error: %v", err)
+	}
+	redeemScript, err := side_chain_manager.GetBtcRedeemScriptBytes(service, params.RedeemKey, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, get btc redeem script with redeem key %v from db error: %v",
+			params.RedeemKey, err)
+	}
+	netParam, err := getNetParam(service, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, %v", err)
+	}
+	detail, err := side_chain_manager.GetBtcTxParam(service, rk, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, failed to get btcTxParam: %v", err)
+	}
+	if detail == nil {
+		return fmt.Errorf("ConsolidateUtxos, no btcTxParam is set for redeem key %s", params.RedeemKey)
+	}
+	feeRate := params.FeeRate
+	if feeRate == 0 {
+		feeRate = detail.FeeRate
+	}
+
+	utxos, err := getUtxos(service, params.ChainID, params.RedeemKey)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, getUtxos error: %v", err)
+	}
+	if len(utxos.Utxos) < 2 {
+		return fmt.Errorf("ConsolidateUtxos, only %d utxo", len(utxos.Utxos))
+	}
+	sort.Sort(utxos)
+	num := len(utxos.Utxos)
+	if uint64(num) > params.MaxInputs {
+		num = int(params.MaxInputs)
+	}
+	inputs := utxos.Utxos[:num]
+
+	_, script, err := getChangeLock(service, params.ChainID, rk, redeemScript, netParam)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, %v", err)
+	}
+	out := wire.NewTxOut(0, script)
+	addrs, keys, m, _ := getVaultSigners(redeemScript, netParam)
+	n := len(addrs)
+	if keys != nil {
+		n = len(keys)
+	}
+	cs := &CoinSelector{txOuts: []*wire.TxOut{out}, feeRate: feeRate, m: m, n: n}
+	var sum uint64
+	for _, u := range inputs {
+		sum += u.Value
+	}
+	fee := cs.estimateTxFee(inputs)
+	if sum < fee+detail.MinChange {
+		return fmt.Errorf("ConsolidateUtxos, sum %d of utxos is not enough for fee %d and min-change %d",
+			sum, fee, detail.MinChange)
+	}
+	out.Value = int64(sum - fee)
+
+	txIns, err := getTxIns(inputs)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, %v", err)
+	}
+	mtx, err := getUnsignedTx(txIns, nil, out, nil)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, get rawtransaction fail: %v", err)
+	}
+	stxos, err := getStxos(service, params.ChainID, params.RedeemKey)
+	if err != nil {
+		return fmt.Errorf("ConsolidateUtxos, failed to get stxos: %v", err)
+	}
+	stxos.Utxos = append(stxos.Utxos, inputs...)
+	putStxos(service, params.ChainID, params.RedeemKey, stxos)
+	putUtxos(service, params.ChainID, params.RedeemKey, &Utxos{Utxos: utxos.Utxos[num:]})
+
+	txHash := service.GetTx().Hash()
+	if err = putBtcTx(service, params.ChainID, mtx, inputs, txHash.ToArray(), service.GetTx().ChainID, rk); err != nil {
+		return fmt.Errorf("ConsolidateUtxos, %v", err)
+	}
+	return nil
+}
+
+// BumpTxFee replaces a withdrawal, signed or not, with a tx spending the same utxos at a higher fee rate
+// paid by the change. The change of a signed tx must not be spent yet. The caller should have checked
+// the approval of governance.
+func (this *BTCHandler) BumpTxFee(service *native.NativeService) error {
+	params := new(BumpTxFeeParam)
+	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
+		return fmt.Errorf("BumpTxFee, contract params deserialize error: %v", err)
+	}
+	replacement, err := getBtcTxReplaced(service, params.TxHash)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, %v", err)
+	}
+	if replacement != nil {
+		return fmt.Errorf("BumpTxFee, tx %s is already replaced by %s", hex.EncodeToString(params.TxHash),
+			hex.EncodeToString(replacement))
+	}
+	txb, err := service.GetCacheDB().Get(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(BTC_TX_PREFIX),
+		params.TxHash))
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, failed to get tx %s from cacheDB: %v", hex.EncodeToString(params.TxHash), err)
+	}
+	if txb == nil {
+		return fmt.Errorf("BumpTxFee, tx %s not found", hex.EncodeToString(params.TxHash))
+	}
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	if err = mtx.BtcDecode(bytes.NewBuffer(txb), wire.ProtocolVersion, wire.LatestEncoding); err != nil {
+		return fmt.Errorf("BumpTxFee, failed to decode tx: %v", err)
+	}
+	inputs, err := getBtcTxInputs(service, params.ChainID, params.TxHash)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, %v", err)
+	}
+	if len(inputs.Utxos) != len(mtx.TxIn) {
+		return fmt.Errorf("BumpTxFee, inputs of tx %s are not recorded", hex.EncodeToString(params.TxHash))
+	}
+
+	rk, err := hex.DecodeString(params.RedeemKey)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, hex.DecodeString error: %v", err)
+	}
+	redeemScript, err := side_chain_manager.GetBtcRedeemScriptBytes(service, params.RedeemKey, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, get btc redeem script with redeem key %v from db error: %v",
+			params.RedeemKey, err)
+	}
+	netParam, err := getNetParam(service, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, %v", err)
+	}
+	detail, err := side_chain_manager.GetBtcTxParam(service, rk, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, failed to get btcTxParam: %v", err)
+	}
+	if detail == nil {
+		return fmt.Errorf("BumpTxFee, no btcTxParam is set for redeem key %s", params.RedeemKey)
+	}
+	changeKey, changeScript, err := getChangeLock(service, params.ChainID, rk, redeemScript, netParam)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, %v", err)
+	}
+	changeIdx := -1
+	for i, out := range mtx.TxOut {
+		if bytes.Equal(out.PkScript, changeScript) {
+			changeIdx = i
+		}
+	}
+	if changeIdx < 0 {
+		return fmt.Errorf("BumpTxFee, no change in tx %s to pay the fee", hex.EncodeToString(params.TxHash))
+	}
+
+	var sumIn, sumOut int64
+	for _, u := range inputs.Utxos {
+		sumIn += int64(u.Value)
+	}
+	for _, out := range mtx.TxOut {
+		sumOut += out.Value
+	}
+	addrs, keys, m, _ := getVaultSigners(redeemScript, netParam)
+	n := len(addrs)
+	if keys != nil {
+		n = len(keys)
+	}
+	cs := &CoinSelector{txOuts: mtx.TxOut, feeRate: params.FeeRate, m: m, n: n}
+	size := int64(cs.estimateTxSize(inputs.Utxos))
+	oldFee, newFee := sumIn-sumOut, int64(cs.estimateTxFee(inputs.Utxos))
+	// BIP125 requires the replacement to pay for its own relay at least at 1 satoshi per byte
+	if newFee < oldFee+size {
+		return fmt.Errorf("BumpTxFee, new fee %d should be at least %d", newFee, oldFee+size)
+	}
+	if mtx.TxOut[changeIdx].Value-(newFee-oldFee) < int64(detail.MinChange) {
+		return fmt.Errorf("BumpTxFee, change %d is not enough to pay fee %d more", mtx.TxOut[changeIdx].Value,
+			newFee-oldFee)
+	}
+
+	multiSignInfo, err := getBtcMultiSignInfo(service, params.TxHash)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, getBtcMultiSignInfo error: %v", err)
+	}
+	if len(multiSignInfo.MultiSignInfo) >= m {
+		// the signed tx has spent its inputs and added its change, take them back
+		utxos, err := getUtxos(service, params.ChainID, changeKey)
+		if err != nil {
+			return fmt.Errorf("BumpTxFee, getUtxos error: %v", err)
+		}
+		op := &OutPoint{Hash: params.TxHash, Index: uint32(changeIdx)}
+		idx := -1
+		for i, u := range utxos.Utxos {
+			if u.Op.String() == op.String() {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("BumpTxFee, change %s is already spent", op.String())
+		}
+		utxos.Utxos = append(utxos.Utxos[:idx], utxos.Utxos[idx+1:]...)
+		putUtxos(service, params.ChainID, changeKey, utxos)
+		stxos, err := getStxos(service, params.ChainID, params.RedeemKey)
+		if err != nil {
+			return fmt.Errorf("BumpTxFee, failed to get stxos: %v", err)
+		}
+		stxos.Utxos = append(stxos.Utxos, inputs.Utxos...)
+		putStxos(service, params.ChainID, params.RedeemKey, stxos)
+	}
+
+	mtx.TxOut[changeIdx].Value -= newFee - oldFee
+	// keep signaling BIP125 so the replacement can be bumped again
+	for _, in := range mtx.TxIn {
+		in.Sequence = RBF_SEQUENCE
+	}
+	fromInfo, err := getBtcFromInfo(service, params.TxHash)
+	if err != nil {
+		return fmt.Errorf("BumpTxFee, failed to get from info: %v", err)
+	}
+	if err = putBtcTx(service, params.ChainID, mtx, inputs.Utxos, fromInfo.FromTxHash, fromInfo.FromChainID, rk); err != nil {
+		return fmt.Errorf("BumpTxFee, %v", err)
+	}
+	newHash := mtx.TxHash()
+	putBtcTxReplaced(service, params.TxHash, newHash[:])
+	service.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States: []interface{}{"btcTxReplaced", params.RedeemKey, hex.EncodeToString(params.TxHash),
+				hex.EncodeToString(newHash[:]), newFee},
+		})
+	return nil
+}
+
 func makeBtcTx(service *native.NativeService, chainID uint64, amounts map[string]int64, fromTxHash []byte,
 	fromChainID uint64, redeemScript, rk []byte) error {
 	if len(amounts) == 0 {
@@ -261,25 +497,24 @@ func makeBtcTx(service *native.NativeService, chainID uint64, amounts map[string
 	if err != nil {
 		return fmt.Errorf("makeBtcTx, %v", err)
 	}
-	script, err := getLockScript(redeemScript, netParam)
+	// the change of a migrated vault goes to the new one
+	_, script, err := getChangeLock(service, chainID, rk, redeemScript, netParam)
 	if err != nil {
 		return fmt.Errorf("makeBtcTx, %v", err)
 	}
 	out := wire.NewTxOut(0, script)
-	_, addrs, m, _ := txscript.ExtractPkScriptAddrs(redeemScript, netParam)
-	choosed, sum, gasFee, err := chooseUtxos(service, chainID, amountSum, append(outs, out), rk, m, len(addrs))
+	addrs, keys, m, _ := getVaultSigners(redeemScript, netParam)
+	n := len(addrs)
+	if keys != nil {
+		n = len(keys)
+	}
+	choosed, sum, gasFee, err := chooseUtxos(service, chainID, amountSum, append(outs, out), rk, m, n)
 	if err != nil {
 		return fmt.Errorf("makeBtcTx, chooseUtxos error: %v", err)
 	}
-	amts := make([]uint64, len(choosed))
-	txIns := make([]*wire.TxIn, len(choosed))
-	for i, u := range choosed {
-		hash, err := chainhash.NewHash(u.Op.Hash)
-		if err != nil {
-			return fmt.Errorf("makeBtcTx, chainhash.NewHash error: %v", err)
-		}
-		txIns[i] = wire.NewTxIn(wire.NewOutPoint(hash, u.Op.Index), u.ScriptPubkey, nil)
-		amts[i] = u.Value
+	txIns, err := getTxIns(choosed)
+	if err != nil {
+		return fmt.Errorf("makeBtcTx, %v", err)
 	}
 	for i := range outs {
 		outs[i].Value = outs[i].Value - int64(float64(gasFee)/float64(amountSum)*float64(outs[i].Value))
@@ -289,11 +524,31 @@ func makeBtcTx(service *native.NativeService, chainID uint64, amounts map[string
 	if err != nil {
 		return fmt.Errorf("makeBtcTx, get rawtransaction fail: %v", err)
 	}
+	if err = putBtcTx(service, chainID, mtx, choosed, fromTxHash, fromChainID, rk); err != nil {
+		return fmt.Errorf("makeBtcTx, %v", err)
+	}
+	return nil
+}
 
+func getTxIns(utxos []*Utxo) ([]*wire.TxIn, error) {
+	txIns := make([]*wire.TxIn, len(utxos))
+	for i, u := range utxos {
+		hash, err := chainhash.NewHash(u.Op.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("chainhash.NewHash error: %v", err)
+		}
+		txIns[i] = wire.NewTxIn(wire.NewOutPoint(hash, u.Op.Index), u.ScriptPubkey, nil)
+	}
+	return txIns, nil
+}
+
+// putBtcTx saves the unsigned tx spending inputs of vault rk for the signers to sign
+func putBtcTx(service *native.NativeService, chainID uint64, mtx *wire.MsgTx, inputs []*Utxo, fromTxHash []byte,
+	fromChainID uint64, rk []byte) error {
 	var buf bytes.Buffer
-	err = mtx.BtcEncode(&buf, wire.ProtocolVersion, wire.LatestEncoding)
+	err := mtx.BtcEncode(&buf, wire.ProtocolVersion, wire.LatestEncoding)
 	if err != nil {
-		return fmt.Errorf("makeBtcTx, serialize rawtransaction fail: %v", err)
+		return fmt.Errorf("serialize rawtransaction fail: %v", err)
 	}
 	txHash := mtx.TxHash()
 	service.GetCacheDB().Put(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(BTC_TX_PREFIX),
@@ -304,13 +559,51 @@ func makeBtcTx(service *native.NativeService, chainID uint64, amounts map[string
 		FromChainID: fromChainID,
 	}
 	if err = putBtcFromInfo(service, txHash[:], btcFromInfo); err != nil {
-		return fmt.Errorf("makeBtcTx, putBtcFromInfo failed: %v", err)
+		return fmt.Errorf("putBtcFromInfo failed: %v", err)
+	}
+	putBtcTxInputs(service, chainID, txHash[:], &Utxos{Utxos: inputs})
+	amts := make([]uint64, len(inputs))
+	for i, u := range inputs {
+		amts[i] = u.Value
 	}
 	service.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.CrossChainManagerContractAddress,
 			States:          []interface{}{"makeBtcTx", hex.EncodeToString(rk), hex.EncodeToString(buf.Bytes()), amts},
 		})
+	return nil
+}
 
+// addChangeUtxos records the outputs of the signed tx paying back to the vault of redeemKey, or to
+// the vault it is migrated to
+func addChangeUtxos(service *native.NativeService, chainID uint64, redeemKey string, redeemScript []byte,
+	netParam *chaincfg.Params, mtx *wire.MsgTx) error {
+	rk, err := hex.DecodeString(redeemKey)
+	if err != nil {
+		return fmt.Errorf("addChangeUtxos, hex.DecodeString error: %v", err)
+	}
+	redeemKey, witScript, err := getChangeLock(service, chainID, rk, redeemScript, netParam)
+	if err != nil {
+		return fmt.Errorf("addChangeUtxos, failed to get lock script: %v", err)
+	}
+	utxos, err := getUtxos(service, chainID, redeemKey)
+	if err != nil {
+		return fmt.Errorf("addChangeUtxos, getUtxos error: %v", err)
+	}
+	txid := mtx.TxHash()
+	for i, v := range mtx.TxOut {
+		if bytes.Equal(witScript, v.PkScript) {
+			newUtxo := &Utxo{
+				Op: &OutPoint{
+					Hash:  txid[:],
+					Index: uint32(i),
+				},
+				Value:        uint64(v.Value),
+				ScriptPubkey: v.PkScript,
+			}
+			utxos.Utxos = append(utxos.Utxos, newUtxo)
+		}
+	}
+	putUtxos(service, chainID, redeemKey, utxos)
 	return nil
 }
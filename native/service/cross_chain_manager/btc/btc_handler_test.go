@@ -21,7 +21,10 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/polynetwork/poly/account"
@@ -43,7 +46,8 @@ import (
 )
 
 var (
-	acct *account.Account = account.NewAccount("")
+	acct     *account.Account = account.NewAccount("")
+	netParam                  = &chaincfg.TestNet3Params
 
 	rdm               = "552102dec9a415b6384ec0a9331d0cdf02020f0f1e5731c327b86e2b5a92455a289748210365b1066bcfa21987c3e207b92e309b95ca6bee5f1133cf04d6ed4ed265eafdbc21031104e387cd1a103c27fdc8a52d5c68dec25ddfb2f574fbdca405edfd8c5187de21031fdb4b44a9f20883aff505009ebc18702774c105cb04b1eecebcb294d404b1cb210387cda955196cc2b2fc0adbbbac1776f8de77b563c6d2a06a77d96457dc3d0d1f2102dd7767b6a7cc83693343ba721e0f5f4c7b4b8d85eeb7aec20d227625ec0f59d321034ad129efdab75061e8d4def08f5911495af2dae6d3e9a4b6e7aeb5186fa432fc57ae"
 	fromBtcTxid       = "2587a59e8069c563d32de9d4a2b946760d740b6963566dd7b32d8ec549f2d238"
@@ -56,14 +60,6 @@ var (
 	toEthAddr         = "0x5cD3143f91a13Fe971043E1e4605C1c23b46bF44"
 	ebtcxAddr         = "0x9702640a6b971CA18EFC20AD73CA4e8bA390C910"
 
-	sigs = []string{
-		"3045022100f77b28268bfed3c0ddc8d35e556164d7ce2f571715b5afbf79373e699f4023e102200f86442f305528088caca96453d7f4fd782c831198c51e7637a7024ca844e10d01",
-		"30450221009a320292de5b0881f2988b603d7dac98c666c9653c177e1add6e26b2ae7902860220211d58d5e261e9ce80fa1b12892f02eb1a10c04712fe1609464629a794f5eaa001",
-		"30440220269ba3ab6e8d2513bb908e13c73b89fad28743136e56434bce7f9467c92a70cc02207149b2ae0b8648d5048d2505464582972f12522b36f1517af16af5408c0ed01a01",
-		"3044022046e9fa4e00ace70f5f1136a97257144cb83947d2685cfb6167fb3d61e563896802202f085fd5a202b1ccd471b4341effb788e36a5beed9b1bed4865e2c9a8a5f86e501",
-		"3045022100b457c88a42276924eafbb07e48f642410a07d72516739ab077386088e31ed6dc0220679a39f6aef46d980d031a0df301d74ee750d82ac2c8e0951d2ab1d8444346ec01",
-	}
-
 	signers = []string{
 		"mxtJn3aRsKLrWRLLAhu2nCBuK2brfazedj",
 		"msu1qgtn4FsQh7xDP15ggStwW4yHquUTYE",
@@ -83,11 +79,14 @@ var (
 	}
 
 	setSideChain = func(ns *native.NativeService) {
+		ccmc := make([]byte, 8)
+		binary.LittleEndian.PutUint64(ccmc, uint64(utils.TyTestnet3))
 		side := &side_chain_manager.SideChain{
 			Name:         "btc",
 			ChainId:      1,
 			BlocksToWait: 1,
 			Router:       0,
+			CCMCAddress:  ccmc,
 		}
 		sink := common.NewZeroCopySink(nil)
 		_ = side.Serialization(sink)
@@ -96,15 +95,6 @@ var (
 			[]byte(side_chain_manager.SIDE_CHAIN), utils.GetUint64Bytes(1)), states.GenRawStorageItem(sink.Bytes()))
 	}
 
-	getSigs = func() [][]byte {
-		res := make([][]byte, len(sigs))
-		for i, sig := range sigs {
-			raw, _ := hex.DecodeString(sig)
-			res[i] = raw
-		}
-		return res
-	}
-
 	registerRC = func(db *storage.CacheDB) *storage.CacheDB {
 		ca, _ := hex.DecodeString(strings.Replace(ebtcxAddr, "0x", "", 1))
 		cb := &side_chain_manager.ContractBinded{
@@ -242,41 +232,62 @@ func TestBTCHandler_MakeTransaction(t *testing.T) {
 }
 
 func TestBTCHandler_MultiSign(t *testing.T) {
-	rawTx, _ := hex.DecodeString(fromBtcRawTx)
-	mtx := wire.NewMsgTx(wire.TxVersion)
-	_ = mtx.BtcDecode(bytes.NewBuffer(rawTx), wire.ProtocolVersion, wire.LatestEncoding)
+	privs := make([]*btcec.PrivateKey, 7)
+	pubs := make([]*btcutil.AddressPubKey, 7)
+	addrs := make([]string, 7)
+	for i := range privs {
+		privs[i], _ = btcec.NewPrivateKey(btcec.S256())
+		pubs[i], _ = btcutil.NewAddressPubKey(privs[i].PubKey().SerializeCompressed(), netParam)
+		addrs[i] = pubs[i].EncodeAddress()
+	}
+	rb, _ := txscript.MultiSigScript(pubs, 5)
+	rk := hex.EncodeToString(btcutil.Hash160(rb))
+	lock, _ := getLockScript(rb, netParam)
+
 	ns := getNativeFunc(nil, nil)
-	_ = addUtxos(ns, 1, 0, mtx)
-	setBtcTxParam(ns.GetCacheDB(), utxoKey)
-	registerRC(ns.GetCacheDB())
+	setSideChain(ns)
+	setBtcTxParam(ns.GetCacheDB(), rk)
+	ns.GetCacheDB().Put(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(side_chain_manager.REDEEM_SCRIPT),
+		utils.GetUint64Bytes(1), []byte(rk)), states.GenRawStorageItem(rb))
+	op := &OutPoint{Hash: bytes.Repeat([]byte{1}, 32)}
+	putUtxos(ns, 1, rk, &Utxos{Utxos: []*Utxo{{Op: op, Value: 10000, ScriptPubkey: lock}}})
 
-	rb, _ := hex.DecodeString(rdm)
 	err := makeBtcTx(ns, 1, map[string]int64{"mjEoyyCPsLzJ23xMX6Mti13zMyN36kzn57": 6000}, []byte{123},
 		2, rb, btcutil.Hash160(rb))
 	assert.NoError(t, err)
 	stateArr := ns.GetNotify()[0].States.([]interface{})
 	assert.Equal(t, "makeBtcTx", stateArr[0].(string))
-	assert.Equal(t, utxoKey, stateArr[1].(string))
+	assert.Equal(t, rk, stateArr[1].(string))
 
-	stxos, err := getStxos(ns, 1, utxoKey)
+	stxos, err := getStxos(ns, 1, rk)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(stxos.Utxos))
 	assert.Equal(t, uint64(10000), stxos.Utxos[0].Value)
-	assert.Equal(t, fromBtcTxid+":0", stxos.Utxos[0].Op.String())
+	assert.Equal(t, op.String(), stxos.Utxos[0].Op.String())
 
-	rawTx, _ = hex.DecodeString(stateArr[2].(string))
+	rawTx, _ := hex.DecodeString(stateArr[2].(string))
+	mtx := wire.NewMsgTx(wire.TxVersion)
 	_ = mtx.BtcDecode(bytes.NewBuffer(rawTx), wire.ProtocolVersion, wire.LatestEncoding)
 	assert.Equal(t, int64(4000), mtx.TxOut[1].Value)
-	handler := NewBTCHandler()
-	sigArr := getSigs()
 	txid := mtx.TxHash()
+	unsigned := mtx.Copy()
+	for _, in := range unsigned.TxIn {
+		in.SignatureScript = nil
+	}
+	sigArr := make([][]byte, len(privs))
+	for i, priv := range privs {
+		sigArr[i], err = txscript.RawTxInWitnessSignature(unsigned, txscript.NewTxSigHashes(unsigned), 0, 10000,
+			rb, txscript.SigHashAll, priv)
+		assert.NoError(t, err)
+	}
+	handler := NewBTCHandler()
 	// commit no.1 to 4 sig
 	for i, sig := range sigArr[:4] {
 		msp := ccmcom.MultiSignParam{
 			ChainID:   1,
 			TxHash:    txid.CloneBytes(),
-			Address:   signers[i],
-			RedeemKey: utxoKey,
+			Address:   addrs[i],
+			RedeemKey: rk,
 			Signs:     [][]byte{sig},
 		}
 		sink := common.NewZeroCopySink(nil)
@@ -291,8 +302,8 @@ func TestBTCHandler_MultiSign(t *testing.T) {
 	msp := ccmcom.MultiSignParam{
 		ChainID:   1,
 		TxHash:    txid.CloneBytes(),
-		Address:   signers[3],
-		RedeemKey: utxoKey,
+		Address:   addrs[3],
+		RedeemKey: rk,
 		Signs:     [][]byte{sigArr[3]},
 	}
 	sink := common.NewZeroCopySink(nil)
@@ -305,8 +316,8 @@ func TestBTCHandler_MultiSign(t *testing.T) {
 	msp = ccmcom.MultiSignParam{
 		ChainID:   1,
 		TxHash:    txid.CloneBytes(),
-		Address:   signers[3],
-		RedeemKey: utxoKey,
+		Address:   addrs[5],
+		RedeemKey: rk,
 		Signs:     [][]byte{sigArr[4]},
 	}
 	sink.Reset()
@@ -319,8 +330,8 @@ func TestBTCHandler_MultiSign(t *testing.T) {
 	msp = ccmcom.MultiSignParam{
 		ChainID:   1,
 		TxHash:    txid.CloneBytes(),
-		Address:   signers[4],
-		RedeemKey: utxoKey,
+		Address:   addrs[4],
+		RedeemKey: rk,
 		Signs:     [][]byte{sigArr[4]},
 	}
 	sink.Reset()
@@ -337,13 +348,141 @@ func TestBTCHandler_MultiSign(t *testing.T) {
 	err = mtx.BtcDecode(bytes.NewBuffer(rawTx), wire.ProtocolVersion, wire.LatestEncoding)
 	assert.NoError(t, err)
 	txid = mtx.TxHash()
-	utxos, err = getUtxos(ns, 1, utxoKey)
+	utxos, err := getUtxos(ns, 1, rk)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(utxos.Utxos))
 	assert.Equal(t, uint64(4000), utxos.Utxos[0].Value)
 	assert.Equal(t, txid.String()+":1", utxos.Utxos[0].Op.String())
 }
 
+func TestBTCHandler_ConsolidateAndBumpFee(t *testing.T) {
+	ns := getNativeFunc(nil, nil)
+	ccmc := make([]byte, 8)
+	binary.LittleEndian.PutUint64(ccmc, uint64(utils.TyTestnet3))
+	side := &side_chain_manager.SideChain{
+		Name:         "btc",
+		ChainId:      1,
+		BlocksToWait: 1,
+		CCMCAddress:  ccmc,
+	}
+	sink := common.NewZeroCopySink(nil)
+	_ = side.Serialization(sink)
+	ns.GetCacheDB().Put(utils.ConcatKey(utils.SideChainManagerContractAddress,
+		[]byte(side_chain_manager.SIDE_CHAIN), utils.GetUint64Bytes(1)), states.GenRawStorageItem(sink.Bytes()))
+	registerRC(ns.GetCacheDB())
+	setBtcTxParam(ns.GetCacheDB(), utxoKey)
+
+	rb, _ := hex.DecodeString(rdm)
+	lock, _ := getLockScript(rb, &chaincfg.TestNet3Params)
+	utxos := &Utxos{}
+	for i, v := range []uint64{10000, 20000, 50000} {
+		utxos.Utxos = append(utxos.Utxos, &Utxo{
+			Op:           &OutPoint{Hash: bytes.Repeat([]byte{byte(i + 1)}, 32)},
+			Value:        v,
+			ScriptPubkey: lock,
+		})
+	}
+	putUtxos(ns, 1, utxoKey, utxos)
+
+	handler := NewBTCHandler()
+	cp := &ConsolidateUtxosParam{ChainID: 1, RedeemKey: utxoKey, MaxInputs: 2}
+	sink = common.NewZeroCopySink(nil)
+	cp.Serialization(sink)
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	assert.NoError(t, handler.ConsolidateUtxos(ns))
+	stateArr := ns.GetNotify()[0].States.([]interface{})
+	assert.Equal(t, "makeBtcTx", stateArr[0].(string))
+	assert.Equal(t, []uint64{10000, 20000}, stateArr[3].([]uint64))
+	rawTx, _ := hex.DecodeString(stateArr[2].(string))
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	_ = mtx.BtcDecode(bytes.NewBuffer(rawTx), wire.ProtocolVersion, wire.LatestEncoding)
+	assert.Equal(t, 2, len(mtx.TxIn))
+	assert.Equal(t, 1, len(mtx.TxOut))
+	assert.Equal(t, lock, mtx.TxOut[0].PkScript)
+	for _, in := range mtx.TxIn {
+		assert.Equal(t, uint32(RBF_SEQUENCE), in.Sequence, "unsigned tx should signal BIP125")
+	}
+	oldValue := mtx.TxOut[0].Value
+	assert.True(t, oldValue < 30000)
+	left, _ := getUtxos(ns, 1, utxoKey)
+	assert.Equal(t, 1, len(left.Utxos))
+	assert.Equal(t, uint64(50000), left.Utxos[0].Value)
+	stxos, _ := getStxos(ns, 1, utxoKey)
+	assert.Equal(t, 2, len(stxos.Utxos))
+
+	// replace the unsigned consolidation
+	oldHash := mtx.TxHash()
+	bp := &BumpTxFeeParam{ChainID: 1, RedeemKey: utxoKey, TxHash: oldHash[:], FeeRate: 2}
+	sink = common.NewZeroCopySink(nil)
+	bp.Serialization(sink)
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	assert.Error(t, handler.BumpTxFee(ns), "same fee rate should fail")
+
+	bp.FeeRate = 10
+	sink = common.NewZeroCopySink(nil)
+	bp.Serialization(sink)
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	assert.NoError(t, handler.BumpTxFee(ns))
+	stateArr = ns.GetNotify()[0].States.([]interface{})
+	rawTx, _ = hex.DecodeString(stateArr[2].(string))
+	_ = mtx.BtcDecode(bytes.NewBuffer(rawTx), wire.ProtocolVersion, wire.LatestEncoding)
+	assert.True(t, mtx.TxOut[0].Value < oldValue)
+	for _, in := range mtx.TxIn {
+		assert.Equal(t, uint32(RBF_SEQUENCE), in.Sequence, "replacement should signal BIP125")
+	}
+	newHash := mtx.TxHash()
+	stateArr = ns.GetNotify()[1].States.([]interface{})
+	assert.Equal(t, "btcTxReplaced", stateArr[0].(string))
+	assert.Equal(t, hex.EncodeToString(newHash[:]), stateArr[3].(string))
+	stxos, _ = getStxos(ns, 1, utxoKey)
+	assert.Equal(t, 2, len(stxos.Utxos))
+
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	assert.Error(t, handler.BumpTxFee(ns), "replaced tx can't be replaced again")
+	msp := ccmcom.MultiSignParam{ChainID: 1, TxHash: oldHash[:], Address: signers[0], RedeemKey: utxoKey,
+		Signs: [][]byte{{1}, {1}}}
+	sink = common.NewZeroCopySink(nil)
+	msp.Serialization(sink)
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	err := handler.MultiSign(ns)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "replaced")
+
+	// take the replacement as signed, then replace it again
+	info := &MultiSignInfo{MultiSignInfo: make(map[string][][]byte)}
+	for _, s := range signers {
+		info.MultiSignInfo[s] = nil
+	}
+	_ = putBtcMultiSignInfo(ns, newHash[:], info)
+	_, stxos, _ = getStxoAmts(ns, 1, mtx.TxIn, utxoKey)
+	putStxos(ns, 1, utxoKey, stxos)
+	assert.NoError(t, addChangeUtxos(ns, 1, utxoKey, rb, &chaincfg.TestNet3Params, mtx))
+	left, _ = getUtxos(ns, 1, utxoKey)
+	assert.Equal(t, 2, len(left.Utxos))
+
+	bp = &BumpTxFeeParam{ChainID: 1, RedeemKey: utxoKey, TxHash: newHash[:], FeeRate: 20}
+	sink = common.NewZeroCopySink(nil)
+	bp.Serialization(sink)
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	assert.NoError(t, handler.BumpTxFee(ns))
+	left, _ = getUtxos(ns, 1, utxoKey)
+	assert.Equal(t, 1, len(left.Utxos))
+	stxos, _ = getStxos(ns, 1, utxoKey)
+	assert.Equal(t, 2, len(stxos.Utxos))
+
+	// a signed tx whose change is not in the utxos can't be replaced
+	stateArr = ns.GetNotify()[1].States.([]interface{})
+	lastHash, _ := hex.DecodeString(stateArr[3].(string))
+	_ = putBtcMultiSignInfo(ns, lastHash, info)
+	bp = &BumpTxFeeParam{ChainID: 1, RedeemKey: utxoKey, TxHash: lastHash, FeeRate: 30}
+	sink = common.NewZeroCopySink(nil)
+	bp.Serialization(sink)
+	ns = getNativeFunc(sink.Bytes(), ns.GetCacheDB())
+	err = handler.BumpTxFee(ns)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already spent")
+}
+
 func syncGenesisHeader(genesisHeader *wire.BlockHeader) (*storage.CacheDB, error) {
 	var buf bytes.Buffer
 	_ = genesisHeader.BtcEncode(&buf, wire.ProtocolVersion, wire.LatestEncoding)
@@ -25,6 +25,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/gcash/bchd/chaincfg/chainhash"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/service/utils/taproot"
 	"sort"
 	"strconv"
 )
@@ -305,20 +306,27 @@ func (selector *CoinSelector) estimateTxSize(selection []*Utxo) int {
 	redeemSize := 1 + selector.m*(1+75) + 1 + 1 + selector.n*(1+33) + 1 + 1
 	p2shInputSize := 43 + redeemSize
 	witnessInputSize := 41 + redeemSize/blockchain.WitnessScaleFactor
+	// n sig items of which m are schnorr sigs, the leaf and the control block
+	leafSize := selector.n*(1+32+1) + 2
+	taprootInputSize := 41 + (1+selector.n+selector.m*64+3+leafSize+1+33)/blockchain.WitnessScaleFactor
 	outsSize := 0
 	for _, txOut := range selector.txOuts {
 		outsSize += txOut.SerializeSize()
 	}
-	witNum := 0
+	witNum, trNum := 0, 0
 	for _, u := range selection {
+		if taproot.IsPayToTaproot(u.ScriptPubkey) {
+			trNum++
+			continue
+		}
 		switch txscript.GetScriptClass(u.ScriptPubkey) {
 		case txscript.WitnessV0ScriptHashTy:
 			witNum++
 		}
 	}
 	return 10 + 2 + wire.VarIntSerializeSize(uint64(len(selection))) +
-		wire.VarIntSerializeSize(uint64(len(selector.txOuts)+1)) + (len(selection)-witNum)*p2shInputSize +
-		witNum*witnessInputSize + outsSize
+		wire.VarIntSerializeSize(uint64(len(selector.txOuts)+1)) + (len(selection)-witNum-trNum)*p2shInputSize +
+		witNum*witnessInputSize + trNum*taprootInputSize + outsSize
 }
 
 type OutPoint struct {
@@ -463,3 +471,76 @@ func (this *BtcFromInfo) Deserialization(source *common.ZeroCopySource) error {
 	this.FromChainID = fromChainID
 	return nil
 }
+
+type ConsolidateUtxosParam struct {
+	ChainID   uint64
+	RedeemKey string
+	// MaxInputs is the max number of the smallest utxos merged into one
+	MaxInputs uint64
+	// FeeRate in satoshi per byte, the rate set by SetBtcTxParam is used if zero
+	FeeRate uint64
+}
+
+func (this *ConsolidateUtxosParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteString(this.RedeemKey)
+	sink.WriteVarUint(this.MaxInputs)
+	sink.WriteVarUint(this.FeeRate)
+}
+
+func (this *ConsolidateUtxosParam) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.ChainID, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ConsolidateUtxosParam deserialize chainID error")
+	}
+	this.RedeemKey, eof = source.NextString()
+	if eof {
+		return fmt.Errorf("ConsolidateUtxosParam deserialize redeemKey error")
+	}
+	this.MaxInputs, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ConsolidateUtxosParam deserialize maxInputs error")
+	}
+	this.FeeRate, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ConsolidateUtxosParam deserialize feeRate error")
+	}
+	return nil
+}
+
+type BumpTxFeeParam struct {
+	ChainID   uint64
+	RedeemKey string
+	TxHash    []byte
+	// FeeRate in satoshi per byte of the replacement
+	FeeRate uint64
+}
+
+func (this *BumpTxFeeParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteString(this.RedeemKey)
+	sink.WriteVarBytes(this.TxHash)
+	sink.WriteVarUint(this.FeeRate)
+}
+
+func (this *BumpTxFeeParam) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.ChainID, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("BumpTxFeeParam deserialize chainID error")
+	}
+	this.RedeemKey, eof = source.NextString()
+	if eof {
+		return fmt.Errorf("BumpTxFeeParam deserialize redeemKey error")
+	}
+	this.TxHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("BumpTxFeeParam deserialize txHash error")
+	}
+	this.FeeRate, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("BumpTxFeeParam deserialize feeRate error")
+	}
+	return nil
+}
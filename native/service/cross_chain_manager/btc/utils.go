@@ -39,6 +39,7 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/btc"
 	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/service/utils/taproot"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -49,9 +50,13 @@ const (
 	UTXOS                   = "utxos"
 	STXOS                   = "stxos"
 	MULTI_SIGN_INFO         = "multiSignInfo"
+	BTC_TX_INPUTS           = "btcTxInputs"
+	BTC_TX_REPLACED         = "btcTxReplaced"
 	MAX_FEE_COST_PERCENTS   = 1.0
 	MAX_SELECTING_TRY_LIMIT = 1000000
 	SELECTING_K             = 4.0
+	// sequence of vault inputs, below MaxTxInSequenceNum-1 to opt in to BIP125 replacement
+	RBF_SEQUENCE = wire.MaxTxInSequenceNum - 2
 )
 
 func getNetParam(service *native.NativeService, chainId uint64) (*chaincfg.Params, error) {
@@ -123,7 +128,10 @@ func verifyFromBtcTx(native *native.NativeService, proof, tx []byte, fromChainID
 	if err != nil {
 		return nil, fmt.Errorf("verifyFromBtcTx, failed to resolve parameter: %v", err)
 	}
-	rk := GetUtxoKey(mtx.TxOut[0].PkScript)
+	rk, err := getUtxoKey(native, fromChainID, mtx.TxOut[0].PkScript)
+	if err != nil {
+		return nil, fmt.Errorf("verifyFromBtcTx, %v", err)
+	}
 	redeemKey, err := hex.DecodeString(rk)
 	if err != nil {
 		return nil, fmt.Errorf("verifyFromBtcTx, hex.DecodeString error: %v", err)
@@ -219,12 +227,11 @@ func getUnsignedTx(txIns []*wire.TxIn, outs []*wire.TxOut, changeOut *wire.TxOut
 	}
 
 	// Add all transaction inputs to a new transaction after performing
-	// some validity checks.
+	// some validity checks. Inputs signal BIP125 so that the tx can be
+	// replaced by BumpTxFee, which also makes the locktime take effect.
 	mtx := wire.NewMsgTx(wire.TxVersion)
 	for _, in := range txIns {
-		if locktime != nil && *locktime != 0 {
-			in.Sequence = wire.MaxTxInSequenceNum - 1
-		}
+		in.Sequence = RBF_SEQUENCE
 		mtx.AddTxIn(in)
 	}
 	for _, out := range outs {
@@ -268,6 +275,9 @@ func getTxOuts(amounts map[string]int64, netParam *chaincfg.Params) ([]*wire.TxO
 }
 
 func getLockScript(redeem []byte, netParam *chaincfg.Params) ([]byte, error) {
+	if taproot.IsMultisigScript(redeem) {
+		return taproot.PayToTaprootScript(redeem), nil
+	}
 	hasher := sha256.New()
 	hasher.Write(redeem)
 	witAddr, err := btcutil.NewAddressWitnessScriptHash(hasher.Sum(nil), netParam)
@@ -296,8 +306,32 @@ func GetUtxoKey(scriptPk []byte) string {
 	}
 }
 
+// getUtxoKey is GetUtxoKey also knowing the P2TR vaults registered
+func getUtxoKey(native *native.NativeService, chainID uint64, scriptPk []byte) (string, error) {
+	if taproot.IsPayToTaproot(scriptPk) {
+		return side_chain_manager.GetTaprootRedeemKey(native, chainID, scriptPk[2:])
+	}
+	return GetUtxoKey(scriptPk), nil
+}
+
+// getVaultSigners returns the signer addresses, or x-only public keys for a taproot vault, and the
+// number of required signatures of redeem
+func getVaultSigners(redeem []byte, netParam *chaincfg.Params) ([]btcutil.Address, [][]byte, int, error) {
+	if keys, m, err := taproot.ParseMultisigScript(redeem); err == nil {
+		return nil, keys, m, nil
+	}
+	_, addrs, m, err := txscript.ExtractPkScriptAddrs(redeem, netParam)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return addrs, nil, m, nil
+}
+
 func addUtxos(native *native.NativeService, chainID uint64, height uint32, mtx *wire.MsgTx) error {
-	utxoKey := GetUtxoKey(mtx.TxOut[0].PkScript)
+	utxoKey, err := getUtxoKey(native, chainID, mtx.TxOut[0].PkScript)
+	if err != nil {
+		return fmt.Errorf("addUtxos, %v", err)
+	}
 
 	utxos, err := getUtxos(native, chainID, utxoKey)
 	if err != nil {
@@ -420,6 +454,54 @@ func getStxos(native *native.NativeService, chainID uint64, stxoKey string) (*Ut
 	return stxos, err
 }
 
+// putBtcTxInputs keeps the utxos spent by the tx so that it can be replaced later
+func putBtcTxInputs(native *native.NativeService, chainID uint64, txid []byte, inputs *Utxos) {
+	putTxos(BTC_TX_INPUTS, native, chainID, hex.EncodeToString(txid), inputs)
+}
+
+func getBtcTxInputs(native *native.NativeService, chainID uint64, txid []byte) (*Utxos, error) {
+	return getTxos(BTC_TX_INPUTS, native, chainID, hex.EncodeToString(txid))
+}
+
+func putBtcTxReplaced(native *native.NativeService, txid, replacement []byte) {
+	key := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(BTC_TX_REPLACED), txid)
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(replacement))
+}
+
+// getBtcTxReplaced returns the hash of the tx replacing txid, nil if it is not replaced
+func getBtcTxReplaced(native *native.NativeService, txid []byte) ([]byte, error) {
+	key := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(BTC_TX_REPLACED), txid)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("getBtcTxReplaced, get replacement error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	replacement, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getBtcTxReplaced, deserialize from raw storage item err:%v", err)
+	}
+	return replacement, nil
+}
+
+// getChangeLock returns the key and lock script of the vault receiving the change of vault rk
+func getChangeLock(native *native.NativeService, chainID uint64, rk, redeem []byte,
+	netParam *chaincfg.Params) (string, []byte, error) {
+	newRedeem, err := side_chain_manager.GetVaultMigration(native, chainID, rk)
+	if err != nil {
+		return "", nil, err
+	}
+	if newRedeem != nil {
+		rk, redeem = btcutil.Hash160(newRedeem), newRedeem
+	}
+	script, err := getLockScript(redeem, netParam)
+	if err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(rk), script, nil
+}
+
 func getStxoAmts(service *native.NativeService, chainID uint64, txIns []*wire.TxIn, redeemKey string) ([]uint64, *Utxos, error) {
 	stxos, err := getStxos(service, chainID, redeemKey)
 	if err != nil {
@@ -493,6 +575,46 @@ func verifySigs(sigs [][]byte, addr string, addrs []btcutil.Address, redeem []by
 	return nil
 }
 
+// verifyTaprootSigs verifies the schnorr signatures of signer with x-only public key addr for all
+// inputs spending the taproot vault leaf redeem
+func verifyTaprootSigs(sigs [][]byte, addr string, keys [][]byte, redeem []byte, tx *wire.MsgTx,
+	pkScripts [][]byte, amts []uint64) error {
+	if len(sigs) != len(tx.TxIn) {
+		return fmt.Errorf("not enough sig, only %d sigs but %d required", len(sigs), len(tx.TxIn))
+	}
+	var signer []byte
+	for _, k := range keys {
+		if hex.EncodeToString(k) == addr {
+			signer = k
+		}
+	}
+	if signer == nil {
+		return fmt.Errorf("public key %s not found in taproot script", addr)
+	}
+
+	prevAmts := make([]int64, len(amts))
+	for i, v := range amts {
+		prevAmts[i] = int64(v)
+	}
+	for i, sig := range sigs {
+		if !taproot.IsPayToTaproot(pkScripts[i]) {
+			return fmt.Errorf("script of no.%d utxo is not P2TR", i)
+		}
+		rawSig, hashType, err := taproot.ParseSignature(sig)
+		if err != nil {
+			return fmt.Errorf("failed to parse no.%d sig: %v", i, err)
+		}
+		hash, err := taproot.CalcScriptPathSigHash(tx, i, prevAmts, pkScripts, hashType, redeem)
+		if err != nil {
+			return fmt.Errorf("failed to calculate sig hash: %v", err)
+		}
+		if !taproot.VerifySchnorr(signer, hash, rawSig) {
+			return fmt.Errorf("verify no.%d sig and not pass", i+1)
+		}
+	}
+	return nil
+}
+
 func putBtcMultiSignInfo(native *native.NativeService, txid []byte, multiSignInfo *MultiSignInfo) error {
 	key := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(MULTI_SIGN_INFO), txid)
 	sink := common.NewZeroCopySink(nil)
@@ -570,6 +692,23 @@ func addSigToTx(sigMap *MultiSignInfo, addrs []btcutil.Address, redeem []byte, t
 	return nil
 }
 
+// addTaprootSigToTx puts the witness <sig of key n> ... <sig of key 1> <leaf> <control block> to
+// every input, with empty signatures of the keys not signing
+func addTaprootSigToTx(sigMap *MultiSignInfo, keys [][]byte, redeem []byte, tx *wire.MsgTx) {
+	controlBlock := taproot.ControlBlock(redeem)
+	for i := 0; i < len(tx.TxIn); i++ {
+		data := make([][]byte, 0, len(keys)+2)
+		for j := len(keys) - 1; j >= 0; j-- {
+			sig := []byte{}
+			if signs, ok := sigMap.MultiSignInfo[hex.EncodeToString(keys[j])]; ok {
+				sig = signs[i]
+			}
+			data = append(data, sig)
+		}
+		tx.TxIn[i].Witness = wire.TxWitness(append(data, redeem, controlBlock))
+	}
+}
+
 func putBtcFromInfo(native *native.NativeService, txid []byte, btcFromInfo *BtcFromInfo) error {
 	key := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(BTC_FROM_TX_PREFIX), txid)
 	sink := common.NewZeroCopySink(nil)
@@ -19,10 +19,13 @@ package btc
 import (
 	"bytes"
 	"encoding/hex"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
+	"github.com/polynetwork/poly/native/service/utils/taproot"
 	"sort"
 	"testing"
 )
@@ -268,3 +271,74 @@ func TestUtxos_Choose(t *testing.T) {
 		t.Fatal("err should not be nil")
 	}
 }
+
+func TestTaprootSigs(t *testing.T) {
+	privs := make([]*btcec.PrivateKey, 3)
+	keys := make([][]byte, 3)
+	for i := range privs {
+		privs[i], _ = btcec.NewPrivateKey(btcec.S256())
+		keys[i] = taproot.XOnlyPubKey(privs[i].PubKey())
+	}
+	rs, err := taproot.MultisigScript(keys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := getLockScript(rs, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(lock, taproot.PayToTaprootScript(rs)) {
+		t.Fatal("lock script of taproot vault should be P2TR")
+	}
+
+	mtx := wire.NewMsgTx(wire.TxVersion)
+	mtx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	mtx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{2}, 1), nil, nil))
+	mtx.AddTxOut(wire.NewTxOut(8000, p2sh))
+	pkScripts := [][]byte{lock, lock}
+	amts := []uint64{5000, 4000}
+
+	sign := func(priv *btcec.PrivateKey) [][]byte {
+		res := make([][]byte, len(mtx.TxIn))
+		for i := range mtx.TxIn {
+			hash, err := taproot.CalcScriptPathSigHash(mtx, i, []int64{5000, 4000}, pkScripts, taproot.SigHashDefault, rs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res[i], _ = taproot.SignSchnorr(priv, hash, make([]byte, 32))
+		}
+		return res
+	}
+	sigMap := &MultiSignInfo{MultiSignInfo: make(map[string][][]byte)}
+	for _, i := range []int{0, 2} {
+		sigs := sign(privs[i])
+		if err = verifyTaprootSigs(sigs, hex.EncodeToString(keys[i]), keys, rs, mtx, pkScripts, amts); err != nil {
+			t.Fatal(err)
+		}
+		sigMap.MultiSignInfo[hex.EncodeToString(keys[i])] = sigs
+	}
+	sigs := sign(privs[0])
+	if err = verifyTaprootSigs(sigs, hex.EncodeToString(keys[1]), keys, rs, mtx, pkScripts, amts); err == nil {
+		t.Fatal("sigs of another signer should fail")
+	}
+	if err = verifyTaprootSigs(sigs, hex.EncodeToString(keys[0]), keys, rs, mtx, pkScripts, []uint64{5000, 4001}); err == nil {
+		t.Fatal("sigs on wrong amounts should fail")
+	}
+
+	addTaprootSigToTx(sigMap, keys, rs, mtx)
+	for i, in := range mtx.TxIn {
+		w := in.Witness
+		if len(w) != 5 || len(w[1]) != 0 || !bytes.Equal(w[0], sigMap.MultiSignInfo[hex.EncodeToString(keys[2])][i]) ||
+			!bytes.Equal(w[2], sigMap.MultiSignInfo[hex.EncodeToString(keys[0])][i]) || !bytes.Equal(w[3], rs) ||
+			!bytes.Equal(w[4], taproot.ControlBlock(rs)) {
+			t.Fatalf("wrong witness of no.%d input", i)
+		}
+	}
+
+	cs := &CoinSelector{txOuts: mtx.TxOut, m: 2, n: 3}
+	trSize := cs.estimateTxSize([]*Utxo{{ScriptPubkey: lock}})
+	witSize := cs.estimateTxSize([]*Utxo{{ScriptPubkey: witPubScript}})
+	if trSize <= 41 || trSize >= witSize+20 {
+		t.Fatalf("unexpected size %d of taproot input against %d of P2WSH", trSize, witSize)
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"math/big"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -35,6 +34,7 @@ import (
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/bytom"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
 )
 
 // Handler ...
@@ -89,6 +89,14 @@ func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID
 		return nil, fmt.Errorf("verifyFromTx, GetCanonicalHeader height:%d, error:%s", height, err)
 	}
 
+	if scom.IsReceiptProofMode(sideChain.ExtraInfo) {
+		txParam, err := scom.VerifyFromReceiptProof(headerWithSum.Header.ReceiptHash, proof, extra, sideChain.CCMCAddress)
+		if err != nil {
+			return nil, fmt.Errorf("verifyFromTx, %v", err)
+		}
+		return txParam, nil
+	}
+
 	bytomProof := new(Proof)
 	err = json.Unmarshal(proof, bytomProof)
 	if err != nil {
@@ -146,7 +154,7 @@ type ProofAccount struct {
 	Codehash ecommon.Hash
 }
 
-func verifyMerkleProof(bytomProof *Proof, blockData *types.Header, contractAddr []byte) ([]byte, error) {
+func verifyMerkleProof(bytomProof *Proof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
 	//1. prepare verify account
 	nodeList := new(light.NodeList)
 
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cometbft
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/cosmos"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync/cometbft"
+	tmcrypto "github.com/switcheo/tendermint/proto/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+// CCM_STORE_KEY is the store of cross chain manager module in app state, cross chain tx params are
+// stored in it under the registered CCMCAddress prefix followed by the cross chain id
+const CCM_STORE_KEY = "ccm"
+
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// ProofValue is the key path and value proved by the ics23 proof in EntranceParam.Proof
+type ProofValue struct {
+	Kp    string
+	Value []byte
+}
+
+func (this *ProofValue) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.Kp)
+	sink.WriteVarBytes(this.Value)
+}
+
+func (this *ProofValue) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Kp, eof = source.NextString()
+	if eof {
+		return fmt.Errorf("deserialize Kp of ProofValue failed")
+	}
+	this.Value, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize Value of ProofValue failed")
+	}
+	return nil
+}
+
+// MakeDepositProposal verifies the protobuf encoded ProofOps in params.Proof against the app hash
+// of the synced header at params.Height. Note that app hash of header H commits to the state after
+// block H-1, so the proof should be queried at height H-1. A serialized LightBlockUpdate for height H
+// can be given in params.HeaderOrCrossChainMsg if it is not synced yet.
+func (this *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
+	params := new(scom.EntranceParam)
+	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, contract params deserialize error: %s", err)
+	}
+	var cs *cometbft.ConsensusState
+	if len(params.HeaderOrCrossChainMsg) != 0 {
+		update := new(cometbft.LightBlockUpdate)
+		if err := update.Deserialization(common.NewZeroCopySource(params.HeaderOrCrossChainMsg)); err != nil {
+			return nil, fmt.Errorf("CometBFT MakeDepositProposal, deserialize light block update error: %v", err)
+		}
+		var err error
+		if cs, _, err = cometbft.VerifyLightBlock(service, params.SourceChainID, update); err != nil {
+			return nil, fmt.Errorf("CometBFT MakeDepositProposal, %v", err)
+		}
+		if cs.Height != int64(params.Height) {
+			return nil, fmt.Errorf("CometBFT MakeDepositProposal, height of your header is %d not equal to %d "+
+				"in parameter", cs.Height, params.Height)
+		}
+	} else {
+		var err error
+		if cs, err = cometbft.GetConsensusState(service, params.SourceChainID, int64(params.Height)); err != nil {
+			return nil, fmt.Errorf("CometBFT MakeDepositProposal, %v", err)
+		}
+		if cs == nil {
+			return nil, fmt.Errorf("CometBFT MakeDepositProposal, header at height %d is not synced", params.Height)
+		}
+	}
+
+	proofValue := new(ProofValue)
+	if err := proofValue.Deserialization(common.NewZeroCopySource(params.Extra)); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, deserialize proof value error: %v", err)
+	}
+	if len(proofValue.Kp) == 0 {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, key path of proof value is empty")
+	}
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, side chain %d is not registered", params.SourceChainID)
+	}
+	ops := new(tmcrypto.ProofOps)
+	if err := ops.Unmarshal(params.Proof); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, unmarshal proof error: %v", err)
+	}
+	proof := &merkle.Proof{Ops: make([]merkle.ProofOp, 0, len(ops.Ops))}
+	for _, op := range ops.Ops {
+		proof.Ops = append(proof.Ops, merkle.ProofOp{Type: op.Type, Key: op.Key, Data: op.Data})
+	}
+	if err := cosmos.ProofRuntime().VerifyValue(proof, cs.AppHash, proofValue.Kp, proofValue.Value); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, proof error: %v", err)
+	}
+
+	txParam := new(scom.MakeTxParam)
+	if err := txParam.Deserialization(common.NewZeroCopySource(proofValue.Value)); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, deserialize merkleValue error: %v", err)
+	}
+	if err := checkKeyPath(proofValue.Kp, sideChain.CCMCAddress, txParam.CrossChainID); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, %v", err)
+	}
+	if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, check done transaction error: %v", err)
+	}
+	if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("CometBFT MakeDepositProposal, PutDoneTx error: %v", err)
+	}
+	return txParam, nil
+}
+
+// checkKeyPath makes sure the proved value is the cross chain tx param stored by the registered
+// cross chain manager, any other key in app state is rejected
+func checkKeyPath(kp string, ccmcAddress, crossChainID []byte) error {
+	keys, err := merkle.KeyPathToKeys(kp)
+	if err != nil {
+		return fmt.Errorf("invalid key path %s: %v", kp, err)
+	}
+	key := append(append([]byte{}, ccmcAddress...), crossChainID...)
+	if len(keys) != 2 || string(keys[0]) != CCM_STORE_KEY || !bytes.Equal(keys[1], key) {
+		expected := merkle.KeyPath{}.AppendKey([]byte(CCM_STORE_KEY), merkle.KeyEncodingURL).
+			AppendKey(key, merkle.KeyEncodingHex)
+		return fmt.Errorf("key path %s of proof value is not %s", kp, expected)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cometbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+func TestCheckKeyPath(t *testing.T) {
+	ccmc := []byte{0x01}
+	id := []byte{0xaa, 0xbb}
+	kp := func(store string, key []byte) string {
+		return merkle.KeyPath{}.AppendKey([]byte(store), merkle.KeyEncodingURL).
+			AppendKey(key, merkle.KeyEncodingHex).String()
+	}
+
+	assert.Nil(t, checkKeyPath(kp(CCM_STORE_KEY, []byte{0x01, 0xaa, 0xbb}), ccmc, id))
+	// same keys in url encoding
+	assert.Nil(t, checkKeyPath(merkle.KeyPath{}.AppendKey([]byte(CCM_STORE_KEY), merkle.KeyEncodingURL).
+		AppendKey([]byte{0x01, 0xaa, 0xbb}, merkle.KeyEncodingURL).String(), ccmc, id))
+
+	assert.NotNil(t, checkKeyPath(kp("bank", []byte{0x01, 0xaa, 0xbb}), ccmc, id))
+	assert.NotNil(t, checkKeyPath(kp(CCM_STORE_KEY, []byte{0x02, 0xaa, 0xbb}), ccmc, id))
+	assert.NotNil(t, checkKeyPath(kp(CCM_STORE_KEY, []byte{0x01, 0xaa}), ccmc, id))
+	assert.NotNil(t, checkKeyPath(kp(CCM_STORE_KEY, []byte{0x01, 0xaa, 0xbb})+"/x:00", ccmc, id))
+	assert.NotNil(t, checkKeyPath("ccm", ccmc, id))
+}
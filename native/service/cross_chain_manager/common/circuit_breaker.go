@@ -0,0 +1,288 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// RateLimit caps the number of cross chain messages accepted in every fixed window of Window blocks.
+type RateLimit struct {
+	Window uint32
+	Limit  uint64
+}
+
+func (this *RateLimit) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.Window)
+	sink.WriteUint64(this.Limit)
+}
+
+func (this *RateLimit) Deserialization(source *common.ZeroCopySource) error {
+	window, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize window error")
+	}
+	limit, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimit deserialize limit error")
+	}
+	this.Window = window
+	this.Limit = limit
+	return nil
+}
+
+// RateCounter counts the messages accepted in the window starting at WindowStart.
+type RateCounter struct {
+	WindowStart uint32
+	Count       uint64
+}
+
+func (this *RateCounter) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint32(this.WindowStart)
+	sink.WriteUint64(this.Count)
+}
+
+func (this *RateCounter) Deserialization(source *common.ZeroCopySource) error {
+	windowStart, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RateCounter deserialize windowStart error")
+	}
+	count, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateCounter deserialize count error")
+	}
+	this.WindowStart = windowStart
+	this.Count = count
+	return nil
+}
+
+func sourceRateKey(prefix string, chainID uint64, contract []byte) []byte {
+	return utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(prefix), utils.GetUint64Bytes(chainID), contract)
+}
+
+func targetRateKey(prefix string, chainID uint64) []byte {
+	return utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(prefix), utils.GetUint64Bytes(chainID))
+}
+
+func getRateLimit(native *native.NativeService, key []byte) (*RateLimit, error) {
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("getRateLimit, get rate limit store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getRateLimit, deserialize from raw storage item error: %v", err)
+	}
+	limit := new(RateLimit)
+	if err := limit.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("getRateLimit, deserialize rate limit error: %v", err)
+	}
+	return limit, nil
+}
+
+func putRateLimit(native *native.NativeService, key []byte, limit *RateLimit) {
+	sink := common.NewZeroCopySink(nil)
+	limit.Serialization(sink)
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(sink.Bytes()))
+}
+
+func getRateCounter(native *native.NativeService, key []byte) (*RateCounter, error) {
+	counter := new(RateCounter)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("getRateCounter, get rate counter store error: %v", err)
+	}
+	if store == nil {
+		return counter, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("getRateCounter, deserialize from raw storage item error: %v", err)
+	}
+	if err := counter.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("getRateCounter, deserialize rate counter error: %v", err)
+	}
+	return counter, nil
+}
+
+func putRateCounter(native *native.NativeService, key []byte, counter *RateCounter) {
+	sink := common.NewZeroCopySink(nil)
+	counter.Serialization(sink)
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(sink.Bytes()))
+}
+
+func GetSourceRateLimit(native *native.NativeService, chainID uint64, contract []byte) (*RateLimit, error) {
+	return getRateLimit(native, sourceRateKey(SOURCE_RATE_LIMIT, chainID, contract))
+}
+
+// PutSourceRateLimit sets the rate limit of (chainID, contract), a zero limit removes it.
+func PutSourceRateLimit(native *native.NativeService, chainID uint64, contract []byte, limit *RateLimit) {
+	if limit.Limit == 0 {
+		native.GetCacheDB().Delete(sourceRateKey(SOURCE_RATE_LIMIT, chainID, contract))
+		native.GetCacheDB().Delete(sourceRateKey(SOURCE_RATE_COUNTER, chainID, contract))
+		return
+	}
+	putRateLimit(native, sourceRateKey(SOURCE_RATE_LIMIT, chainID, contract), limit)
+}
+
+func GetTargetRateLimit(native *native.NativeService, chainID uint64) (*RateLimit, error) {
+	return getRateLimit(native, targetRateKey(TARGET_RATE_LIMIT, chainID))
+}
+
+// PutTargetRateLimit sets the rate limit of messages to chainID, a zero limit removes it.
+func PutTargetRateLimit(native *native.NativeService, chainID uint64, limit *RateLimit) {
+	if limit.Limit == 0 {
+		native.GetCacheDB().Delete(targetRateKey(TARGET_RATE_LIMIT, chainID))
+		native.GetCacheDB().Delete(targetRateKey(TARGET_RATE_COUNTER, chainID))
+		return
+	}
+	putRateLimit(native, targetRateKey(TARGET_RATE_LIMIT, chainID), limit)
+}
+
+// consumeRate counts one message against limit and fails once the current window is full.
+func consumeRate(native *native.NativeService, limit *RateLimit, counterKey []byte) error {
+	height := native.GetHeight()
+	windowStart := height - height%limit.Window
+	counter, err := getRateCounter(native, counterKey)
+	if err != nil {
+		return err
+	}
+	if counter.WindowStart != windowStart {
+		counter.WindowStart = windowStart
+		counter.Count = 0
+	}
+	if counter.Count+1 > limit.Limit {
+		return fmt.Errorf("rate limit %d per %d blocks exceeded", limit.Limit, limit.Window)
+	}
+	counter.Count++
+	putRateCounter(native, counterKey, counter)
+	return nil
+}
+
+func GetFromContractAllowlist(native *native.NativeService, chainID uint64) ([][]byte, error) {
+	store, err := native.GetCacheDB().Get(targetRateKey(FROM_CONTRACT_ALLOWLIST, chainID))
+	if err != nil {
+		return nil, fmt.Errorf("GetFromContractAllowlist, get allowlist store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	value, err := states.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetFromContractAllowlist, deserialize from raw storage item error: %v", err)
+	}
+	param := new(FromContractAllowlistParam)
+	if err := param.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("GetFromContractAllowlist, deserialize allowlist error: %v", err)
+	}
+	return param.Contracts, nil
+}
+
+// PutFromContractAllowlist replaces the allowlist of chainID, an empty list removes it.
+func PutFromContractAllowlist(native *native.NativeService, chainID uint64, contracts [][]byte) {
+	key := targetRateKey(FROM_CONTRACT_ALLOWLIST, chainID)
+	if len(contracts) == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	param := &FromContractAllowlistParam{ChainID: chainID, Contracts: contracts}
+	param.Serialization(sink)
+	native.GetCacheDB().Put(key, states.GenRawStorageItem(sink.Bytes()))
+}
+
+func PutPausedToContract(native *native.NativeService, chainID uint64, contract []byte) {
+	native.GetCacheDB().Put(sourceRateKey(PAUSED_TO_CONTRACT, chainID, contract),
+		states.GenRawStorageItem(utils.GetUint64Bytes(chainID)))
+}
+
+func RemovePausedToContract(native *native.NativeService, chainID uint64, contract []byte) {
+	native.GetCacheDB().Delete(sourceRateKey(PAUSED_TO_CONTRACT, chainID, contract))
+}
+
+func CheckIfToContractPaused(native *native.NativeService, chainID uint64, contract []byte) (bool, error) {
+	store, err := native.GetCacheDB().Get(sourceRateKey(PAUSED_TO_CONTRACT, chainID, contract))
+	if err != nil {
+		return true, fmt.Errorf("CheckIfToContractPaused, get paused contract store error: %v", err)
+	}
+	return store != nil, nil
+}
+
+// CheckCircuitBreakers applies the governance configured allowlist, contract pause and rate limits
+// to a message from fromChainID, and counts it against the rate limits when it passes.
+func CheckCircuitBreakers(native *native.NativeService, fromChainID uint64, txParam *MakeTxParam) error {
+	allowlist, err := GetFromContractAllowlist(native, fromChainID)
+	if err != nil {
+		return fmt.Errorf("CheckCircuitBreakers, %v", err)
+	}
+	if allowlist != nil {
+		allowed := false
+		for _, v := range allowlist {
+			if bytes.Equal(v, txParam.FromContractAddress) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("CheckCircuitBreakers, source contract %x of chain %d is not allowed",
+				txParam.FromContractAddress, fromChainID)
+		}
+	}
+
+	paused, err := CheckIfToContractPaused(native, txParam.ToChainID, txParam.ToContractAddress)
+	if err != nil {
+		return fmt.Errorf("CheckCircuitBreakers, %v", err)
+	}
+	if paused {
+		return fmt.Errorf("CheckCircuitBreakers, target contract %x of chain %d is paused",
+			txParam.ToContractAddress, txParam.ToChainID)
+	}
+
+	limit, err := GetSourceRateLimit(native, fromChainID, txParam.FromContractAddress)
+	if err != nil {
+		return fmt.Errorf("CheckCircuitBreakers, %v", err)
+	}
+	if limit != nil {
+		err = consumeRate(native, limit, sourceRateKey(SOURCE_RATE_COUNTER, fromChainID, txParam.FromContractAddress))
+		if err != nil {
+			return fmt.Errorf("CheckCircuitBreakers, source contract %x of chain %d: %v",
+				txParam.FromContractAddress, fromChainID, err)
+		}
+	}
+
+	limit, err = GetTargetRateLimit(native, txParam.ToChainID)
+	if err != nil {
+		return fmt.Errorf("CheckCircuitBreakers, %v", err)
+	}
+	if limit != nil {
+		err = consumeRate(native, limit, targetRateKey(TARGET_RATE_COUNTER, txParam.ToChainID))
+		if err != nil {
+			return fmt.Errorf("CheckCircuitBreakers, target chain %d: %v", txParam.ToChainID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNativeAt(db *storage.CacheDB, height uint32) *native.NativeService {
+	ns, _ := native.NewNativeService(db, &types.Transaction{}, 0, height, common.Uint256{}, 0, nil, false)
+	return ns
+}
+
+func TestCheckCircuitBreakers(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	txParam := &MakeTxParam{
+		FromContractAddress: []byte("from"),
+		ToChainID:           2,
+		ToContractAddress:   []byte("to"),
+	}
+
+	ns := newNativeAt(db, 1)
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+
+	// allowlist
+	PutFromContractAllowlist(ns, 1, [][]byte{[]byte("other")})
+	assert.NotNil(t, CheckCircuitBreakers(ns, 1, txParam))
+	PutFromContractAllowlist(ns, 1, [][]byte{[]byte("other"), []byte("from")})
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+	PutFromContractAllowlist(ns, 1, nil)
+	list, err := GetFromContractAllowlist(ns, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, list)
+
+	// paused target contract only
+	PutPausedToContract(ns, 2, []byte("to"))
+	assert.NotNil(t, CheckCircuitBreakers(ns, 1, txParam))
+	other := *txParam
+	other.ToContractAddress = []byte("to2")
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, &other))
+	RemovePausedToContract(ns, 2, []byte("to"))
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+
+	// source rate limit resets with the window
+	PutSourceRateLimit(ns, 1, []byte("from"), &RateLimit{Window: 10, Limit: 2})
+	ns = newNativeAt(db, 10)
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+	assert.NotNil(t, CheckCircuitBreakers(ns, 1, txParam))
+	other = *txParam
+	other.FromContractAddress = []byte("from2")
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, &other))
+	ns = newNativeAt(db, 20)
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+	PutSourceRateLimit(ns, 1, []byte("from"), &RateLimit{})
+	limit, err := GetSourceRateLimit(ns, 1, []byte("from"))
+	assert.Nil(t, err)
+	assert.Nil(t, limit)
+
+	// target rate limit counts all sources
+	PutTargetRateLimit(ns, 2, &RateLimit{Window: 5, Limit: 1})
+	assert.Nil(t, CheckCircuitBreakers(ns, 1, txParam))
+	assert.NotNil(t, CheckCircuitBreakers(ns, 3, txParam))
+	ns = newNativeAt(db, 25)
+	assert.Nil(t, CheckCircuitBreakers(ns, 3, txParam))
+}
@@ -25,14 +25,31 @@ import (
 )
 
 const (
-	IMPORT_OUTER_TRANSFER_NAME = "ImportOuterTransfer"
-	MULTI_SIGN                 = "MultiSign"
-	MULTI_SIGN_RIPPLE          = "MultiSignRipple"
-	RECONSTRUCT_RIPPLE_TX      = "ReconstructRippleTx"
-	BLACK_CHAIN                = "BlackChain"
-	WHITE_CHAIN                = "WhiteChain"
+	IMPORT_OUTER_TRANSFER_NAME  = "ImportOuterTransfer"
+	IMPORT_OUTER_TRANSFER_BATCH = "ImportOuterTransferBatch"
+	MULTI_SIGN                  = "MultiSign"
+	MULTI_SIGN_RIPPLE           = "MultiSignRipple"
+	RECONSTRUCT_RIPPLE_TX       = "ReconstructRippleTx"
+	BLACK_CHAIN                 = "BlackChain"
+	WHITE_CHAIN                 = "WhiteChain"
+	SET_SOURCE_RATE_LIMIT       = "SetSourceRateLimit"
+	SET_TARGET_RATE_LIMIT       = "SetTargetRateLimit"
+	SET_FROM_CONTRACT_ALLOWLIST = "SetFromContractAllowlist"
+	PAUSE_TO_CONTRACT           = "PauseToContract"
+	UNPAUSE_TO_CONTRACT         = "UnpauseToContract"
+	CONSOLIDATE_BTC_UTXOS       = "ConsolidateBtcUtxos"
+	BUMP_BTC_TX_FEE             = "BumpBtcTxFee"
 
 	BLACKED_CHAIN = "BlackedChain"
+
+	SOURCE_RATE_LIMIT       = "sourceRateLimit"
+	TARGET_RATE_LIMIT       = "targetRateLimit"
+	SOURCE_RATE_COUNTER     = "sourceRateCounter"
+	TARGET_RATE_COUNTER     = "targetRateCounter"
+	FROM_CONTRACT_ALLOWLIST = "fromContractAllowlist"
+	PAUSED_TO_CONTRACT      = "pausedToContract"
+
+	MAX_BATCH_ITEMS = 256
 )
 
 var (
@@ -44,13 +61,26 @@ var (
 	MULTISIGN_INFO      = "multisignInfo"
 	RIPPLE_TX_INFO      = "rippleTxInfo"
 
-	NOTIFY_MAKE_PROOF = "makeProof"
+	NOTIFY_MAKE_PROOF      = "makeProof"
+	NOTIFY_BATCH_ITEM      = "batchItem"
+	NOTIFY_METHOD_REJECTED = "methodRejected"
 )
 
 type ChainHandler interface {
 	MakeDepositProposal(service *native.NativeService) (*MakeTxParam, error)
 }
 
+// ProofVerifier verifies the cross chain messages proved against one source chain block
+type ProofVerifier interface {
+	VerifyProof(proof, extra []byte) (*MakeTxParam, error)
+}
+
+// BatchChainHandler is a ChainHandler resolving the source chain block of a batch once for all its items
+type BatchChainHandler interface {
+	ChainHandler
+	NewProofVerifier(service *native.NativeService, params *BatchEntranceParam) (ProofVerifier, error)
+}
+
 type InitRedeemScriptParam struct {
 	RedeemScript string
 }
@@ -122,6 +152,89 @@ func (this *EntranceParam) Deserialization(source *common.ZeroCopySource) error
 	return nil
 }
 
+// BatchItem is one cross chain message of a batch, proved against the header of the batch
+type BatchItem struct {
+	Proof []byte `json:"proof"`
+	Extra []byte `json:"extra"`
+}
+
+// BatchEntranceParam imports many messages of one source chain block with a single header reference
+type BatchEntranceParam struct {
+	SourceChainID         uint64       `json:"sourceChainId"`
+	Height                uint32       `json:"height"`
+	RelayerAddress        []byte       `json:"relayerAddress"`
+	HeaderOrCrossChainMsg []byte       `json:"headerOrCrossChainMsg"`
+	Items                 []*BatchItem `json:"items"`
+}
+
+func (this *BatchEntranceParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.SourceChainID)
+	sink.WriteUint32(this.Height)
+	sink.WriteVarBytes(this.RelayerAddress)
+	sink.WriteVarBytes(this.HeaderOrCrossChainMsg)
+	sink.WriteVarUint(uint64(len(this.Items)))
+	for _, item := range this.Items {
+		sink.WriteVarBytes(item.Proof)
+		sink.WriteVarBytes(item.Extra)
+	}
+}
+
+func (this *BatchEntranceParam) Deserialization(source *common.ZeroCopySource) error {
+	sourceChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("BatchEntranceParam deserialize sourcechainid error")
+	}
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("BatchEntranceParam deserialize height error")
+	}
+	relayerAddr, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("BatchEntranceParam deserialize relayerAddr error")
+	}
+	headerOrCrossChainMsg, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("BatchEntranceParam deserialize headerOrCrossChainMsg error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("BatchEntranceParam deserialize items length error")
+	}
+	if n == 0 || n > MAX_BATCH_ITEMS {
+		return fmt.Errorf("BatchEntranceParam deserialize items length %d out of range (0, %d]", n, MAX_BATCH_ITEMS)
+	}
+	items := make([]*BatchItem, 0, n)
+	for i := uint64(0); i < n; i++ {
+		proof, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("BatchEntranceParam deserialize proof of item %d error", i)
+		}
+		extra, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("BatchEntranceParam deserialize extra of item %d error", i)
+		}
+		items = append(items, &BatchItem{Proof: proof, Extra: extra})
+	}
+	this.SourceChainID = sourceChainID
+	this.Height = height
+	this.RelayerAddress = relayerAddr
+	this.HeaderOrCrossChainMsg = headerOrCrossChainMsg
+	this.Items = items
+	return nil
+}
+
+// EntranceParam returns the single message param handlers verify item i with
+func (this *BatchEntranceParam) EntranceParam(i int) *EntranceParam {
+	return &EntranceParam{
+		SourceChainID:         this.SourceChainID,
+		Height:                this.Height,
+		Proof:                 this.Items[i].Proof,
+		RelayerAddress:        this.RelayerAddress,
+		Extra:                 this.Items[i].Extra,
+		HeaderOrCrossChainMsg: this.HeaderOrCrossChainMsg,
+	}
+}
+
 type MakeTxParamWithSender struct {
 	Sender ethcommon.Address
 	MakeTxParam
@@ -317,3 +430,103 @@ func (this *BlackChainParam) Deserialization(source *common.ZeroCopySource) erro
 	this.ChainID = chainID
 	return nil
 }
+
+type RateLimitParam struct {
+	ChainID  uint64
+	Contract []byte //source contract for source rate limit, empty for target rate limit
+	Window   uint32 //blocks
+	Limit    uint64 //max messages in a window, 0 to remove the limit
+}
+
+func (this *RateLimitParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarBytes(this.Contract)
+	sink.WriteUint32(this.Window)
+	sink.WriteUint64(this.Limit)
+}
+
+func (this *RateLimitParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize chainID error")
+	}
+	contract, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize contract error")
+	}
+	window, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize window error")
+	}
+	limit, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RateLimitParam deserialize limit error")
+	}
+
+	this.ChainID = chainID
+	this.Contract = contract
+	this.Window = window
+	this.Limit = limit
+	return nil
+}
+
+type FromContractAllowlistParam struct {
+	ChainID   uint64
+	Contracts [][]byte //empty to remove the allowlist
+}
+
+func (this *FromContractAllowlistParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarUint(uint64(len(this.Contracts)))
+	for _, v := range this.Contracts {
+		sink.WriteVarBytes(v)
+	}
+}
+
+func (this *FromContractAllowlistParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("FromContractAllowlistParam deserialize chainID error")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("FromContractAllowlistParam deserialize contracts length error")
+	}
+	contracts := make([][]byte, 0)
+	for i := 0; uint64(i) < n; i++ {
+		v, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("FromContractAllowlistParam deserialize contract error")
+		}
+		contracts = append(contracts, v)
+	}
+
+	this.ChainID = chainID
+	this.Contracts = contracts
+	return nil
+}
+
+type ToContractParam struct {
+	ChainID  uint64
+	Contract []byte
+}
+
+func (this *ToContractParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(this.ChainID)
+	sink.WriteVarBytes(this.Contract)
+}
+
+func (this *ToContractParam) Deserialization(source *common.ZeroCopySource) error {
+	chainID, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("ToContractParam deserialize chainID error")
+	}
+	contract, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("ToContractParam deserialize contract error")
+	}
+
+	this.ChainID = chainID
+	this.Contract = contract
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,3 +44,35 @@ func TestMakeTxParamWithSender(t *testing.T) {
 
 	assert.Equal(t, value, decoded)
 }
+
+func TestBatchEntranceParam(t *testing.T) {
+	param := BatchEntranceParam{
+		SourceChainID:         2,
+		Height:                100,
+		RelayerAddress:        []byte("relayer"),
+		HeaderOrCrossChainMsg: []byte("header"),
+		Items: []*BatchItem{
+			{Proof: []byte("proof1"), Extra: []byte("extra1")},
+			{Proof: []byte("proof2"), Extra: []byte("extra2")},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+
+	var decoded BatchEntranceParam
+	err := decoded.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, param, decoded)
+
+	item := decoded.EntranceParam(1)
+	assert.Equal(t, uint64(2), item.SourceChainID)
+	assert.Equal(t, []byte("proof2"), item.Proof)
+	assert.Equal(t, []byte("extra2"), item.Extra)
+	assert.Equal(t, []byte("header"), item.HeaderOrCrossChainMsg)
+
+	param.Items = nil
+	sink = common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	err = decoded.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.NotNil(t, err)
+}
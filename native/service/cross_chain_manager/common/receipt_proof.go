@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/polynetwork/poly/common"
+)
+
+// RECEIPT_PROOF_MODE in the json ExtraInfo of an ETH like side chain, e.g. {"ProofMode":"receipt"}, makes
+// cross chain events proved by receipts instead of the storage of the CCM contract
+const RECEIPT_PROOF_MODE = "receipt"
+
+var (
+	// CrossChainEventID is the topic of CrossChainEvent emitted by the CCM contract
+	CrossChainEventID = crypto.Keccak256Hash([]byte("CrossChainEvent(address,bytes,address,uint64,bytes,bytes)"))
+
+	crossChainEventData abi.Arguments
+)
+
+func init() {
+	bytesTy, _ := abi.NewType("bytes", "", nil)
+	addressTy, _ := abi.NewType("address", "", nil)
+	uint64Ty, _ := abi.NewType("uint64", "", nil)
+	// txId, proxyOrAssetContract, toChainId, toContract, rawdata; sender is indexed
+	crossChainEventData = abi.Arguments{{Type: bytesTy}, {Type: addressTy}, {Type: uint64Ty}, {Type: bytesTy},
+		{Type: bytesTy}}
+}
+
+type proofModeInfo struct {
+	ProofMode string `json:",omitempty"`
+}
+
+// IsReceiptProofMode tells if the side chain with extraInfo proves cross chain events by receipts
+func IsReceiptProofMode(extraInfo []byte) bool {
+	if len(extraInfo) == 0 {
+		return false
+	}
+	info := new(proofModeInfo)
+	if err := json.Unmarshal(extraInfo, info); err != nil {
+		return false
+	}
+	return info.ProofMode == RECEIPT_PROOF_MODE
+}
+
+// ReceiptProof proves the log with index LogIndex in the receipt of the no.TxIndex tx of a block
+type ReceiptProof struct {
+	TxIndex  uint64   `json:"txIndex"`
+	LogIndex uint64   `json:"logIndex"`
+	Proof    []string `json:"proof"`
+}
+
+type receiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             types.Bloom
+	Logs              []*types.Log
+}
+
+// VerifyFromReceiptProof verifies the receipt under receiptHash carrying a CrossChainEvent from ccmcAddress
+// with rawdata extra, and returns the param decoded from extra
+func VerifyFromReceiptProof(receiptHash ecom.Hash, proof, extra, ccmcAddress []byte) (*MakeTxParam, error) {
+	receiptProof := new(ReceiptProof)
+	if err := json.Unmarshal(proof, receiptProof); err != nil {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, unmarshal proof error:%s", err)
+	}
+	nodeList := new(light.NodeList)
+	for _, s := range receiptProof.Proof {
+		nodeList.Put(nil, ecom.Hex2Bytes(Replace0x(s)))
+	}
+	key, err := rlp.EncodeToBytes(uint(receiptProof.TxIndex))
+	if err != nil {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, encode tx index error:%s", err)
+	}
+	val, err := trie.VerifyProof(receiptHash, key, nodeList.NodeSet())
+	if err != nil {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, verify receipt proof error:%s", err)
+	}
+	if len(val) == 0 {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, no receipt of tx %d", receiptProof.TxIndex)
+	}
+	// typed receipts are prefixed by the tx type
+	if val[0] <= 0x7f {
+		val = val[1:]
+	}
+	receipt := new(receiptRLP)
+	if err = rlp.DecodeBytes(val, receipt); err != nil {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, decode receipt error:%s", err)
+	}
+	// receipts before byzantium carry the state root instead of status
+	if len(receipt.PostStateOrStatus) != len(ecom.Hash{}) && !bytes.Equal(receipt.PostStateOrStatus, []byte{1}) {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, tx %d failed", receiptProof.TxIndex)
+	}
+	if receiptProof.LogIndex >= uint64(len(receipt.Logs)) {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, log index %d out of range %d", receiptProof.LogIndex,
+			len(receipt.Logs))
+	}
+	log := receipt.Logs[receiptProof.LogIndex]
+	if !bytes.Equal(log.Address.Bytes(), ccmcAddress) {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, log is emitted by %s not the side chain CCM contract %s",
+			log.Address.Hex(), hex.EncodeToString(ccmcAddress))
+	}
+	if len(log.Topics) == 0 || log.Topics[0] != CrossChainEventID {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, log is not CrossChainEvent")
+	}
+	values, err := crossChainEventData.UnpackValues(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, unpack CrossChainEvent error:%s", err)
+	}
+	rawData, ok := values[len(values)-1].([]byte)
+	if !ok || !bytes.Equal(rawData, extra) {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, rawdata of event is not equal to extra:%x", extra)
+	}
+
+	txParam := new(MakeTxParam)
+	if err := txParam.Deserialization(common.NewZeroCopySource(extra)); err != nil {
+		return nil, fmt.Errorf("VerifyFromReceiptProof, deserialize merkleValue error:%s", err)
+	}
+	return txParam, nil
+}
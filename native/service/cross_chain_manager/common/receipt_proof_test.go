@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/polynetwork/poly/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeReceiptProof(t *testing.T, receipts [][]byte, txIndex, logIndex uint64) (ethcommon.Hash, []byte) {
+	tr, _ := trie.New(ethcommon.Hash{}, trie.NewDatabase(memorydb.New()))
+	for i, r := range receipts {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, r)
+	}
+	key, _ := rlp.EncodeToBytes(uint(txIndex))
+	nodeSet := light.NewNodeSet()
+	assert.Nil(t, tr.Prove(key, 0, nodeSet))
+	proof := &ReceiptProof{TxIndex: txIndex, LogIndex: logIndex}
+	for _, node := range nodeSet.NodeList() {
+		proof.Proof = append(proof.Proof, "0x"+hex.EncodeToString(node))
+	}
+	raw, _ := json.Marshal(proof)
+	return tr.Hash(), raw
+}
+
+func TestVerifyFromReceiptProof(t *testing.T) {
+	ccmc := ethcommon.HexToAddress("0x1234")
+	txParam := &MakeTxParam{TxHash: []byte("hash"),
+		CrossChainID:        []byte("1"),
+		FromContractAddress: []byte("from addr"),
+		ToChainID:           2,
+		ToContractAddress:   []byte("to addr"),
+		Method:              "unlock",
+		Args:                []byte("args")}
+	sink := common.NewZeroCopySink(nil)
+	txParam.Serialization(sink)
+	extra := sink.Bytes()
+
+	data, err := crossChainEventData.Pack([]byte("1"), ethcommon.HexToAddress("0x5678"), uint64(2),
+		[]byte("to addr"), extra)
+	assert.Nil(t, err)
+	event := &ethtypes.Log{Address: ccmc, Topics: []ethcommon.Hash{CrossChainEventID,
+		ethcommon.HexToHash("0x9abc")}, Data: data}
+	other := &ethtypes.Log{Address: ethcommon.HexToAddress("0x5678"), Topics: []ethcommon.Hash{CrossChainEventID},
+		Data: data}
+
+	encode := func(status uint64, logs ...*ethtypes.Log) []byte {
+		raw, _ := rlp.EncodeToBytes(&ethtypes.Receipt{Status: status, CumulativeGasUsed: 21000, Logs: logs})
+		return raw
+	}
+	typed := append([]byte{2}, encode(ethtypes.ReceiptStatusSuccessful, other, event)...)
+	receipts := [][]byte{encode(ethtypes.ReceiptStatusSuccessful), typed,
+		encode(ethtypes.ReceiptStatusFailed, event)}
+
+	root, proof := makeReceiptProof(t, receipts, 1, 1)
+	param, err := VerifyFromReceiptProof(root, proof, extra, ccmc.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, txParam, param)
+
+	_, err = VerifyFromReceiptProof(root, proof, extra, ethcommon.HexToAddress("0x5678").Bytes())
+	assert.NotNil(t, err)
+	_, err = VerifyFromReceiptProof(root, proof, append(extra, 0), ccmc.Bytes())
+	assert.NotNil(t, err)
+	_, err = VerifyFromReceiptProof(ethcommon.Hash{1}, proof, extra, ccmc.Bytes())
+	assert.NotNil(t, err)
+
+	root, proof = makeReceiptProof(t, receipts, 1, 0)
+	_, err = VerifyFromReceiptProof(root, proof, extra, ccmc.Bytes())
+	assert.NotNil(t, err)
+	root, proof = makeReceiptProof(t, receipts, 2, 0)
+	_, err = VerifyFromReceiptProof(root, proof, extra, ccmc.Bytes())
+	assert.NotNil(t, err)
+	root, proof = makeReceiptProof(t, receipts, 0, 0)
+	_, err = VerifyFromReceiptProof(root, proof, extra, ccmc.Bytes())
+	assert.NotNil(t, err)
+}
+
+func TestIsReceiptProofMode(t *testing.T) {
+	assert.False(t, IsReceiptProofMode(nil))
+	assert.False(t, IsReceiptProofMode([]byte(`{"RequireFinality":true}`)))
+	assert.True(t, IsReceiptProofMode([]byte(`{"ProofMode":"receipt"}`)))
+	assert.False(t, IsReceiptProofMode([]byte{0x01, 0x02}))
+}
@@ -17,6 +17,8 @@
 package common
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -42,6 +44,54 @@ func NotifyMakeProof(native *native.NativeService, fromChainID, toChainID uint64
 		})
 }
 
+// MethodRejectedError is a call of Method on ToContractAddress of chain ToChainID which is not permitted
+// by the method policy of that chain
+type MethodRejectedError struct {
+	ToChainID         uint64
+	ToContractAddress []byte
+	Method            string
+	Err               error
+}
+
+func (this *MethodRejectedError) Error() string {
+	return fmt.Sprintf("CheckMethodPolicy, chain %d: %v", this.ToChainID, this.Err)
+}
+
+// RevertOnError runs do, reverting what it made if it fails. A call rejected by the method policy of
+// the target chain is notified after the revert, so the rejection is kept with the tx
+func RevertOnError(native *native.NativeService, do func() error) (bool, error) {
+	snapshot := native.Snapshot()
+	err := do()
+	if err == nil {
+		native.Release(snapshot)
+		return false, nil
+	}
+	native.Revert(snapshot)
+	var rejected *MethodRejectedError
+	if !errors.As(err, &rejected) {
+		return false, err
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.SideChainManagerContractAddress,
+			States: []interface{}{NOTIFY_METHOD_REJECTED, rejected.ToChainID,
+				hex.EncodeToString(rejected.ToContractAddress), rejected.Method},
+		})
+	return true, err
+}
+
+// NotifyBatchItem reports the result of one item of a batched import, errMsg is empty on success
+func NotifyBatchItem(native *native.NativeService, fromChainID uint64, index uint32, txHash string, errMsg string) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{NOTIFY_BATCH_ITEM, fromChainID, index, txHash, errMsg == "", errMsg},
+		})
+}
+
 func PutDoneTx(native *native.NativeService, crossChainID []byte, chainID uint64) error {
 	contract := utils.CrossChainManagerContractAddress
 	chainIDBytes := utils.GetUint64Bytes(chainID)
@@ -17,14 +17,17 @@
 package cross_chain_manager
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/bsc"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/btc"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/bytom"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/cometbft"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/cosmos"
@@ -41,22 +44,33 @@ import (
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/polygon"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/quorum"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/ripple"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/rollup"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/solana"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/starcoin"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqa"
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/zilliqalegacy"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
 func RegisterCrossChainManagerContract(native *native.NativeService) {
 	native.Register(scom.IMPORT_OUTER_TRANSFER_NAME, ImportExTransfer)
+	native.Register(scom.IMPORT_OUTER_TRANSFER_BATCH, ImportExTransferBatch)
 	native.Register(scom.MULTI_SIGN, MultiSign)
 	native.Register(scom.MULTI_SIGN_RIPPLE, MultiSignRipple)
 	native.Register(scom.RECONSTRUCT_RIPPLE_TX, ReconstructRippleTx)
+	native.Register(scom.CONSOLIDATE_BTC_UTXOS, ConsolidateBtcUtxos)
+	native.Register(scom.BUMP_BTC_TX_FEE, BumpBtcTxFee)
 
 	native.Register(scom.BLACK_CHAIN, BlackChain)
 	native.Register(scom.WHITE_CHAIN, WhiteChain)
+	native.Register(scom.SET_SOURCE_RATE_LIMIT, SetSourceRateLimit)
+	native.Register(scom.SET_TARGET_RATE_LIMIT, SetTargetRateLimit)
+	native.Register(scom.SET_FROM_CONTRACT_ALLOWLIST, SetFromContractAllowlist)
+	native.Register(scom.PAUSE_TO_CONTRACT, PauseToContract)
+	native.Register(scom.UNPAUSE_TO_CONTRACT, UnpauseToContract)
 }
 
 func GetChainHandler(router uint64) (scom.ChainHandler, error) {
@@ -101,6 +115,12 @@ func GetChainHandler(router uint64) (scom.ChainHandler, error) {
 		return harmony.NewHandler(), nil
 	case utils.BYTOM_ROUTER:
 		return bytom.NewHandler(), nil
+	case utils.COMETBFT_ROUTER:
+		return cometbft.NewHandler(), nil
+	case utils.ROLLUP_ROUTER:
+		return rollup.NewHandler(), nil
+	case utils.SOLANA_ROUTER:
+		return solana.NewHandler(), nil
 	case utils.RIPPLE_ROUTER:
 		return ripple.NewRippleHandler(), nil
 	default:
@@ -108,87 +128,193 @@ func GetChainHandler(router uint64) (scom.ChainHandler, error) {
 	}
 }
 
+// ImportExTransfer imports a message of the source chain. A message calling a method rejected by the
+// method policy of its target chain is reverted but the tx is kept, with the rejection notified
 func ImportExTransfer(native *native.NativeService) ([]byte, error) {
 	params := new(scom.EntranceParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, contract params deserialize error: %v", err)
 	}
+	rejected, err := scom.RevertOnError(native, func() error {
+		return importExTransfer(native, params, native.GetTx().Hash(), nil)
+	})
+	if rejected {
+		return utils.BYTE_FALSE, nil
+	}
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return utils.BYTE_TRUE, nil
+}
 
+// ImportExTransferBatch imports the messages of one source chain block, each item is verified
+// and made into its own ToMerkleValue, a failed item is reverted without affecting the others
+func ImportExTransferBatch(native *native.NativeService) ([]byte, error) {
+	input := native.GetInput()
+	params := new(scom.BatchEntranceParam)
+	if err := params.Deserialization(common.NewZeroCopySource(input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransferBatch, contract params deserialize error: %v", err)
+	}
+	verifier, err := newProofVerifier(native, params)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransferBatch, %v", err)
+	}
+	defer native.SetInput(input)
+
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarUint(uint64(len(params.Items)))
+	for i := range params.Items {
+		itemParam := params.EntranceParam(i)
+		itemSink := common.NewZeroCopySink(nil)
+		itemParam.Serialization(itemSink)
+		native.SetInput(itemSink.Bytes())
+
+		txHash := BatchItemTxHash(native.GetTx().Hash(), uint32(i))
+		errMsg := ""
+		if _, err := scom.RevertOnError(native, func() error {
+			return importExTransfer(native, itemParam, txHash, verifier)
+		}); err != nil {
+			errMsg = err.Error()
+		}
+		scom.NotifyBatchItem(native, params.SourceChainID, uint32(i), txHash.ToHexString(), errMsg)
+		sink.WriteBool(errMsg == "")
+		sink.WriteString(errMsg)
+	}
+	return sink.Bytes(), nil
+}
+
+// BatchItemTxHash is the poly tx hash the ToMerkleValue of the index-th item of a batch is keyed by
+func BatchItemTxHash(txHash common.Uint256, index uint32) common.Uint256 {
+	return sha256.Sum256(append(txHash.ToArray(), utils.GetUint32Bytes(index)...))
+}
+
+// newProofVerifier resolves the source chain block of a batch once when its handler supports it, otherwise the
+// items are verified one by one by MakeDepositProposal
+func newProofVerifier(native *native.NativeService, params *scom.BatchEntranceParam) (scom.ProofVerifier, error) {
+	sideChain, err := side_chain_manager.GetSideChain(native, params.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return nil, nil
+	}
+	handler, err := GetChainHandler(sideChain.Router)
+	if err != nil {
+		return nil, nil
+	}
+	batchHandler, ok := handler.(scom.BatchChainHandler)
+	if !ok {
+		return nil, nil
+	}
+	return batchHandler.NewProofVerifier(native, params)
+}
+
+// makeBatchItemProposal verifies a batch item with the proof verifier of its batch
+func makeBatchItemProposal(native *native.NativeService, verifier scom.ProofVerifier, params *scom.EntranceParam) (*scom.MakeTxParam, error) {
+	txParam, err := verifier.VerifyProof(params.Proof, params.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("ImportExTransfer, verify proof error: %v", err)
+	}
+	if err := scom.CheckDoneTx(native, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("ImportExTransfer, check done transaction error:%s", err)
+	}
+	if err := scom.PutDoneTx(native, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("ImportExTransfer, PutDoneTx error:%s", err)
+	}
+	return txParam, nil
+}
+
+func importExTransfer(native *native.NativeService, params *scom.EntranceParam, txHash common.Uint256, verifier scom.ProofVerifier) error {
 	chainID := params.SourceChainID
 	blacked, err := scom.CheckIfChainBlacked(native, chainID)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, CheckIfChainBlacked error: %v", err)
+		return fmt.Errorf("ImportExTransfer, CheckIfChainBlacked error: %v", err)
 	}
 	if blacked {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, source chain is blacked")
+		return fmt.Errorf("ImportExTransfer, source chain is blacked")
 	}
 
 	//check if chainid exist
 	sideChain, err := side_chain_manager.GetSideChain(native, chainID)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side_chain_manager.GetSideChain error: %v", err)
+		return fmt.Errorf("ImportExTransfer, side_chain_manager.GetSideChain error: %v", err)
 	}
 	if sideChain == nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side chain %d is not registered", chainID)
+		return fmt.Errorf("ImportExTransfer, side chain %d is not registered", chainID)
 	}
 
 	handler, err := GetChainHandler(sideChain.Router)
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		return err
 	}
 	err = utils.CheckRouterStartBlock(sideChain.Router, native.GetHeight())
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		return err
 	}
 
-	//1. verify tx
-	txParam, err := handler.MakeDepositProposal(native)
+	//1. verify tx, batch items share the source chain block resolved by verifier
+	var txParam *scom.MakeTxParam
+	if verifier != nil {
+		txParam, err = makeBatchItemProposal(native, verifier, params)
+	} else {
+		txParam, err = handler.MakeDepositProposal(native)
+	}
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		return err
 	}
 	if txParam == nil && (sideChain.Router == utils.VOTE_ROUTER || sideChain.Router == utils.RIPPLE_ROUTER) {
-		return utils.BYTE_TRUE, nil
+		return nil
 	}
 
 	//2. make target chain tx
 	targetid := txParam.ToChainID
 	blacked, err = scom.CheckIfChainBlacked(native, targetid)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, CheckIfChainBlacked error: %v", err)
+		return fmt.Errorf("ImportExTransfer, CheckIfChainBlacked error: %v", err)
 	}
 	if blacked {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, target chain is blacked")
+		return fmt.Errorf("ImportExTransfer, target chain is blacked")
 	}
 
 	//check if chainid exist
 	sideChain, err = side_chain_manager.GetSideChain(native, targetid)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side_chain_manager.GetSideChain error: %v", err)
+		return fmt.Errorf("ImportExTransfer, side_chain_manager.GetSideChain error: %v", err)
 	}
 	if sideChain == nil {
-		return utils.BYTE_FALSE, fmt.Errorf("ImportExTransfer, side chain %d is not registered", targetid)
-	}
-	if sideChain.Router == utils.BTC_ROUTER {
-		err := btc.NewBTCHandler().MakeTransaction(native, txParam, chainID)
-		if err != nil {
-			return utils.BYTE_FALSE, err
-		}
-		return utils.BYTE_TRUE, nil
+		return fmt.Errorf("ImportExTransfer, side chain %d is not registered", targetid)
 	}
 
-	if sideChain.Router == utils.RIPPLE_ROUTER {
-		err := ripple.NewRippleHandler().MakeTransaction(native, txParam, chainID)
-		if err != nil {
-			return utils.BYTE_FALSE, err
+	//check allowlist, paused target contract and rate limits
+	err = scom.CheckCircuitBreakers(native, chainID, txParam)
+	if err != nil {
+		return fmt.Errorf("ImportExTransfer, %v", err)
+	}
+	switch sideChain.Router {
+	case utils.BTC_ROUTER, utils.RIPPLE_ROUTER:
+		//btc and ripple keep their target tx by the poly tx hash, one per poly tx
+		if txHash != native.GetTx().Hash() {
+			return fmt.Errorf("ImportExTransfer, batched transfer to router %d is not supported", sideChain.Router)
+		}
+		if sideChain.Router == utils.BTC_ROUTER {
+			err = btc.NewBTCHandler().MakeTransaction(native, txParam, chainID)
+		} else {
+			err = ripple.NewRippleHandler().MakeTransaction(native, txParam, chainID)
 		}
-		return utils.BYTE_TRUE, nil
+	default:
+		//NOTE, you need to store the tx in this
+		err = makeTransaction(native, txHash, txParam, chainID)
 	}
-	//NOTE, you need to store the tx in this
-	err = MakeTransaction(native, txParam, chainID)
 	if err != nil {
-		return utils.BYTE_FALSE, err
+		return err
 	}
-	return utils.BYTE_TRUE, nil
+
+	//3. record fee quoted for target chain, and credit it to relayer
+	err = relayer_manager.RecordDeliveredProof(native, params.RelayerAddress, chainID, targetid, txHash)
+	if err != nil {
+		return fmt.Errorf("ImportExTransfer, relayer_manager.RecordDeliveredProof error: %v", err)
+	}
+	return nil
 }
 
 func MultiSign(native *native.NativeService) ([]byte, error) {
@@ -223,7 +349,15 @@ func ReconstructRippleTx(native *native.NativeService) ([]byte, error) {
 }
 
 func MakeTransaction(service *native.NativeService, params *scom.MakeTxParam, fromChainID uint64) error {
-	txHash := service.GetTx().Hash()
+	return makeTransaction(service, service.GetTx().Hash(), params, fromChainID)
+}
+
+func makeTransaction(service *native.NativeService, txHash common.Uint256, params *scom.MakeTxParam, fromChainID uint64) error {
+	err := side_chain_manager.CheckMethodPolicy(service, params.ToChainID, params.ToContractAddress, params.Method)
+	if err != nil {
+		return fmt.Errorf("MakeTransaction, %w", err)
+	}
+
 	merkleValue := &scom.ToMerkleValue{
 		TxHash:      txHash.ToArray(),
 		FromChainID: fromChainID,
@@ -232,7 +366,7 @@ func MakeTransaction(service *native.NativeService, params *scom.MakeTxParam, fr
 
 	sink := common.NewZeroCopySink(nil)
 	merkleValue.Serialization(sink)
-	err := PutRequest(service, merkleValue.TxHash, params.ToChainID, sink.Bytes())
+	err = PutRequest(service, merkleValue.TxHash, params.ToChainID, sink.Bytes())
 	if err != nil {
 		return fmt.Errorf("MakeTransaction, putRequest error:%s", err)
 	}
@@ -250,6 +384,40 @@ func PutRequest(native *native.NativeService, txHash []byte, chainID uint64, req
 	return nil
 }
 
+func ConsolidateBtcUtxos(native *native.NativeService) ([]byte, error) {
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ConsolidateBtcUtxos, get current consensus operator address error: %v", err)
+	}
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ConsolidateBtcUtxos, checkWitness error: %v", err)
+	}
+
+	handler := btc.NewBTCHandler()
+	if err = handler.ConsolidateUtxos(native); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return utils.BYTE_TRUE, nil
+}
+
+func BumpBtcTxFee(native *native.NativeService) ([]byte, error) {
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("BumpBtcTxFee, get current consensus operator address error: %v", err)
+	}
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("BumpBtcTxFee, checkWitness error: %v", err)
+	}
+
+	handler := btc.NewBTCHandler()
+	if err = handler.BumpTxFee(native); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return utils.BYTE_TRUE, nil
+}
+
 func BlackChain(native *native.NativeService) ([]byte, error) {
 	params := new(scom.BlackChainParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -291,3 +459,117 @@ func WhiteChain(native *native.NativeService) ([]byte, error) {
 	scom.RemoveBlackChain(native, params.ChainID)
 	return utils.BYTE_TRUE, nil
 }
+
+func checkOperator(native *native.NativeService) error {
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return fmt.Errorf("checkWitness error: %v", err)
+	}
+	return nil
+}
+
+func SetSourceRateLimit(native *native.NativeService) ([]byte, error) {
+	params := new(scom.RateLimitParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetSourceRateLimit, contract params deserialize error: %v", err)
+	}
+	if params.Limit != 0 && params.Window == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("SetSourceRateLimit, window must be positive")
+	}
+	if err := checkOperator(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetSourceRateLimit, %v", err)
+	}
+
+	scom.PutSourceRateLimit(native, params.ChainID, params.Contract, &scom.RateLimit{Window: params.Window, Limit: params.Limit})
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.SET_SOURCE_RATE_LIMIT, params.ChainID, hex.EncodeToString(params.Contract), params.Window, params.Limit},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+func SetTargetRateLimit(native *native.NativeService) ([]byte, error) {
+	params := new(scom.RateLimitParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetTargetRateLimit, contract params deserialize error: %v", err)
+	}
+	if params.Limit != 0 && params.Window == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("SetTargetRateLimit, window must be positive")
+	}
+	if err := checkOperator(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetTargetRateLimit, %v", err)
+	}
+
+	scom.PutTargetRateLimit(native, params.ChainID, &scom.RateLimit{Window: params.Window, Limit: params.Limit})
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.SET_TARGET_RATE_LIMIT, params.ChainID, params.Window, params.Limit},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+func SetFromContractAllowlist(native *native.NativeService) ([]byte, error) {
+	params := new(scom.FromContractAllowlistParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFromContractAllowlist, contract params deserialize error: %v", err)
+	}
+	if err := checkOperator(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SetFromContractAllowlist, %v", err)
+	}
+
+	scom.PutFromContractAllowlist(native, params.ChainID, params.Contracts)
+	contracts := make([]string, 0, len(params.Contracts))
+	for _, v := range params.Contracts {
+		contracts = append(contracts, hex.EncodeToString(v))
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.SET_FROM_CONTRACT_ALLOWLIST, params.ChainID, contracts},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+func PauseToContract(native *native.NativeService) ([]byte, error) {
+	params := new(scom.ToContractParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("PauseToContract, contract params deserialize error: %v", err)
+	}
+	if err := checkOperator(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("PauseToContract, %v", err)
+	}
+
+	scom.PutPausedToContract(native, params.ChainID, params.Contract)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.PAUSE_TO_CONTRACT, params.ChainID, hex.EncodeToString(params.Contract)},
+		})
+	return utils.BYTE_TRUE, nil
+}
+
+func UnpauseToContract(native *native.NativeService) ([]byte, error) {
+	params := new(scom.ToContractParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("UnpauseToContract, contract params deserialize error: %v", err)
+	}
+	if err := checkOperator(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("UnpauseToContract, %v", err)
+	}
+
+	scom.RemovePausedToContract(native, params.ChainID, params.Contract)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.CrossChainManagerContractAddress,
+			States:          []interface{}{scom.UNPAUSE_TO_CONTRACT, params.ChainID, hex.EncodeToString(params.Contract)},
+		})
+	return utils.BYTE_TRUE, nil
+}
@@ -56,3 +56,16 @@ func (this *ETHHandler) MakeDepositProposal(service *native.NativeService) (*sco
 	}
 	return value, nil
 }
+
+// NewProofVerifier resolves the confirmed header the items of params are proved against
+func (this *ETHHandler) NewProofVerifier(service *native.NativeService, params *scom.BatchEntranceParam) (scom.ProofVerifier, error) {
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("eth NewProofVerifier, side_chain_manager.GetSideChain error: %v", err)
+	}
+	blockData, err := getConfirmedHeader(service, params.SourceChainID, params.Height, sideChain)
+	if err != nil {
+		return nil, fmt.Errorf("eth NewProofVerifier, %s", err)
+	}
+	return NewBlockVerifier(blockData, sideChain), nil
+}
@@ -39,6 +39,14 @@ import (
 )
 
 func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *cmanager.SideChain) (*scom.MakeTxParam, error) {
+	blockData, err := getConfirmedHeader(native, fromChainID, height, sideChain)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockVerifier(blockData, sideChain).VerifyProof(proof, extra)
+}
+
+func getConfirmedHeader(native *native.NativeService, fromChainID uint64, height uint32, sideChain *cmanager.SideChain) (*eth.Header, error) {
 	bestHeader, _, err := eth.GetCurrentHeader(native, fromChainID)
 	if err != nil {
 		return nil, fmt.Errorf("VerifyFromEthProof, get current header fail, error:%s", err)
@@ -52,17 +60,50 @@ func verifyFromEthTx(native *native.NativeService, proof, extra []byte, fromChai
 	if err != nil {
 		return nil, fmt.Errorf("VerifyFromEthProof, get header by height, height:%d, error:%s", height, err)
 	}
+	return blockData, nil
+}
+
+// BlockVerifier verifies the storage or receipt proofs of cross chain messages under one eth like header,
+// the account proof of the CCM contract is verified once and the storage root it proves kept for later proofs
+type BlockVerifier struct {
+	header      *eth.Header
+	sideChain   *cmanager.SideChain
+	storageHash *ecom.Hash
+}
+
+func NewBlockVerifier(header *eth.Header, sideChain *cmanager.SideChain) *BlockVerifier {
+	return &BlockVerifier{header: header, sideChain: sideChain}
+}
+
+// VerifyProof verifies proof of extra under the header and returns the txParam parsed from extra
+func (this *BlockVerifier) VerifyProof(proof, extra []byte) (*scom.MakeTxParam, error) {
+	if scom.IsReceiptProofMode(this.sideChain.ExtraInfo) {
+		txParam, err := scom.VerifyFromReceiptProof(this.header.ReceiptHash, proof, extra, this.sideChain.CCMCAddress)
+		if err != nil {
+			return nil, fmt.Errorf("VerifyFromEthProof, %v", err)
+		}
+		return txParam, nil
+	}
+
 	ethProof := new(ETHProof)
-	err = json.Unmarshal(proof, ethProof)
+	err := json.Unmarshal(proof, ethProof)
 	if err != nil {
 		return nil, fmt.Errorf("VerifyFromEthProof, unmarshal proof error:%s", err)
 	}
 	if len(ethProof.StorageProofs) != 1 {
 		return nil, fmt.Errorf("VerifyFromEthProof, incorrect proof format")
 	}
-	//todo 1. verify the proof with header
-	//determine where the k and v from
-	proofResult, err := VerifyMerkleProof(ethProof, blockData, sideChain.CCMCAddress)
+	//the storage root of the contract is proved once per header
+	if this.storageHash == nil {
+		storageHash, err := verifyAccountProof(ethProof, this.header, this.sideChain.CCMCAddress)
+		if err != nil {
+			return nil, fmt.Errorf("VerifyFromEthProof, verifyMerkleProof error:%v", err)
+		}
+		this.storageHash = &storageHash
+	} else if err := checkAccount(ethProof, *this.storageHash, this.sideChain.CCMCAddress); err != nil {
+		return nil, fmt.Errorf("VerifyFromEthProof, verifyMerkleProof error:%v", err)
+	}
+	proofResult, err := verifyStorageProof(ethProof, *this.storageHash)
 	if err != nil {
 		return nil, fmt.Errorf("VerifyFromEthProof, verifyMerkleProof error:%v", err)
 	}
@@ -86,6 +127,15 @@ func VerifyMerkleProofLegacy(ethProof *ETHProof, blockData *types.Header, contra
 }
 
 func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
+	storageHash, err := verifyAccountProof(ethProof, blockData, contractAddr)
+	if err != nil {
+		return nil, err
+	}
+	return verifyStorageProof(ethProof, storageHash)
+}
+
+// verifyAccountProof verifies the account of contractAddr under the state root of blockData and returns its storage root
+func verifyAccountProof(ethProof *ETHProof, blockData *eth.Header, contractAddr []byte) (ecom.Hash, error) {
 	//1. prepare verify account
 	nodeList := new(light.NodeList)
 
@@ -97,26 +147,26 @@ func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr [
 
 	addr := ecom.Hex2Bytes(scom.Replace0x(ethProof.Address))
 	if !bytes.Equal(addr, contractAddr) {
-		return nil, fmt.Errorf("verifyMerkleProof, contract address is error, proof address: %s, side chain address: %s", ethProof.Address, hex.EncodeToString(contractAddr))
+		return ecom.Hash{}, fmt.Errorf("verifyMerkleProof, contract address is error, proof address: %s, side chain address: %s", ethProof.Address, hex.EncodeToString(contractAddr))
 	}
 	acctKey := crypto.Keccak256(addr)
 
 	// 2. verify account proof
 	acctVal, err := trie.VerifyProof(blockData.Root, acctKey, ns)
 	if err != nil {
-		return nil, fmt.Errorf("verifyMerkleProof, verify account proof error:%s\n", err)
+		return ecom.Hash{}, fmt.Errorf("verifyMerkleProof, verify account proof error:%s\n", err)
 	}
 
 	nounce := new(big.Int)
 	_, ok := nounce.SetString(scom.Replace0x(ethProof.Nonce), 16)
 	if !ok {
-		return nil, fmt.Errorf("verifyMerkleProof, invalid format of nounce:%s\n", ethProof.Nonce)
+		return ecom.Hash{}, fmt.Errorf("verifyMerkleProof, invalid format of nounce:%s\n", ethProof.Nonce)
 	}
 
 	balance := new(big.Int)
 	_, ok = balance.SetString(scom.Replace0x(ethProof.Balance), 16)
 	if !ok {
-		return nil, fmt.Errorf("verifyMerkleProof, invalid format of balance:%s\n", ethProof.Balance)
+		return ecom.Hash{}, fmt.Errorf("verifyMerkleProof, invalid format of balance:%s\n", ethProof.Balance)
 	}
 
 	storageHash := ecom.HexToHash(scom.Replace0x(ethProof.StorageHash))
@@ -131,15 +181,31 @@ func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr [
 
 	acctrlp, err := rlp.EncodeToBytes(acct)
 	if err != nil {
-		return nil, err
+		return ecom.Hash{}, err
 	}
 
 	if !bytes.Equal(acctrlp, acctVal) {
-		return nil, fmt.Errorf("verifyMerkleProof, verify account proof failed, wanted:%v, get:%v", acctrlp, acctVal)
+		return ecom.Hash{}, fmt.Errorf("verifyMerkleProof, verify account proof failed, wanted:%v, get:%v", acctrlp, acctVal)
 	}
+	return storageHash, nil
+}
 
+// checkAccount checks ethProof is of contractAddr with the storage root already proved
+func checkAccount(ethProof *ETHProof, storageHash ecom.Hash, contractAddr []byte) error {
+	addr := ecom.Hex2Bytes(scom.Replace0x(ethProof.Address))
+	if !bytes.Equal(addr, contractAddr) {
+		return fmt.Errorf("verifyMerkleProof, contract address is error, proof address: %s, side chain address: %s", ethProof.Address, hex.EncodeToString(contractAddr))
+	}
+	if ecom.HexToHash(scom.Replace0x(ethProof.StorageHash)) != storageHash {
+		return fmt.Errorf("verifyMerkleProof, storage hash %s is not the proved %s", ethProof.StorageHash, storageHash.Hex())
+	}
+	return nil
+}
+
+// verifyStorageProof verifies the storage proof of ethProof under storageHash and returns the proved value
+func verifyStorageProof(ethProof *ETHProof, storageHash ecom.Hash) ([]byte, error) {
 	//3.verify storage proof
-	nodeList = new(light.NodeList)
+	nodeList := new(light.NodeList)
 	if len(ethProof.StorageProofs) != 1 {
 		return nil, fmt.Errorf("verifyMerkleProof, invalid storage proof format")
 	}
@@ -151,7 +217,7 @@ func VerifyMerkleProof(ethProof *ETHProof, blockData *eth.Header, contractAddr [
 		nodeList.Put(nil, ecom.Hex2Bytes(scom.Replace0x(prf)))
 	}
 
-	ns = nodeList.NodeSet()
+	ns := nodeList.NodeSet()
 	val, err := trie.VerifyProof(storageHash, storageKey, ns)
 	if err != nil {
 		return nil, fmt.Errorf("verifyMerkleProof, verify storage proof error:%s\n", err)
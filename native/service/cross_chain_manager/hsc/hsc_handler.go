@@ -33,6 +33,7 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	eth2 "github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/hsc"
@@ -73,21 +74,31 @@ func (h *HscHandler) MakeDepositProposal(service *native.NativeService) (*scom.M
 	return value, nil
 }
 
-func verifyFromHscTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (param *scom.MakeTxParam, err error) {
-	cheight, err := hsc.GetCanonicalHeight(native, fromChainID)
+// NewProofVerifier resolves the confirmed header the items of params are proved against
+func (h *HscHandler) NewProofVerifier(service *native.NativeService, params *scom.BatchEntranceParam) (scom.ProofVerifier, error) {
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("hsc NewProofVerifier, side_chain_manager.GetSideChain error: %v", err)
 	}
-
-	cheight32 := uint32(cheight)
-
-	if cheight32 < height || cheight32-height < uint32(sideChain.BlocksToWait-1) {
-		return nil, fmt.Errorf("verifyFromHscTx, transaction is not confirmed, current height: %d, input height: %d", cheight, height)
+	header, err := getConfirmedHeader(service, params.SourceChainID, params.Height, sideChain)
+	if err != nil {
+		return nil, fmt.Errorf("hsc NewProofVerifier, %s", err)
 	}
+	return eth2.NewBlockVerifier(header, sideChain), nil
+}
 
-	headerWithSum, err := hsc.GetCanonicalHeader(native, fromChainID, uint64(height))
+func verifyFromHscTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (param *scom.MakeTxParam, err error) {
+	header, err := getConfirmedHeader(native, fromChainID, height, sideChain)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromHscTx, GetCanonicalHeader height:%d, error:%s", height, err)
+		return nil, err
+	}
+
+	if scom.IsReceiptProofMode(sideChain.ExtraInfo) {
+		txParam, err := scom.VerifyFromReceiptProof(header.ReceiptHash, proof, extra, sideChain.CCMCAddress)
+		if err != nil {
+			return nil, fmt.Errorf("verifyFromHscTx, %v", err)
+		}
+		return txParam, nil
 	}
 
 	hscProof := new(Proof)
@@ -100,7 +111,7 @@ func verifyFromHscTx(native *native.NativeService, proof, extra []byte, fromChai
 		return nil, fmt.Errorf("verifyFromHscTx, incorrect proof format")
 	}
 
-	proofResult, err := verifyMerkleProof(hscProof, headerWithSum.Header, sideChain.CCMCAddress)
+	proofResult, err := verifyMerkleProof(hscProof, header, sideChain.CCMCAddress)
 	if err != nil {
 		return nil, fmt.Errorf("verifyFromHscTx, verifyMerkleProof error:%v", err)
 	}
@@ -121,6 +132,25 @@ func verifyFromHscTx(native *native.NativeService, proof, extra []byte, fromChai
 	return txParam, nil
 }
 
+func getConfirmedHeader(native *native.NativeService, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (*eth.Header, error) {
+	cheight, err := hsc.GetCanonicalHeight(native, fromChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cheight32 := uint32(cheight)
+
+	if cheight32 < height || cheight32-height < uint32(sideChain.BlocksToWait-1) {
+		return nil, fmt.Errorf("verifyFromHscTx, transaction is not confirmed, current height: %d, input height: %d", cheight, height)
+	}
+
+	headerWithSum, err := hsc.GetCanonicalHeader(native, fromChainID, uint64(height))
+	if err != nil {
+		return nil, fmt.Errorf("verifyFromHscTx, GetCanonicalHeader height:%d, error:%s", height, err)
+	}
+	return headerWithSum.Header, nil
+}
+
 // Proof ...
 type Proof struct {
 	Address       string         `json:"address"`
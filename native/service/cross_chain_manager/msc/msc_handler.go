@@ -24,7 +24,6 @@ import (
 	"math/big"
 
 	ecommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -33,7 +32,9 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	eth2 "github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/msc"
 )
 
@@ -72,21 +73,31 @@ func (h *Handler) MakeDepositProposal(service *native.NativeService) (*scom.Make
 	return value, nil
 }
 
-func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (param *scom.MakeTxParam, err error) {
-	cheight, err := msc.GetCanonicalHeight(native, fromChainID)
+// NewProofVerifier resolves the confirmed header the items of params are proved against
+func (h *Handler) NewProofVerifier(service *native.NativeService, params *scom.BatchEntranceParam) (scom.ProofVerifier, error) {
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("msc NewProofVerifier, side_chain_manager.GetSideChain error: %v", err)
 	}
-
-	cheight32 := uint32(cheight)
-
-	if cheight32 < height || cheight32-height < uint32(sideChain.BlocksToWait-1) {
-		return nil, fmt.Errorf("verifyFromTx, transaction is not confirmed, current height: %d, input height: %d", cheight, height)
+	header, err := getConfirmedHeader(service, params.SourceChainID, params.Height, sideChain)
+	if err != nil {
+		return nil, fmt.Errorf("msc NewProofVerifier, %s", err)
 	}
+	return eth2.NewBlockVerifier(header, sideChain), nil
+}
 
-	headerWithSum, err := msc.GetCanonicalHeader(native, fromChainID, uint64(height))
+func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (param *scom.MakeTxParam, err error) {
+	header, err := getConfirmedHeader(native, fromChainID, height, sideChain)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromTx, GetCanonicalHeader height:%d, error:%s", height, err)
+		return nil, err
+	}
+
+	if scom.IsReceiptProofMode(sideChain.ExtraInfo) {
+		txParam, err := scom.VerifyFromReceiptProof(header.ReceiptHash, proof, extra, sideChain.CCMCAddress)
+		if err != nil {
+			return nil, fmt.Errorf("verifyFromTx, %v", err)
+		}
+		return txParam, nil
 	}
 
 	mscProof := new(Proof)
@@ -99,7 +110,7 @@ func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID
 		return nil, fmt.Errorf("verifyFromTx, incorrect proof format")
 	}
 
-	proofResult, err := verifyMerkleProof(mscProof, headerWithSum.Header, sideChain.CCMCAddress)
+	proofResult, err := verifyMerkleProof(mscProof, header, sideChain.CCMCAddress)
 	if err != nil {
 		return nil, fmt.Errorf("verifyFromTx, verifyMerkleProof error:%v", err)
 	}
@@ -120,6 +131,25 @@ func verifyFromTx(native *native.NativeService, proof, extra []byte, fromChainID
 	return txParam, nil
 }
 
+func getConfirmedHeader(native *native.NativeService, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (*eth.Header, error) {
+	cheight, err := msc.GetCanonicalHeight(native, fromChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cheight32 := uint32(cheight)
+
+	if cheight32 < height || cheight32-height < uint32(sideChain.BlocksToWait-1) {
+		return nil, fmt.Errorf("verifyFromTx, transaction is not confirmed, current height: %d, input height: %d", cheight, height)
+	}
+
+	headerWithSum, err := msc.GetCanonicalHeader(native, fromChainID, uint64(height))
+	if err != nil {
+		return nil, fmt.Errorf("verifyFromTx, GetCanonicalHeader height:%d, error:%s", height, err)
+	}
+	return headerWithSum.Header, nil
+}
+
 // Proof ...
 type Proof struct {
 	Address       string         `json:"address"`
@@ -146,7 +176,7 @@ type ProofAccount struct {
 	Codehash ecommon.Hash
 }
 
-func verifyMerkleProof(mscProof *Proof, blockData *types.Header, contractAddr []byte) ([]byte, error) {
+func verifyMerkleProof(mscProof *Proof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
 	//1. prepare verify account
 	nodeList := new(light.NodeList)
 
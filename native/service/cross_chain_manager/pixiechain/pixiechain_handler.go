@@ -33,6 +33,7 @@ import (
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/native"
 	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	eth2 "github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
 	"github.com/polynetwork/poly/native/service/header_sync/pixiechain"
@@ -69,21 +70,31 @@ func (h *PixieHandler) MakeDepositProposal(service *native.NativeService) (*scom
 	return value, nil
 }
 
-func verifyFromPixieTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (param *scom.MakeTxParam, err error) {
-	cheight, err := pixiechain.GetCanonicalHeight(native, fromChainID)
+// NewProofVerifier resolves the confirmed header the items of params are proved against
+func (h *PixieHandler) NewProofVerifier(service *native.NativeService, params *scom.BatchEntranceParam) (scom.ProofVerifier, error) {
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
 	if err != nil {
-		return
+		return nil, fmt.Errorf("pixie NewProofVerifier, side_chain_manager.GetSideChain error: %v", err)
 	}
-
-	cheight32 := uint32(cheight)
-
-	if cheight32 < height || cheight32-height < uint32(sideChain.BlocksToWait-1) {
-		return nil, fmt.Errorf("verifyFromPixieTx, transaction is not confirmed, current height: %d, input height: %d", cheight, height)
+	header, err := getConfirmedHeader(service, params.SourceChainID, params.Height, sideChain)
+	if err != nil {
+		return nil, fmt.Errorf("pixie NewProofVerifier, %s", err)
 	}
+	return eth2.NewBlockVerifier(header, sideChain), nil
+}
 
-	headerWithSum, err := pixiechain.GetCanonicalHeader(native, fromChainID, uint64(height))
+func verifyFromPixieTx(native *native.NativeService, proof, extra []byte, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (param *scom.MakeTxParam, err error) {
+	header, err := getConfirmedHeader(native, fromChainID, height, sideChain)
 	if err != nil {
-		return nil, fmt.Errorf("verifyFromPixieTx, GetCanonicalHeader height:%d, error:%s", height, err)
+		return nil, err
+	}
+
+	if scom.IsReceiptProofMode(sideChain.ExtraInfo) {
+		txParam, err := scom.VerifyFromReceiptProof(header.ReceiptHash, proof, extra, sideChain.CCMCAddress)
+		if err != nil {
+			return nil, fmt.Errorf("verifyFromPixieTx, %v", err)
+		}
+		return txParam, nil
 	}
 
 	pixieProof := new(Proof)
@@ -96,7 +107,7 @@ func verifyFromPixieTx(native *native.NativeService, proof, extra []byte, fromCh
 		return nil, fmt.Errorf("verifyFromPixieTx, incorrect proof format")
 	}
 
-	proofResult, err := verifyMerkleProof(pixieProof, headerWithSum.Header, sideChain.CCMCAddress)
+	proofResult, err := verifyMerkleProof(pixieProof, header, sideChain.CCMCAddress)
 	if err != nil {
 		return nil, fmt.Errorf("verifyFromPixieTx, verifyMerkleProof error:%v", err)
 	}
@@ -117,6 +128,25 @@ func verifyFromPixieTx(native *native.NativeService, proof, extra []byte, fromCh
 	return txParam, nil
 }
 
+func getConfirmedHeader(native *native.NativeService, fromChainID uint64, height uint32, sideChain *side_chain_manager.SideChain) (*eth.Header, error) {
+	cheight, err := pixiechain.GetCanonicalHeight(native, fromChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cheight32 := uint32(cheight)
+
+	if cheight32 < height || cheight32-height < uint32(sideChain.BlocksToWait-1) {
+		return nil, fmt.Errorf("verifyFromPixieTx, transaction is not confirmed, current height: %d, input height: %d", cheight, height)
+	}
+
+	headerWithSum, err := pixiechain.GetCanonicalHeader(native, fromChainID, uint64(height))
+	if err != nil {
+		return nil, fmt.Errorf("verifyFromPixieTx, GetCanonicalHeader height:%d, error:%s", height, err)
+	}
+	return headerWithSum.Header, nil
+}
+
 func verifyMerkleProof(pixieProof *Proof, blockData *eth.Header, contractAddr []byte) ([]byte, error) {
 	// 1. prepare verify account
 	nodeList := new(light.NodeList)
@@ -21,11 +21,18 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	eth2 "github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
 	cmanager "github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 )
 
 func verifyFromQuorumTx(proof, extra []byte, hdr *types.Header, sideChain *cmanager.SideChain) error {
+	if scom.IsReceiptProofMode(sideChain.ExtraInfo) {
+		if _, err := scom.VerifyFromReceiptProof(hdr.ReceiptHash, proof, extra, sideChain.CCMCAddress); err != nil {
+			return fmt.Errorf("VerifyFromEthProof, %v", err)
+		}
+		return nil
+	}
 	ethProof := new(eth2.ETHProof)
 	if err := json.Unmarshal(proof, ethProof); err != nil {
 		return fmt.Errorf("VerifyFromEthProof, unmarshal proof error:%s", err)
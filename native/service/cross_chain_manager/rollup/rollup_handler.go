@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollup
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	heth "github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+var maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// MakeDepositProposal verifies a cross chain tx of a rollup through the output root posted on
+// L1 with no header sync of the rollup itself
+func (this *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
+	params := new(scom.EntranceParam)
+	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, contract params deserialize error: %s", err)
+	}
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, side_chain_manager.GetSideChain error: %v", err)
+	}
+	cfg, err := parseRollupConfig(sideChain.ExtraInfo)
+	if err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, %v", err)
+	}
+	l1Header, err := getConfirmedL1Header(service, cfg.L1ChainID, uint64(params.Height))
+	if err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, %v", err)
+	}
+	proof := new(RollupProof)
+	if err := json.Unmarshal(params.Proof, proof); err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, unmarshal proof error: %v", err)
+	}
+	txParam, err := verifyFromRollupTx(cfg, l1Header, proof, sideChain.CCMCAddress, params.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, %v", err)
+	}
+	if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, check done transaction error:%s", err)
+	}
+	if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("rollup MakeDepositProposal, PutDoneTx error:%s", err)
+	}
+	return txParam, nil
+}
+
+// getConfirmedL1Header gets the synced L1 header at height with enough confirmations of the L1
+func getConfirmedL1Header(service *native.NativeService, l1ChainID, height uint64) (*heth.Header, error) {
+	l1, err := side_chain_manager.GetSideChain(service, l1ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("getConfirmedL1Header, get L1 side chain error: %v", err)
+	}
+	if l1 == nil || l1.Router != utils.ETH_ROUTER {
+		return nil, fmt.Errorf("getConfirmedL1Header, L1 %d is not a registered eth router chain", l1ChainID)
+	}
+	best, err := heth.GetCurrentHeaderHeight(service, l1ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("getConfirmedL1Header, get current L1 height error: %v", err)
+	}
+	if best < height || best-height+1 < l1.BlocksToWait {
+		return nil, fmt.Errorf("getConfirmedL1Header, L1 height %d is not confirmed, current height: %d", height, best)
+	}
+	header, _, err := heth.GetHeaderByHeight(service, height, l1ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("getConfirmedL1Header, get L1 header at %d error: %v", height, err)
+	}
+	return header, nil
+}
+
+func verifyFromRollupTx(cfg *RollupConfig, l1Header *heth.Header, proof *RollupProof, ccmc,
+	extra []byte) (*scom.MakeTxParam, error) {
+	if proof.OracleProof == nil || proof.L2Proof == nil {
+		return nil, fmt.Errorf("verifyFromRollupTx, oracle proof or L2 proof is missing")
+	}
+	root, err := verifyOutput(cfg, l1Header, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	stateRoot := root
+	if !cfg.CommitsStateRoot {
+		if proof.Output == nil {
+			return nil, fmt.Errorf("verifyFromRollupTx, preimage of output root is missing")
+		}
+		o := proof.Output
+		hash := crypto.Keccak256Hash(o.Version[:], o.StateRoot[:], o.MessagePasserStorageRoot[:], o.BlockHash[:])
+		if hash != root {
+			return nil, fmt.Errorf("verifyFromRollupTx, output root %s not match its preimage %s", root.Hex(), hash.Hex())
+		}
+		stateRoot = o.StateRoot
+	}
+
+	if len(proof.L2Proof.StorageProofs) != 1 {
+		return nil, fmt.Errorf("verifyFromRollupTx, incorrect L2 proof format")
+	}
+	result, err := eth.VerifyMerkleProof(proof.L2Proof, &heth.Header{Root: stateRoot}, ccmc)
+	if err != nil {
+		return nil, fmt.Errorf("verifyFromRollupTx, verify L2 proof error: %v", err)
+	}
+	if result == nil || !eth.CheckProofResult(result, extra) {
+		return nil, fmt.Errorf("verifyFromRollupTx, verify proof value hash failed, proof result:%x, extra:%x",
+			result, extra)
+	}
+	txParam := new(scom.MakeTxParam)
+	if err := txParam.Deserialization(common.NewZeroCopySource(extra)); err != nil {
+		return nil, fmt.Errorf("verifyFromRollupTx, deserialize merkleValue error:%s", err)
+	}
+	return txParam, nil
+}
+
+// verifyOutput proves the output root at proof.OutputIndex in the oracle and checks that it has
+// passed the challenge window by the time of l1Header
+func verifyOutput(cfg *RollupConfig, l1Header *heth.Header, proof *RollupProof) (ecom.Hash, error) {
+	base := new(big.Int).SetBytes(crypto.Keccak256(cfg.OutputsSlot[:]))
+	base.Add(base, new(big.Int).Mul(new(big.Int).SetUint64(proof.OutputIndex), new(big.Int).SetUint64(cfg.OutputSize)))
+	slots := []*big.Int{base}
+	if cfg.ChallengeWindow != 0 {
+		slots = append(slots, new(big.Int).Add(base, big.NewInt(1)))
+	}
+	if len(proof.OracleProof.StorageProofs) != len(slots) {
+		return ecom.Hash{}, fmt.Errorf("verifyOutput, expect %d storage proofs of oracle, got %d", len(slots),
+			len(proof.OracleProof.StorageProofs))
+	}
+	values := make([]ecom.Hash, len(slots))
+	for i, slot := range slots {
+		sp := proof.OracleProof.StorageProofs[i]
+		// BigToHash keeps the low 256 bits as slots wrap around
+		if ecom.HexToHash(scom.Replace0x(sp.Key)) != ecom.BigToHash(slot) {
+			return ecom.Hash{}, fmt.Errorf("verifyOutput, storage proof %d is for slot %s, expect %s", i, sp.Key,
+				ecom.BigToHash(slot).Hex())
+		}
+		single := *proof.OracleProof
+		single.StorageProofs = []eth.StorageProof{sp}
+		result, err := eth.VerifyMerkleProof(&single, l1Header, cfg.OutputOracle[:])
+		if err != nil {
+			return ecom.Hash{}, fmt.Errorf("verifyOutput, verify oracle proof error: %v", err)
+		}
+		if result == nil {
+			return ecom.Hash{}, fmt.Errorf("verifyOutput, slot %s of oracle is empty", sp.Key)
+		}
+		var raw []byte
+		if err := rlp.DecodeBytes(result, &raw); err != nil {
+			return ecom.Hash{}, fmt.Errorf("verifyOutput, decode slot value error: %v", err)
+		}
+		values[i] = ecom.BytesToHash(raw)
+	}
+	if values[0] == (ecom.Hash{}) {
+		return ecom.Hash{}, fmt.Errorf("verifyOutput, output %d is not posted", proof.OutputIndex)
+	}
+	if cfg.ChallengeWindow != 0 {
+		timestamp := new(big.Int).And(values[1].Big(), maxUint128)
+		if !timestamp.IsUint64() || timestamp.Uint64()+cfg.ChallengeWindow > l1Header.Time {
+			return ecom.Hash{}, fmt.Errorf("verifyOutput, output %d posted at %s is still in challenge window "+
+				"at L1 time %d", proof.OutputIndex, timestamp, l1Header.Time)
+		}
+	}
+	return values[0], nil
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollup
+
+import (
+	"math/big"
+	"testing"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/polynetwork/poly/common"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
+	heth "github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	oracle = ecom.HexToAddress("0x1000000000000000000000000000000000000001")
+	ccmc   = ecom.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+// makeProof builds a state with storage of addr and proves the given slots
+func makeProof(t *testing.T, addr ecom.Address, storage map[ecom.Hash]ecom.Hash, slots ...ecom.Hash) (ecom.Hash, *eth.ETHProof) {
+	db, err := state.New(ecom.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	assert.Nil(t, err)
+	db.SetNonce(addr, 1)
+	for k, v := range storage {
+		db.SetState(addr, k, v)
+	}
+	root := db.IntermediateRoot(false)
+
+	accountProof, err := db.GetProof(addr)
+	assert.Nil(t, err)
+	proof := &eth.ETHProof{
+		Address:     addr.Hex(),
+		Balance:     "0x0",
+		Nonce:       "0x1",
+		CodeHash:    db.GetCodeHash(addr).Hex(),
+		StorageHash: db.StorageTrie(addr).Hash().Hex(),
+	}
+	for _, p := range accountProof {
+		proof.AccountProof = append(proof.AccountProof, hexutil.Encode(p))
+	}
+	for _, slot := range slots {
+		storageProof, err := db.GetStorageProof(addr, slot)
+		assert.Nil(t, err)
+		sp := eth.StorageProof{Key: slot.Hex()}
+		for _, p := range storageProof {
+			sp.Proof = append(sp.Proof, hexutil.Encode(p))
+		}
+		proof.StorageProofs = append(proof.StorageProofs, sp)
+	}
+	return root, proof
+}
+
+func TestVerifyFromRollupTx(t *testing.T) {
+	txParam := &scom.MakeTxParam{
+		TxHash:              []byte{1},
+		CrossChainID:        []byte{2},
+		FromContractAddress: []byte{3},
+		ToChainID:           2,
+		ToContractAddress:   []byte{4},
+		Method:              "unlock",
+		Args:                []byte{5},
+	}
+	sink := common.NewZeroCopySink(nil)
+	txParam.Serialization(sink)
+	extra := sink.Bytes()
+
+	// L2: the ccm contract keeps hash of the tx param
+	txSlot := ecom.Hash{0x77}
+	l2Root, l2Proof := makeProof(t, ccmc, map[ecom.Hash]ecom.Hash{txSlot: crypto.Keccak256Hash(extra)}, txSlot)
+	output := &OutputRootPreimage{StateRoot: l2Root, MessagePasserStorageRoot: ecom.Hash{9}, BlockHash: ecom.Hash{8}}
+	outputRoot := crypto.Keccak256Hash(output.Version[:], output.StateRoot[:], output.MessagePasserStorageRoot[:],
+		output.BlockHash[:])
+
+	// L1: output 3 of the oracle at slot 3 is posted at time 1000
+	cfg := &RollupConfig{OutputOracle: oracle, OutputsSlot: ecom.BigToHash(big.NewInt(3)), OutputSize: 2,
+		ChallengeWindow: 600}
+	base := new(big.Int).SetBytes(crypto.Keccak256(cfg.OutputsSlot[:]))
+	rootSlot := ecom.BigToHash(new(big.Int).Add(base, big.NewInt(6)))
+	timeSlot := ecom.BigToHash(new(big.Int).Add(base, big.NewInt(7)))
+	packed := new(big.Int).Or(new(big.Int).Lsh(big.NewInt(12345), 128), big.NewInt(1000))
+	l1Root, oracleProof := makeProof(t, oracle, map[ecom.Hash]ecom.Hash{
+		rootSlot: outputRoot,
+		timeSlot: ecom.BigToHash(packed),
+	}, rootSlot, timeSlot)
+
+	proof := &RollupProof{OutputIndex: 3, OracleProof: oracleProof, Output: output, L2Proof: l2Proof}
+	l1Header := &heth.Header{Root: l1Root, Time: 1600}
+	result, err := verifyFromRollupTx(cfg, l1Header, proof, ccmc[:], extra)
+	assert.Nil(t, err)
+	assert.Equal(t, txParam, result)
+
+	// still in challenge window
+	_, err = verifyFromRollupTx(cfg, &heth.Header{Root: l1Root, Time: 1599}, proof, ccmc[:], extra)
+	assert.NotNil(t, err)
+
+	// wrong output index
+	proof.OutputIndex = 2
+	_, err = verifyFromRollupTx(cfg, l1Header, proof, ccmc[:], extra)
+	assert.NotNil(t, err)
+	proof.OutputIndex = 3
+
+	// tampered preimage
+	proof.Output = &OutputRootPreimage{StateRoot: l2Root}
+	_, err = verifyFromRollupTx(cfg, l1Header, proof, ccmc[:], extra)
+	assert.NotNil(t, err)
+	proof.Output = output
+
+	// tx param not in L2 storage
+	txParam.Method = "lock"
+	sink = common.NewZeroCopySink(nil)
+	txParam.Serialization(sink)
+	_, err = verifyFromRollupTx(cfg, l1Header, proof, ccmc[:], sink.Bytes())
+	assert.NotNil(t, err)
+}
+
+func TestVerifyFromRollupTxStateRoot(t *testing.T) {
+	extra := []byte{1, 2, 3}
+	txSlot := ecom.Hash{0x77}
+	l2Root, l2Proof := makeProof(t, ccmc, map[ecom.Hash]ecom.Hash{txSlot: crypto.Keccak256Hash(extra)}, txSlot)
+
+	cfg := &RollupConfig{OutputOracle: oracle, OutputSize: 1, CommitsStateRoot: true}
+	rootSlot := ecom.BytesToHash(crypto.Keccak256(cfg.OutputsSlot[:]))
+	l1Root, oracleProof := makeProof(t, oracle, map[ecom.Hash]ecom.Hash{rootSlot: l2Root}, rootSlot)
+
+	proof := &RollupProof{OracleProof: oracleProof, L2Proof: l2Proof}
+	_, err := verifyFromRollupTx(cfg, &heth.Header{Root: l1Root}, proof, ccmc[:], extra)
+	// proofs pass while the extra is not a valid tx param
+	assert.Contains(t, err.Error(), "deserialize merkleValue")
+}
+
+func TestParseRollupConfig(t *testing.T) {
+	_, err := parseRollupConfig([]byte(`{"l1_chain_id":2}`))
+	assert.NotNil(t, err)
+	cfg, err := parseRollupConfig([]byte(`{"l1_chain_id":2,"output_oracle":"0x1000000000000000000000000000000000000001","challenge_window":604800}`))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), cfg.OutputSize)
+	assert.Equal(t, oracle, cfg.OutputOracle)
+	_, err = parseRollupConfig([]byte(`{"output_oracle":"0x1000000000000000000000000000000000000001","output_size":1,"challenge_window":1}`))
+	assert.NotNil(t, err)
+}
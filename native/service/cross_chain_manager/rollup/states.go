@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rollup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ecom "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager/eth"
+)
+
+// RollupConfig is the json encoded extra info of a rollup side chain. The rollup posts its output
+// roots into an array of OutputsSlot in the OutputOracle contract on L1, each element taking
+// OutputSize slots with the output root at the first one and the submission timestamp in the low
+// 128 bits of the second one, like the L2OutputOracle of the OP stack.
+type RollupConfig struct {
+	// Poly chain id of the L1, which must be synced by the eth router
+	L1ChainID    uint64       `json:"l1_chain_id"`
+	OutputOracle ecom.Address `json:"output_oracle"`
+	OutputsSlot  ecom.Hash    `json:"outputs_slot"`
+	OutputSize   uint64       `json:"output_size"`
+	// Seconds an output has to stay on L1 before it is final, 0 for validity proven rollups
+	ChallengeWindow uint64 `json:"challenge_window"`
+	// The committed root is the L2 state root itself instead of an OP stack output root
+	CommitsStateRoot bool `json:"commits_state_root"`
+}
+
+func parseRollupConfig(raw []byte) (*RollupConfig, error) {
+	cfg := new(RollupConfig)
+	if err := json.Unmarshal(raw, cfg); err != nil {
+		return nil, fmt.Errorf("parseRollupConfig, unmarshal extra info error: %v", err)
+	}
+	if cfg.OutputOracle == (ecom.Address{}) {
+		return nil, fmt.Errorf("parseRollupConfig, output oracle is not set")
+	}
+	if cfg.OutputSize == 0 {
+		cfg.OutputSize = 2
+	}
+	if cfg.ChallengeWindow != 0 && cfg.OutputSize < 2 {
+		return nil, fmt.Errorf("parseRollupConfig, output size %d leaves no slot for timestamp", cfg.OutputSize)
+	}
+	return cfg, nil
+}
+
+// OutputRootPreimage is what an OP stack output root hashes: version, L2 state root, storage root
+// of the L2ToL1MessagePasser and the L2 block hash
+type OutputRootPreimage struct {
+	Version                  ecom.Hash `json:"version"`
+	StateRoot                ecom.Hash `json:"state_root"`
+	MessagePasserStorageRoot ecom.Hash `json:"message_passer_storage_root"`
+	BlockHash                ecom.Hash `json:"block_hash"`
+}
+
+// RollupProof is the json encoded proof of a cross chain tx on a rollup. The output at OutputIndex
+// is proved by OracleProof against the L1 header at EntranceParam.Height, whose first storage proof
+// is for the output root and the second one for the timestamp if there is a challenge window.
+// L2Proof proves the cross chain tx in the CCM contract of L2 against the state root of the output.
+type RollupProof struct {
+	OutputIndex uint64              `json:"output_index"`
+	OracleProof *eth.ETHProof       `json:"oracle_proof"`
+	Output      *OutputRootPreimage `json:"output,omitempty"`
+	L2Proof     *eth.ETHProof       `json:"l2_proof"`
+}
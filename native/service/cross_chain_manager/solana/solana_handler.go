@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solana
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync/solana"
+)
+
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// MakeDepositProposal verifies the account written by the cross chain program against the bank
+// hash attested at slot params.Height. params.Proof is a serialized AccountProof and the account
+// data must be params.Extra. A serialized SlotAttestation for the slot can be given in
+// params.HeaderOrCrossChainMsg if it is not synced yet.
+func (this *Handler) MakeDepositProposal(service *native.NativeService) (*scom.MakeTxParam, error) {
+	params := new(scom.EntranceParam)
+	if err := params.Deserialization(common.NewZeroCopySource(service.GetInput())); err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, contract params deserialize error: %s", err)
+	}
+	sideChain, err := side_chain_manager.GetSideChain(service, params.SourceChainID)
+	if err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, side chain %d is not registered", params.SourceChainID)
+	}
+	slot := uint64(params.Height)
+	if len(params.HeaderOrCrossChainMsg) != 0 {
+		att := new(solana.SlotAttestation)
+		if err := att.Deserialization(common.NewZeroCopySource(params.HeaderOrCrossChainMsg)); err != nil {
+			return nil, fmt.Errorf("Solana MakeDepositProposal, deserialize attestation error: %v", err)
+		}
+		if att.Slot != slot {
+			return nil, fmt.Errorf("Solana MakeDepositProposal, attestation is for slot %d not %d", att.Slot, slot)
+		}
+		if _, err := solana.VerifyAndPutAttestation(service, params.SourceChainID, att); err != nil {
+			return nil, fmt.Errorf("Solana MakeDepositProposal, %v", err)
+		}
+	}
+	bankHash, err := solana.GetBankHash(service, params.SourceChainID, slot)
+	if err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, %v", err)
+	}
+	if bankHash == nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, slot %d is not attested", slot)
+	}
+
+	proof := new(solana.AccountProof)
+	if err := proof.Deserialization(common.NewZeroCopySource(params.Proof)); err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, deserialize account proof error: %v", err)
+	}
+	if !bytes.Equal(proof.Account.Owner, sideChain.CCMCAddress) {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, account owner %x is not the cross chain program %x",
+			proof.Account.Owner, sideChain.CCMCAddress)
+	}
+	if !bytes.Equal(proof.Account.Data, params.Extra) {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, account data not match extra")
+	}
+	proved, err := proof.BankHash()
+	if err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, %v", err)
+	}
+	if !bytes.Equal(proved, bankHash) {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, account proof is for bank %x, attested %x", proved, bankHash)
+	}
+
+	txParam := new(scom.MakeTxParam)
+	if err := txParam.Deserialization(common.NewZeroCopySource(params.Extra)); err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, deserialize merkleValue error:%s", err)
+	}
+	if err := scom.CheckDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, check done transaction error:%s", err)
+	}
+	if err := scom.PutDoneTx(service, txParam.CrossChainID, params.SourceChainID); err != nil {
+		return nil, fmt.Errorf("Solana MakeDepositProposal, PutDoneTx error:%s", err)
+	}
+	return txParam, nil
+}
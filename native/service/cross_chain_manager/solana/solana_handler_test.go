@@ -0,0 +1,171 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/solana"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChainID = uint64(200)
+
+var (
+	acct         = account.NewAccount("")
+	ccmProgram   = hash("cross chain program")
+	validatorKey = ed25519.NewKeyFromSeed(hash("validator"))
+)
+
+func init() {
+	genesis.GenesisBookkeepers = []keypair.PublicKey{acct.PublicKey}
+}
+
+func hash(s string) []byte {
+	h := sha256.Sum256([]byte(s))
+	return h[:]
+}
+
+func newNative(t *testing.T, args []byte, db *storage.CacheDB) *native.NativeService {
+	tx := &types.Transaction{SignedAddr: []common.Address{acct.Address}}
+	ns, err := native.NewNativeService(db, tx, 0, 0, common.Uint256{}, 0, args, false)
+	assert.Nil(t, err)
+	return ns
+}
+
+func TestMakeDepositProposal(t *testing.T) {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	sink := common.NewZeroCopySink(nil)
+	view := &node_manager.GovernanceView{TxHash: common.UINT256_EMPTY}
+	view.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+	peerPoolMap := &node_manager.PeerPoolMap{PeerPoolMap: map[string]*node_manager.PeerPoolItem{
+		vconfig.PubkeyID(acct.PublicKey): {
+			Address:    acct.Address,
+			Status:     node_manager.ConsensusStatus,
+			PeerPubkey: vconfig.PubkeyID(acct.PublicKey),
+		},
+	}}
+	sink.Reset()
+	peerPoolMap.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+
+	extra, _ := json.Marshal(map[string]string{
+		"stake_table_account": hex.EncodeToString(hash("stake table account")),
+		"stake_table_owner":   hex.EncodeToString(hash("stake program")),
+	})
+	assert.Nil(t, side_chain_manager.PutSideChain(newNative(t, nil, db), &side_chain_manager.SideChain{
+		ChainId:     testChainID,
+		Router:      utils.SOLANA_ROUTER,
+		Name:        "solana",
+		CCMCAddress: ccmProgram,
+		ExtraInfo:   extra,
+	}))
+
+	table := &solana.StakeTable{LastSlot: 1000, Validators: []*solana.Validator{
+		{PubKey: validatorKey.Public().(ed25519.PublicKey), Stake: 100},
+	}}
+	sink.Reset()
+	table.Serialization(sink)
+	genesisParam := &hscommon.SyncGenesisHeaderParam{ChainID: testChainID, GenesisHeader: sink.Bytes()}
+	sink = common.NewZeroCopySink(nil)
+	genesisParam.Serialization(sink)
+	assert.Nil(t, solana.NewHandler().SyncGenesisHeader(newNative(t, sink.Bytes(), db)))
+
+	txParam := &scom.MakeTxParam{
+		TxHash:              []byte{1},
+		CrossChainID:        []byte{2},
+		FromContractAddress: []byte{3},
+		ToChainID:           2,
+		ToContractAddress:   []byte{4},
+		Method:              "unlock",
+		Args:                []byte{5},
+	}
+	sink = common.NewZeroCopySink(nil)
+	txParam.Serialization(sink)
+	value := sink.Bytes()
+
+	proof := &solana.AccountProof{
+		Account:        &solana.Account{Pubkey: hash("ccm account"), Owner: ccmProgram, Lamports: 1, Data: value},
+		Path:           []*solana.ProofLevel{{Index: 0, Siblings: [][]byte{hash("other account")}}},
+		ParentBankHash: hash("parent"),
+		LastBlockhash:  hash("blockhash"),
+	}
+	bankHash, err := proof.BankHash()
+	assert.Nil(t, err)
+	att := &solana.SlotAttestation{Slot: 10, BankHash: bankHash, Votes: []*solana.Vote{
+		{Index: 0, Signature: ed25519.Sign(validatorKey, solana.VoteSignBytes(10, bankHash))},
+	}}
+
+	makeParam := func(slot uint32, att *solana.SlotAttestation) []byte {
+		param := &scom.EntranceParam{SourceChainID: testChainID, Height: slot, Extra: value}
+		sink := common.NewZeroCopySink(nil)
+		proof.Serialization(sink)
+		param.Proof = sink.Bytes()
+		if att != nil {
+			sink = common.NewZeroCopySink(nil)
+			att.Serialization(sink)
+			param.HeaderOrCrossChainMsg = sink.Bytes()
+		}
+		sink = common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		return sink.Bytes()
+	}
+
+	// side chain is not registered
+	sink = common.NewZeroCopySink(nil)
+	(&scom.EntranceParam{SourceChainID: testChainID + 1, Height: 10, Extra: value}).Serialization(sink)
+	_, err = NewHandler().MakeDepositProposal(newNative(t, sink.Bytes(), db))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is not registered")
+
+	// slot is not attested
+	_, err = NewHandler().MakeDepositProposal(newNative(t, makeParam(10, nil), db))
+	assert.NotNil(t, err)
+
+	result, err := NewHandler().MakeDepositProposal(newNative(t, makeParam(10, att), db))
+	assert.Nil(t, err)
+	assert.Equal(t, txParam, result)
+
+	// done already
+	_, err = NewHandler().MakeDepositProposal(newNative(t, makeParam(10, nil), db))
+	assert.NotNil(t, err)
+}
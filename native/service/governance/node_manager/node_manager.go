@@ -37,24 +37,28 @@ const (
 	BlackStatus
 
 	//function name
-	REGISTER_CANDIDATE   = "registerCandidate"
-	UNREGISTER_CANDIDATE = "unRegisterCandidate"
-	APPROVE_CANDIDATE    = "approveCandidate"
-	BLACK_NODE           = "blackNode"
-	WHITE_NODE           = "whiteNode"
-	QUIT_NODE            = "quitNode"
-	UPDATE_CONFIG        = "updateConfig"
-	COMMIT_DPOS          = "commitDpos"
+	REGISTER_CANDIDATE        = "registerCandidate"
+	UNREGISTER_CANDIDATE      = "unRegisterCandidate"
+	APPROVE_CANDIDATE         = "approveCandidate"
+	BLACK_NODE                = "blackNode"
+	WHITE_NODE                = "whiteNode"
+	QUIT_NODE                 = "quitNode"
+	UPDATE_CONFIG             = "updateConfig"
+	COMMIT_DPOS               = "commitDpos"
+	UPDATE_PERFORMANCE_POLICY = "updatePerformancePolicy"
 
 	//key prefix
-	GOVERNANCE_VIEW = "governanceView"
-	VBFT_CONFIG     = "vbftConfig"
-	CANDIDITE_INDEX = "candidateIndex"
-	PEER_APPLY      = "peerApply"
-	PEER_POOL       = "peerPool"
-	PEER_INDEX      = "peerIndex"
-	BLACK_LIST      = "blackList"
-	CONSENSUS_SIGNS = "consensusSigns"
+	GOVERNANCE_VIEW    = "governanceView"
+	VBFT_CONFIG        = "vbftConfig"
+	CANDIDITE_INDEX    = "candidateIndex"
+	PEER_APPLY         = "peerApply"
+	PEER_POOL          = "peerPool"
+	PEER_INDEX         = "peerIndex"
+	BLACK_LIST         = "blackList"
+	CONSENSUS_SIGNS    = "consensusSigns"
+	PEER_PERFORMANCE   = "peerPerformance"
+	PERFORMANCE_POLICY = "performancePolicy"
+	CONFIG_HEIGHT      = "configHeight"
 
 	//const
 	MIN_PEER_NUM = 4
@@ -71,6 +75,7 @@ func RegisterNodeManagerContract(native *native.NativeService) {
 	native.Register(WHITE_NODE, WhiteNode)
 	native.Register(UPDATE_CONFIG, UpdateConfig)
 	native.Register(COMMIT_DPOS, CommitDpos)
+	native.Register(UPDATE_PERFORMANCE_POLICY, UpdatePerformancePolicy)
 }
 
 //Init node_manager contract
@@ -586,18 +591,63 @@ func CommitDpos(native *native.NativeService) ([]byte, error) {
 	}
 
 	//check witness
+	byConsensus := false
 	err = utils.ValidateOwner(native, operatorAddress)
 	if err != nil {
 		cycle := (native.GetHeight() - governanceView.Height) >= config.MaxBlockChangeView
 		if !cycle {
 			return utils.BYTE_FALSE, fmt.Errorf("commitDpos, authentication Failed")
 		}
+		byConsensus = true
+	}
+
+	//consensus forces its governance transaction max block change view after the config block of current view,
+	//peer performance is only taken there so that a peer can't commit its own one in the blocks before
+	configHeight, err := getConfigHeight(native, governanceView)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commitDpos, getConfigHeight error: %v", err)
+	}
+	enforced := native.GetHeight() == configHeight+config.MaxBlockChangeView
+
+	//record peer performance committed by consensus, input of other callers is ignored as before performance tracking
+	var demoted []string
+	if input := native.GetInput(); len(input) > 0 && byConsensus && enforced && peerPerformanceEnabled(native) {
+		witnessed, err := checkConsensusWitness(native, governanceView.View)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("commitDpos, checkConsensusWitness error: %v", err)
+		}
+		if witnessed {
+			params := new(CommitDposParam)
+			if err := params.Deserialization(common.NewZeroCopySource(input)); err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("commitDpos, contract params deserialize error: %v", err)
+			}
+			demoted, err = commitPeerPerformance(native, governanceView.View, params.Performances)
+			if err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("commitDpos, commitPeerPerformance error: %v", err)
+			}
+		}
 	}
 
 	err = executeCommitDpos(native)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
 	}
+	//the block of the transaction forced by consensus carries the new config, otherwise it comes in next block
+	if enforced {
+		putConfigHeight(native, native.GetHeight())
+	} else {
+		putConfigHeight(native, native.GetHeight()+1)
+	}
+	if len(demoted) > 0 {
+		if err := demotePeers(native, governanceView.View+1, demoted); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("commitDpos, demotePeers error: %v", err)
+		}
+		native.AddNotify(
+			&event.NotifyEventInfo{
+				ContractAddress: utils.NodeManagerContractAddress,
+				States:          []interface{}{"demotePeers", demoted},
+			})
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
@@ -647,3 +697,34 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+//Update policy of demoting offline consensus peers
+func UpdatePerformancePolicy(native *native.NativeService) ([]byte, error) {
+	params := new(UpdatePerformancePolicyParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("updatePerformancePolicy, deserialize policy error: %v", err)
+	}
+
+	// Get current epoch operator
+	operatorAddress, err := GetCurConOperator(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("updatePerformancePolicy, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("updatePerformancePolicy, checkWitness error: %v", err)
+	}
+
+	if params.Policy.MaxMissedRate > 100 {
+		return utils.BYTE_FALSE, fmt.Errorf("updatePerformancePolicy. MaxMissedRate must <= 100")
+	}
+
+	putPerformancePolicy(native, params.Policy)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"updatePerformancePolicy", params.Policy},
+		})
+	return utils.BYTE_TRUE, nil
+}
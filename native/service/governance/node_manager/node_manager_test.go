@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCommitDposNative returns a native service at height executing tx, over a view of 7 consensus peers
+// started at height 0, where the first peer is offline
+func newCommitDposNative(t *testing.T, peers []keypair.PublicKey, tx *types.Transaction, height uint32) *native.NativeService {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+
+	performances := make([]*PeerPerformance, 0, len(peers))
+	for i, pk := range peers {
+		performance := &PeerPerformance{PeerPubkey: hex.EncodeToString(keypair.SerializePublicKey(pk)), EndorsedBlocks: 10}
+		if i == 0 {
+			performance.EndorsedBlocks, performance.MissedEndorsements = 0, 10
+		}
+		performances = append(performances, performance)
+	}
+	param := &CommitDposParam{Performances: performances}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+
+	ns, err := native.NewNativeService(db, tx, 0, height, common.Uint256{}, 0, sink.Bytes(), false)
+	assert.Nil(t, err)
+	peerPoolMap := &PeerPoolMap{PeerPoolMap: make(map[string]*PeerPoolItem)}
+	for i, p := range performances {
+		peerPoolMap.PeerPoolMap[p.PeerPubkey] = &PeerPoolItem{Index: uint32(i + 1), PeerPubkey: p.PeerPubkey, Status: ConsensusStatus}
+	}
+	putPeerPoolMap(ns, peerPoolMap, 1)
+	putGovernanceView(ns, &GovernanceView{View: 1})
+	putConfig(ns, &Configuration{MaxBlockChangeView: 10})
+	putPerformancePolicy(ns, &PerformancePolicy{MinExpected: 1, MaxMissedRate: 50, OfflineEpochs: 1})
+	return ns
+}
+
+func TestCommitDposPeerPerformance(t *testing.T) {
+	networkId := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkId
+	}()
+
+	peers := make([]keypair.PublicKey, 7)
+	for i := range peers {
+		_, pk, err := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+		assert.Nil(t, err)
+		peers[i] = pk
+	}
+	offline := hex.EncodeToString(keypair.SerializePublicKey(peers[0]))
+	signedBy := func(pk keypair.PublicKey) *types.Transaction {
+		return &types.Transaction{SignedAddr: []common.Address{types.AddressFromPubKey(pk)}}
+	}
+	status := func(ns *native.NativeService) Status {
+		peerPoolMap, err := GetPeerPoolMap(ns, 2)
+		assert.Nil(t, err)
+		return peerPoolMap.PeerPoolMap[offline].Status
+	}
+
+	// the governance transaction signed by a consensus peer demotes the offline peer
+	ns := newCommitDposNative(t, peers, signedBy(peers[3]), 10)
+	_, err := CommitDpos(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, CandidateStatus, status(ns))
+	records, err := GetPeerPerformanceMap(ns, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), records.PeerPerformanceMap[offline].OfflineEpochs)
+	assert.Equal(t, []interface{}{"demotePeers", []string{offline}}, ns.GetNotify()[0].States)
+
+	// performance of other callers is ignored, commitDpos goes on as without input
+	_, other, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	for _, tx := range []*types.Transaction{new(types.Transaction), signedBy(other)} {
+		ns = newCommitDposNative(t, peers, tx, 10)
+		_, err = CommitDpos(ns)
+		assert.Nil(t, err)
+		assert.Equal(t, ConsensusStatus, status(ns))
+		records, err = GetPeerPerformanceMap(ns, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, records.PeerPerformanceMap)
+		assert.Equal(t, 1, len(ns.GetNotify()))
+	}
+
+	// the view was changed by a governance transaction at height 0, its config comes in block 1 and consensus
+	// forces commitDpos at 11, so the performance of a peer committing at 10 is ignored
+	ns = newCommitDposNative(t, peers, signedBy(peers[5]), 10)
+	putConfigHeight(ns, 1)
+	_, err = CommitDpos(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, ConsensusStatus, status(ns))
+	records, err = GetPeerPerformanceMap(ns, 1)
+	assert.Nil(t, err)
+	assert.Empty(t, records.PeerPerformanceMap)
+	configHeight, err := getConfigHeight(ns, &GovernanceView{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(11), configHeight)
+
+	ns = newCommitDposNative(t, peers, signedBy(peers[3]), 11)
+	putConfigHeight(ns, 1)
+	_, err = CommitDpos(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, CandidateStatus, status(ns))
+	configHeight, err = getConfigHeight(ns, &GovernanceView{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(11), configHeight)
+
+	// nobody commits before the view ends
+	ns = newCommitDposNative(t, peers, new(types.Transaction), 9)
+	_, err = CommitDpos(ns)
+	assert.NotNil(t, err)
+
+	// performance is not committed before peer performance height
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	ns = newCommitDposNative(t, peers, signedBy(peers[3]), 10)
+	_, err = CommitDpos(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, ConsensusStatus, status(ns))
+}
@@ -137,3 +137,49 @@ func (this *UpdateConfigParam) Deserialization(source *common.ZeroCopySource) er
 	this.Configuration = configuration
 	return nil
 }
+
+type CommitDposParam struct {
+	Performances []*PeerPerformance
+}
+
+func (this *CommitDposParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.Performances)))
+	for _, v := range this.Performances {
+		v.Serialization(sink)
+	}
+}
+
+func (this *CommitDposParam) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize Performances length error")
+	}
+	performances := make([]*PeerPerformance, 0)
+	for i := 0; uint64(i) < n; i++ {
+		performance := new(PeerPerformance)
+		if err := performance.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize performance error: %v", err)
+		}
+		performances = append(performances, performance)
+	}
+	this.Performances = performances
+	return nil
+}
+
+type UpdatePerformancePolicyParam struct {
+	Policy *PerformancePolicy
+}
+
+func (this *UpdatePerformancePolicyParam) Serialization(sink *common.ZeroCopySink) {
+	this.Policy.Serialization(sink)
+}
+
+func (this *UpdatePerformancePolicyParam) Deserialization(source *common.ZeroCopySource) error {
+	policy := new(PerformancePolicy)
+	err := policy.Deserialization(source)
+	if err != nil {
+		return fmt.Errorf("policy.Deserialization, deserialize policy error: %s", err)
+	}
+	this.Policy = policy
+	return nil
+}
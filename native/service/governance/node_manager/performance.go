@@ -0,0 +1,264 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// peerPerformanceEnabled returns if commitDpos takes the peer performance of consensus at current height
+func peerPerformanceEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetPeerPerformanceHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+// checkConsensusWitness returns if the transaction is signed by a consensus peer of view, the governance
+// transaction built by consensus is signed by its proposer and checked by the other peers
+func checkConsensusWitness(native *native.NativeService, view uint32) (bool, error) {
+	peerPoolMap, err := GetPeerPoolMap(native, view)
+	if err != nil {
+		return false, fmt.Errorf("checkConsensusWitness, get peerPoolMap error: %v", err)
+	}
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status != ConsensusStatus {
+			continue
+		}
+		raw, err := hex.DecodeString(peerPoolItem.PeerPubkey)
+		if err != nil {
+			return false, fmt.Errorf("checkConsensusWitness, peerPubkey format error: %v", err)
+		}
+		pk, err := keypair.DeserializePublicKey(raw)
+		if err != nil {
+			return false, fmt.Errorf("checkConsensusWitness, deserialize peerPubkey error: %v", err)
+		}
+		if native.CheckWitness(types.AddressFromPubKey(pk)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getConfigHeight returns the height of the block carrying the chain config of current view, it is the height
+// of governance view if not recorded, which is the case when the view was changed by consensus
+func getConfigHeight(native *native.NativeService, governanceView *GovernanceView) (uint32, error) {
+	contract := utils.NodeManagerContractAddress
+	heightBytes, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(CONFIG_HEIGHT)))
+	if err != nil {
+		return 0, fmt.Errorf("getConfigHeight, get heightBytes error: %v", err)
+	}
+	if heightBytes == nil {
+		return governanceView.Height, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(heightBytes)
+	if err != nil {
+		return 0, fmt.Errorf("getConfigHeight, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint32(value), nil
+}
+
+func putConfigHeight(native *native.NativeService, height uint32) {
+	contract := utils.NodeManagerContractAddress
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(CONFIG_HEIGHT)),
+		cstates.GenRawStorageItem(utils.GetUint32Bytes(height)))
+}
+
+func GetPerformancePolicy(native *native.NativeService) (*PerformancePolicy, error) {
+	contract := utils.NodeManagerContractAddress
+	policyBytes, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PERFORMANCE_POLICY)))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get policyBytes error: %v", err)
+	}
+	if policyBytes == nil {
+		return nil, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(policyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getPerformancePolicy, deserialize from raw storage item err:%v", err)
+	}
+	policy := new(PerformancePolicy)
+	if err := policy.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("getPerformancePolicy, deserialize policy error: %v", err)
+	}
+	return policy, nil
+}
+
+func putPerformancePolicy(native *native.NativeService, policy *PerformancePolicy) {
+	contract := utils.NodeManagerContractAddress
+	sink := common.NewZeroCopySink(nil)
+	policy.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PERFORMANCE_POLICY)), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func GetPeerPerformanceMap(native *native.NativeService, view uint32) (*PeerPerformanceMap, error) {
+	contract := utils.NodeManagerContractAddress
+	viewBytes := utils.GetUint32Bytes(view)
+	peerPerformanceMap := &PeerPerformanceMap{
+		PeerPerformanceMap: make(map[string]*PeerPerformance),
+	}
+	peerPerformanceMapBytes, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PEER_PERFORMANCE), viewBytes))
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPerformanceMap, get peerPerformanceMap error: %v", err)
+	}
+	if peerPerformanceMapBytes == nil {
+		return peerPerformanceMap, nil
+	}
+	value, err := cstates.GetValueFromRawStorageItem(peerPerformanceMapBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getPeerPerformanceMap, deserialize from raw storage item err:%v", err)
+	}
+	if err := peerPerformanceMap.Deserialization(common.NewZeroCopySource(value)); err != nil {
+		return nil, fmt.Errorf("getPeerPerformanceMap, deserialize peerPerformanceMap error: %v", err)
+	}
+	return peerPerformanceMap, nil
+}
+
+func putPeerPerformanceMap(native *native.NativeService, peerPerformanceMap *PeerPerformanceMap, view uint32) {
+	contract := utils.NodeManagerContractAddress
+	viewBytes := utils.GetUint32Bytes(view)
+	sink := common.NewZeroCopySink(nil)
+	peerPerformanceMap.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PEER_PERFORMANCE), viewBytes), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// EvaluatePeerPerformance merges the performances of peers in current view with the records of last view,
+// and returns the records of current view and the consensus peers to be demoted to candidates.
+//
+// A peer whose missed rate exceeds policy.MaxMissedRate in a view is offline in that view, a peer judged
+// online resets its offline epochs, a peer not judged (absent, or too few expected participations) keeps them.
+// Peers offline in current view for at least policy.OfflineEpochs consecutive views are demoted, worst first,
+// as long as at most (N-1)/3 of N consensus peers are demoted and at least MIN_PEER_NUM peers are left.
+// It is shared by the consensus and the node_manager contract, and must be deterministic.
+func EvaluatePeerPerformance(policy *PerformancePolicy, last *PeerPerformanceMap, performances []*PeerPerformance,
+	peerPoolMap *PeerPoolMap) (*PeerPerformanceMap, []string) {
+	records := &PeerPerformanceMap{
+		PeerPerformanceMap: make(map[string]*PeerPerformance),
+	}
+	for k, v := range last.PeerPerformanceMap {
+		if v.OfflineEpochs > 0 {
+			records.PeerPerformanceMap[k] = &PeerPerformance{PeerPubkey: k, OfflineEpochs: v.OfflineEpochs}
+		}
+	}
+
+	offline := make([]*PeerPerformance, 0)
+	for _, p := range performances {
+		record := *p
+		record.OfflineEpochs = 0
+		if l, ok := last.PeerPerformanceMap[p.PeerPubkey]; ok {
+			record.OfflineEpochs = l.OfflineEpochs
+		}
+		if policy != nil && record.expected() > 0 && record.expected() >= policy.MinExpected {
+			if record.missed()*100 > record.expected()*uint64(policy.MaxMissedRate) {
+				record.OfflineEpochs++
+				offline = append(offline, &record)
+			} else {
+				record.OfflineEpochs = 0
+			}
+		}
+		records.PeerPerformanceMap[p.PeerPubkey] = &record
+	}
+	if policy == nil || policy.OfflineEpochs == 0 {
+		return records, nil
+	}
+
+	num := 0
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
+			num++
+		}
+	}
+	max := (num - 1) / 3
+	if num-MIN_PEER_NUM < max {
+		max = num - MIN_PEER_NUM
+	}
+
+	candidates := make([]*PeerPerformance, 0)
+	for _, record := range offline {
+		peerPoolItem, ok := peerPoolMap.PeerPoolMap[record.PeerPubkey]
+		if !ok || peerPoolItem.Status != ConsensusStatus {
+			continue
+		}
+		if record.OfflineEpochs >= policy.OfflineEpochs {
+			candidates = append(candidates, record)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].OfflineEpochs != candidates[j].OfflineEpochs {
+			return candidates[i].OfflineEpochs > candidates[j].OfflineEpochs
+		}
+		ri := candidates[i].missed() * candidates[j].expected()
+		rj := candidates[j].missed() * candidates[i].expected()
+		if ri != rj {
+			return ri > rj
+		}
+		return candidates[i].PeerPubkey < candidates[j].PeerPubkey
+	})
+
+	demoted := make([]string, 0)
+	for i := 0; i < len(candidates) && i < max; i++ {
+		demoted = append(demoted, candidates[i].PeerPubkey)
+	}
+	return records, demoted
+}
+
+func commitPeerPerformance(native *native.NativeService, view uint32, performances []*PeerPerformance) ([]string, error) {
+	policy, err := GetPerformancePolicy(native)
+	if err != nil {
+		return nil, fmt.Errorf("commitPeerPerformance, get performance policy error: %v", err)
+	}
+	last := &PeerPerformanceMap{
+		PeerPerformanceMap: make(map[string]*PeerPerformance),
+	}
+	if view > 1 {
+		last, err = GetPeerPerformanceMap(native, view-1)
+		if err != nil {
+			return nil, fmt.Errorf("commitPeerPerformance, get last peerPerformanceMap error: %v", err)
+		}
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, view)
+	if err != nil {
+		return nil, fmt.Errorf("commitPeerPerformance, get peerPoolMap error: %v", err)
+	}
+	records, demoted := EvaluatePeerPerformance(policy, last, performances, peerPoolMap)
+	putPeerPerformanceMap(native, records, view)
+	return demoted, nil
+}
+
+func demotePeers(native *native.NativeService, view uint32, demoted []string) error {
+	peerPoolMap, err := GetPeerPoolMap(native, view)
+	if err != nil {
+		return fmt.Errorf("demotePeers, get peerPoolMap error: %v", err)
+	}
+	for _, peerPubkey := range demoted {
+		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
+		if !ok {
+			return fmt.Errorf("demotePeers, peer %s is not in peerPoolMap", peerPubkey)
+		}
+		peerPoolItem.Status = CandidateStatus
+	}
+	putPeerPoolMap(native, peerPoolMap, view)
+	return nil
+}
@@ -261,3 +261,134 @@ func (this *Configuration) Deserialization(source *common.ZeroCopySource) error
 	this.MaxBlockChangeView = maxBlockChangeView
 	return nil
 }
+
+// PeerPerformance is the participation of a consensus peer in blocks of one governance view
+type PeerPerformance struct {
+	PeerPubkey         string
+	ProposedBlocks     uint64 //blocks proposed by this peer
+	MissedProposals    uint64 //blocks this peer was the first proposer of, but proposed by others
+	EndorsedBlocks     uint64 //blocks endorsed by this peer
+	MissedEndorsements uint64 //blocks this peer was selected to endorse, but not signed by this peer
+	OfflineEpochs      uint32 //consecutive views judged offline by performance policy, till this view
+}
+
+func (this *PeerPerformance) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.PeerPubkey)
+	sink.WriteUint64(this.ProposedBlocks)
+	sink.WriteUint64(this.MissedProposals)
+	sink.WriteUint64(this.EndorsedBlocks)
+	sink.WriteUint64(this.MissedEndorsements)
+	sink.WriteUint32(this.OfflineEpochs)
+}
+
+func (this *PeerPerformance) Deserialization(source *common.ZeroCopySource) error {
+	peerPubkey, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("source.NextString, deserialize peerPubkey error")
+	}
+	proposedBlocks, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize proposedBlocks error")
+	}
+	missedProposals, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize missedProposals error")
+	}
+	endorsedBlocks, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize endorsedBlocks error")
+	}
+	missedEndorsements, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize missedEndorsements error")
+	}
+	offlineEpochs, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize offlineEpochs error")
+	}
+
+	this.PeerPubkey = peerPubkey
+	this.ProposedBlocks = proposedBlocks
+	this.MissedProposals = missedProposals
+	this.EndorsedBlocks = endorsedBlocks
+	this.MissedEndorsements = missedEndorsements
+	this.OfflineEpochs = offlineEpochs
+	return nil
+}
+
+// expected return the number of blocks this peer was expected to take part in
+func (this *PeerPerformance) expected() uint64 {
+	return this.ProposedBlocks + this.MissedProposals + this.EndorsedBlocks + this.MissedEndorsements
+}
+
+func (this *PeerPerformance) missed() uint64 {
+	return this.MissedProposals + this.MissedEndorsements
+}
+
+type PeerPerformanceMap struct {
+	PeerPerformanceMap map[string]*PeerPerformance
+}
+
+func (this *PeerPerformanceMap) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.PeerPerformanceMap)))
+	var performanceList []*PeerPerformance
+	for _, v := range this.PeerPerformanceMap {
+		performanceList = append(performanceList, v)
+	}
+	sort.SliceStable(performanceList, func(i, j int) bool {
+		return performanceList[i].PeerPubkey > performanceList[j].PeerPubkey
+	})
+	for _, v := range performanceList {
+		v.Serialization(sink)
+	}
+}
+
+func (this *PeerPerformanceMap) Deserialization(source *common.ZeroCopySource) error {
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("source.NextVarUint, deserialize PeerPerformanceMap length error")
+	}
+	peerPerformanceMap := make(map[string]*PeerPerformance)
+	for i := 0; uint64(i) < n; i++ {
+		peerPerformance := new(PeerPerformance)
+		if err := peerPerformance.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize peerPerformance error: %v", err)
+		}
+		peerPerformanceMap[peerPerformance.PeerPubkey] = peerPerformance
+	}
+	this.PeerPerformanceMap = peerPerformanceMap
+	return nil
+}
+
+// PerformancePolicy decides when a consensus peer is chronically offline and demoted to candidate
+type PerformancePolicy struct {
+	MinExpected   uint64 //least expected participations in a view for a peer to be judged
+	MaxMissedRate uint32 //percent of missed participations over which a peer is offline in a view
+	OfflineEpochs uint32 //consecutive offline views before demotion, 0 disables demotion
+}
+
+func (this *PerformancePolicy) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.MinExpected)
+	sink.WriteUint32(this.MaxMissedRate)
+	sink.WriteUint32(this.OfflineEpochs)
+}
+
+func (this *PerformancePolicy) Deserialization(source *common.ZeroCopySource) error {
+	minExpected, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize minExpected error")
+	}
+	maxMissedRate, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize maxMissedRate error")
+	}
+	offlineEpochs, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize offlineEpochs error")
+	}
+
+	this.MinExpected = minExpected
+	this.MaxMissedRate = maxMissedRate
+	this.OfflineEpochs = offlineEpochs
+	return nil
+}
@@ -38,3 +38,72 @@ func Test_Deserialize_GovernanceView(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, *govView, *govView1)
 }
+
+func Test_Deserialize_PeerPerformanceMap(t *testing.T) {
+	performanceMap := &PeerPerformanceMap{
+		PeerPerformanceMap: map[string]*PeerPerformance{
+			"01": {PeerPubkey: "01", ProposedBlocks: 10, MissedProposals: 1, EndorsedBlocks: 20, MissedEndorsements: 2, OfflineEpochs: 0},
+			"02": {PeerPubkey: "02", ProposedBlocks: 0, MissedProposals: 11, EndorsedBlocks: 0, MissedEndorsements: 22, OfflineEpochs: 3},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	performanceMap.Serialization(sink)
+
+	source := common.NewZeroCopySource(sink.Bytes())
+	performanceMap1 := new(PeerPerformanceMap)
+	err := performanceMap1.Deserialization(source)
+	assert.Nil(t, err)
+	assert.Equal(t, performanceMap, performanceMap1)
+
+	policy := &PerformancePolicy{MinExpected: 100, MaxMissedRate: 50, OfflineEpochs: 2}
+	sink = common.NewZeroCopySink(nil)
+	policy.Serialization(sink)
+	policy1 := new(PerformancePolicy)
+	err = policy1.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, policy, policy1)
+}
+
+func Test_EvaluatePeerPerformance(t *testing.T) {
+	peerPoolMap := &PeerPoolMap{PeerPoolMap: make(map[string]*PeerPoolItem)}
+	var performances []*PeerPerformance
+	for _, k := range []string{"01", "02", "03", "04", "05", "06", "07"} {
+		peerPoolMap.PeerPoolMap[k] = &PeerPoolItem{PeerPubkey: k, Status: ConsensusStatus}
+		performances = append(performances, &PeerPerformance{PeerPubkey: k, ProposedBlocks: 10, EndorsedBlocks: 90})
+	}
+	// 05 and 06 missed most of blocks, 07 missed half of blocks
+	performances[4].EndorsedBlocks, performances[4].MissedEndorsements = 0, 90
+	performances[5].EndorsedBlocks, performances[5].MissedEndorsements = 10, 80
+	performances[6].EndorsedBlocks, performances[6].MissedEndorsements = 50, 40
+	last := &PeerPerformanceMap{PeerPerformanceMap: map[string]*PeerPerformance{
+		"05": {PeerPubkey: "05", OfflineEpochs: 1},
+		"06": {PeerPubkey: "06", OfflineEpochs: 1},
+		"08": {PeerPubkey: "08", OfflineEpochs: 2},
+	}}
+
+	// no policy, nothing judged
+	records, demoted := EvaluatePeerPerformance(nil, last, performances, peerPoolMap)
+	assert.Nil(t, demoted)
+	assert.Equal(t, uint32(1), records.PeerPerformanceMap["05"].OfflineEpochs)
+	assert.Equal(t, uint32(2), records.PeerPerformanceMap["08"].OfflineEpochs)
+
+	// 05 and 06 are offline for 2 views, but only (7-1)/3 = 2 of 7 peers can be demoted, worst first
+	policy := &PerformancePolicy{MinExpected: 50, MaxMissedRate: 30, OfflineEpochs: 2}
+	records, demoted = EvaluatePeerPerformance(policy, last, performances, peerPoolMap)
+	assert.Equal(t, []string{"05", "06"}, demoted)
+	assert.Equal(t, uint32(2), records.PeerPerformanceMap["06"].OfflineEpochs)
+	assert.Equal(t, uint32(1), records.PeerPerformanceMap["07"].OfflineEpochs)
+	assert.Equal(t, uint32(0), records.PeerPerformanceMap["01"].OfflineEpochs)
+
+	// at least MIN_PEER_NUM peers are left
+	delete(peerPoolMap.PeerPoolMap, "01")
+	delete(peerPoolMap.PeerPoolMap, "02")
+	_, demoted = EvaluatePeerPerformance(policy, last, performances, peerPoolMap)
+	assert.Equal(t, []string{"05"}, demoted)
+
+	// too few expected participations to be judged
+	policy.MinExpected = 1000
+	records, demoted = EvaluatePeerPerformance(policy, last, performances, peerPoolMap)
+	assert.Empty(t, demoted)
+	assert.Equal(t, uint32(1), records.PeerPerformanceMap["06"].OfflineEpochs)
+}
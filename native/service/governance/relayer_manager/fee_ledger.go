@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// feeLedgerEnabled returns if relayer work is accounted at current height
+func feeLedgerEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetFeeLedgerHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+func GetRelayerReward(native *native.NativeService, relayer common.Address, chainID uint64) (*RelayerReward, error) {
+	contract := utils.RelayerManagerContractAddress
+	reward := &RelayerReward{
+		Address: relayer,
+		ChainID: chainID,
+		Earned:  new(big.Int),
+		Claimed: new(big.Int),
+	}
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(RELAYER_REWARD), relayer[:], utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("GetRelayerReward, get relayer reward store error: %v", err)
+	}
+	if store == nil {
+		return reward, nil
+	}
+	rewardBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRelayerReward, deserialize from raw storage item err:%v", err)
+	}
+	if err := reward.Deserialization(common.NewZeroCopySource(rewardBytes)); err != nil {
+		return nil, fmt.Errorf("GetRelayerReward, deserialize relayer reward err:%v", err)
+	}
+	return reward, nil
+}
+
+func putRelayerReward(native *native.NativeService, reward *RelayerReward) {
+	contract := utils.RelayerManagerContractAddress
+	sink := common.NewZeroCopySink(nil)
+	reward.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_REWARD), reward.Address[:], utils.GetUint64Bytes(reward.ChainID)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func GetRequestFee(native *native.NativeService, toChainID uint64, txHash []byte) (*RequestFee, error) {
+	contract := utils.RelayerManagerContractAddress
+	store, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(REQUEST_FEE), utils.GetUint64Bytes(toChainID), txHash))
+	if err != nil {
+		return nil, fmt.Errorf("GetRequestFee, get request fee store error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	feeBytes, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetRequestFee, deserialize from raw storage item err:%v", err)
+	}
+	requestFee := new(RequestFee)
+	if err := requestFee.Deserialization(common.NewZeroCopySource(feeBytes)); err != nil {
+		return nil, fmt.Errorf("GetRequestFee, deserialize request fee err:%v", err)
+	}
+	return requestFee, nil
+}
+
+func putRequestFee(native *native.NativeService, requestFee *RequestFee) {
+	contract := utils.RelayerManagerContractAddress
+	sink := common.NewZeroCopySink(nil)
+	requestFee.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(REQUEST_FEE), utils.GetUint64Bytes(requestFee.ToChainID), requestFee.TxHash),
+		cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+// RecordSyncedHeaders attributes headers synced for a chain to the relayer, if the relayer signed the tx
+func RecordSyncedHeaders(native *native.NativeService, relayer common.Address, chainID uint64, count uint64) error {
+	if !feeLedgerEnabled(native) || count == 0 {
+		return nil
+	}
+	if err := utils.ValidateOwner(native, relayer); err != nil {
+		return nil
+	}
+	reward, err := GetRelayerReward(native, relayer, chainID)
+	if err != nil {
+		return fmt.Errorf("RecordSyncedHeaders, %v", err)
+	}
+	reward.HeadersSynced += count
+	putRelayerReward(native, reward)
+	return nil
+}
+
+// RecordDeliveredProof records the fee quoted for the target chain of the cross chain request keyed by txHash, the
+// poly tx hash of its ToMerkleValue, and credits it to the relayer delivered the proof, if the relayer signed the tx.
+func RecordDeliveredProof(native *native.NativeService, relayerAddress []byte, fromChainID, toChainID uint64,
+	txHash common.Uint256) error {
+	if !feeLedgerEnabled(native) {
+		return nil
+	}
+	fee, err := side_chain_manager.GetFee(native, toChainID)
+	if err != nil {
+		return fmt.Errorf("RecordDeliveredProof, side_chain_manager.GetFee error: %v", err)
+	}
+	requestFee := &RequestFee{
+		FromChainID: fromChainID,
+		ToChainID:   toChainID,
+		TxHash:      txHash.ToArray(),
+		FeeView:     fee.View,
+		Fee:         fee.Fee,
+	}
+	relayer, err := common.AddressParseFromBytes(relayerAddress)
+	if err == nil && utils.ValidateOwner(native, relayer) == nil {
+		requestFee.Relayer = relayer
+		reward, err := GetRelayerReward(native, relayer, toChainID)
+		if err != nil {
+			return fmt.Errorf("RecordDeliveredProof, %v", err)
+		}
+		reward.ProofsDelivered++
+		reward.Earned = new(big.Int).Add(reward.Earned, fee.Fee)
+		putRelayerReward(native, reward)
+	}
+	putRequestFee(native, requestFee)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.RelayerManagerContractAddress,
+			States:          []interface{}{"requestFee", fromChainID, toChainID, txHash.ToHexString(), requestFee.Relayer.ToBase58(), fee.Fee.String()},
+		})
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeLedger(t *testing.T) {
+	native.Contracts[utils.RelayerManagerContractAddress] = RegisterRelayerManagerContract
+	networkId := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	defer func() {
+		config.DefConfig.P2PNode.NetworkId = networkId
+	}()
+
+	relayer := conAccts()[0].Address
+	tx := &types.Transaction{
+		SignedAddr: []common.Address{relayer},
+	}
+	ns := NewNative(nil, tx, nil)
+	// not accounted before fee ledger height
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_MAIN_NET
+	assert.Nil(t, RecordSyncedHeaders(ns, relayer, 1, 5))
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	side_chain_manager.PutFee(ns, 2, &side_chain_manager.Fee{View: 3, Fee: big.NewInt(100)})
+
+	err := RecordSyncedHeaders(ns, relayer, 1, 5)
+	assert.Nil(t, err)
+	// headers synced by others are not attributed to relayer
+	err = RecordSyncedHeaders(ns, acct.Address, 1, 5)
+	assert.Nil(t, err)
+	txHash := tx.Hash()
+	err = RecordDeliveredProof(ns, relayer[:], 1, 2, txHash)
+	assert.Nil(t, err)
+	// items of a batch are keyed by their own hash
+	itemHash := common.Uint256{1}
+	err = RecordDeliveredProof(ns, relayer[:], 1, 2, itemHash)
+	assert.Nil(t, err)
+
+	reward, err := GetRelayerReward(ns, relayer, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), reward.HeadersSynced)
+	reward, err = GetRelayerReward(ns, acct.Address, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), reward.HeadersSynced)
+
+	reward, err = GetRelayerReward(ns, relayer, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), reward.ProofsDelivered)
+	assert.Equal(t, big.NewInt(200), reward.Claimable())
+
+	requestFee, err := GetRequestFee(ns, 2, txHash.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, relayer, requestFee.Relayer)
+	assert.Equal(t, uint64(3), requestFee.FeeView)
+	assert.Equal(t, big.NewInt(100), requestFee.Fee)
+	requestFee, err = GetRequestFee(ns, 2, itemHash.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, relayer, requestFee.Relayer)
+
+	params := &ClaimRelayerRewardParam{Address: relayer, ChainID: 2}
+	sink := common.NewZeroCopySink(nil)
+	params.Serialization(sink)
+	// only the relayer claims its reward
+	ns = NewNative(nil, &types.Transaction{SignedAddr: []common.Address{acct.Address}}, ns.GetCacheDB())
+	_, err = ns.NativeCall(utils.RelayerManagerContractAddress, CLAIM_RELAYER_REWARD, sink.Bytes())
+	assert.NotNil(t, err)
+	reward, err = GetRelayerReward(ns, relayer, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(200), reward.Claimable())
+
+	ns = NewNative(nil, tx, ns.GetCacheDB())
+	res, err := ns.NativeCall(utils.RelayerManagerContractAddress, CLAIM_RELAYER_REWARD, sink.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1}, res)
+	reward, err = GetRelayerReward(ns, relayer, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, reward.Claimable().Sign())
+	assert.Equal(t, big.NewInt(200), reward.Claimed)
+	notify := ns.GetNotify()
+	assert.Equal(t, 1, len(notify))
+	assert.Equal(t, []interface{}{"claimRelayerReward", relayer.ToBase58(), uint64(2), "200"}, notify[0].States)
+
+	// claimed stays across newly earned fees, nothing left to claim before
+	_, err = ns.NativeCall(utils.RelayerManagerContractAddress, CLAIM_RELAYER_REWARD, sink.Bytes())
+	assert.NotNil(t, err)
+	err = RecordDeliveredProof(ns, relayer[:], 1, 2, common.Uint256{2})
+	assert.Nil(t, err)
+	reward, err = GetRelayerReward(ns, relayer, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), reward.Claimable())
+	assert.Equal(t, big.NewInt(200), reward.Claimed)
+}
@@ -94,3 +94,31 @@ func (this *ApproveRelayerParam) Deserialization(source *common.ZeroCopySource)
 	this.Address = addr
 	return nil
 }
+
+type ClaimRelayerRewardParam struct {
+	Address common.Address
+	ChainID uint64
+}
+
+func (this *ClaimRelayerRewardParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+	sink.WriteUint64(this.ChainID)
+}
+
+func (this *ClaimRelayerRewardParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize chainID error")
+	}
+	this.Address = addr
+	this.ChainID = chainID
+	return nil
+}
@@ -20,6 +20,7 @@ package relayer_manager
 import (
 	"fmt"
 	"github.com/polynetwork/poly/native/event"
+	"math/big"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
@@ -33,6 +34,7 @@ const (
 	APPROVE_REGISTER_RELAYER = "approveRegisterRelayer"
 	REMOVE_RELAYER           = "RemoveRelayer"
 	APPROVE_REMOVE_RELAYER   = "approveRemoveRelayer"
+	CLAIM_RELAYER_REWARD     = "claimRelayerReward"
 
 	//key prefix
 	RELAYER        = "relayer"
@@ -40,6 +42,8 @@ const (
 	RELAYER_REMOVE = "relayerRemove"
 	APPLY_ID       = "applyID"
 	REMOVE_ID      = "removeID"
+	RELAYER_REWARD = "relayerReward"
+	REQUEST_FEE    = "requestFee"
 )
 
 //Register methods of node_manager contract
@@ -48,6 +52,7 @@ func RegisterRelayerManagerContract(native *native.NativeService) {
 	native.Register(APPROVE_REGISTER_RELAYER, ApproveRegisterRelayer)
 	native.Register(REMOVE_RELAYER, RemoveRelayer)
 	native.Register(APPROVE_REMOVE_RELAYER, ApproveRemoveRelayer)
+	native.Register(CLAIM_RELAYER_REWARD, ClaimRelayerReward)
 }
 
 func RegisterRelayer(native *native.NativeService) ([]byte, error) {
@@ -158,3 +163,31 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+func ClaimRelayerReward(native *native.NativeService) ([]byte, error) {
+	params := new(ClaimRelayerRewardParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ClaimRelayerReward, contract params deserialize error: %v", err)
+	}
+	//check witness
+	if err := utils.ValidateOwner(native, params.Address); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ClaimRelayerReward, checkWitness: %s, error: %v", params.Address.ToBase58(), err)
+	}
+
+	reward, err := GetRelayerReward(native, params.Address, params.ChainID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("ClaimRelayerReward, GetRelayerReward error: %v", err)
+	}
+	amount := reward.Claimable()
+	if amount.Sign() <= 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("ClaimRelayerReward, no claimable reward of chain %d", params.ChainID)
+	}
+	reward.Claimed = new(big.Int).Set(reward.Earned)
+	putRelayerReward(native, reward)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.RelayerManagerContractAddress,
+			States:          []interface{}{"claimRelayerReward", params.Address.ToBase58(), params.ChainID, amount.String()},
+		})
+	return utils.BYTE_TRUE, nil
+}
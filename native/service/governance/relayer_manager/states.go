@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relayer_manager
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+)
+
+// RelayerReward is the work of a relayer for a chain and the fee credited for it
+type RelayerReward struct {
+	Address         common.Address
+	ChainID         uint64
+	HeadersSynced   uint64
+	ProofsDelivered uint64
+	Earned          *big.Int //fee quoted by requests of proofs delivered
+	Claimed         *big.Int
+}
+
+func (this *RelayerReward) Claimable() *big.Int {
+	return new(big.Int).Sub(this.Earned, this.Claimed)
+}
+
+func (this *RelayerReward) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Address[:])
+	sink.WriteUint64(this.ChainID)
+	sink.WriteUint64(this.HeadersSynced)
+	sink.WriteUint64(this.ProofsDelivered)
+	sink.WriteVarBytes(this.Earned.Bytes())
+	sink.WriteVarBytes(this.Claimed.Bytes())
+}
+
+func (this *RelayerReward) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerReward deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("RelayerReward deserialize address error: %s", err)
+	}
+	chainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerReward deserialize chainID error")
+	}
+	headersSynced, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerReward deserialize headersSynced error")
+	}
+	proofsDelivered, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RelayerReward deserialize proofsDelivered error")
+	}
+	earned, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerReward deserialize earned error")
+	}
+	claimed, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RelayerReward deserialize claimed error")
+	}
+	this.Address = addr
+	this.ChainID = chainID
+	this.HeadersSynced = headersSynced
+	this.ProofsDelivered = proofsDelivered
+	this.Earned = new(big.Int).SetBytes(earned)
+	this.Claimed = new(big.Int).SetBytes(claimed)
+	return nil
+}
+
+// RequestFee is the fee quoted for the target chain when a cross chain request is made
+type RequestFee struct {
+	FromChainID uint64
+	ToChainID   uint64
+	TxHash      []byte //poly tx hash of the request
+	Relayer     common.Address
+	FeeView     uint64
+	Fee         *big.Int
+}
+
+func (this *RequestFee) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.FromChainID)
+	sink.WriteUint64(this.ToChainID)
+	sink.WriteVarBytes(this.TxHash)
+	sink.WriteVarBytes(this.Relayer[:])
+	sink.WriteUint64(this.FeeView)
+	sink.WriteVarBytes(this.Fee.Bytes())
+}
+
+func (this *RequestFee) Deserialization(source *common.ZeroCopySource) error {
+	fromChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RequestFee deserialize fromChainID error")
+	}
+	toChainID, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RequestFee deserialize toChainID error")
+	}
+	txHash, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RequestFee deserialize txHash error")
+	}
+	relayer, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RequestFee deserialize relayer error")
+	}
+	addr, err := common.AddressParseFromBytes(relayer)
+	if err != nil {
+		return fmt.Errorf("RequestFee deserialize relayer error: %s", err)
+	}
+	feeView, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("RequestFee deserialize feeView error")
+	}
+	fee, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("RequestFee deserialize fee error")
+	}
+	this.FromChainID = fromChainID
+	this.ToChainID = toChainID
+	this.TxHash = txHash
+	this.Relayer = addr
+	this.FeeView = feeView
+	this.Fee = new(big.Int).SetBytes(fee)
+	return nil
+}
@@ -131,6 +131,10 @@ type RegisterRedeemParam struct {
 	CVersion        uint64
 	ContractAddress []byte
 	Signs           [][]byte
+	// PrevRedeem is optional, the vault of the contract which is migrated to Redeem
+	PrevRedeem []byte
+	// PrevSigns are the signatures of the keys of PrevRedeem on the same message as Signs
+	PrevSigns [][]byte
 }
 
 func (this *RegisterRedeemParam) Serialization(sink *common.ZeroCopySink) {
@@ -143,6 +147,13 @@ func (this *RegisterRedeemParam) Serialization(sink *common.ZeroCopySink) {
 	for _, v := range this.Signs {
 		sink.WriteVarBytes(v)
 	}
+	if len(this.PrevRedeem) > 0 {
+		sink.WriteVarBytes(this.PrevRedeem)
+		sink.WriteVarUint(uint64(len(this.PrevSigns)))
+		for _, v := range this.PrevSigns {
+			sink.WriteVarBytes(v)
+		}
+	}
 }
 
 func (this *RegisterRedeemParam) Deserialization(source *common.ZeroCopySource) error {
@@ -178,6 +189,25 @@ func (this *RegisterRedeemParam) Deserialization(source *common.ZeroCopySource)
 		}
 		signs = append(signs, v)
 	}
+	var prevRedeem []byte
+	prevSigns := make([][]byte, 0)
+	if source.Len() > 0 {
+		prevRedeem, eof = source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("RegisterRedeemParam deserialize prevRedeem error")
+		}
+		n, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("RegisterRedeemParam deserialize prevSigns length error")
+		}
+		for i := 0; uint64(i) < n; i++ {
+			v, eof := source.NextVarBytes()
+			if eof {
+				return fmt.Errorf("deserialize PrevSigns error")
+			}
+			prevSigns = append(prevSigns, v)
+		}
+	}
 
 	this.RedeemChainID = redeemChainID
 	this.ContractChainID = contractChainID
@@ -185,6 +215,8 @@ func (this *RegisterRedeemParam) Deserialization(source *common.ZeroCopySource)
 	this.CVersion = cver
 	this.ContractAddress = contractAddress
 	this.Signs = signs
+	this.PrevRedeem = prevRedeem
+	this.PrevSigns = prevSigns
 	return nil
 }
 
@@ -468,3 +500,35 @@ func (this *UpdateFeeParam) Deserialization(source *common.ZeroCopySource) error
 	this.Fee = new(big.Int).SetBytes(fee)
 	return nil
 }
+
+type UpdateMethodPolicyParam struct {
+	Address common.Address
+	ChainId uint64
+	Policy  *MethodPolicy
+}
+
+func (this *UpdateMethodPolicyParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteAddress(this.Address)
+	sink.WriteUint64(this.ChainId)
+	this.Policy.Serialization(sink)
+}
+
+func (this *UpdateMethodPolicyParam) Deserialization(source *common.ZeroCopySource) error {
+	address, eof := source.NextAddress()
+	if eof {
+		return fmt.Errorf("UpdateMethodPolicyParam deserialize address error")
+	}
+	chainId, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("UpdateMethodPolicyParam deserialize chain id error")
+	}
+	policy := new(MethodPolicy)
+	if err := policy.Deserialization(source); err != nil {
+		return fmt.Errorf("UpdateMethodPolicyParam deserialize policy error: %v", err)
+	}
+
+	this.Address = address
+	this.ChainId = chainId
+	this.Policy = policy
+	return nil
+}
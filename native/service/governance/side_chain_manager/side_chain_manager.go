@@ -18,6 +18,7 @@
 package side_chain_manager
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -25,7 +26,6 @@ import (
 
 	"github.com/polynetwork/poly/native/service/cross_chain_manager/consensus_vote"
 
-	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcutil"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
@@ -46,6 +46,7 @@ const (
 	REGISTER_ASSET              = "registerAsset"
 	UPDATE_FEE                  = "updateFee"
 	SET_BTC_TX_PARAM            = "setBtcTxParam"
+	UPDATE_METHOD_POLICY        = "updateMethodPolicy"
 
 	//key prefix
 	SIDE_CHAIN_APPLY          = "sideChainApply"
@@ -59,6 +60,9 @@ const (
 	ASSET_BIND                = "assetBind"
 	FEE                       = "fee"
 	FEE_INFO                  = "feeInfo"
+	METHOD_POLICY             = "methodPolicy"
+	TAPROOT_REDEEM_KEY        = "taprootRedeemKey"
+	VAULT_MIGRATION           = "vaultMigration"
 
 	UPDATE_FEE_TIMEOUT = 300
 )
@@ -73,6 +77,7 @@ func RegisterSideChainManagerContract(native *native.NativeService) {
 	native.Register(APPROVE_QUIT_SIDE_CHAIN, ApproveQuitSideChain)
 	native.Register(REGISTER_ASSET, RegisterAsset)
 	native.Register(UPDATE_FEE, UpdateFee)
+	native.Register(UPDATE_METHOD_POLICY, UpdateMethodPolicy)
 
 	native.Register(REGISTER_REDEEM, RegisterRedeem)
 	native.Register(SET_BTC_TX_PARAM, SetBtcTxParam)
@@ -333,12 +338,9 @@ func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, contract params deserialize error: %v", err)
 	}
-	ty, addrs, m, err := txscript.ExtractPkScriptAddrs(params.Redeem, netParam)
+	_, _, m, err := parseRedeem(params.Redeem)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to extract addrs: %v", err)
-	}
-	if ty != txscript.MultiSigTy {
-		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, wrong type of redeem: %s", ty.String())
+		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, %v", err)
 	}
 	rk := btcutil.Hash160(params.Redeem)
 	contract, err := GetContractBind(native, params.RedeemChainID, params.ContractChainID, rk)
@@ -349,12 +351,38 @@ func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, previous version is %d and your version should "+
 			"be %d not %d", contract.Ver, contract.Ver+1, params.CVersion)
 	}
-	verified, err := verifyRedeemRegister(params, addrs)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to verify: %v", err)
+	var prevKey []byte
+	var prevM int
+	if len(params.PrevRedeem) > 0 {
+		if _, _, prevM, err = parseRedeem(params.PrevRedeem); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, previous vault: %v", err)
+		}
+		prevKey = btcutil.Hash160(params.PrevRedeem)
+		if bytes.Equal(prevKey, rk) {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, can't migrate the vault to itself")
+		}
+		prev, err := GetContractBind(native, params.RedeemChainID, params.ContractChainID, prevKey)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to get contract of previous vault: %v", err)
+		}
+		if prev == nil || !bytes.Equal(prev.Contract, params.ContractAddress) {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, previous vault %s is not bound to contract %s",
+				hex.EncodeToString(prevKey), hex.EncodeToString(params.ContractAddress))
+		}
+	}
+	// the keys of the previous vault may sign the migration in their own calls
+	var verified map[string][]byte
+	if len(params.Signs) > 0 || len(params.PrevSigns) == 0 {
+		verified, err = verifyRedeemRegister(params)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to verify: %v", err)
+		}
 	}
 	key := append(append(append(rk, utils.GetUint64Bytes(params.RedeemChainID)...),
 		params.ContractAddress...), utils.GetUint64Bytes(params.ContractChainID)...)
+	if prevKey != nil {
+		key = append(key, prevKey...)
+	}
 	bindSignInfo, err := getBindSignInfo(native, key)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, getBindSignInfo error: %v", err)
@@ -366,7 +394,30 @@ func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to putBindSignInfo: %v", err)
 	}
-	if len(bindSignInfo.BindSignInfo) >= m {
+	// the change of the previous vault goes to the new one only if m of its own keys approve
+	approved := true
+	if prevKey != nil {
+		migrationKey := append(append([]byte{}, key...), []byte(VAULT_MIGRATION)...)
+		migrationSignInfo, err := getBindSignInfo(native, migrationKey)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, getBindSignInfo of migration error: %v", err)
+		}
+		if len(params.PrevSigns) > 0 {
+			prevVerified, err := verifyRedeemMigration(params)
+			if err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to verify signs of previous vault: %v", err)
+			}
+			for k, v := range prevVerified {
+				migrationSignInfo.BindSignInfo[k] = v
+			}
+			err = putBindSignInfo(native, migrationKey, migrationSignInfo)
+			if err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, failed to putBindSignInfo of migration: %v", err)
+			}
+		}
+		approved = len(migrationSignInfo.BindSignInfo) >= prevM
+	}
+	if len(bindSignInfo.BindSignInfo) >= m && approved {
 		err = putContractBind(native, params.RedeemChainID, params.ContractChainID, rk, params.ContractAddress, params.CVersion)
 		if err != nil {
 			return utils.BYTE_FALSE, fmt.Errorf("RegisterRedeem, putContractBind error: %v", err)
@@ -379,6 +430,14 @@ func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 				ContractAddress: utils.SideChainManagerContractAddress,
 				States:          []interface{}{"RegisterRedeem", hex.EncodeToString(rk), hex.EncodeToString(params.ContractAddress)},
 			})
+		if prevKey != nil {
+			putVaultMigration(native, params.RedeemChainID, prevKey, params.Redeem)
+			native.AddNotify(
+				&event.NotifyEventInfo{
+					ContractAddress: utils.SideChainManagerContractAddress,
+					States:          []interface{}{"MigrateRedeem", hex.EncodeToString(prevKey), hex.EncodeToString(rk)},
+				})
+		}
 	}
 
 	return utils.BYTE_TRUE, nil
@@ -395,12 +454,9 @@ func SetBtcTxParam(native *native.NativeService) ([]byte, error) {
 	if params.Detial.MinChange < 2000 {
 		return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, min-change can't less than 2000")
 	}
-	cls, addrs, m, err := txscript.ExtractPkScriptAddrs(params.Redeem, netParam)
+	_, _, m, err := parseRedeem(params.Redeem)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, extract addrs from redeem %v", err)
-	}
-	if cls != txscript.MultiSigTy {
-		return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, redeem script is not multisig script: %s", cls.String())
+		return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, %v", err)
 	}
 	rk := btcutil.Hash160(params.Redeem)
 	prev, err := GetBtcTxParam(native, rk, params.RedeemChainId)
@@ -421,7 +477,7 @@ func SetBtcTxParam(native *native.NativeService) ([]byte, error) {
 	if len(info.BindSignInfo) >= m {
 		return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, the signatures are already enough")
 	}
-	verified, err := verifyBtcTxParam(params, addrs)
+	verified, err := verifyBtcTxParam(params)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("SetBtcTxParam, failed to verify: %v", err)
 	}
@@ -551,3 +607,37 @@ func UpdateFee(native *native.NativeService) ([]byte, error) {
 	PutFee(native, params.ChainId, fee)
 	return utils.BYTE_TRUE, nil
 }
+
+func UpdateMethodPolicy(native *native.NativeService) ([]byte, error) {
+	params := new(UpdateMethodPolicyParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("UpdateMethodPolicy, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("UpdateMethodPolicy, checkWitness error: %v", err)
+	}
+
+	//check consensus signs
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint64(params.ChainId)
+	params.Policy.Serialization(sink)
+	ok, err := node_manager.CheckConsensusSigns(native, UPDATE_METHOD_POLICY, sink.Bytes(), params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("UpdateMethodPolicy, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	putMethodPolicy(native, params.ChainId, params.Policy)
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.SideChainManagerContractAddress,
+			States: []interface{}{"UpdateMethodPolicy", params.ChainId, len(params.Policy.AllowedCalls),
+				len(params.Policy.DeniedMethods)},
+		})
+	return utils.BYTE_TRUE, nil
+}
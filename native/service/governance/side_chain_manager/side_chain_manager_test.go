@@ -18,7 +18,12 @@
 package side_chain_manager
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
@@ -27,7 +32,10 @@ import (
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/service/utils/taproot"
 	"github.com/polynetwork/poly/native/storage"
 	"github.com/stretchr/testify/assert"
 	"strings"
@@ -207,6 +215,101 @@ func TestRegisterRedeem(t *testing.T) {
 	assert.Equal(t, utils.BYTE_FALSE, ok)
 }
 
+func TestRegisterTaprootRedeem(t *testing.T) {
+	ca, _ := hex.DecodeString("9a20bEd97360d28AE93c21750e9492ea8f85989f")
+	prevPrivs := make([]*btcec.PrivateKey, 3)
+	prevAddrs := make([]*btcutil.AddressPubKey, 3)
+	for i := range prevPrivs {
+		prevPrivs[i], _ = btcec.NewPrivateKey(btcec.S256())
+		prevAddrs[i], _ = btcutil.NewAddressPubKey(prevPrivs[i].PubKey().SerializeCompressed(), netParam)
+	}
+	prev, _ := txscript.MultiSigScript(prevAddrs, 2)
+	ns := getNativeFunc(nil)
+	putContractBind(ns, 1, 2, btcutil.Hash160(prev), ca, 0)
+
+	privs := make([]*btcec.PrivateKey, 3)
+	keys := make([][]byte, 3)
+	for i := range privs {
+		privs[i], _ = btcec.NewPrivateKey(btcec.S256())
+		keys[i] = taproot.XOnlyPubKey(privs[i].PubKey())
+	}
+	redeem, _ := taproot.MultisigScript(keys, 2)
+	param := &RegisterRedeemParam{
+		RedeemChainID:   1,
+		ContractChainID: 2,
+		Redeem:          redeem,
+		ContractAddress: ca,
+		PrevRedeem:      prev,
+	}
+	msg := append(append(append(append(append(append([]byte{}, redeem...), utils.GetUint64Bytes(1)...), ca...),
+		utils.GetUint64Bytes(2)...), utils.GetUint64Bytes(0)...), prev...)
+	hash := sha256.Sum256(msg)
+	register := func(signs, prevSigns [][]byte) ([]byte, error) {
+		param.Signs = signs
+		param.PrevSigns = prevSigns
+		sink := common.NewZeroCopySink(nil)
+		param.Serialization(sink)
+		ns = NewNative(sink.Bytes(), new(types.Transaction), ns.GetCacheDB())
+		return RegisterRedeem(ns)
+	}
+	sign := func(priv *btcec.PrivateKey) [][]byte {
+		sig, _ := taproot.SignSchnorr(priv, hash[:], make([]byte, 32))
+		return [][]byte{sig}
+	}
+	prevSign := func(priv *btcec.PrivateKey) [][]byte {
+		sig, _ := priv.Sign(btcutil.Hash160(msg))
+		return [][]byte{sig.Serialize()}
+	}
+
+	ok, err := register(sign(privs[0]), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, ok)
+	ok, err = register(sign(privs[2]), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, ok)
+	// signed only by the keys of the new vault, the migration is not approved
+	migrated, err := GetVaultMigration(ns, 1, btcutil.Hash160(prev))
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+	bind, err := GetContractBind(ns, 1, 2, btcutil.Hash160(redeem))
+	assert.NoError(t, err)
+	assert.Nil(t, bind)
+	assert.Equal(t, 0, len(ns.GetNotify()))
+
+	// the keys of the new vault don't sign for the previous one
+	_, err = register(nil, sign(privs[1]))
+	assert.Error(t, err)
+	ok, err = register(nil, prevSign(prevPrivs[0]))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, ok)
+	migrated, err = GetVaultMigration(ns, 1, btcutil.Hash160(prev))
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+
+	ok, err = register(nil, prevSign(prevPrivs[2]))
+	assert.NoError(t, err)
+	assert.Equal(t, utils.BYTE_TRUE, ok)
+	rk := hex.EncodeToString(btcutil.Hash160(redeem))
+	states := ns.GetNotify()[1].States.([]interface{})
+	assert.Equal(t, "MigrateRedeem", states[0])
+	assert.Equal(t, rk, states[2])
+
+	migrated, err = GetVaultMigration(ns, 1, btcutil.Hash160(prev))
+	assert.NoError(t, err)
+	assert.Equal(t, redeem, migrated)
+	outputKey, _ := taproot.OutputKey(redeem)
+	taprootKey, err := GetTaprootRedeemKey(ns, 1, outputKey)
+	assert.NoError(t, err)
+	assert.Equal(t, rk, taprootKey)
+	saved, err := GetBtcRedeemScriptBytes(ns, rk, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, redeem, saved)
+
+	// the signature of a legacy key is not accepted for the taproot vault
+	_, err = register([][]byte{make([]byte, 71)}, nil)
+	assert.Error(t, err)
+}
+
 func TestSetBtcTxParam(t *testing.T) {
 	redeem, _ := hex.DecodeString("552102dec9a415b6384ec0a9331d0cdf02020f0f1e5731c327b86e2b5a92455a289748210365b1066bcfa21987c3e207b92e309b95ca6bee5f1133cf04d6ed4ed265eafdbc21031104e387cd1a103c27fdc8a52d5c68dec25ddfb2f574fbdca405edfd8c5187de21031fdb4b44a9f20883aff505009ebc18702774c105cb04b1eecebcb294d404b1cb210387cda955196cc2b2fc0adbbbac1776f8de77b563c6d2a06a77d96457dc3d0d1f2102dd7767b6a7cc83693343ba721e0f5f4c7b4b8d85eeb7aec20d227625ec0f59d321034ad129efdab75061e8d4def08f5911495af2dae6d3e9a4b6e7aeb5186fa432fc57ae")
 	sigStr := strings.Split("3045022100dbd452e851efbe8ae56c9a7da38d8ba59bf9fa5baefd439383271dba8998d4a00220227313c17e1438c5f679f10d520a5b7a1e56cbf6f0e6a824537a963ffb4d27f8,3045022100f22368985fbb00e3649b6d36e85b849c91dd57d3fc762fd63bbb7cdd478e3aeb022029aee48ed40473dcb9d1c634fe93b182b3ea6df400f09c00040339dabe67fd30,30450221009d3f736b27c991f78b84856c70e30813448d33284d04bb4dd0560b3bd250a15a02201e722d0087d537b2609e9140b398ce992b89ed583f2beb793ac78ded9bd0a207,3044022018f6cc301029843332794745b020dcb3fc768198c04026eb2efcbf0ba7febc0d02203c463b1b96eb6b7dcfff61bb6ccb87bffd6388d43ed464a9537e09b9c58c7e88,30440220301e5e1c37e699d8a0f999796b481a0611c11da7ea16389a760dccf5a8b659e8022072b4e0d48ef80db976bbd8f249a9b2aa0fb7fc995363a3ee9c629401cffe05b7", ",")
@@ -242,3 +345,42 @@ func TestSetBtcTxParam(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, utils.BYTE_FALSE, ok)
 }
+
+func TestMethodRejectedNotify(t *testing.T) {
+	ns := NewNative(nil, new(types.Transaction), nil)
+	putMethodPolicy(ns, 2, &MethodPolicy{DeniedMethods: []string{"changeBookKeeper"}})
+
+	key := []byte("doneTx")
+	rejected, err := scom.RevertOnError(ns, func() error {
+		ns.GetCacheDB().Put(key, key)
+		return fmt.Errorf("MakeTransaction, %w", CheckMethodPolicy(ns, 2, []byte{3}, "changeBookKeeper"))
+	})
+	assert.True(t, rejected)
+	assert.NotNil(t, err)
+	value, err := ns.GetCacheDB().Get(key)
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+	// the rejection is notified after the revert
+	notify := ns.GetNotify()
+	assert.Equal(t, 1, len(notify))
+	assert.Equal(t, []interface{}{scom.NOTIFY_METHOD_REJECTED, uint64(2), "03", "changeBookKeeper"}, notify[0].States)
+
+	// other failures are reverted with their notifies
+	rejected, err = scom.RevertOnError(ns, func() error {
+		ns.AddNotify(&event.NotifyEventInfo{})
+		return fmt.Errorf("verify proof error")
+	})
+	assert.False(t, rejected)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(ns.GetNotify()))
+
+	rejected, err = scom.RevertOnError(ns, func() error {
+		ns.GetCacheDB().Put(key, key)
+		return CheckMethodPolicy(ns, 2, []byte{3}, "unlock")
+	})
+	assert.False(t, rejected)
+	assert.Nil(t, err)
+	value, err = ns.GetCacheDB().Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, key, value)
+}
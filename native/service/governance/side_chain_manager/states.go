@@ -18,6 +18,7 @@
 package side_chain_manager
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"sort"
@@ -289,3 +290,80 @@ func (this *RippleExtraInfo) Deserialization(source *common.ZeroCopySource) erro
 	this.ReserveAmount = new(big.Int).SetBytes(reserveAmount)
 	return nil
 }
+
+type MethodCall struct {
+	ToContractAddress []byte
+	Method            string
+}
+
+// MethodPolicy filters the calls forwarded to a target chain. Denied methods are rejected for
+// every contract, and once AllowedCalls is not empty only the listed calls are forwarded.
+type MethodPolicy struct {
+	AllowedCalls  []*MethodCall
+	DeniedMethods []string
+}
+
+func (this *MethodPolicy) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.AllowedCalls)))
+	for _, v := range this.AllowedCalls {
+		sink.WriteVarBytes(v.ToContractAddress)
+		sink.WriteString(v.Method)
+	}
+	sink.WriteVarUint(uint64(len(this.DeniedMethods)))
+	for _, v := range this.DeniedMethods {
+		sink.WriteString(v)
+	}
+}
+
+func (this *MethodPolicy) Deserialization(source *common.ZeroCopySource) error {
+	l, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("MethodPolicy deserialize length of allowed calls error")
+	}
+	allowedCalls := make([]*MethodCall, 0, l)
+	for i := uint64(0); i < l; i++ {
+		toContractAddress, eof := source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("MethodPolicy deserialize no.%d toContractAddress error", i+1)
+		}
+		method, eof := source.NextString()
+		if eof {
+			return fmt.Errorf("MethodPolicy deserialize no.%d method error", i+1)
+		}
+		allowedCalls = append(allowedCalls, &MethodCall{ToContractAddress: toContractAddress, Method: method})
+	}
+	l, eof = source.NextVarUint()
+	if eof {
+		return fmt.Errorf("MethodPolicy deserialize length of denied methods error")
+	}
+	deniedMethods := make([]string, 0, l)
+	for i := uint64(0); i < l; i++ {
+		method, eof := source.NextString()
+		if eof {
+			return fmt.Errorf("MethodPolicy deserialize no.%d denied method error", i+1)
+		}
+		deniedMethods = append(deniedMethods, method)
+	}
+
+	this.AllowedCalls = allowedCalls
+	this.DeniedMethods = deniedMethods
+	return nil
+}
+
+// Check returns an error if calling method on toContractAddress violates the policy
+func (this *MethodPolicy) Check(toContractAddress []byte, method string) error {
+	for _, v := range this.DeniedMethods {
+		if v == method {
+			return fmt.Errorf("method %s is denied", method)
+		}
+	}
+	if len(this.AllowedCalls) == 0 {
+		return nil
+	}
+	for _, v := range this.AllowedCalls {
+		if v.Method == method && bytes.Equal(v.ToContractAddress, toContractAddress) {
+			return nil
+		}
+	}
+	return fmt.Errorf("method %s of contract %x is not allowed", method, toContractAddress)
+}
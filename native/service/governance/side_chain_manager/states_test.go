@@ -38,3 +38,28 @@ func TestSideChain_Serialization(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, paramDeserialize, paramSerialize)
 }
+
+func TestMethodPolicy(t *testing.T) {
+	policy := &MethodPolicy{
+		AllowedCalls: []*MethodCall{
+			{ToContractAddress: []byte("proxy"), Method: "unlock"},
+		},
+		DeniedMethods: []string{"putCurEpochConPubKeyBytes"},
+	}
+	sink := common.NewZeroCopySink(nil)
+	policy.Serialization(sink)
+	decoded := new(MethodPolicy)
+	err := decoded.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, policy, decoded)
+
+	assert.Nil(t, policy.Check([]byte("proxy"), "unlock"))
+	assert.NotNil(t, policy.Check([]byte("other"), "unlock"))
+	assert.NotNil(t, policy.Check([]byte("proxy"), "putCurEpochConPubKeyBytes"))
+
+	// denied methods only
+	policy.AllowedCalls = nil
+	assert.Nil(t, policy.Check([]byte("other"), "unlock"))
+	assert.NotNil(t, policy.Check([]byte("other"), "putCurEpochConPubKeyBytes"))
+	assert.Nil(t, new(MethodPolicy).Check([]byte("other"), "anything"))
+}
@@ -18,6 +18,8 @@
 package side_chain_manager
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 
@@ -28,7 +30,9 @@ import (
 	"github.com/polynetwork/poly/common"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
 	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/service/utils/taproot"
 )
 
 var netParam = &chaincfg.TestNet3Params
@@ -280,26 +284,65 @@ func GetBtcTxParam(native *native.NativeService, redeemKey []byte, redeemChainId
 	return nil, nil
 }
 
-func verifyRedeemRegister(param *RegisterRedeemParam, addrs []btcutil.Address) (map[string][]byte, error) {
+// parseRedeem returns the signers and the number of required signatures of a btc vault, which is
+// either a multisig redeem script or a taproot multisig leaf script
+func parseRedeem(redeem []byte) ([]btcutil.Address, [][]byte, int, error) {
+	if keys, m, err := taproot.ParseMultisigScript(redeem); err == nil {
+		return nil, keys, m, nil
+	}
+	ty, addrs, m, err := txscript.ExtractPkScriptAddrs(redeem, netParam)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to extract addrs: %v", err)
+	}
+	if ty != txscript.MultiSigTy {
+		return nil, nil, 0, fmt.Errorf("wrong type of redeem: %s", ty.String())
+	}
+	return addrs, nil, m, nil
+}
+
+// verifyRedeemSigs verifies DER signatures on Hash160(msg) for a multisig redeem and schnorr
+// signatures on sha256(msg) for a taproot vault
+func verifyRedeemSigs(redeem, msg []byte, sigs [][]byte) (map[string][]byte, error) {
+	addrs, keys, _, err := parseRedeem(redeem)
+	if err != nil {
+		return nil, err
+	}
+	if keys != nil {
+		hash := sha256.Sum256(msg)
+		return verifySchnorr(sigs, keys, hash[:])
+	}
+	return verify(sigs, addrs, btcutil.Hash160(msg))
+}
+
+func redeemRegisterMsg(param *RegisterRedeemParam) []byte {
 	r := make([]byte, len(param.Redeem))
 	copy(r, param.Redeem)
 	cverBytes := utils.GetUint64Bytes(param.CVersion)
 	fromChainId := utils.GetUint64Bytes(param.RedeemChainID)
 	toChainId := utils.GetUint64Bytes(param.ContractChainID)
-	hash := btcutil.Hash160(append(append(append(append(r, fromChainId...), param.ContractAddress...),
-		toChainId...), cverBytes...))
-	return verify(param.Signs, addrs, hash)
+	return append(append(append(append(append(r, fromChainId...), param.ContractAddress...),
+		toChainId...), cverBytes...), param.PrevRedeem...)
 }
 
-func verifyBtcTxParam(param *BtcTxParam, addrs []btcutil.Address) (map[string][]byte, error) {
+func verifyRedeemRegister(param *RegisterRedeemParam) (map[string][]byte, error) {
+	return verifyRedeemSigs(param.Redeem, redeemRegisterMsg(param), param.Signs)
+}
+
+// verifyRedeemMigration verifies the signatures of the keys of the previous vault approving its
+// migration to the new redeem
+func verifyRedeemMigration(param *RegisterRedeemParam) (map[string][]byte, error) {
+	return verifyRedeemSigs(param.PrevRedeem, redeemRegisterMsg(param), param.PrevSigns)
+}
+
+func verifyBtcTxParam(param *BtcTxParam) (map[string][]byte, error) {
 	r := make([]byte, len(param.Redeem))
 	copy(r, param.Redeem)
 	fromChainId := utils.GetUint64Bytes(param.RedeemChainId)
 	frBytes := utils.GetUint64Bytes(param.Detial.FeeRate)
 	mcBytes := utils.GetUint64Bytes(param.Detial.MinChange)
 	verBytes := utils.GetUint64Bytes(param.Detial.PVersion)
-	hash := btcutil.Hash160(append(append(append(append(r, fromChainId...), frBytes...), mcBytes...), verBytes...))
-	return verify(param.Sigs, addrs, hash)
+	msg := append(append(append(append(r, fromChainId...), frBytes...), mcBytes...), verBytes...)
+	return verifyRedeemSigs(param.Redeem, msg, param.Sigs)
 }
 
 func verify(sigs [][]byte, addrs []btcutil.Address, hash []byte) (map[string][]byte, error) {
@@ -324,18 +367,85 @@ func verify(sigs [][]byte, addrs []btcutil.Address, hash []byte) (map[string][]b
 	return res, nil
 }
 
+// verifySchnorr is like verify but the signers of a taproot vault are keyed by hex of x-only public key
+func verifySchnorr(sigs [][]byte, keys [][]byte, hash []byte) (map[string][]byte, error) {
+	res := make(map[string][]byte)
+	for i, sig := range sigs {
+		if len(sig) != 64 {
+			return nil, fmt.Errorf("length of no.%d schnorr sig is %d not 64", i, len(sig))
+		}
+		for _, key := range keys {
+			if taproot.VerifySchnorr(key, hash, sig) {
+				res[hex.EncodeToString(key)] = sig
+			}
+		}
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no sigs is verified")
+	}
+	return res, nil
+}
+
 func putBtcRedeemScript(native *native.NativeService, redeemScriptKey string, redeemScriptBytes []byte, redeemChainId uint64) error {
 	chainIDBytes := utils.GetUint64Bytes(redeemChainId)
 	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(REDEEM_SCRIPT), chainIDBytes, []byte(redeemScriptKey))
 
-	cls := txscript.GetScriptClass(redeemScriptBytes)
-	if cls.String() != "multisig" {
+	if taproot.IsMultisigScript(redeemScriptBytes) {
+		// P2TR outputs commit to the output key only, so keep the way back to the redeem key
+		outputKey, _ := taproot.OutputKey(redeemScriptBytes)
+		native.GetCacheDB().Put(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(TAPROOT_REDEEM_KEY),
+			chainIDBytes, outputKey), cstates.GenRawStorageItem([]byte(redeemScriptKey)))
+	} else if cls := txscript.GetScriptClass(redeemScriptBytes); cls.String() != "multisig" {
 		return fmt.Errorf("putBtcRedeemScript, wrong type of redeem: %s", cls)
 	}
 	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(redeemScriptBytes))
 	return nil
 }
 
+// GetTaprootRedeemKey returns the hex redeem key of the taproot vault with x-only output key, or
+// empty string if no such vault registered
+func GetTaprootRedeemKey(native *native.NativeService, redeemChainId uint64, outputKey []byte) (string, error) {
+	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(TAPROOT_REDEEM_KEY),
+		utils.GetUint64Bytes(redeemChainId), outputKey)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return "", fmt.Errorf("GetTaprootRedeemKey, get redeem key error: %v", err)
+	}
+	if store == nil {
+		return "", nil
+	}
+	rk, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return "", fmt.Errorf("GetTaprootRedeemKey, deserialize from raw storage item err:%v", err)
+	}
+	return string(rk), nil
+}
+
+func putVaultMigration(native *native.NativeService, redeemChainId uint64, prevRedeemKey, redeem []byte) {
+	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(VAULT_MIGRATION),
+		utils.GetUint64Bytes(redeemChainId), prevRedeemKey)
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(redeem))
+}
+
+// GetVaultMigration returns the redeem of the vault which the vault of prevRedeemKey is migrated to,
+// or nil if it is not migrated
+func GetVaultMigration(native *native.NativeService, redeemChainId uint64, prevRedeemKey []byte) ([]byte, error) {
+	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(VAULT_MIGRATION),
+		utils.GetUint64Bytes(redeemChainId), prevRedeemKey)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("GetVaultMigration, get migration error: %v", err)
+	}
+	if store == nil {
+		return nil, nil
+	}
+	redeem, err := cstates.GetValueFromRawStorageItem(store)
+	if err != nil {
+		return nil, fmt.Errorf("GetVaultMigration, deserialize from raw storage item err:%v", err)
+	}
+	return redeem, nil
+}
+
 func GetBtcRedeemScriptBytes(native *native.NativeService, redeemScriptKey string, redeemChainId uint64) ([]byte, error) {
 	chainIDBytes := utils.GetUint64Bytes(redeemChainId)
 	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(REDEEM_SCRIPT), chainIDBytes, []byte(redeemScriptKey))
@@ -480,4 +590,55 @@ func PutRippleExtraInfo(native *native.NativeService, chainId uint64, rippleExtr
 		return fmt.Errorf("PutRippleExtraInfo, PutSideChain error: %v", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func putMethodPolicy(native *native.NativeService, chainId uint64, policy *MethodPolicy) {
+	chainIdBytes := utils.GetUint64Bytes(chainId)
+	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(METHOD_POLICY), chainIdBytes)
+	if len(policy.AllowedCalls) == 0 && len(policy.DeniedMethods) == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	policy.Serialization(sink)
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func GetMethodPolicy(native *native.NativeService, chainID uint64) (*MethodPolicy, error) {
+	chainIDBytes := utils.GetUint64Bytes(chainID)
+	key := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(METHOD_POLICY), chainIDBytes)
+	store, err := native.GetCacheDB().Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("GetMethodPolicy, get method policy store error: %v", err)
+	}
+	policy := new(MethodPolicy)
+	if store != nil {
+		policyBytes, err := cstates.GetValueFromRawStorageItem(store)
+		if err != nil {
+			return nil, fmt.Errorf("GetMethodPolicy, deserialize from raw storage item err:%v", err)
+		}
+		err = policy.Deserialization(common.NewZeroCopySource(policyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("GetMethodPolicy, deserialize method policy err:%v", err)
+		}
+	}
+	return policy, nil
+}
+
+// CheckMethodPolicy rejects a call of method on toContractAddress of chain toChainID which is
+// not permitted by the method policy of that chain with a *scom.MethodRejectedError.
+func CheckMethodPolicy(native *native.NativeService, toChainID uint64, toContractAddress []byte, method string) error {
+	policy, err := GetMethodPolicy(native, toChainID)
+	if err != nil {
+		return fmt.Errorf("CheckMethodPolicy, %v", err)
+	}
+	if err := policy.Check(toContractAddress, method); err != nil {
+		return &scom.MethodRejectedError{
+			ToChainID:         toChainID,
+			ToContractAddress: toContractAddress,
+			Method:            method,
+			Err:               err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bsc
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+const (
+	blsPublicKeyLength = 48
+	blsSignatureLength = 96
+)
+
+// blsDST is the domain separation tag of the proof of possession scheme used by bsc voters
+var blsDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+var (
+	blsModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	// (p+1)/4, square roots in Fp can be taken with a single exponentiation as p = 3 mod 4
+	blsSqrtExp = new(big.Int).Rsh(new(big.Int).Add(blsModulus, big.NewInt(1)), 2)
+	// (p-1)/2, used to check quadratic residues
+	blsLegendreExp = new(big.Int).Rsh(new(big.Int).Sub(blsModulus, big.NewInt(1)), 1)
+)
+
+// fp2Elem is an element c0 + c1*u of Fp2 = Fp[u]/(u^2+1), only used to decompress points
+type fp2Elem struct {
+	c0, c1 *big.Int
+}
+
+func fpMod(a *big.Int) *big.Int {
+	return a.Mod(a, blsModulus)
+}
+
+func (a fp2Elem) add(b fp2Elem) fp2Elem {
+	return fp2Elem{fpMod(new(big.Int).Add(a.c0, b.c0)), fpMod(new(big.Int).Add(a.c1, b.c1))}
+}
+
+func (a fp2Elem) mul(b fp2Elem) fp2Elem {
+	c0 := new(big.Int).Sub(new(big.Int).Mul(a.c0, b.c0), new(big.Int).Mul(a.c1, b.c1))
+	c1 := new(big.Int).Add(new(big.Int).Mul(a.c0, b.c1), new(big.Int).Mul(a.c1, b.c0))
+	return fp2Elem{fpMod(c0), fpMod(c1)}
+}
+
+func (a fp2Elem) equal(b fp2Elem) bool {
+	return a.c0.Cmp(b.c0) == 0 && a.c1.Cmp(b.c1) == 0
+}
+
+// sqrt returns a square root of a if there is one. It works on the norm of a, with
+// sqrt(a) = sqrt((c0 + sqrt(c0^2+c1^2)) / 2) + c1 / (2 * sqrt((c0 + sqrt(c0^2+c1^2)) / 2)) * u
+func (a fp2Elem) sqrt() (fp2Elem, bool) {
+	if a.c1.Sign() == 0 {
+		if r, ok := fpSqrt(a.c0); ok {
+			return fp2Elem{r, new(big.Int)}, true
+		}
+		// c0 is a non residue, so -c0 is a residue and sqrt(c0) = sqrt(-c0) * u
+		r, ok := fpSqrt(fpMod(new(big.Int).Neg(a.c0)))
+		if !ok {
+			return fp2Elem{}, false
+		}
+		return fp2Elem{new(big.Int), r}, true
+	}
+	norm := fpMod(new(big.Int).Add(new(big.Int).Mul(a.c0, a.c0), new(big.Int).Mul(a.c1, a.c1)))
+	alpha, ok := fpSqrt(norm)
+	if !ok {
+		return fp2Elem{}, false
+	}
+	inv2 := new(big.Int).ModInverse(big.NewInt(2), blsModulus)
+	delta := fpMod(new(big.Int).Mul(new(big.Int).Add(a.c0, alpha), inv2))
+	x0, ok := fpSqrt(delta)
+	if !ok {
+		delta = fpMod(new(big.Int).Mul(new(big.Int).Sub(a.c0, alpha), inv2))
+		if x0, ok = fpSqrt(delta); !ok {
+			return fp2Elem{}, false
+		}
+	}
+	if x0.Sign() == 0 {
+		return fp2Elem{}, false
+	}
+	x1 := fpMod(new(big.Int).Mul(a.c1, new(big.Int).ModInverse(new(big.Int).Lsh(x0, 1), blsModulus)))
+	r := fp2Elem{x0, x1}
+	if !r.mul(r).equal(a) {
+		return fp2Elem{}, false
+	}
+	return r, true
+}
+
+func fpSqrt(a *big.Int) (*big.Int, bool) {
+	r := new(big.Int).Exp(a, blsSqrtExp, blsModulus)
+	if new(big.Int).Exp(r, big.NewInt(2), blsModulus).Cmp(fpMod(new(big.Int).Set(a))) != 0 {
+		return nil, false
+	}
+	return r, true
+}
+
+// fpLexLarger reports whether y > p - y, the sign convention of compressed points
+func fpLexLarger(y *big.Int) bool {
+	return y.Cmp(blsLegendreExp) > 0
+}
+
+func fpBytes(a *big.Int) []byte {
+	out := make([]byte, 48)
+	b := a.Bytes()
+	copy(out[48-len(b):], b)
+	return out
+}
+
+// decompressFlags checks the zcash flags of a compressed point and returns x bytes without flags
+func decompressFlags(in []byte) (x []byte, largest bool, err error) {
+	if in[0]&0x80 == 0 {
+		return nil, false, errors.New("point is not compressed")
+	}
+	if in[0]&0x40 != 0 {
+		return nil, false, errors.New("point at infinity is not allowed")
+	}
+	largest = in[0]&0x20 != 0
+	x = make([]byte, len(in))
+	copy(x, in)
+	x[0] &= 0x1f
+	return x, largest, nil
+}
+
+// decodeBLSPublicKey decompresses a 48 bytes public key in G1 and checks it is in the prime order subgroup
+func decodeBLSPublicKey(g1 *bls12381.G1, in []byte) (*bls12381.PointG1, error) {
+	if len(in) != blsPublicKeyLength {
+		return nil, fmt.Errorf("invalid bls public key length: %d", len(in))
+	}
+	xBytes, largest, err := decompressFlags(in)
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int).SetBytes(xBytes)
+	if x.Cmp(blsModulus) >= 0 {
+		return nil, errors.New("invalid bls public key x coordinate")
+	}
+	// y^2 = x^3 + 4
+	y2 := fpMod(new(big.Int).Add(new(big.Int).Exp(x, big.NewInt(3), blsModulus), big.NewInt(4)))
+	y, ok := fpSqrt(y2)
+	if !ok {
+		return nil, errors.New("bls public key is not on curve")
+	}
+	if fpLexLarger(y) != largest {
+		y = fpMod(y.Neg(y))
+	}
+	p, err := g1.FromBytes(append(fpBytes(x), fpBytes(y)...))
+	if err != nil {
+		return nil, err
+	}
+	if !g1.InCorrectSubgroup(p) {
+		return nil, errors.New("bls public key is not in correct subgroup")
+	}
+	return p, nil
+}
+
+// decodeBLSSignature decompresses a 96 bytes signature in G2 and checks it is in the prime order subgroup
+func decodeBLSSignature(g2 *bls12381.G2, in []byte) (*bls12381.PointG2, error) {
+	if len(in) != blsSignatureLength {
+		return nil, fmt.Errorf("invalid bls signature length: %d", len(in))
+	}
+	xBytes, largest, err := decompressFlags(in)
+	if err != nil {
+		return nil, err
+	}
+	x := fp2Elem{new(big.Int).SetBytes(xBytes[48:]), new(big.Int).SetBytes(xBytes[:48])}
+	if x.c0.Cmp(blsModulus) >= 0 || x.c1.Cmp(blsModulus) >= 0 {
+		return nil, errors.New("invalid bls signature x coordinate")
+	}
+	// y^2 = x^3 + 4(u+1)
+	y2 := x.mul(x).mul(x).add(fp2Elem{big.NewInt(4), big.NewInt(4)})
+	y, ok := y2.sqrt()
+	if !ok {
+		return nil, errors.New("bls signature is not on curve")
+	}
+	yLarger := fpLexLarger(y.c1)
+	if y.c1.Sign() == 0 {
+		yLarger = fpLexLarger(y.c0)
+	}
+	if yLarger != largest {
+		y = fp2Elem{fpMod(y.c0.Neg(y.c0)), fpMod(y.c1.Neg(y.c1))}
+	}
+	buf := make([]byte, 0, 192)
+	buf = append(buf, fpBytes(x.c1)...)
+	buf = append(buf, fpBytes(x.c0)...)
+	buf = append(buf, fpBytes(y.c1)...)
+	buf = append(buf, fpBytes(y.c0)...)
+	p, err := g2.FromBytes(buf)
+	if err != nil {
+		return nil, err
+	}
+	if !g2.InCorrectSubgroup(p) {
+		return nil, errors.New("bls signature is not in correct subgroup")
+	}
+	return p, nil
+}
+
+// expandMessageXMD implements expand_message_xmd of RFC 9380 with sha256
+func expandMessageXMD(msg, dst []byte, length int) ([]byte, error) {
+	const bInBytes, rInBytes = 32, 64
+	ell := (length + bInBytes - 1) / bInBytes
+	if ell > 255 || len(dst) > 255 {
+		return nil, errors.New("expandMessageXMD, invalid length")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, rInBytes))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+	out := append([]byte{}, bi...)
+	for i := 2; i <= ell; i++ {
+		x := make([]byte, bInBytes)
+		for j := range x {
+			x[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(x)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// hashToG2 implements hash_to_curve of the BLS12381G2_XMD:SHA-256_SSWU_RO_ suite
+func hashToG2(g2 *bls12381.G2, msg, dst []byte) (*bls12381.PointG2, error) {
+	const l = 64
+	uniform, err := expandMessageXMD(msg, dst, 4*l)
+	if err != nil {
+		return nil, err
+	}
+	q := g2.Zero()
+	for i := 0; i < 2; i++ {
+		c0 := fpMod(new(big.Int).SetBytes(uniform[(2*i)*l : (2*i+1)*l]))
+		c1 := fpMod(new(big.Int).SetBytes(uniform[(2*i+1)*l : (2*i+2)*l]))
+		// both mapped points have cleared cofactors, which is the same as clearing the sum
+		p, err := g2.MapToCurve(append(fpBytes(c1), fpBytes(c0)...))
+		if err != nil {
+			return nil, err
+		}
+		g2.Add(q, q, p)
+	}
+	return g2.Affine(q), nil
+}
+
+// fastAggregateVerify verifies sig is the aggregated signature of msg by all pubKeys
+func fastAggregateVerify(pubKeys [][]byte, msg []byte, sig []byte) error {
+	if len(pubKeys) == 0 {
+		return errors.New("no public key")
+	}
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+	aggPubKey := g1.Zero()
+	for i, v := range pubKeys {
+		p, err := decodeBLSPublicKey(g1, v)
+		if err != nil {
+			return fmt.Errorf("no.%d public key error: %v", i+1, err)
+		}
+		g1.Add(aggPubKey, aggPubKey, p)
+	}
+	signature, err := decodeBLSSignature(g2, sig)
+	if err != nil {
+		return err
+	}
+	h, err := hashToG2(g2, msg, blsDST)
+	if err != nil {
+		return err
+	}
+	engine := bls12381.NewPairingEngine()
+	engine.AddPair(aggPubKey, h)
+	engine.AddPairInv(g1.One(), signature)
+	if !engine.Check() {
+		return errors.New("invalid aggregated signature")
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bsc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/evmpoa"
+)
+
+const (
+	defaultEpoch              = 200
+	validatorNumberSize       = 1
+	turnLengthSize            = 1
+	validatorBytesLength      = ecommon.AddressLength + blsPublicKeyLength
+	maxAttestationExtraLength = 256
+)
+
+// VoteData is the source and target checkpoint voted by bsc validators
+type VoteData struct {
+	SourceNumber uint64
+	SourceHash   ecommon.Hash
+	TargetNumber uint64
+	TargetHash   ecommon.Hash
+}
+
+// Hash returns the message signed by voters
+func (d *VoteData) Hash() ecommon.Hash {
+	enc, _ := rlp.EncodeToBytes(d)
+	return crypto.Keccak256Hash(enc)
+}
+
+// VoteAttestation is the aggregated vote carried in header extra since luban
+type VoteAttestation struct {
+	VoteAddressSet uint64
+	AggSignature   [blsSignatureLength]byte
+	Data           *VoteData
+	Extra          []byte
+}
+
+// chainInfo returns the bsc extra info decoded by the engine
+func chainInfo(ctx *Context) *ExtraInfo {
+	return ctx.Extra.(*ExtraInfo)
+}
+
+func (info *ExtraInfo) isLuban(number *big.Int) bool {
+	return info.LubanHeight != nil && number.Cmp(info.LubanHeight) >= 0
+}
+
+func (info *ExtraInfo) isBohr(time uint64) bool {
+	return info.BohrTime != nil && time >= *info.BohrTime
+}
+
+func (info *ExtraInfo) epoch() uint64 {
+	if info.Epoch == 0 {
+		return defaultEpoch
+	}
+	return info.Epoch
+}
+
+// isEpochHeader reports whether header carries a new validator set
+func isEpochHeader(header *eth.Header, ctx *Context) bool {
+	if len(header.Extra) <= extraVanity+extraSeal {
+		return false
+	}
+	info := chainInfo(ctx)
+	if !info.isLuban(header.Number) {
+		return true
+	}
+	return header.Number.Uint64()%info.epoch() == 0
+}
+
+// epochValidators is the evmpoa.Config.EpochValidators of bsc, which carries bls keys since luban
+func epochValidators(ctx *Context, header *eth.Header) (*HeightAndValidators, error) {
+	if !chainInfo(ctx).isLuban(header.Number) {
+		return evmpoa.ListedValidators(ctx, header)
+	}
+	validators, blsKeys, err := parseEpochValidators(header, ctx)
+	if err != nil || validators == nil {
+		return nil, err
+	}
+	return &HeightAndValidators{Height: header.Number, Validators: validators, BLSKeys: blsKeys}, nil
+}
+
+// parseEpochValidators returns the validators of an epoch header and, since luban, their bls keys.
+// Nothing is returned for other headers.
+func parseEpochValidators(header *eth.Header, ctx *Context) (validators []ecommon.Address, blsKeys [][]byte, err error) {
+	if !isEpochHeader(header, ctx) {
+		return
+	}
+	if !chainInfo(ctx).isLuban(header.Number) {
+		validators, err = ParseValidators(header.Extra[extraVanity : len(header.Extra)-extraSeal])
+		return
+	}
+	num := int(header.Extra[extraVanity])
+	if num == 0 || len(header.Extra) < extraVanity+validatorNumberSize+num*validatorBytesLength+extraSeal {
+		err = errors.New("invalid validators bytes")
+		return
+	}
+	start := extraVanity + validatorNumberSize
+	validators = make([]ecommon.Address, num)
+	blsKeys = make([][]byte, num)
+	for i := 0; i < num; i++ {
+		b := header.Extra[start+i*validatorBytesLength : start+(i+1)*validatorBytesLength]
+		validators[i] = ecommon.BytesToAddress(b[:ecommon.AddressLength])
+		blsKeys[i] = append([]byte{}, b[ecommon.AddressLength:]...)
+	}
+	return
+}
+
+// getVoteAttestation returns the vote attestation in header extra, nil if there is none
+func getVoteAttestation(header *eth.Header, ctx *Context) (*VoteAttestation, error) {
+	info := chainInfo(ctx)
+	if len(header.Extra) <= extraVanity+extraSeal || !info.isLuban(header.Number) {
+		return nil, nil
+	}
+	start := extraVanity
+	if header.Number.Uint64()%info.epoch() == 0 {
+		num := int(header.Extra[extraVanity])
+		start = extraVanity + validatorNumberSize + num*validatorBytesLength
+		if info.isBohr(header.Time) {
+			start += turnLengthSize
+		}
+		if len(header.Extra) <= start+extraSeal {
+			return nil, nil
+		}
+	}
+	attestation := new(VoteAttestation)
+	if err := rlp.DecodeBytes(header.Extra[start:len(header.Extra)-extraSeal], attestation); err != nil {
+		return nil, fmt.Errorf("decode vote attestation error: %v", err)
+	}
+	return attestation, nil
+}
+
+// verifyVoteAttestation verifies the attestation in header against the validators signing its parent,
+// it returns the attested vote data, or nil if there is nothing to account
+func verifyVoteAttestation(header *eth.Header, parent *HeaderWithDifficultySum, phv, pphv *HeightAndValidators,
+	ctx *Context) (*VoteData, error) {
+	attestation, err := getVoteAttestation(header, ctx)
+	if err != nil || attestation == nil {
+		return nil, err
+	}
+	data := attestation.Data
+	if data == nil {
+		return nil, errors.New("vote attestation without data")
+	}
+	if len(attestation.Extra) > maxAttestationExtraLength {
+		return nil, fmt.Errorf("vote attestation extra too long: %d", len(attestation.Extra))
+	}
+
+	// the target should be the parent, and the source the highest justified block
+	if data.TargetNumber != parent.Header.Number.Uint64() || data.TargetHash != parent.Header.Hash() {
+		return nil, fmt.Errorf("vote attestation target %d %s is not parent", data.TargetNumber, data.TargetHash.Hex())
+	}
+	if parent.Justified != nil {
+		if data.SourceNumber != parent.Justified.Number || data.SourceHash != parent.Justified.Hash {
+			return nil, fmt.Errorf("vote attestation source %d %s is not the justified block", data.SourceNumber, data.SourceHash.Hex())
+		}
+	} else if data.SourceNumber >= data.TargetNumber {
+		return nil, fmt.Errorf("vote attestation source %d is not lower than target %d", data.SourceNumber, data.TargetNumber)
+	}
+
+	// votes are checked against the validators which signed the parent
+	hv := phv
+	if new(big.Int).Sub(parent.Header.Number, phv.Height).Int64() <= int64(len(pphv.Validators)/2) {
+		hv = pphv
+	}
+	if len(hv.BLSKeys) != len(hv.Validators) {
+		// the validators have no bls key before the first luban epoch
+		return nil, nil
+	}
+	indexes := make([]int, len(hv.Validators))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return bytes.Compare(hv.Validators[indexes[i]][:], hv.Validators[indexes[j]][:]) < 0
+	})
+	if len(indexes) < 64 && attestation.VoteAddressSet>>uint(len(indexes)) != 0 {
+		return nil, errors.New("vote attestation has votes of unknown validators")
+	}
+	voters := make([][]byte, 0, len(indexes))
+	for i, index := range indexes {
+		if i < 64 && attestation.VoteAddressSet&(1<<uint(i)) != 0 {
+			voters = append(voters, hv.BLSKeys[index])
+		}
+	}
+	if len(voters) < (2*len(hv.Validators)+2)/3 {
+		return nil, fmt.Errorf("vote attestation has %d votes of %d validators, not enough", len(voters), len(hv.Validators))
+	}
+	if err := fastAggregateVerify(voters, data.Hash().Bytes(), attestation.AggSignature[:]); err != nil {
+		return nil, fmt.Errorf("vote attestation signature error: %v", err)
+	}
+	return data, nil
+}
+
+// finality is the evmpoa.Config.Finality of bsc, the target of a vote attestation is justified,
+// and its source is finalized as well if they are adjacent
+func finality(ctx *Context, header *eth.Header, parent *HeaderWithDifficultySum, phv, pphv *HeightAndValidators) (
+	justified, finalized *BlockID, err error) {
+	vote, err := verifyVoteAttestation(header, parent, phv, pphv, ctx)
+	if err != nil {
+		return
+	}
+	justified, finalized = parent.Justified, parent.Finalized
+	if vote != nil {
+		justified = &BlockID{Number: vote.TargetNumber, Hash: vote.TargetHash}
+		// a justified block is finalized once its direct child is justified
+		if vote.TargetNumber == vote.SourceNumber+1 &&
+			(parent.Finalized == nil || vote.SourceNumber > parent.Finalized.Number) {
+			finalized = &BlockID{Number: vote.SourceNumber, Hash: vote.SourceHash}
+		}
+	}
+	return
+}
+
+// GetFinalizedHeight returns the height of the latest block finalized by vote attestations on the canonical chain
+func GetFinalizedHeight(native *native.NativeService, chainID uint64) (height uint64, err error) {
+	cheight, err := GetCanonicalHeight(native, chainID)
+	if err != nil {
+		return
+	}
+	cheader, err := GetCanonicalHeader(native, chainID, cheight)
+	if err != nil {
+		return
+	}
+	if cheader == nil || cheader.Finalized == nil {
+		return
+	}
+	height = cheader.Finalized.Number
+	return
+}
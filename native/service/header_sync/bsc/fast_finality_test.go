@@ -0,0 +1,247 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bsc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressG1(g1 *bls12381.G1, p *bls12381.PointG1) []byte {
+	raw := g1.ToBytes(p)
+	out := append([]byte{}, raw[:48]...)
+	out[0] |= 0x80
+	if fpLexLarger(new(big.Int).SetBytes(raw[48:])) {
+		out[0] |= 0x20
+	}
+	return out
+}
+
+func compressG2(g2 *bls12381.G2, p *bls12381.PointG2) []byte {
+	raw := g2.ToBytes(p)
+	out := append([]byte{}, raw[:96]...)
+	out[0] |= 0x80
+	y := new(big.Int).SetBytes(raw[96:144])
+	if y.Sign() == 0 {
+		y.SetBytes(raw[144:])
+	}
+	if fpLexLarger(y) {
+		out[0] |= 0x20
+	}
+	return out
+}
+
+type blsSigner struct {
+	sk     *big.Int
+	pubKey []byte
+}
+
+func newBLSSigner(sk int64) *blsSigner {
+	g1 := bls12381.NewG1()
+	s := &blsSigner{sk: big.NewInt(sk)}
+	s.pubKey = compressG1(g1, g1.MulScalar(g1.New(), g1.One(), s.sk))
+	return s
+}
+
+func aggregateSign(signers []*blsSigner, msg []byte) []byte {
+	g2 := bls12381.NewG2()
+	h, _ := hashToG2(g2, msg, blsDST)
+	sig := g2.Zero()
+	for _, s := range signers {
+		g2.Add(sig, sig, g2.MulScalar(g2.New(), h, s.sk))
+	}
+	return compressG2(g2, sig)
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	// RFC 9380 K.1
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	out, err := expandMessageXMD([]byte(""), dst, 0x20)
+	assert.Nil(t, err)
+	assert.Equal(t, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235", hex.EncodeToString(out))
+	out, err = expandMessageXMD([]byte("abc"), dst, 0x20)
+	assert.Nil(t, err)
+	assert.Equal(t, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615", hex.EncodeToString(out))
+}
+
+func TestHashToG2(t *testing.T) {
+	// RFC 9380 J.10.1, x of hash_to_curve("")
+	g2 := bls12381.NewG2()
+	p, err := hashToG2(g2, []byte(""), []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"))
+	assert.Nil(t, err)
+	assert.Equal(t, "05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d"+
+		"0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a",
+		hex.EncodeToString(g2.ToBytes(p)[:96]))
+}
+
+func TestDecodeBLSGenerators(t *testing.T) {
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+	g1Bytes, _ := hex.DecodeString("97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb")
+	g2Bytes, _ := hex.DecodeString("93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e" +
+		"024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8")
+
+	p1, err := decodeBLSPublicKey(g1, g1Bytes)
+	assert.Nil(t, err)
+	assert.True(t, g1.Equal(p1, g1.One()))
+	g1Bytes[0] ^= 0x20
+	p1, err = decodeBLSPublicKey(g1, g1Bytes)
+	assert.Nil(t, err)
+	assert.True(t, g1.Equal(p1, g1.Neg(g1.New(), g1.One())))
+
+	p2, err := decodeBLSSignature(g2, g2Bytes)
+	assert.Nil(t, err)
+	assert.True(t, g2.Equal(p2, g2.One()))
+	g2Bytes[0] ^= 0x20
+	p2, err = decodeBLSSignature(g2, g2Bytes)
+	assert.Nil(t, err)
+	assert.True(t, g2.Equal(p2, g2.Neg(g2.New(), g2.One())))
+
+	g2Bytes[0] &^= 0x80
+	_, err = decodeBLSSignature(g2, g2Bytes)
+	assert.NotNil(t, err)
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	signers := []*blsSigner{newBLSSigner(11), newBLSSigner(22), newBLSSigner(33)}
+	msg := []byte("vote")
+	sig := aggregateSign(signers, msg)
+	pubKeys := [][]byte{signers[0].pubKey, signers[1].pubKey, signers[2].pubKey}
+	assert.Nil(t, fastAggregateVerify(pubKeys, msg, sig))
+	assert.NotNil(t, fastAggregateVerify(pubKeys[:2], msg, sig))
+	assert.NotNil(t, fastAggregateVerify(pubKeys, []byte("other"), sig))
+}
+
+func lubanExtra(validators []ecommon.Address, signers []*blsSigner, attestation *VoteAttestation) []byte {
+	extra := make([]byte, extraVanity)
+	if len(validators) > 0 {
+		extra = append(extra, byte(len(validators)))
+		for i, v := range validators {
+			extra = append(extra, v[:]...)
+			extra = append(extra, signers[i].pubKey...)
+		}
+	}
+	if attestation != nil {
+		enc, _ := rlp.EncodeToBytes(attestation)
+		extra = append(extra, enc...)
+	}
+	return append(extra, make([]byte, extraSeal)...)
+}
+
+func TestVoteAttestation(t *testing.T) {
+	ctx := &Context{ChainID: 1, Extra: &ExtraInfo{LubanHeight: big.NewInt(0), Epoch: 10}}
+	// validators are listed unsorted to check that votes follow the address order
+	validators := []ecommon.Address{{3}, {1}, {4}, {2}}
+	signers := []*blsSigner{newBLSSigner(3), newBLSSigner(1), newBLSSigner(4), newBLSSigner(2)}
+
+	epoch := &eth.Header{Number: big.NewInt(10), Extra: lubanExtra(validators, signers, nil)}
+	assert.True(t, isEpochHeader(epoch, ctx))
+	parsed, blsKeys, err := parseEpochValidators(epoch, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, validators, parsed)
+	assert.Equal(t, signers[2].pubKey, blsKeys[2])
+	hv := &HeightAndValidators{Height: epoch.Number, Validators: parsed, BLSKeys: blsKeys}
+
+	justified := &BlockID{Number: 18, Hash: ecommon.Hash{18}}
+	parent := &HeaderWithDifficultySum{
+		Header:    &eth.Header{Number: big.NewInt(19), Extra: lubanExtra(nil, nil, nil)},
+		Justified: justified,
+	}
+	data := &VoteData{SourceNumber: 18, SourceHash: justified.Hash, TargetNumber: 19, TargetHash: parent.Header.Hash()}
+	newHeader := func(voteSet uint64, voters []*blsSigner, data *VoteData) *eth.Header {
+		attestation := &VoteAttestation{VoteAddressSet: voteSet, Data: data}
+		copy(attestation.AggSignature[:], aggregateSign(voters, data.Hash().Bytes()))
+		return &eth.Header{Number: big.NewInt(20), Extra: lubanExtra(validators, signers, attestation)}
+	}
+
+	// bits follow sorted addresses {1}, {2}, {3}, {4}
+	header := newHeader(0x7, []*blsSigner{signers[1], signers[3], signers[0]}, data)
+	vote, err := verifyVoteAttestation(header, parent, hv, hv, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, data, vote)
+	// the target is justified, and the adjacent source finalized
+	justifiedID, finalizedID, err := finality(ctx, header, parent, hv, hv)
+	assert.Nil(t, err)
+	assert.Equal(t, &BlockID{Number: 19, Hash: parent.Header.Hash()}, justifiedID)
+	assert.Equal(t, justified, finalizedID)
+	// headers without attestation keep those of the parent
+	justifiedID, finalizedID, err = finality(ctx, &eth.Header{Number: big.NewInt(20), Extra: lubanExtra(nil, nil, nil)},
+		parent, hv, hv)
+	assert.Nil(t, err)
+	assert.Equal(t, justified, justifiedID)
+	assert.Nil(t, finalizedID)
+
+	// not enough votes
+	header = newHeader(0x3, []*blsSigner{signers[1], signers[3]}, data)
+	_, err = verifyVoteAttestation(header, parent, hv, hv, ctx)
+	assert.NotNil(t, err)
+
+	// votes do not match the signature
+	header = newHeader(0xe, []*blsSigner{signers[1], signers[3], signers[0]}, data)
+	_, err = verifyVoteAttestation(header, parent, hv, hv, ctx)
+	assert.NotNil(t, err)
+
+	// source is not the justified block
+	wrong := *data
+	wrong.SourceNumber = 17
+	header = newHeader(0x7, []*blsSigner{signers[1], signers[3], signers[0]}, &wrong)
+	_, err = verifyVoteAttestation(header, parent, hv, hv, ctx)
+	assert.NotNil(t, err)
+
+	// attestations are ignored until validators have bls keys
+	header = newHeader(0x7, []*blsSigner{signers[1], signers[3], signers[0]}, data)
+	vote, err = verifyVoteAttestation(header, parent, &HeightAndValidators{Height: epoch.Number, Validators: validators}, hv, ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, vote)
+}
+
+// TestBohrVoteAttestation decodes testdata/bohr_epoch_header.json, an epoch header laid out as since bohr
+// with 21 validators, a turn length of 4 and a vote attestation
+func TestBohrVoteAttestation(t *testing.T) {
+	raw, err := ioutil.ReadFile("testdata/bohr_epoch_header.json")
+	assert.Nil(t, err)
+	header := new(eth.Header)
+	assert.Nil(t, json.Unmarshal(raw, header))
+
+	bohrTime := header.Time
+	ctx := &Context{ChainID: 56, Extra: &ExtraInfo{LubanHeight: big.NewInt(0), BohrTime: &bohrTime}}
+	validators, blsKeys, err := parseEpochValidators(header, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 21, len(validators))
+	assert.Equal(t, 21, len(blsKeys))
+
+	attestation, err := getVoteAttestation(header, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0x1fffff), attestation.VoteAddressSet)
+	assert.Equal(t, header.Number.Uint64()-1, attestation.Data.TargetNumber)
+	assert.Nil(t, fastAggregateVerify(blsKeys, attestation.Data.Hash().Bytes(), attestation.AggSignature[:]))
+
+	// before bohr the turn length is taken for the attestation
+	bohrTime = header.Time + 1
+	_, err = getVoteAttestation(header, ctx)
+	assert.NotNil(t, err)
+}
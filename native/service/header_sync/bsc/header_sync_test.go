@@ -18,6 +18,7 @@ package bsc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -27,6 +28,7 @@ import (
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly-io-test/chains/eth"
 	"github.com/polynetwork/poly/account"
@@ -41,6 +43,8 @@ import (
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	ethheader "github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/evmpoa"
 	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/polynetwork/poly/native/storage"
 	"gotest.tools/assert"
@@ -143,7 +147,7 @@ func getHeaderHashByHeight(native *native.NativeService, height uint64) ethcommo
 }
 
 func getHeaderByHashWithCheck(native *native.NativeService, hash ethcommon.Hash, check bool, exist bool) []byte {
-	hws, err := getHeader(native, hash, BSCChainID)
+	hws, err := engine.GetHeader(native, hash, BSCChainID)
 	if err != nil {
 		return nil
 	}
@@ -172,11 +176,36 @@ func getTool() *eth.ETHTools {
 
 func getBlockHeaderByHash(t *testing.T, hash ethcommon.Hash) *etypes.Header {
 	tool := getTool()
-	hdr, err := tool.GetBlockHeaderByHash(hash)
+	hdr, err := tool.GetEthClient().HeaderByHash(context.Background(), hash)
 	assert.NilError(t, err)
 	return hdr
 }
 
+func toEthHeader(t *testing.T, hdr *etypes.Header) ethheader.Header {
+	raw, err := json.Marshal(hdr)
+	assert.NilError(t, err)
+	var header ethheader.Header
+	assert.NilError(t, json.Unmarshal(raw, &header))
+	return header
+}
+
+// ecrecover returns the signer of a parlia header of chainID
+func ecrecover(header *etypes.Header, chainID *big.Int) (ethcommon.Address, error) {
+	raw, err := json.Marshal(header)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	var h ethheader.Header
+	if err = json.Unmarshal(raw, &h); err != nil {
+		return ethcommon.Address{}, err
+	}
+	pubkey, err := crypto.SigToPub(evmpoa.ParliaSeal(&h, chainID).Bytes(), h.Extra[len(h.Extra)-crypto.SignatureLength:])
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
 func getBlockHeader(t *testing.T, height uint64) *etypes.Header {
 	tool := getTool()
 	hdr, err := tool.GetBlockHeader(height)
@@ -195,7 +224,7 @@ func getGenesisHeaderByHeight(t *testing.T, epochHeight uint64) *GenesisHeader {
 	pvalidators, err := ParseValidators(phdr.Extra[32 : len(phdr.Extra)-65])
 	assert.NilError(t, err)
 
-	genesisHeader := GenesisHeader{Header: *hdr, PrevValidators: []HeightAndValidators{
+	genesisHeader := GenesisHeader{Header: toEthHeader(t, hdr), PrevValidators: []HeightAndValidators{
 		{Height: big.NewInt(int64(pEpochHeight)), Validators: pvalidators},
 	}}
 
@@ -92,6 +92,7 @@ func (this *BTCHandler) SyncBlockHeader(native *native.NativeService) error {
 		if err != nil {
 			return fmt.Errorf("SyncBlockHeader, commit header err: %v", err)
 		}
+		native.AddSyncedHeaders(1)
 
 	}
 	return nil
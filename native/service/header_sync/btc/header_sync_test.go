@@ -26,28 +26,58 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/polynetwork/poly/native/storage"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
 var (
-	acct *account.Account = account.NewAccount("")
+	acct     *account.Account = account.NewAccount("")
+	netParam                  = &chaincfg.TestNet3Params
 
 	getNativeFunc = func(args []byte, db *storage.CacheDB) *native.NativeService {
 		if db == nil {
 			store, _ := leveldbstore.NewMemLevelDBStore()
 			db = storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+			setSideChain(db)
 		}
 		ns, _ := native.NewNativeService(db, new(types.Transaction), 0, 0, common.Uint256{0}, 0, args, false)
 		return ns
 	}
 
+	// setSideChain registers the side chain 0 of netParam
+	setSideChain = func(db *storage.CacheDB) {
+		netType := utils.TyMainnet
+		switch netParam.Name {
+		case chaincfg.TestNet3Params.Name:
+			netType = utils.TyTestnet3
+		case chaincfg.RegressionNetParams.Name:
+			netType = utils.TyRegtest
+		case chaincfg.SimNetParams.Name:
+			netType = utils.TySimnet
+		}
+		ccmc := make([]byte, 8)
+		binary.LittleEndian.PutUint64(ccmc, uint64(netType))
+		side := &side_chain_manager.SideChain{
+			Name:         "btc",
+			ChainId:      0,
+			BlocksToWait: 1,
+			CCMCAddress:  ccmc,
+		}
+		sink := common.NewZeroCopySink(nil)
+		_ = side.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(side_chain_manager.SIDE_CHAIN),
+			utils.GetUint64Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
+	}
+
 	getHeaders = func() []*wire.BlockHeader {
 		res := make([]*wire.BlockHeader, 0)
 		for _, v := range chain {
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cometbft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// Handler syncs protobuf light blocks of tendermint v0.34+ and cometbft chains
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// SyncGenesisHeader takes a protobuf encoded light block as the trusted root of the light client.
+// It can be reset only if the latest synced header is out of trusting period.
+func (this *Handler) SyncGenesisHeader(native *native.NativeService) error {
+	param := new(hscommon.SyncGenesisHeaderParam)
+	if err := param.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, contract params deserialize error: %v", err)
+	}
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, checkWitness error: %v", err)
+	}
+	opts, err := GetTrustOptions(native, param.ChainID)
+	if err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, %v", err)
+	}
+	client, err := GetClientState(native, param.ChainID)
+	if err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, %v", err)
+	}
+	if client != nil {
+		latest, err := GetConsensusState(native, param.ChainID, client.LatestHeight)
+		if err != nil {
+			return fmt.Errorf("CometBFTHandler SyncGenesisHeader, %v", err)
+		}
+		if latest != nil && !expired(latest, opts, time.Unix(int64(native.GetTime()), 0)) {
+			return fmt.Errorf("CometBFTHandler SyncGenesisHeader, genesis header had been initialized")
+		}
+	}
+
+	lb, err := decodeLightBlock(param.GenesisHeader)
+	if err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, %v", err)
+	}
+	if err := lb.ValidateBasic(lb.ChainID); err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, validate light block error: %v", err)
+	}
+	if err := lb.ValidatorSet.VerifyCommitLight(lb.ChainID, lb.Commit.BlockID, lb.Height, lb.Commit); err != nil {
+		return fmt.Errorf("CometBFTHandler SyncGenesisHeader, verify commit error: %v", err)
+	}
+	storeLightBlock(native, param.ChainID, &ClientState{ChainID: lb.ChainID}, NewConsensusState(lb.Header))
+	return nil
+}
+
+// SyncBlockHeader takes serialized LightBlockUpdate as headers
+func (this *Handler) SyncBlockHeader(native *native.NativeService) error {
+	params := new(hscommon.SyncBlockHeaderParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("CometBFTHandler SyncBlockHeader, contract params deserialize error: %v", err)
+	}
+	for i, v := range params.Headers {
+		update := new(LightBlockUpdate)
+		if err := update.Deserialization(common.NewZeroCopySource(v)); err != nil {
+			return fmt.Errorf("CometBFTHandler SyncBlockHeader, deserialize update %d error: %v", i, err)
+		}
+		_, stored, err := VerifyLightBlock(native, params.ChainID, update)
+		if err != nil {
+			return fmt.Errorf("CometBFTHandler SyncBlockHeader, update %d: %v", i, err)
+		}
+		if stored {
+			native.AddSyncedHeaders(1)
+		}
+	}
+	return nil
+}
+
+func (this *Handler) SyncCrossChainMsg(native *native.NativeService) error {
+	return nil
+}
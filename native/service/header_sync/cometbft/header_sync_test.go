@@ -0,0 +1,295 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cometbft
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	ptypes "github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+	tmmath "github.com/switcheo/tendermint/libs/math"
+	tmproto "github.com/switcheo/tendermint/proto/tendermint/types"
+	tmversion "github.com/switcheo/tendermint/proto/tendermint/version"
+	"github.com/switcheo/tendermint/types"
+)
+
+const (
+	testChainID   = uint64(100)
+	testTmChainID = "test-chain"
+)
+
+var genesisTime = time.Unix(1600000000, 0)
+
+func newNativeService(t *testing.T, db *storage.CacheDB, now time.Time) *native.NativeService {
+	ns, err := native.NewNativeService(db, &ptypes.Transaction{}, uint32(now.Unix()), 0, common.Uint256{}, 0, nil, false)
+	assert.Nil(t, err)
+	return ns
+}
+
+func newTestDB(t *testing.T) *storage.CacheDB {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns := newNativeService(t, db, genesisTime)
+	extra, _ := json.Marshal(&TrustOptions{TrustingPeriod: 3600, MaxClockDrift: 10})
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{
+		ChainId:   testChainID,
+		Router:    utils.COMETBFT_ROUTER,
+		Name:      "cometbft",
+		ExtraInfo: extra,
+	}))
+	return db
+}
+
+func newPrivVals(n int) []types.PrivValidator {
+	pvs := make([]types.PrivValidator, n)
+	for i := range pvs {
+		pvs[i] = types.NewMockPV()
+	}
+	sort.Sort(types.PrivValidatorsByAddress(pvs))
+	return pvs
+}
+
+func valSet(pvs []types.PrivValidator) *types.ValidatorSet {
+	vals := make([]*types.Validator, len(pvs))
+	for i, pv := range pvs {
+		vals[i] = pv.(types.MockPV).ExtractIntoValidator(10)
+	}
+	return types.NewValidatorSet(vals)
+}
+
+func hashOf(s string) []byte {
+	h := sha256.Sum256([]byte(s))
+	return h[:]
+}
+
+// makeLightBlock makes a light block at height signed by signers in vals
+func makeLightBlock(t *testing.T, height int64, tm time.Time, vals, nextVals []types.PrivValidator,
+	signers []types.PrivValidator) []byte {
+	valset, nextValset := valSet(vals), valSet(nextVals)
+	header := &types.Header{
+		Version:            tmversion.Consensus{Block: 11},
+		ChainID:            testTmChainID,
+		Height:             height,
+		Time:               tm,
+		ValidatorsHash:     valset.Hash(),
+		NextValidatorsHash: nextValset.Hash(),
+		AppHash:            hashOf("app"),
+		ProposerAddress:    valset.Validators[0].Address,
+	}
+	blockID := types.BlockID{Hash: header.Hash(), PartSetHeader: types.PartSetHeader{Total: 1, Hash: hashOf("part")}}
+	sigs := make([]types.CommitSig, valset.Size())
+	for i := range sigs {
+		sigs[i] = types.NewCommitSigAbsent()
+	}
+	for _, pv := range signers {
+		pk, _ := pv.GetPubKey()
+		idx, _ := valset.GetByAddress(pk.Address())
+		vote := &types.Vote{
+			ValidatorAddress: pk.Address(),
+			ValidatorIndex:   idx,
+			Height:           height,
+			Round:            1,
+			Type:             tmproto.PrecommitType,
+			BlockID:          blockID,
+			Timestamp:        tm,
+		}
+		v := vote.ToProto()
+		assert.Nil(t, pv.SignVote(testTmChainID, v))
+		vote.Signature = v.Signature
+		sigs[idx] = vote.CommitSig()
+	}
+	lb := &types.LightBlock{
+		SignedHeader: &types.SignedHeader{Header: header, Commit: types.NewCommit(height, 1, blockID, sigs)},
+		ValidatorSet: valset,
+	}
+	pb, err := lb.ToProto()
+	assert.Nil(t, err)
+	raw, err := pb.Marshal()
+	assert.Nil(t, err)
+	return raw
+}
+
+func marshalValSet(t *testing.T, pvs []types.PrivValidator) []byte {
+	pb, err := valSet(pvs).ToProto()
+	assert.Nil(t, err)
+	raw, err := pb.Marshal()
+	assert.Nil(t, err)
+	return raw
+}
+
+func initClient(t *testing.T, ns *native.NativeService, pvs []types.PrivValidator) {
+	lb, err := decodeLightBlock(makeLightBlock(t, 1, genesisTime, pvs, pvs, pvs))
+	assert.Nil(t, err)
+	storeLightBlock(ns, testChainID, &ClientState{ChainID: testTmChainID}, NewConsensusState(lb.Header))
+}
+
+func TestVerifyAdjacent(t *testing.T) {
+	db := newTestDB(t)
+	pvs := newPrivVals(4)
+	initClient(t, newNativeService(t, db, genesisTime), pvs)
+	now := genesisTime.Add(time.Minute)
+
+	// less than 2/3 signed
+	ns := newNativeService(t, db, now)
+	_, _, err := VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 1, LightBlock: makeLightBlock(t, 2, now, pvs, pvs, pvs[:2])})
+	assert.NotNil(t, err)
+
+	// validators not the trusted next validators
+	others := newPrivVals(4)
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 1, LightBlock: makeLightBlock(t, 2, now, others, others, others)})
+	assert.NotNil(t, err)
+
+	// from the future
+	future := now.Add(time.Minute)
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 1, LightBlock: makeLightBlock(t, 2, future, pvs, pvs, pvs)})
+	assert.NotNil(t, err)
+
+	cs, _, err := VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 1, LightBlock: makeLightBlock(t, 2, now, pvs, others, pvs[:3])})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), cs.Height)
+	client, err := GetClientState(ns, testChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), client.LatestHeight)
+
+	// trusted header expired
+	ns = newNativeService(t, db, genesisTime.Add(2*time.Hour))
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 2, LightBlock: makeLightBlock(t, 3, genesisTime.Add(2*time.Hour), others, others, others)})
+	assert.NotNil(t, err)
+}
+
+func TestVerifyNonAdjacent(t *testing.T) {
+	db := newTestDB(t)
+	pvs := newPrivVals(4)
+	initClient(t, newNativeService(t, db, genesisTime), pvs)
+	now := genesisTime.Add(time.Minute)
+	ns := newNativeService(t, db, now)
+
+	// one of four trusted validators left, still 1/3 trusted power is not reached by the rest
+	next := append(newPrivVals(3), pvs[0])
+	sort.Sort(types.PrivValidatorsByAddress(next))
+	_, _, err := VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 1, LightBlock: makeLightBlock(t, 10, now, next, next, next)})
+	assert.NotNil(t, err, "trusted validators are required")
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight:     1,
+		LightBlock:        makeLightBlock(t, 10, now, next, next, next),
+		TrustedValidators: marshalValSet(t, pvs),
+	})
+	assert.NotNil(t, err, "not enough trusted voting power")
+
+	// trusted validators not match trusted header
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight:     1,
+		LightBlock:        makeLightBlock(t, 10, now, next, next, next),
+		TrustedValidators: marshalValSet(t, next),
+	})
+	assert.NotNil(t, err)
+
+	// two of four trusted validators left which is more than 1/3
+	next = append(newPrivVals(2), pvs[:2]...)
+	sort.Sort(types.PrivValidatorsByAddress(next))
+	cs, _, err := VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight:     1,
+		LightBlock:        makeLightBlock(t, 10, now, next, next, next),
+		TrustedValidators: marshalValSet(t, pvs),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), cs.Height)
+
+	stored, err := GetConsensusState(ns, testChainID, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, cs, stored)
+
+	// trust comes from the next validators of trusted header, not its current validators
+	later := now.Add(time.Minute)
+	ns = newNativeService(t, db, later)
+	others := newPrivVals(4)
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight: 10, LightBlock: makeLightBlock(t, 11, now.Add(time.Second), next, others, next)})
+	assert.Nil(t, err)
+	_, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight:     11,
+		LightBlock:        makeLightBlock(t, 20, later, others, others, others),
+		TrustedValidators: marshalValSet(t, next),
+	})
+	assert.NotNil(t, err)
+	cs, _, err = VerifyLightBlock(ns, testChainID, &LightBlockUpdate{
+		TrustedHeight:     11,
+		LightBlock:        makeLightBlock(t, 20, later, others, others, others),
+		TrustedValidators: marshalValSet(t, others),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), cs.Height)
+}
+
+func TestSyncBlockHeaderTwice(t *testing.T) {
+	db := newTestDB(t)
+	pvs := newPrivVals(4)
+	initClient(t, newNativeService(t, db, genesisTime), pvs)
+	now := genesisTime.Add(time.Minute)
+
+	sink := common.NewZeroCopySink(nil)
+	(&LightBlockUpdate{TrustedHeight: 1, LightBlock: makeLightBlock(t, 2, now, pvs, pvs, pvs)}).Serialization(sink)
+	update := sink.Bytes()
+	sink = common.NewZeroCopySink(nil)
+	(&hscommon.SyncBlockHeaderParam{ChainID: testChainID, Headers: [][]byte{update, update}}).Serialization(sink)
+
+	ns, err := native.NewNativeService(db, &ptypes.Transaction{}, uint32(now.Unix()), 0, common.Uint256{}, 0,
+		sink.Bytes(), false)
+	assert.Nil(t, err)
+	assert.Nil(t, NewHandler().SyncBlockHeader(ns))
+	assert.Equal(t, uint64(1), ns.GetSyncedHeaders())
+	assert.Equal(t, 1, len(ns.GetNotify()))
+
+	// the same light block synced again by another transaction stores nothing
+	ns, err = native.NewNativeService(db, &ptypes.Transaction{}, uint32(now.Unix()), 0, common.Uint256{}, 0,
+		sink.Bytes(), false)
+	assert.Nil(t, err)
+	assert.Nil(t, NewHandler().SyncBlockHeader(ns))
+	assert.Equal(t, uint64(0), ns.GetSyncedHeaders())
+	assert.Equal(t, 0, len(ns.GetNotify()))
+}
+
+func TestTrustOptions(t *testing.T) {
+	opts := &TrustOptions{TrustingPeriod: 1}
+	assert.Nil(t, opts.validate())
+	assert.Equal(t, tmmath.Fraction{Numerator: 1, Denominator: 3}, opts.TrustLevel)
+
+	opts.TrustLevel = tmmath.Fraction{Numerator: 1, Denominator: 4}
+	assert.NotNil(t, opts.validate())
+	opts.TrustLevel = tmmath.Fraction{Numerator: 4, Denominator: 3}
+	assert.NotNil(t, opts.validate())
+	assert.NotNil(t, (&TrustOptions{}).validate())
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cometbft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	tmmath "github.com/switcheo/tendermint/libs/math"
+	"github.com/switcheo/tendermint/types"
+)
+
+// TrustOptions is the json encoded extra info of side chains using the cometbft router
+type TrustOptions struct {
+	// TrustingPeriod in seconds, a trusted header older than it can not be used to verify new headers
+	TrustingPeriod uint64 `json:"trusting_period"`
+	// MaxClockDrift in seconds, how far a new header time can be ahead of poly block time
+	MaxClockDrift uint64 `json:"max_clock_drift"`
+	// TrustLevel of skipping verification, 1/3 if not set
+	TrustLevel tmmath.Fraction `json:"trust_level"`
+}
+
+func (opts *TrustOptions) validate() error {
+	if opts.TrustingPeriod == 0 {
+		return fmt.Errorf("trusting period is not set")
+	}
+	if opts.TrustLevel.Denominator == 0 {
+		opts.TrustLevel = tmmath.Fraction{Numerator: 1, Denominator: 3}
+	}
+	lvl := opts.TrustLevel
+	if lvl.Numerator*3 < lvl.Denominator || lvl.Numerator > lvl.Denominator {
+		return fmt.Errorf("trust level must be within [1/3, 1], given %v", lvl)
+	}
+	return nil
+}
+
+func (opts *TrustOptions) trustingPeriod() time.Duration {
+	return time.Duration(opts.TrustingPeriod) * time.Second
+}
+
+func (opts *TrustOptions) maxClockDrift() time.Duration {
+	return time.Duration(opts.MaxClockDrift) * time.Second
+}
+
+// ClientState is the light client state of a cometbft chain
+type ClientState struct {
+	// The cometbft chain-id of this chain
+	ChainID      string
+	LatestHeight int64
+}
+
+func (this *ClientState) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.ChainID)
+	sink.WriteInt64(this.LatestHeight)
+}
+
+func (this *ClientState) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.ChainID, eof = source.NextString()
+	if eof {
+		return fmt.Errorf("deserialize ChainID of ClientState failed")
+	}
+	this.LatestHeight, eof = source.NextInt64()
+	if eof {
+		return fmt.Errorf("deserialize LatestHeight of ClientState failed")
+	}
+	return nil
+}
+
+// ConsensusState is what poly keeps of a verified header
+type ConsensusState struct {
+	Height int64
+	// Time of the header in unix nanoseconds
+	Time               int64
+	BlockHash          []byte
+	AppHash            []byte
+	ValidatorsHash     []byte
+	NextValidatorsHash []byte
+}
+
+func NewConsensusState(header *types.Header) *ConsensusState {
+	return &ConsensusState{
+		Height:             header.Height,
+		Time:               header.Time.UnixNano(),
+		BlockHash:          header.Hash(),
+		AppHash:            header.AppHash,
+		ValidatorsHash:     header.ValidatorsHash,
+		NextValidatorsHash: header.NextValidatorsHash,
+	}
+}
+
+func (this *ConsensusState) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteInt64(this.Height)
+	sink.WriteInt64(this.Time)
+	sink.WriteVarBytes(this.BlockHash)
+	sink.WriteVarBytes(this.AppHash)
+	sink.WriteVarBytes(this.ValidatorsHash)
+	sink.WriteVarBytes(this.NextValidatorsHash)
+}
+
+func (this *ConsensusState) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Height, eof = source.NextInt64()
+	if eof {
+		return fmt.Errorf("deserialize Height of ConsensusState failed")
+	}
+	this.Time, eof = source.NextInt64()
+	if eof {
+		return fmt.Errorf("deserialize Time of ConsensusState failed")
+	}
+	this.BlockHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize BlockHash of ConsensusState failed")
+	}
+	this.AppHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize AppHash of ConsensusState failed")
+	}
+	this.ValidatorsHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize ValidatorsHash of ConsensusState failed")
+	}
+	this.NextValidatorsHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize NextValidatorsHash of ConsensusState failed")
+	}
+	return nil
+}
+
+// LightBlockUpdate is a protobuf encoded light block to be verified against the consensus state
+// at TrustedHeight. TrustedValidators, the protobuf encoded validators hashing to the NextValidatorsHash
+// of the trusted consensus state, are only needed if the light block is not adjacent to the trusted one.
+type LightBlockUpdate struct {
+	TrustedHeight     int64
+	LightBlock        []byte
+	TrustedValidators []byte
+}
+
+func (this *LightBlockUpdate) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteInt64(this.TrustedHeight)
+	sink.WriteVarBytes(this.LightBlock)
+	sink.WriteVarBytes(this.TrustedValidators)
+}
+
+func (this *LightBlockUpdate) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.TrustedHeight, eof = source.NextInt64()
+	if eof {
+		return fmt.Errorf("deserialize TrustedHeight of LightBlockUpdate failed")
+	}
+	this.LightBlock, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize LightBlock of LightBlockUpdate failed")
+	}
+	this.TrustedValidators, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize TrustedValidators of LightBlockUpdate failed")
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cometbft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	tmproto "github.com/switcheo/tendermint/proto/tendermint/types"
+	"github.com/switcheo/tendermint/types"
+)
+
+func GetTrustOptions(native *native.NativeService, chainID uint64) (*TrustOptions, error) {
+	sideChain, err := side_chain_manager.GetSideChain(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("GetTrustOptions, get side chain error: %v", err)
+	}
+	if sideChain == nil {
+		return nil, fmt.Errorf("GetTrustOptions, side chain %d is not registered", chainID)
+	}
+	opts := new(TrustOptions)
+	if err := json.Unmarshal(sideChain.ExtraInfo, opts); err != nil {
+		return nil, fmt.Errorf("GetTrustOptions, unmarshal extra info error: %v", err)
+	}
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("GetTrustOptions, invalid trust options: %v", err)
+	}
+	return opts, nil
+}
+
+func GetClientState(native *native.NativeService, chainID uint64) (*ClientState, error) {
+	val, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.CLIENT_STATE), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("GetClientState, get from cache error: %v", err)
+	}
+	if val == nil {
+		return nil, nil
+	}
+	raw, err := cstates.GetValueFromRawStorageItem(val)
+	if err != nil {
+		return nil, fmt.Errorf("GetClientState, deserialize from raw storage item error: %v", err)
+	}
+	state := new(ClientState)
+	if err := state.Deserialization(common.NewZeroCopySource(raw)); err != nil {
+		return nil, fmt.Errorf("GetClientState, deserialize client state error: %v", err)
+	}
+	return state, nil
+}
+
+func putClientState(native *native.NativeService, chainID uint64, state *ClientState) {
+	sink := common.NewZeroCopySink(nil)
+	state.Serialization(sink)
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.CLIENT_STATE), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func GetConsensusState(native *native.NativeService, chainID uint64, height int64) (*ConsensusState, error) {
+	val, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.CONSENSUS_STATE), utils.GetUint64Bytes(chainID),
+			utils.GetUint64Bytes(uint64(height))))
+	if err != nil {
+		return nil, fmt.Errorf("GetConsensusState, get from cache error: %v", err)
+	}
+	if val == nil {
+		return nil, nil
+	}
+	raw, err := cstates.GetValueFromRawStorageItem(val)
+	if err != nil {
+		return nil, fmt.Errorf("GetConsensusState, deserialize from raw storage item error: %v", err)
+	}
+	state := new(ConsensusState)
+	if err := state.Deserialization(common.NewZeroCopySource(raw)); err != nil {
+		return nil, fmt.Errorf("GetConsensusState, deserialize consensus state error: %v", err)
+	}
+	return state, nil
+}
+
+func putConsensusState(native *native.NativeService, chainID uint64, state *ConsensusState) {
+	sink := common.NewZeroCopySink(nil)
+	state.Serialization(sink)
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.CONSENSUS_STATE), utils.GetUint64Bytes(chainID),
+			utils.GetUint64Bytes(uint64(state.Height))),
+		cstates.GenRawStorageItem(sink.Bytes()))
+}
+
+func decodeLightBlock(raw []byte) (*types.LightBlock, error) {
+	pb := new(tmproto.LightBlock)
+	if err := pb.Unmarshal(raw); err != nil {
+		return nil, fmt.Errorf("unmarshal light block error: %v", err)
+	}
+	lb, err := types.LightBlockFromProto(pb)
+	if err != nil {
+		return nil, fmt.Errorf("light block from proto error: %v", err)
+	}
+	return lb, nil
+}
+
+func decodeValidatorSet(raw []byte) (*types.ValidatorSet, error) {
+	pb := new(tmproto.ValidatorSet)
+	if err := pb.Unmarshal(raw); err != nil {
+		return nil, fmt.Errorf("unmarshal validator set error: %v", err)
+	}
+	vals, err := types.ValidatorSetFromProto(pb)
+	if err != nil {
+		return nil, fmt.Errorf("validator set from proto error: %v", err)
+	}
+	return vals, nil
+}
+
+// storeLightBlock saves the consensus state of a verified light block and moves the client forward
+func storeLightBlock(native *native.NativeService, chainID uint64, client *ClientState, cs *ConsensusState) {
+	putConsensusState(native, chainID, cs)
+	if cs.Height > client.LatestHeight {
+		client.LatestHeight = cs.Height
+		putClientState(native, chainID, client)
+	}
+	hscommon.NotifyPutHeader(native, chainID, uint64(cs.Height), fmt.Sprintf("%X", cs.BlockHash))
+}
+
+func expired(trusted *ConsensusState, opts *TrustOptions, now time.Time) bool {
+	return !time.Unix(0, trusted.Time).Add(opts.trustingPeriod()).After(now)
+}
+
+// VerifyLightBlock verifies the light block in update against the stored consensus state at
+// update.TrustedHeight with the rules of the cometbft light client: adjacent blocks must be signed
+// by the trusted next validators, non-adjacent ones need at least trust level of the trusted next
+// validators given in update.TrustedValidators. Either way +2/3 of the block's own validators must have signed it. The consensus
+// state of the verified block is returned and stored if its height is not synced yet, the returned bool tells
+// whether it is newly stored.
+func VerifyLightBlock(native *native.NativeService, chainID uint64, update *LightBlockUpdate) (*ConsensusState, bool, error) {
+	client, err := GetClientState(native, chainID)
+	if err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, %v", err)
+	}
+	if client == nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, genesis header of chain %d is not synced", chainID)
+	}
+	opts, err := GetTrustOptions(native, chainID)
+	if err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, %v", err)
+	}
+	trusted, err := GetConsensusState(native, chainID, update.TrustedHeight)
+	if err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, %v", err)
+	}
+	if trusted == nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, no consensus state at trusted height %d", update.TrustedHeight)
+	}
+	lb, err := decodeLightBlock(update.LightBlock)
+	if err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, %v", err)
+	}
+	if err := lb.ValidateBasic(client.ChainID); err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, validate light block error: %v", err)
+	}
+
+	now := time.Unix(int64(native.GetTime()), 0)
+	if expired(trusted, opts, now) {
+		return nil, false, fmt.Errorf("VerifyLightBlock, trusted header at height %d is expired", trusted.Height)
+	}
+	if lb.Height <= trusted.Height {
+		return nil, false, fmt.Errorf("VerifyLightBlock, new header height %d is not greater than trusted height %d",
+			lb.Height, trusted.Height)
+	}
+	if lb.Time.UnixNano() <= trusted.Time {
+		return nil, false, fmt.Errorf("VerifyLightBlock, new header time %v is not after trusted header time %v",
+			lb.Time, time.Unix(0, trusted.Time))
+	}
+	if !lb.Time.Before(now.Add(opts.maxClockDrift())) {
+		return nil, false, fmt.Errorf("VerifyLightBlock, new header has a time from the future %v (now: %v)", lb.Time, now)
+	}
+
+	if lb.Height == trusted.Height+1 {
+		if !bytes.Equal(lb.ValidatorsHash, trusted.NextValidatorsHash) {
+			return nil, false, fmt.Errorf("VerifyLightBlock, validators hash %X of adjacent header not match trusted "+
+				"next validators hash %X", lb.ValidatorsHash, trusted.NextValidatorsHash)
+		}
+	} else {
+		if len(update.TrustedValidators) == 0 {
+			return nil, false, fmt.Errorf("VerifyLightBlock, trusted validators are required for non-adjacent header")
+		}
+		trustedVals, err := decodeValidatorSet(update.TrustedValidators)
+		if err != nil {
+			return nil, false, fmt.Errorf("VerifyLightBlock, %v", err)
+		}
+		// same as cometbft VerifyNonAdjacent, trust is extended from the next validators of trusted header
+		if hash := trustedVals.Hash(); !bytes.Equal(hash, trusted.NextValidatorsHash) {
+			return nil, false, fmt.Errorf("VerifyLightBlock, trusted validators hash %X not match trusted next "+
+				"validators hash %X", hash, trusted.NextValidatorsHash)
+		}
+		if err := trustedVals.VerifyCommitLightTrusting(client.ChainID, lb.Commit, opts.TrustLevel); err != nil {
+			return nil, false, fmt.Errorf("VerifyLightBlock, not enough trusted validators signed: %v", err)
+		}
+	}
+	// check this in the end since the new validator set may be intentionally made large
+	if err := lb.ValidatorSet.VerifyCommitLight(client.ChainID, lb.Commit.BlockID, lb.Height, lb.Commit); err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, verify commit error: %v", err)
+	}
+
+	cs := NewConsensusState(lb.Header)
+	exist, err := GetConsensusState(native, chainID, cs.Height)
+	if err != nil {
+		return nil, false, fmt.Errorf("VerifyLightBlock, %v", err)
+	}
+	if exist != nil && !bytes.Equal(exist.BlockHash, cs.BlockHash) {
+		return nil, false, fmt.Errorf("VerifyLightBlock, conflicting header at height %d: %X, stored: %X",
+			cs.Height, cs.BlockHash, exist.BlockHash)
+	}
+	if exist != nil {
+		return cs, false, nil
+	}
+	storeLightBlock(native, chainID, client, cs)
+	return cs, true, nil
+}
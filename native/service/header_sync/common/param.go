@@ -44,6 +44,10 @@ const (
 	SYNC_HEADER_NAME            = "syncHeader"
 	SYNC_CROSSCHAIN_MSG         = "syncCrossChainMsg"
 	POLYGON_SPAN                = "polygonSpan"
+	CLIENT_STATE                = "clientState"
+	CONSENSUS_STATE             = "consensusState"
+	STAKE_TABLE                 = "stakeTable"
+	BANK_HASH                   = "bankHash"
 )
 
 const (
@@ -131,6 +131,7 @@ func (this *CosmosHandler) SyncBlockHeader(native *native.NativeService) error {
 		return fmt.Errorf("no header you commited is useful")
 	}
 	PutEpochSwitchInfo(native, params.ChainID, info)
+	native.AddSyncedHeaders(uint64(cnt))
 	return nil
 }
 
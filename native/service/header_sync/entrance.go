@@ -22,10 +22,12 @@ import (
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
 	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
 	"github.com/polynetwork/poly/native/service/header_sync/bsc"
 	"github.com/polynetwork/poly/native/service/header_sync/btc"
 	"github.com/polynetwork/poly/native/service/header_sync/bytom"
+	"github.com/polynetwork/poly/native/service/header_sync/cometbft"
 	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
 	"github.com/polynetwork/poly/native/service/header_sync/cosmos"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
@@ -41,6 +43,7 @@ import (
 	"github.com/polynetwork/poly/native/service/header_sync/pixiechain"
 	"github.com/polynetwork/poly/native/service/header_sync/polygon"
 	"github.com/polynetwork/poly/native/service/header_sync/quorum"
+	"github.com/polynetwork/poly/native/service/header_sync/solana"
 	"github.com/polynetwork/poly/native/service/header_sync/starcoin"
 	"github.com/polynetwork/poly/native/service/header_sync/zilliqa"
 	"github.com/polynetwork/poly/native/service/header_sync/zilliqalegacy"
@@ -98,6 +101,10 @@ func GetChainHandler(router uint64) (hscommon.HeaderSyncHandler, error) {
 		return harmony.NewHandler(), nil
 	case utils.BYTOM_ROUTER:
 		return bytom.NewHandler(), nil
+	case utils.COMETBFT_ROUTER:
+		return cometbft.NewHandler(), nil
+	case utils.SOLANA_ROUTER:
+		return solana.NewHandler(), nil
 	default:
 		return nil, fmt.Errorf("not a supported router:%d", router)
 	}
@@ -164,6 +171,10 @@ func SyncBlockHeader(native *native.NativeService) ([]byte, error) {
 	if err != nil {
 		return utils.BYTE_FALSE, err
 	}
+	err = relayer_manager.RecordSyncedHeaders(native, params.Address, chainID, native.GetSyncedHeaders())
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("SyncBlockHeader, relayer_manager.RecordSyncedHeaders error: %v", err)
+	}
 	return utils.BYTE_TRUE, nil
 }
 
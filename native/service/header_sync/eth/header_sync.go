@@ -192,6 +192,7 @@ func (this *ETHHandler) SyncBlockHeader(native *native.NativeService) error {
 		if err != nil {
 			return fmt.Errorf("SyncGenesisHeader, put blockHeader error: %v, header: %s", err, string(v))
 		}
+		native.AddSyncedHeaders(1)
 		// get current header of main
 		currentHeader, currentDifficultySum, err := GetCurrentHeader(native, headerParams.ChainID)
 		if err != nil {
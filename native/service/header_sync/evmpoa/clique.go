@@ -0,0 +1,415 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evmpoa
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+)
+
+var (
+	nonceAuthVote = hexutil.MustDecode("0xffffffffffffffff") // Magic nonce number to vote on adding a new signer
+	nonceDropVote = hexutil.MustDecode("0x0000000000000000") // Magic nonce number to vote on removing a signer.
+)
+
+var (
+	// errInvalidVote is returned if a nonce value is something else that the two
+	// allowed constants of 0x00..0 or 0xff..f.
+	errInvalidVote = errors.New("vote nonce not 0x00..0 or 0xff..f")
+
+	// errInvalidCheckpointVote is returned if a checkpoint/epoch transition block
+	// has a vote nonce set to non-zeroes.
+	errInvalidCheckpointVote = errors.New("vote nonce in checkpoint block non-zero")
+
+	// errExtraSigners is returned if non-checkpoint block contain signer data in
+	// their extra-data fields.
+	errExtraSigners = errors.New("non-checkpoint block contains extra signer list")
+
+	// errInvalidCheckpointSigners is returned if a checkpoint block contains an
+	// invalid list of signers (i.e. non divisible by 20 bytes).
+	errInvalidCheckpointSigners = errors.New("invalid signer list on checkpoint block")
+
+	// errMismatchingCheckpointSigners is returned if a checkpoint block contains a
+	// list of signers different than the one the local node calculated.
+	errMismatchingCheckpointSigners = errors.New("mismatching signer list on checkpoint block")
+
+	// errInvalidCheckpointBeneficiary is returned if a checkpoint/epoch transition
+	// block has a beneficiary set to non-zeroes.
+	errInvalidCheckpointBeneficiary = errors.New("beneficiary in checkpoint block non-zero")
+)
+
+func (e *Engine) verifyCliqueGenesis(header *eth.Header, ctx *Context) error {
+	if ctx.ExtraInfo.Epoch == 0 {
+		return errors.New("invalid epoch")
+	}
+	if ctx.ExtraInfo.Period == 0 {
+		return errors.New("invalid period")
+	}
+	if header.Number.Uint64()%ctx.ExtraInfo.Epoch != 0 {
+		return fmt.Errorf("invalid genesis height:%d", header.Number.Uint64())
+	}
+	signersBytes := len(header.Extra) - extraVanity - extraSeal
+	if signersBytes <= 0 || signersBytes%ecommon.AddressLength != 0 {
+		return fmt.Errorf("invalid signer list, signersBytes:%d", signersBytes)
+	}
+	return nil
+}
+
+// verifyCliqueFields verifies the votes and the signer list of header
+func (e *Engine) verifyCliqueFields(header *eth.Header, ctx *Context) error {
+	// Checkpoint blocks need to enforce zero beneficiary
+	checkpoint := header.Number.Uint64()%ctx.ExtraInfo.Epoch == 0
+	if checkpoint && header.Coinbase != (ecommon.Address{}) {
+		return errInvalidCheckpointBeneficiary
+	}
+
+	// Nonces must be 0x00..0 or 0xff..f, zeroes enforced on checkpoints
+	if !bytes.Equal(header.Nonce[:], nonceAuthVote) && !bytes.Equal(header.Nonce[:], nonceDropVote) {
+		return errInvalidVote
+	}
+	if checkpoint && !bytes.Equal(header.Nonce[:], nonceDropVote) {
+		return errInvalidCheckpointVote
+	}
+
+	// Ensure that the extra-data contains a signer list on checkpoint, but none otherwise
+	signersBytes := len(header.Extra) - extraVanity - extraSeal
+	if !checkpoint && signersBytes != 0 {
+		return errExtraSigners
+	}
+	if checkpoint && (signersBytes == 0 || signersBytes%ecommon.AddressLength != 0) {
+		return errInvalidCheckpointSigners
+	}
+	return nil
+}
+
+// verifyVotes verifies header sealed by signer against the signers voted in as of its parent
+func (e *Engine) verifyVotes(native *native.NativeService, header *eth.Header, signer ecommon.Address,
+	ctx *Context) (*HeaderWithDifficultySum, error) {
+	number := header.Number.Uint64()
+	snap, lastSeenHeight, err := e.snapshot(native, number-1, header.ParentHash, signer, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler SyncBlockHeader, snapshot err: %v", e.cfg.Name, err)
+	}
+
+	signers := snap.signers()
+	checkpoint := number%ctx.ExtraInfo.Epoch == 0
+	if checkpoint {
+		list := make([]byte, 0, len(signers)*ecommon.AddressLength)
+		for _, signer := range signers {
+			list = append(list, signer[:]...)
+		}
+		if !bytes.Equal(header.Extra[extraVanity:len(header.Extra)-extraSeal], list) {
+			return nil, errMismatchingCheckpointSigners
+		}
+	}
+
+	err = e.verifyTurn(header, signer, signers, int64(lastSeenHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	// link to the last checkpoint or vote header, which the snapshot is applied from
+	headerWithSum := &HeaderWithDifficultySum{Header: header}
+	if !checkpoint {
+		parent, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("%s Handler SyncBlockHeader, getHeader err: %v", e.cfg.Name, err)
+		}
+		if parent.EpochParentHash == nil || parent.Header.Coinbase != (ecommon.Address{}) {
+			hash := parent.Header.Hash()
+			headerWithSum.EpochParentHash = &hash
+		} else {
+			headerWithSum.EpochParentHash = parent.EpochParentHash
+		}
+	}
+	return headerWithSum, nil
+}
+
+// snapshot returns the signers voted in as of the header number with hash, and the height targetSigner
+// signed last at within the recent limit
+func (e *Engine) snapshot(native *native.NativeService, number uint64, hash ecommon.Hash,
+	targetSigner ecommon.Address, ctx *Context) (snap *snapshot, lastSeenHeight uint64, err error) {
+	var (
+		headerWSs []*HeaderWithDifficultySum
+		headerWS  *HeaderWithDifficultySum
+		signer    ecommon.Address
+	)
+
+	startHash := hash
+	genesis, err := e.getGenesis(native, ctx.ChainID)
+	if err != nil {
+		err = fmt.Errorf("%s Handler snapshot getGenesis error: %v", e.cfg.Name, err)
+		return
+	}
+	if genesis == nil {
+		err = fmt.Errorf("%s Handler snapshot genesis not set", e.cfg.Name)
+		return
+	}
+	if number < genesis.Header.Number.Uint64() {
+		err = fmt.Errorf("%s Handler snapshot header before genesis is not allowed", e.cfg.Name)
+		return
+	}
+
+	for snap == nil {
+		headerWS, err = e.GetHeader(native, hash, ctx.ChainID)
+		if err != nil {
+			err = fmt.Errorf("%s Handler snapshot getHeader error: %v", e.cfg.Name, err)
+			return
+		}
+
+		// checkpoint headers list the signers
+		if headerWS.EpochParentHash == nil {
+			var signers []ecommon.Address
+			signers, err = ParseValidators(headerWS.Header.Extra[extraVanity : len(headerWS.Header.Extra)-extraSeal])
+			if err != nil {
+				return
+			}
+			signer, err = e.ecrecover(headerWS.Header, ctx.ExtraInfo.ChainID)
+			if err != nil {
+				err = fmt.Errorf("%s Handler snapshot ecrecover error: %v", e.cfg.Name, err)
+				return
+			}
+			if targetSigner == signer {
+				lastSeenHeight = headerWS.Header.Number.Uint64()
+			}
+			snap = newSnapshot(headerWS.Header.Number.Uint64(), hash, signers)
+			break
+		}
+
+		if headerWS.Header.Coinbase != (ecommon.Address{}) {
+			headerWSs = append(headerWSs, headerWS)
+		}
+		hash = *headerWS.EpochParentHash
+	}
+
+	// Previous snapshot found, apply any pending headers on top of it
+	for i := 0; i < len(headerWSs)/2; i++ {
+		headerWSs[i], headerWSs[len(headerWSs)-1-i] = headerWSs[len(headerWSs)-1-i], headerWSs[i]
+	}
+	for _, headerWS := range headerWSs {
+		signer, err = e.ecrecover(headerWS.Header, ctx.ExtraInfo.ChainID)
+		if err != nil {
+			err = fmt.Errorf("%s Handler snapshot ecrecover error: %v", e.cfg.Name, err)
+			return
+		}
+		if targetSigner == signer {
+			lastSeenHeight = headerWS.Header.Number.Uint64()
+		}
+		if err = snap.apply(headerWS.Header, signer); err != nil {
+			err = fmt.Errorf("%s Handler snapshot apply error: %v", e.cfg.Name, err)
+			return
+		}
+	}
+	if lastSeenHeight > 0 {
+		return
+	}
+
+	// the headers without votes are skipped above, search the recent ones
+	for i := 0; i < len(snap.Signers)/2; i++ {
+		headerWS, err = e.GetHeader(native, startHash, ctx.ChainID)
+		if err != nil {
+			err = fmt.Errorf("%s Handler snapshot getHeader error: %v", e.cfg.Name, err)
+			return
+		}
+		signer, err = e.ecrecover(headerWS.Header, ctx.ExtraInfo.ChainID)
+		if err != nil {
+			err = fmt.Errorf("%s Handler snapshot ecrecover error: %v", e.cfg.Name, err)
+			return
+		}
+		if targetSigner == signer {
+			lastSeenHeight = headerWS.Header.Number.Uint64()
+			break
+		}
+		number, startHash = number-1, headerWS.Header.ParentHash
+		if number < genesis.Header.Number.Uint64() {
+			break
+		}
+	}
+	return
+}
+
+// tally is a simple vote tally to keep the current score of votes. Votes that
+// go against the proposal aren't counted since it's equivalent to not voting.
+type tally struct {
+	Authorize bool // Whether the vote is about authorizing or kicking someone
+	Votes     int  // Number of votes until now wanting to pass the proposal
+}
+
+// vote represents a single vote that an authorized signer made to modify the
+// list of authorizations.
+type vote struct {
+	Signer    ecommon.Address // Authorized signer that cast this vote
+	Block     uint64          // Block number the vote was cast in (expire old votes)
+	Address   ecommon.Address // Account being voted on to change its authorization
+	Authorize bool            // Whether to authorize or deauthorize the voted account
+}
+
+// snapshot is the state of the clique votes at a given point in time.
+type snapshot struct {
+	Number  uint64                       // Block number where the snapshot was created
+	Hash    ecommon.Hash                 // Block hash where the snapshot was created
+	Signers map[ecommon.Address]struct{} // Set of authorized signers at this moment
+	Votes   []*vote                      // List of votes cast in chronological order
+	Tally   map[ecommon.Address]tally    // Current vote tally to avoid recalculating
+}
+
+func newSnapshot(number uint64, hash ecommon.Hash, signers []ecommon.Address) *snapshot {
+	snap := &snapshot{
+		Number:  number,
+		Hash:    hash,
+		Signers: make(map[ecommon.Address]struct{}),
+		Tally:   make(map[ecommon.Address]tally),
+	}
+	for _, signer := range signers {
+		snap.Signers[signer] = struct{}{}
+	}
+	return snap
+}
+
+// validVote returns whether it makes sense to cast the specified vote in the
+// given snapshot context (e.g. don't try to add an already authorized signer).
+func (s *snapshot) validVote(address ecommon.Address, authorize bool) bool {
+	_, signer := s.Signers[address]
+	return (signer && !authorize) || (!signer && authorize)
+}
+
+// cast adds a new vote into the tally.
+func (s *snapshot) cast(address ecommon.Address, authorize bool) bool {
+	// Ensure the vote is meaningful
+	if !s.validVote(address, authorize) {
+		return false
+	}
+	// Cast the vote into an existing or new tally
+	if old, ok := s.Tally[address]; ok {
+		old.Votes++
+		s.Tally[address] = old
+	} else {
+		s.Tally[address] = tally{Authorize: authorize, Votes: 1}
+	}
+	return true
+}
+
+// uncast removes a previously cast vote from the tally.
+func (s *snapshot) uncast(address ecommon.Address, authorize bool) bool {
+	// If there's no tally, it's a dangling vote, just drop
+	t, ok := s.Tally[address]
+	if !ok {
+		return false
+	}
+	// Ensure we only revert counted votes
+	if t.Authorize != authorize {
+		return false
+	}
+	// Otherwise revert the vote
+	if t.Votes > 1 {
+		t.Votes--
+		s.Tally[address] = t
+	} else {
+		delete(s.Tally, address)
+	}
+	return true
+}
+
+// apply updates the snapshot with the vote of header sealed by signer
+func (s *snapshot) apply(header *eth.Header, signer ecommon.Address) error {
+	number := header.Number.Uint64()
+	if _, ok := s.Signers[signer]; !ok {
+		return fmt.Errorf("unauthorized signer for block %d", number)
+	}
+
+	// Header authorized, discard any previous votes from the signer
+	for i, vote := range s.Votes {
+		if vote.Signer == signer && vote.Address == header.Coinbase {
+			// Uncast the vote from the cached tally
+			s.uncast(vote.Address, vote.Authorize)
+
+			// Uncast the vote from the chronological list
+			s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+			break // only one vote allowed
+		}
+	}
+	// Tally up the new vote from the signer
+	var authorize bool
+	switch {
+	case bytes.Equal(header.Nonce[:], nonceAuthVote):
+		authorize = true
+	case bytes.Equal(header.Nonce[:], nonceDropVote):
+		authorize = false
+	default:
+		return errInvalidVote
+	}
+	if s.cast(header.Coinbase, authorize) {
+		s.Votes = append(s.Votes, &vote{
+			Signer:    signer,
+			Block:     number,
+			Address:   header.Coinbase,
+			Authorize: authorize,
+		})
+	}
+	// If the vote passed, update the list of signers
+	if t := s.Tally[header.Coinbase]; t.Votes > len(s.Signers)/2 {
+		if t.Authorize {
+			s.Signers[header.Coinbase] = struct{}{}
+		} else {
+			delete(s.Signers, header.Coinbase)
+
+			// Discard any previous votes the deauthorized signer cast
+			for i := 0; i < len(s.Votes); i++ {
+				if s.Votes[i].Signer == header.Coinbase {
+					// Uncast the vote from the cached tally
+					s.uncast(s.Votes[i].Address, s.Votes[i].Authorize)
+
+					// Uncast the vote from the chronological list
+					s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+
+					i--
+				}
+			}
+		}
+		// Discard any previous votes around the just changed account
+		for i := 0; i < len(s.Votes); i++ {
+			if s.Votes[i].Address == header.Coinbase {
+				s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+				i--
+			}
+		}
+		delete(s.Tally, header.Coinbase)
+	}
+
+	s.Number = number
+	s.Hash = header.Hash()
+	return nil
+}
+
+// signers retrieves the list of authorized signers in ascending order.
+func (s *snapshot) signers() []ecommon.Address {
+	sigs := make([]ecommon.Address, 0, len(s.Signers))
+	for sig := range s.Signers {
+		sigs = append(sigs, sig)
+	}
+	sort.Slice(sigs, func(i, j int) bool { return bytes.Compare(sigs[i][:], sigs[j][:]) < 0 })
+	return sigs
+}
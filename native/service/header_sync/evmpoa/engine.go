@@ -0,0 +1,386 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package evmpoa is the header sync engine shared by the EVM chains sealed by a rotating set of
+// validators, e.g. heco, hsc, pixiechain, bytom, bsc and msc. The chains differ only in the Config they use.
+package evmpoa
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// Config describes how the headers of a chain are verified
+type Config struct {
+	// Name of the chain, used in errors
+	Name string
+	// Seal recovers the producer of a header from its signature
+	Seal Seal
+	// Epoch picks the validators in turn for a header
+	Epoch EpochRule
+	// CheckPeriod requires headers to be at least ExtraInfo.Period seconds apart
+	CheckPeriod bool
+	// PrepareHeader adjusts a header just decoded from the relayer, e.g. drops the fields unknown to the chain,
+	// optional
+	PrepareHeader func(native *native.NativeService, header *eth.Header)
+	// VerifyFork verifies the fork dependent fields of a header against its parent, e.g. gas limit and base fee,
+	// optional
+	VerifyFork func(native *native.NativeService, parent, header *eth.Header) error
+	// NoCheckSig skips the seal verification once it points to true, only for testing
+	NoCheckSig *bool
+	// MockSigner is taken as the producer of headers without verifying the seal once it points to a non-zero
+	// address, only for testing
+	MockSigner *ecommon.Address
+	// Clique makes the validators voted in and out by headers and listed by a checkpoint header every
+	// ExtraInfo.Epoch headers as clique does, instead of carried by epoch headers. The genesis is a bare
+	// header then, and Epoch, EpochValidators and Finality are not used
+	Clique bool
+	// EpochValidators returns the validators starting an epoch from header, or nil if header starts none,
+	// optional, ListedValidators by default
+	EpochValidators func(ctx *Context, header *eth.Header) (*HeightAndValidators, error)
+	// Finality returns the justified and finalized blocks as of header, e.g. from the votes it carries,
+	// where phv and pphv are the validators of the last epochs, optional. A heavier fork does not become
+	// canonical if it leaves out the block finalized as of the canonical head
+	Finality func(ctx *Context, header *eth.Header, parent *HeaderWithDifficultySum,
+		phv, pphv *HeightAndValidators) (justified, finalized *BlockID, err error)
+	// ExtraInfo makes the chain specific extra info the side chain one is also decoded into as Context.Extra,
+	// optional
+	ExtraInfo func() interface{}
+}
+
+// Engine syncs the headers of the chains described by its Config
+type Engine struct {
+	cfg *Config
+}
+
+// NewEngine ...
+func NewEngine(cfg *Config) *Engine {
+	return &Engine{cfg: cfg}
+}
+
+// GenesisHeader ...
+type GenesisHeader struct {
+	Header         eth.Header
+	PrevValidators []HeightAndValidators
+}
+
+// ExtraInfo ...
+type ExtraInfo struct {
+	ChainID *big.Int // chainId of the side chain, used in the seal of parlia
+	Period  uint64
+	Epoch   uint64 `json:",omitempty"` // checkpoint interval of clique
+}
+
+// Context ...
+type Context struct {
+	ExtraInfo ExtraInfo
+	ChainID   uint64
+	// Extra is the side chain extra info decoded by Config.ExtraInfo
+	Extra interface{}
+}
+
+// HeaderWithChainID ...
+type HeaderWithChainID struct {
+	Header  *HeaderWithDifficultySum
+	ChainID uint64
+}
+
+// HeaderWithDifficultySum ...
+type HeaderWithDifficultySum struct {
+	Header        *eth.Header `json:"header"`
+	DifficultySum *big.Int    `json:"difficultySum"`
+	// EpochParentHash links to the last epoch header, or with Config.Clique, the last checkpoint or vote
+	// header, nil for those headers themselves
+	EpochParentHash *ecommon.Hash `json:"epochParentHash"`
+	Justified       *BlockID      `json:"justified,omitempty"`
+	Finalized       *BlockID      `json:"finalized,omitempty"`
+}
+
+// BlockID ...
+type BlockID struct {
+	Number uint64       `json:"number"`
+	Hash   ecommon.Hash `json:"hash"`
+}
+
+func (e *Engine) newContext(chainID uint64) *Context {
+	ctx := &Context{ChainID: chainID}
+	if e.cfg.ExtraInfo != nil {
+		ctx.Extra = e.cfg.ExtraInfo()
+	}
+	return ctx
+}
+
+func (e *Engine) decodeExtraInfo(ctx *Context, extraInfo []byte) error {
+	if err := json.Unmarshal(extraInfo, &ctx.ExtraInfo); err != nil {
+		return err
+	}
+	if ctx.Extra != nil {
+		return json.Unmarshal(extraInfo, ctx.Extra)
+	}
+	return nil
+}
+
+func (e *Engine) prepareHeader(native *native.NativeService, header *eth.Header) {
+	if e.cfg.PrepareHeader != nil {
+		e.cfg.PrepareHeader(native, header)
+	}
+}
+
+// SyncGenesisHeader ...
+func (e *Engine) SyncGenesisHeader(native *native.NativeService) (err error) {
+	params := new(scom.SyncGenesisHeaderParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, contract params deserialize error: %v", e.cfg.Name, err)
+	}
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, get current consensus operator address error: %v",
+			e.cfg.Name, err)
+	}
+
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, checkWitness error: %v", e.cfg.Name, err)
+	}
+
+	// can only store once
+	genesisStored, err := e.getGenesis(native, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, isGenesisStored error: %v", e.cfg.Name, err)
+	}
+	if genesisStored != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, genesis had been initialized", e.cfg.Name)
+	}
+
+	ctx := e.newContext(params.ChainID)
+	side, err := side_chain_manager.GetSideChain(native, params.ChainID)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, GetSideChain error: %v", e.cfg.Name, err)
+	}
+	if side != nil && len(side.ExtraInfo) > 0 {
+		err = e.decodeExtraInfo(ctx, side.ExtraInfo)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncGenesisHeader, ExtraInfo Unmarshal error: %v", e.cfg.Name, err)
+		}
+	}
+
+	var genesis GenesisHeader
+	if e.cfg.Clique {
+		err = json.Unmarshal(params.GenesisHeader, &genesis.Header)
+	} else {
+		err = json.Unmarshal(params.GenesisHeader, &genesis)
+	}
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, deserialize GenesisHeader err: %v", e.cfg.Name, err)
+	}
+	e.prepareHeader(native, &genesis.Header)
+
+	if e.cfg.Clique {
+		err = e.verifyCliqueGenesis(&genesis.Header, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncGenesisHeader, %v", e.cfg.Name, err)
+		}
+	} else {
+		var hv *HeightAndValidators
+		hv, err = e.epochValidators(ctx, &genesis.Header)
+		if err != nil || hv == nil || len(hv.Validators) == 0 {
+			return fmt.Errorf("invalid signer list, signersBytes:%d", len(genesis.Header.Extra)-extraVanity-extraSeal)
+		}
+
+		if len(genesis.PrevValidators) != 1 {
+			return fmt.Errorf("invalid PrevValidators")
+		}
+		if genesis.Header.Number.Cmp(genesis.PrevValidators[0].Height) <= 0 {
+			return fmt.Errorf("invalid height orders")
+		}
+		genesis.PrevValidators = append([]HeightAndValidators{*hv}, genesis.PrevValidators...)
+	}
+
+	err = e.storeGenesis(native, params.ChainID, &genesis)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncGenesisHeader, storeGenesis error: %v", e.cfg.Name, err)
+	}
+
+	return
+}
+
+// SyncBlockHeader ...
+func (e *Engine) SyncBlockHeader(native *native.NativeService) error {
+	headerParams := new(scom.SyncBlockHeaderParam)
+	if err := headerParams.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, contract params deserialize error: %v", e.cfg.Name, err)
+	}
+
+	side, err := side_chain_manager.GetSideChain(native, headerParams.ChainID)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, GetSideChain error: %v", e.cfg.Name, err)
+	}
+	if side == nil {
+		return fmt.Errorf("%s Hander SyncBlockHeader, GetSideChain info nil", e.cfg.Name)
+	}
+	ctx := e.newContext(headerParams.ChainID)
+	err = e.decodeExtraInfo(ctx, side.ExtraInfo)
+	if err != nil {
+		return fmt.Errorf("%s Handler SyncBlockHeader, ExtraInfo Unmarshal error: %v", e.cfg.Name, err)
+	}
+
+	for _, v := range headerParams.Headers {
+		var header eth.Header
+		err := json.Unmarshal(v, &header)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, deserialize header err: %v", e.cfg.Name, err)
+		}
+		e.prepareHeader(native, &header)
+		headerHash := header.Hash()
+
+		exist, err := e.isHeaderExist(native, headerHash, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, isHeaderExist headerHash err: %v", e.cfg.Name, err)
+		}
+		if exist {
+			log.Warnf("%s Handler SyncBlockHeader, header has exist. Header: %s", e.cfg.Name, string(v))
+			continue
+		}
+
+		parentExist, err := e.isHeaderExist(native, header.ParentHash, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, isHeaderExist ParentHash err: %v", e.cfg.Name, err)
+		}
+		if !parentExist {
+			log.Warnf("%s Handler SyncBlockHeader, parent header not exist. Header: %s", e.cfg.Name, string(v))
+			continue
+		}
+
+		signer, err := e.verifyHeader(native, &header, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, verifySignature err: %v", e.cfg.Name, err)
+		}
+
+		var headerWithSum *HeaderWithDifficultySum
+		if e.cfg.Clique {
+			headerWithSum, err = e.verifyVotes(native, &header, signer, ctx)
+		} else {
+			headerWithSum, err = e.verifyEpochs(native, &header, signer, ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = e.addHeader(native, headerWithSum, ctx)
+		if err != nil {
+			return fmt.Errorf("%s Handler SyncBlockHeader, addHeader err: %v", e.cfg.Name, err)
+		}
+		native.AddSyncedHeaders(1)
+
+		scom.NotifyPutHeader(native, headerParams.ChainID, header.Number.Uint64(), header.Hash().Hex())
+	}
+	return nil
+}
+
+// verifyEpochs verifies header sealed by signer against the validators of the last epochs
+func (e *Engine) verifyEpochs(native *native.NativeService, header *eth.Header, signer ecommon.Address,
+	ctx *Context) (*HeaderWithDifficultySum, error) {
+	// get prev epochs, also checking recent limit
+	phv, pphv, lastSeenHeight, err := e.getPrevHeightAndValidators(native, header, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler SyncBlockHeader, getPrevHeightAndValidators err: %v", e.cfg.Name, err)
+	}
+
+	hv, err := e.epochValidators(ctx, header)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler SyncBlockHeader, epochValidators err: %v", e.cfg.Name, err)
+	}
+	inTurnHV, err := e.cfg.Epoch(header, hv != nil, phv, pphv)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler SyncBlockHeader: %v", e.cfg.Name, err)
+	}
+
+	err = e.verifyTurn(header, signer, inTurnHV.Validators, lastSeenHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	headerWithSum := &HeaderWithDifficultySum{Header: header, EpochParentHash: phv.Hash}
+	if e.cfg.Finality != nil {
+		parent, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("%s Handler SyncBlockHeader, getHeader err: %v", e.cfg.Name, err)
+		}
+		headerWithSum.Justified, headerWithSum.Finalized, err = e.cfg.Finality(ctx, header, parent, phv, pphv)
+		if err != nil {
+			return nil, fmt.Errorf("%s Handler SyncBlockHeader, Finality err: %v", e.cfg.Name, err)
+		}
+	}
+	return headerWithSum, nil
+}
+
+// verifyTurn verifies signer is one of validators, not signed within the recent limit since lastSeenHeight,
+// and the difficulty of header matches if it is in turn
+func (e *Engine) verifyTurn(header *eth.Header, signer ecommon.Address, validators []ecommon.Address,
+	lastSeenHeight int64) error {
+	if lastSeenHeight > 0 {
+		limit := int64(len(validators) / 2)
+		if header.Number.Int64() <= lastSeenHeight+limit {
+			return fmt.Errorf("%s Handler SyncBlockHeader, RecentlySigned, lastSeenHeight:%d currentHeight:%d #V:%d",
+				e.cfg.Name, lastSeenHeight, header.Number.Int64(), len(validators))
+		}
+	}
+
+	indexInTurn := int(header.Number.Uint64()) % len(validators)
+	if indexInTurn < 0 {
+		return fmt.Errorf("indexInTurn is negative:%d header.Number:%d", indexInTurn, header.Number.Int64())
+	}
+	valid := false
+	for idx, v := range validators {
+		if v == signer {
+			valid = true
+			if indexInTurn == idx {
+				if header.Difficulty.Cmp(diffInTurn) != 0 {
+					return fmt.Errorf("invalid difficulty, got %v expect %v index:%v", header.Difficulty.Int64(),
+						diffInTurn.Int64(), indexInTurn)
+				}
+			} else {
+				if header.Difficulty.Cmp(diffNoTurn) != 0 {
+					return fmt.Errorf("invalid difficulty, got %v expect %v index:%v", header.Difficulty.Int64(),
+						diffNoTurn.Int64(), indexInTurn)
+				}
+			}
+		}
+	}
+	if !valid {
+		return fmt.Errorf("%s Handler SyncBlockHeader, invalid signer", e.cfg.Name)
+	}
+	return nil
+}
+
+// SyncCrossChainMsg ...
+func (e *Engine) SyncCrossChainMsg(native *native.NativeService) error {
+	return nil
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evmpoa
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	ptypes "github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChainID = 7
+
+var acct = account.NewAccount("")
+
+func init() {
+	genesis.GenesisBookkeepers = []keypair.PublicKey{acct.PublicKey}
+}
+
+func newNative(args []byte, db *storage.CacheDB) *native.NativeService {
+	if db == nil {
+		store, _ := leveldbstore.NewMemLevelDBStore()
+		db = storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+		sink := common.NewZeroCopySink(nil)
+		view := &node_manager.GovernanceView{TxHash: common.UINT256_EMPTY}
+		view.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)),
+			states.GenRawStorageItem(sink.Bytes()))
+
+		peerPoolMap := &node_manager.PeerPoolMap{
+			PeerPoolMap: map[string]*node_manager.PeerPoolItem{
+				vconfig.PubkeyID(acct.PublicKey): {
+					Address:    acct.Address,
+					Status:     node_manager.ConsensusStatus,
+					PeerPubkey: vconfig.PubkeyID(acct.PublicKey),
+				},
+			},
+		}
+		sink.Reset()
+		peerPoolMap.Serialization(sink)
+		db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.PEER_POOL),
+			utils.GetUint32Bytes(0)), states.GenRawStorageItem(sink.Bytes()))
+	}
+	tx := &ptypes.Transaction{SignedAddr: []common.Address{acct.Address}}
+	ns, _ := native.NewNativeService(db, tx, 0, 0, common.Uint256{0}, 0, args, false)
+	return ns
+}
+
+type testChain struct {
+	t       *testing.T
+	engine  *Engine
+	keys    []*ecdsa.PrivateKey
+	extra   *ExtraInfo
+	db      *storage.CacheDB
+	genesis *eth.Header
+}
+
+func newTestChain(t *testing.T, cfg *Config) *testChain {
+	c := &testChain{t: t, engine: NewEngine(cfg), extra: &ExtraInfo{ChainID: big.NewInt(56), Period: 3}}
+	validators := make([]byte, 0)
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		c.keys = append(c.keys, key)
+		validators = append(validators, crypto.PubkeyToAddress(key.PublicKey).Bytes()...)
+	}
+	ns := newNative(nil, nil)
+	c.db = ns.GetCacheDB()
+	extraBytes, _ := json.Marshal(c.extra)
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: testChainID,
+		ExtraInfo: extraBytes}))
+
+	c.genesis = &eth.Header{
+		Number:     big.NewInt(200),
+		Difficulty: big.NewInt(2),
+		GasLimit:   8000000,
+		Time:       uint64(time.Now().Unix()) - 1000,
+		UncleHash:  uncleHash,
+		Extra:      append(append(make([]byte, extraVanity), validators...), make([]byte, extraSeal)...),
+	}
+	pvalidators, _ := ParseValidators(validators)
+	genesisBytes, _ := json.Marshal(&GenesisHeader{Header: *c.genesis, PrevValidators: []HeightAndValidators{
+		{Height: big.NewInt(0), Validators: pvalidators}}})
+	param := &scom.SyncGenesisHeaderParam{ChainID: testChainID, GenesisHeader: genesisBytes}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	assert.Nil(t, c.engine.SyncGenesisHeader(newNative(sink.Bytes(), c.db)))
+	return c
+}
+
+// makeHeader makes the child of parent signed in turn, or by key if not nil
+func (c *testChain) makeHeader(parent *eth.Header, key *ecdsa.PrivateKey) *eth.Header {
+	number := new(big.Int).Add(parent.Number, big.NewInt(1))
+	difficulty := diffInTurn
+	if key == nil {
+		key = c.keys[number.Uint64()%uint64(len(c.keys))]
+	} else {
+		difficulty = diffNoTurn
+	}
+	header := &eth.Header{
+		ParentHash: parent.Hash(),
+		Number:     number,
+		Coinbase:   crypto.PubkeyToAddress(key.PublicKey),
+		Difficulty: difficulty,
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + c.extra.Period,
+		UncleHash:  uncleHash,
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	sig, err := crypto.Sign(c.engine.cfg.Seal(header, c.extra.ChainID).Bytes(), key)
+	assert.Nil(c.t, err)
+	copy(header.Extra[extraVanity:], sig)
+	return header
+}
+
+func (c *testChain) sync(headers ...*eth.Header) error {
+	param := &scom.SyncBlockHeaderParam{ChainID: testChainID}
+	for _, h := range headers {
+		raw, _ := json.Marshal(h)
+		param.Headers = append(param.Headers, raw)
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return c.engine.SyncBlockHeader(newNative(sink.Bytes(), c.db))
+}
+
+func (c *testChain) height() uint64 {
+	height, err := c.engine.GetCanonicalHeight(newNative(nil, c.db), testChainID)
+	assert.Nil(c.t, err)
+	return height
+}
+
+func TestSyncBlockHeader(t *testing.T) {
+	for _, cfg := range []*Config{
+		{Name: "congress", Seal: CongressSeal, Epoch: CongressEpoch, CheckPeriod: true, VerifyFork: VerifyLegacyFork},
+		{Name: "parlia", Seal: ParliaSeal, Epoch: ParliaEpoch, PrepareHeader: DropBaseFee, VerifyFork: VerifyLegacyFork},
+	} {
+		c := newTestChain(t, cfg)
+		assert.Equal(t, uint64(200), c.height())
+
+		headers := []*eth.Header{c.makeHeader(c.genesis, nil)}
+		for i := 0; i < 4; i++ {
+			headers = append(headers, c.makeHeader(headers[len(headers)-1], nil))
+		}
+		assert.Nil(t, c.sync(headers...), cfg.Name)
+		assert.Equal(t, uint64(205), c.height(), cfg.Name)
+		header, err := c.engine.GetCanonicalHeader(newNative(nil, c.db), testChainID, 203)
+		assert.Nil(t, err)
+		assert.Equal(t, headers[2].Hash(), header.Header.Hash())
+
+		// signed by a validator recently signed
+		tip := headers[len(headers)-1]
+		assert.NotNil(t, c.sync(c.makeHeader(tip, c.keys[tip.Number.Uint64()%3])), cfg.Name)
+		// signed by an outsider
+		outsider, _ := crypto.GenerateKey()
+		assert.NotNil(t, c.sync(c.makeHeader(tip, outsider)), cfg.Name)
+		// signed with the seal of the other consensus
+		other := c.makeHeader(tip, nil)
+		seal := ParliaSeal
+		if cfg.Name == "parlia" {
+			seal = CongressSeal
+		}
+		sig, _ := crypto.Sign(seal(other, c.extra.ChainID).Bytes(), c.keys[other.Number.Uint64()%3])
+		copy(other.Extra[extraVanity:], sig)
+		assert.NotNil(t, c.sync(other), cfg.Name)
+		assert.Equal(t, uint64(205), c.height(), cfg.Name)
+	}
+}
+
+func TestHeaderCompatible(t *testing.T) {
+	header := &types.Header{
+		ParentHash: ecommon.HexToHash("0x01"),
+		Coinbase:   ecommon.HexToAddress("0x02"),
+		Difficulty: big.NewInt(2),
+		Number:     big.NewInt(100),
+		GasLimit:   8000000,
+		GasUsed:    21000,
+		Time:       1600000000,
+		UncleHash:  uncleHash,
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	raw, err := json.Marshal(header)
+	assert.Nil(t, err)
+	// headers stored by the engine are the same as go-ethereum ones if without base fee
+	h := new(eth.Header)
+	assert.Nil(t, json.Unmarshal(raw, h))
+	assert.Equal(t, header.Hash(), h.Hash())
+	hRaw, err := json.Marshal(h)
+	assert.Nil(t, err)
+	assert.Equal(t, raw, hRaw)
+}
+
+func TestFinalizedReorg(t *testing.T) {
+	// the grandparent of a header is finalized as of it
+	finality := func(ctx *Context, header *eth.Header, parent *HeaderWithDifficultySum,
+		phv, pphv *HeightAndValidators) (justified, finalized *BlockID, err error) {
+		return nil, &BlockID{Number: parent.Header.Number.Uint64() - 1, Hash: parent.Header.ParentHash}, nil
+	}
+	c := newTestChain(t, &Config{Name: "parlia", Seal: ParliaSeal, Epoch: ParliaEpoch, PrepareHeader: DropBaseFee,
+		VerifyFork: VerifyLegacyFork, Finality: finality})
+	headers := []*eth.Header{c.makeHeader(c.genesis, nil)}
+	for i := 0; i < 4; i++ {
+		headers = append(headers, c.makeHeader(headers[len(headers)-1], nil))
+	}
+	assert.Nil(t, c.sync(headers...))
+	assert.Equal(t, uint64(205), c.height())
+
+	// a heavier fork from 202 leaves out 203 finalized as of 205
+	fork := []*eth.Header{c.makeHeader(headers[1], c.keys[0])}
+	fork = append(fork, c.makeHeader(fork[0], c.keys[2]))
+	for i := 0; i < 4; i++ {
+		fork = append(fork, c.makeHeader(fork[len(fork)-1], nil))
+	}
+	assert.Nil(t, c.sync(fork...))
+	assert.Equal(t, uint64(205), c.height())
+	header, err := c.engine.GetCanonicalHeader(newNative(nil, c.db), testChainID, 203)
+	assert.Nil(t, err)
+	assert.Equal(t, headers[2].Hash(), header.Header.Hash())
+
+	// a heavier fork from 204 keeps 203
+	fork = []*eth.Header{c.makeHeader(headers[3], c.keys[2])}
+	fork = append(fork, c.makeHeader(fork[0], c.keys[1]))
+	fork = append(fork, c.makeHeader(fork[1], nil))
+	assert.Nil(t, c.sync(fork...))
+	assert.Equal(t, uint64(207), c.height())
+	header, err = c.engine.GetCanonicalHeader(newNative(nil, c.db), testChainID, 205)
+	assert.Nil(t, err)
+	assert.Equal(t, fork[0].Hash(), header.Header.Hash())
+}
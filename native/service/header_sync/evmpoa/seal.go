@@ -0,0 +1,274 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evmpoa
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/eth/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	extraVanity = 32                       // Fixed number of extra-data prefix bytes reserved for signer vanity
+	extraSeal   = crypto.SignatureLength   // Fixed number of extra-data suffix bytes reserved for signer seal
+	uncleHash   = types.CalcUncleHash(nil) // Always Keccak256(RLP([])) as uncles are meaningless outside of PoW.
+	diffInTurn  = big.NewInt(2)            // Block difficulty for in-turn signatures
+	diffNoTurn  = big.NewInt(1)            // Block difficulty for out-of-turn signatures
+
+	gasLimitMax = uint64(0x7fffffffffffffff) // GasLimit maximum ( GasLimit <= 2^63-1)
+)
+
+// Seal returns the hash signed by the producer of header
+type Seal func(header *eth.Header, chainID *big.Int) ecommon.Hash
+
+var (
+	// CliqueSeal is the seal of clique, which signs the header without chain id
+	// https://github.com/ethereum/go-ethereum/blob/master/consensus/clique/clique.go
+	CliqueSeal Seal = func(header *eth.Header, chainID *big.Int) ecommon.Hash {
+		return sealHash(header, nil)
+	}
+
+	// CongressSeal is the seal of congress, which signs the header without chain id like clique
+	// https://github.com/HuobiGroup/huobi-eco-chain/tree/master/consensus/congress
+	CongressSeal = CliqueSeal
+
+	// ParliaSeal is the seal of parlia, which signs the header prefixed by chain id
+	// https://github.com/binance-chain/bsc/blob/master/consensus/parlia/parlia.go
+	ParliaSeal Seal = func(header *eth.Header, chainID *big.Int) ecommon.Hash {
+		// a nil chain id is encoded as zero
+		if chainID == nil {
+			chainID = new(big.Int)
+		}
+		return sealHash(header, chainID)
+	}
+)
+
+// DropBaseFee is a Config.PrepareHeader for chains without EIP-1559, whose headers never hash the base fee
+func DropBaseFee(native *native.NativeService, header *eth.Header) {
+	header.BaseFee = nil
+}
+
+// VerifyLegacyFork is a Config.VerifyFork for chains without EIP-1559
+func VerifyLegacyFork(native *native.NativeService, parent, header *eth.Header) error {
+	// Verify BaseFee not present before EIP-1559 fork.
+	if header.BaseFee != nil {
+		return fmt.Errorf("invalid baseFee before fork: have %d, want <nil>", header.BaseFee)
+	}
+	return VerifyGaslimit(parent.GasLimit, header.GasLimit)
+}
+
+// VerifyGaslimit verifies the header gas limit according increase/decrease
+// in relation to the parent gas limit.
+func VerifyGaslimit(parentGasLimit, headerGasLimit uint64) error {
+	// Verify that the gas limit remains within allowed bounds
+	diff := int64(parentGasLimit) - int64(headerGasLimit)
+	if diff < 0 {
+		diff *= -1
+	}
+	limit := parentGasLimit / params.GasLimitBoundDivisor
+	if uint64(diff) >= limit {
+		return fmt.Errorf("invalid gas limit: have %d, want %d +-= %d", headerGasLimit, parentGasLimit, limit-1)
+	}
+	if headerGasLimit < params.MinGasLimit {
+		return errors.New("invalid gas limit below 5000")
+	}
+	return nil
+}
+
+func (e *Engine) verifyHeader(native *native.NativeService, header *eth.Header, ctx *Context) (signer ecommon.Address, err error) {
+	// Don't waste time checking blocks from the future
+	if header.Time > uint64(time.Now().Unix()) {
+		err = errors.New("block in the future")
+		return
+	}
+
+	// Check that the extra-data contains both the vanity and signature
+	if len(header.Extra) < extraVanity {
+		err = errors.New("extra-data 32 byte vanity prefix missing")
+		return
+	}
+	if len(header.Extra) < extraVanity+extraSeal {
+		err = errors.New("extra-data 65 byte signature suffix missing")
+		return
+	}
+
+	// Ensure that the extra-data contains a signer list on checkpoint, but none otherwise
+	if e.cfg.Clique {
+		err = e.verifyCliqueFields(header, ctx)
+	} else {
+		_, err = e.epochValidators(ctx, header)
+	}
+	if err != nil {
+		return
+	}
+
+	// Ensure that the mix digest is zero as we don't have fork protection currently
+	if header.MixDigest != (ecommon.Hash{}) {
+		err = errors.New("non-zero mix digest")
+		return
+	}
+
+	// Ensure that the block doesn't contain any uncles which are meaningless in PoA
+	if header.UncleHash != uncleHash {
+		err = errors.New("non empty uncle hash")
+		return
+	}
+
+	// Ensure that the block's difficulty is meaningful (may not be correct at this point)
+	if header.Difficulty == nil || (header.Difficulty.Cmp(diffInTurn) != 0 && header.Difficulty.Cmp(diffNoTurn) != 0) {
+		err = errors.New("invalid difficulty")
+		return
+	}
+
+	// Verify that the gas limit is <= 2^63-1
+	if header.GasLimit > gasLimitMax {
+		err = fmt.Errorf("invalid gasLimit: have %v, max %v", header.GasLimit, gasLimitMax)
+		return
+	}
+
+	// All basic checks passed, verify cascading fields
+	return e.verifyCascadingFields(native, header, ctx)
+}
+
+func (e *Engine) verifyCascadingFields(native *native.NativeService, header *eth.Header, ctx *Context) (signer ecommon.Address, err error) {
+	number := header.Number.Uint64()
+
+	parent, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+	if err != nil {
+		return
+	}
+
+	if parent.Header.Number.Uint64() != number-1 {
+		err = errors.New("unknown ancestor")
+		return
+	}
+
+	if e.cfg.CheckPeriod && parent.Header.Time+ctx.ExtraInfo.Period > header.Time {
+		err = errors.New("invalid timestamp")
+		return
+	}
+
+	// Verify that the gasUsed is <= gasLimit
+	if header.GasUsed > header.GasLimit {
+		err = fmt.Errorf("invalid gasUsed: have %d, gasLimit %d", header.GasUsed, header.GasLimit)
+		return
+	}
+
+	if e.cfg.VerifyFork != nil {
+		if err = e.cfg.VerifyFork(native, parent.Header, header); err != nil {
+			return
+		}
+	}
+
+	return e.verifySeal(header, ctx)
+}
+
+func (e *Engine) verifySeal(header *eth.Header, ctx *Context) (signer ecommon.Address, err error) {
+	// Verifying the genesis block is not supported
+	number := header.Number.Uint64()
+	if number == 0 {
+		err = errors.New("unknown block")
+		return
+	}
+	if e.cfg.MockSigner != nil && *e.cfg.MockSigner != (ecommon.Address{}) {
+		signer = *e.cfg.MockSigner
+		return
+	}
+	if e.cfg.NoCheckSig != nil && *e.cfg.NoCheckSig {
+		signer = header.Coinbase
+		return
+	}
+	// Resolve the authorization key and check against validators
+	signer, err = e.ecrecover(header, ctx.ExtraInfo.ChainID)
+	if err != nil {
+		return
+	}
+
+	// the coinbase of clique is the account voted on instead
+	if !e.cfg.Clique && signer != header.Coinbase {
+		err = errors.New("coinbase do not match with signature")
+		return
+	}
+
+	return
+}
+
+// ecrecover extracts the Ethereum account address from a signed header.
+func (e *Engine) ecrecover(header *eth.Header, chainID *big.Int) (ecommon.Address, error) {
+	// Retrieve the signature from the header extra-data
+	if len(header.Extra) < extraSeal {
+		return ecommon.Address{}, errors.New("extra-data 65 byte signature suffix missing")
+	}
+	signature := header.Extra[len(header.Extra)-extraSeal:]
+
+	// Recover the public key and the Ethereum address
+	pubkey, err := crypto.Ecrecover(e.cfg.Seal(header, chainID).Bytes(), signature)
+	if err != nil {
+		return ecommon.Address{}, err
+	}
+	var signer ecommon.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	return signer, nil
+}
+
+// sealHash returns the hash of a block prior to it being sealed, prefixed by chainID if not nil
+func sealHash(header *eth.Header, chainID *big.Int) (hash ecommon.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	encodeSigHeader(hasher, header, chainID)
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+func encodeSigHeader(w io.Writer, header *eth.Header, chainID *big.Int) {
+	fields := []interface{}{
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		header.Extra[:len(header.Extra)-65], // this will panic if extra is too short, should check before calling encodeSigHeader
+		header.MixDigest,
+		header.Nonce,
+	}
+	if chainID != nil {
+		fields = append([]interface{}{chainID}, fields...)
+	}
+	err := rlp.Encode(w, fields)
+	if err != nil {
+		panic("can't encode: " + err.Error())
+	}
+}
@@ -0,0 +1,340 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evmpoa
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+func (e *Engine) getGenesis(native *native.NativeService, chainID uint64) (genesisHeader *GenesisHeader, err error) {
+	genesisBytes, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		err = fmt.Errorf("getGenesis, GetCacheDB err:%v", err)
+		return
+	}
+
+	if genesisBytes == nil {
+		return
+	}
+
+	genesisBytes, err = cstates.GetValueFromRawStorageItem(genesisBytes)
+	if err != nil {
+		err = fmt.Errorf("getGenesis, GetValueFromRawStorageItem err:%v", err)
+		return
+	}
+
+	genesisHeader = &GenesisHeader{}
+	if e.cfg.Clique {
+		err = json.Unmarshal(genesisBytes, &genesisHeader.Header)
+	} else {
+		err = json.Unmarshal(genesisBytes, &genesisHeader)
+	}
+	if err != nil {
+		err = fmt.Errorf("getGenesis, json.Unmarshal err:%v", err)
+		return
+	}
+	return
+}
+
+func (e *Engine) storeGenesis(native *native.NativeService, chainID uint64, genesisHeader *GenesisHeader) (err error) {
+	var genesisBytes []byte
+	if e.cfg.Clique {
+		genesisBytes, err = json.Marshal(&genesisHeader.Header)
+	} else {
+		genesisBytes, err = json.Marshal(genesisHeader)
+	}
+	if err != nil {
+		return
+	}
+
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.GENESIS_HEADER), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(genesisBytes))
+
+	headerWithSum := &HeaderWithDifficultySum{Header: &genesisHeader.Header, DifficultySum: genesisHeader.Header.Difficulty}
+
+	err = e.putHeaderWithSum(native, chainID, headerWithSum)
+	if err != nil {
+		return
+	}
+
+	putCanonicalHeight(native, chainID, genesisHeader.Header.Number.Uint64())
+	putCanonicalHash(native, chainID, genesisHeader.Header.Number.Uint64(), genesisHeader.Header.Hash())
+
+	scom.NotifyPutHeader(native, chainID, genesisHeader.Header.Number.Uint64(), genesisHeader.Header.Hash().Hex())
+	return
+}
+
+func (e *Engine) isHeaderExist(native *native.NativeService, headerHash ecommon.Hash, ctx *Context) (bool, error) {
+	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(ctx.ChainID), headerHash.Bytes()))
+	if err != nil {
+		return false, fmt.Errorf("%s Handler isHeaderExist error: %v", e.cfg.Name, err)
+	}
+
+	return headerStore != nil, nil
+}
+
+// GetCanonicalHeight ...
+func (e *Engine) GetCanonicalHeight(native *native.NativeService, chainID uint64) (height uint64, err error) {
+	heightStore, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		err = fmt.Errorf("%s Handler GetCanonicalHeight err:%v", e.cfg.Name, err)
+		return
+	}
+
+	storeBytes, err := cstates.GetValueFromRawStorageItem(heightStore)
+	if err != nil {
+		err = fmt.Errorf("%s Handler GetCanonicalHeight, GetValueFromRawStorageItem err:%v", e.cfg.Name, err)
+		return
+	}
+
+	height = utils.GetBytesUint64(storeBytes)
+	return
+}
+
+// GetCanonicalHeader ...
+func (e *Engine) GetCanonicalHeader(native *native.NativeService, chainID uint64, height uint64) (headerWithSum *HeaderWithDifficultySum, err error) {
+	hash, err := e.getCanonicalHash(native, chainID, height)
+	if err != nil {
+		return
+	}
+
+	if hash == (ecommon.Hash{}) {
+		return
+	}
+
+	headerWithSum, err = e.GetHeader(native, hash, chainID)
+	return
+}
+
+func deleteCanonicalHash(native *native.NativeService, chainID uint64, height uint64) {
+	native.GetCacheDB().Delete(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
+}
+
+func (e *Engine) getCanonicalHash(native *native.NativeService, chainID uint64, height uint64) (hash ecommon.Hash, err error) {
+	hashBytesStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.MAIN_CHAIN), utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)))
+	if err != nil {
+		return
+	}
+
+	if hashBytesStore == nil {
+		return
+	}
+
+	hashBytes, err := cstates.GetValueFromRawStorageItem(hashBytesStore)
+	if err != nil {
+		err = fmt.Errorf("%s Handler getCanonicalHash, GetValueFromRawStorageItem err:%v", e.cfg.Name, err)
+		return
+	}
+
+	hash = ecommon.BytesToHash(hashBytes)
+	return
+}
+
+func putCanonicalHash(native *native.NativeService, chainID uint64, height uint64, hash ecommon.Hash) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.MAIN_CHAIN),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(height)), cstates.GenRawStorageItem(hash.Bytes()))
+}
+
+// cliqueHeaderWithDifficultySum is how HeaderWithDifficultySum is stored with Config.Clique
+type cliqueHeaderWithDifficultySum struct {
+	Header          *eth.Header   `json:"header"`
+	DifficultySum   *big.Int      `json:"difficultySum"`
+	EpochParentHash *ecommon.Hash `json:"lastVoteParentOrEpoch"`
+	Justified       *BlockID      `json:"-"`
+	Finalized       *BlockID      `json:"-"`
+}
+
+func (e *Engine) putHeaderWithSum(native *native.NativeService, chainID uint64, headerWithSum *HeaderWithDifficultySum) (err error) {
+	var headerBytes []byte
+	if e.cfg.Clique {
+		headerBytes, err = json.Marshal((*cliqueHeaderWithDifficultySum)(headerWithSum))
+	} else {
+		headerBytes, err = json.Marshal(headerWithSum)
+	}
+	if err != nil {
+		return
+	}
+
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID),
+			headerWithSum.Header.Hash().Bytes()),
+		cstates.GenRawStorageItem(headerBytes))
+	return
+}
+
+func putCanonicalHeight(native *native.NativeService, chainID uint64, height uint64) {
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(scom.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(height)))
+}
+
+// addHeader stores headerWithSum with its difficulty sum, which becomes the head of the canonical chain if heavier
+// and not forking below the block finalized as of the current head
+func (e *Engine) addHeader(native *native.NativeService, headerWithSum *HeaderWithDifficultySum, ctx *Context) (err error) {
+	header := headerWithSum.Header
+	parentHeader, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+	if err != nil {
+		return
+	}
+
+	cheight, err := e.GetCanonicalHeight(native, ctx.ChainID)
+	if err != nil {
+		return
+	}
+	cheader, err := e.GetCanonicalHeader(native, ctx.ChainID, cheight)
+	if err != nil {
+		return
+	}
+	if cheader == nil {
+		err = fmt.Errorf("getCanonicalHeader returns nil")
+		return
+	}
+
+	localTd := cheader.DifficultySum
+	externTd := new(big.Int).Add(header.Difficulty, parentHeader.DifficultySum)
+
+	headerWithSum.DifficultySum = externTd
+	err = e.putHeaderWithSum(native, ctx.ChainID, headerWithSum)
+	if err != nil {
+		return
+	}
+
+	if externTd.Cmp(localTd) > 0 {
+		var reverted bool
+		reverted, err = e.revertsFinalized(native, header, cheader.Finalized, ctx)
+		if err != nil || reverted {
+			return
+		}
+
+		// Delete any canonical number assignments above the new head
+		var headerWithSum *HeaderWithDifficultySum
+		for i := header.Number.Uint64() + 1; ; i++ {
+			headerWithSum, err = e.GetCanonicalHeader(native, ctx.ChainID, i)
+			if err != nil {
+				return
+			}
+			if headerWithSum == nil {
+				break
+			}
+
+			deleteCanonicalHash(native, ctx.ChainID, i)
+		}
+
+		// Overwrite any stale canonical number assignments
+		var (
+			hash       ecommon.Hash
+			headHeader *HeaderWithDifficultySum
+		)
+		cheight := header.Number.Uint64() - 1
+		headHash := header.ParentHash
+
+		for {
+			hash, err = e.getCanonicalHash(native, ctx.ChainID, cheight)
+			if err != nil {
+				return
+			}
+			if hash == headHash {
+				break
+			}
+
+			putCanonicalHash(native, ctx.ChainID, cheight, headHash)
+			headHeader, err = e.GetHeader(native, headHash, ctx.ChainID)
+			if err != nil {
+				return
+			}
+			headHash = headHeader.Header.ParentHash
+			cheight--
+		}
+
+		// Extend the canonical chain with the new header
+		putCanonicalHash(native, ctx.ChainID, header.Number.Uint64(), header.Hash())
+		putCanonicalHeight(native, ctx.ChainID, header.Number.Uint64())
+	}
+
+	return nil
+}
+
+// revertsFinalized returns whether the chain of header does not contain the finalized block of the canonical chain
+func (e *Engine) revertsFinalized(native *native.NativeService, header *eth.Header, finalized *BlockID,
+	ctx *Context) (bool, error) {
+	if finalized == nil {
+		return false, nil
+	}
+	if header.Number.Uint64() <= finalized.Number {
+		return true, nil
+	}
+	number, hash := header.Number.Uint64()-1, header.ParentHash
+	for number > finalized.Number {
+		chash, err := e.getCanonicalHash(native, ctx.ChainID, number)
+		if err != nil {
+			return false, err
+		}
+		if chash == hash {
+			return false, nil
+		}
+		parent, err := e.GetHeader(native, hash, ctx.ChainID)
+		if err != nil {
+			return false, err
+		}
+		number, hash = number-1, parent.Header.ParentHash
+	}
+	return hash != finalized.Hash, nil
+}
+
+// GetHeader returns the synced header by hash
+func (e *Engine) GetHeader(native *native.NativeService, hash ecommon.Hash, chainID uint64) (headerWithSum *HeaderWithDifficultySum, err error) {
+	headerStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress,
+		[]byte(scom.HEADER_INDEX), utils.GetUint64Bytes(chainID), hash.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler getHeader error: %v", e.cfg.Name, err)
+	}
+	if headerStore == nil {
+		return nil, fmt.Errorf("%s Handler getHeader, can not find any header records", e.cfg.Name)
+	}
+	storeBytes, err := cstates.GetValueFromRawStorageItem(headerStore)
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler getHeader, deserialize headerBytes from raw storage item err:%v",
+			e.cfg.Name, err)
+	}
+	headerWithSum = &HeaderWithDifficultySum{}
+	if e.cfg.Clique {
+		err = json.Unmarshal(storeBytes, (*cliqueHeaderWithDifficultySum)(headerWithSum))
+	} else {
+		err = json.Unmarshal(storeBytes, headerWithSum)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s Handler getHeader, deserialize header error: %v", e.cfg.Name, err)
+	}
+
+	return
+}
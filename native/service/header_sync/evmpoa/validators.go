@@ -0,0 +1,236 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package evmpoa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+)
+
+// HeightAndValidators ...
+type HeightAndValidators struct {
+	Height     *big.Int
+	Validators []ecommon.Address
+	Hash       *ecommon.Hash
+	BLSKeys    [][]byte `json:",omitempty"` // vote keys of the validators, only for parlia since luban
+}
+
+// EpochRule picks the validators in turn for header, which starts an epoch if epoch, from the validators of
+// the last epoch phv and the one before pphv
+type EpochRule func(header *eth.Header, epoch bool, phv, pphv *HeightAndValidators) (*HeightAndValidators, error)
+
+var (
+	// CongressEpoch makes new validators in turn right from the epoch header, which can not come
+	// within half the validators from the last one
+	CongressEpoch EpochRule = func(header *eth.Header, epoch bool, phv, pphv *HeightAndValidators) (*HeightAndValidators, error) {
+		if epoch {
+			diffWithLastEpoch := big.NewInt(0).Sub(header.Number, phv.Height).Int64()
+			if diffWithLastEpoch <= int64(len(phv.Validators)/2) {
+				return nil, errors.New("can not change epoch continuously")
+			}
+		}
+		return phv, nil
+	}
+
+	// ImmediateEpoch makes new validators in turn right from the epoch header without spacing epochs
+	ImmediateEpoch EpochRule = func(header *eth.Header, epoch bool, phv, pphv *HeightAndValidators) (*HeightAndValidators, error) {
+		return phv, nil
+	}
+
+	// ParliaEpoch keeps the validators of the previous epoch in turn for half of them after the epoch header
+	ParliaEpoch EpochRule = func(header *eth.Header, epoch bool, phv, pphv *HeightAndValidators) (*HeightAndValidators, error) {
+		diffWithLastEpoch := big.NewInt(0).Sub(header.Number, phv.Height).Int64()
+		if diffWithLastEpoch > int64(len(pphv.Validators)/2) {
+			// phv is in effect
+			return phv, nil
+		}
+		// pphv is in effect
+		if epoch {
+			return nil, errors.New("can not change epoch continuously")
+		}
+		return pphv, nil
+	}
+)
+
+// ListedValidators is the default Config.EpochValidators, where headers carrying anything between the vanity
+// and the seal start an epoch with the addresses listed there
+func ListedValidators(ctx *Context, header *eth.Header) (*HeightAndValidators, error) {
+	signersBytes := len(header.Extra) - extraVanity - extraSeal
+	if signersBytes <= 0 {
+		return nil, nil
+	}
+	if signersBytes%ecommon.AddressLength != 0 {
+		return nil, errors.New("invalid signer list")
+	}
+	validators, err := ParseValidators(header.Extra[extraVanity : extraVanity+signersBytes])
+	if err != nil {
+		return nil, err
+	}
+	return &HeightAndValidators{Height: header.Number, Validators: validators}, nil
+}
+
+func (e *Engine) epochValidators(ctx *Context, header *eth.Header) (*HeightAndValidators, error) {
+	if e.cfg.EpochValidators != nil {
+		return e.cfg.EpochValidators(ctx, header)
+	}
+	return ListedValidators(ctx, header)
+}
+
+func (e *Engine) getPrevHeightAndValidators(native *native.NativeService, header *eth.Header, ctx *Context) (phv, pphv *HeightAndValidators, lastSeenHeight int64, err error) {
+	genesis, err := e.getGenesis(native, ctx.ChainID)
+	if err != nil {
+		err = fmt.Errorf("%s Handler getGenesis error: %v", e.cfg.Name, err)
+		return
+	}
+
+	if genesis == nil {
+		err = fmt.Errorf("%s Handler genesis not set", e.cfg.Name)
+		return
+	}
+
+	genesisHeaderHash := genesis.Header.Hash()
+	if header.Hash() == genesisHeaderHash {
+		err = fmt.Errorf("genesis header should not be synced again")
+		return
+	}
+
+	lastSeenHeight = -1
+	targetCoinbase := header.Coinbase
+	if header.ParentHash == genesisHeaderHash {
+		if genesis.Header.Coinbase == targetCoinbase {
+			lastSeenHeight = genesis.Header.Number.Int64()
+		}
+
+		phv = &genesis.PrevValidators[0]
+		phv.Hash = &genesisHeaderHash
+		pphv = &genesis.PrevValidators[1]
+		return
+	}
+
+	prevHeaderWithSum, err := e.GetHeader(native, header.ParentHash, ctx.ChainID)
+	if err != nil {
+		err = fmt.Errorf("%s Handler getHeader error: %v", e.cfg.Name, err)
+		return
+	}
+
+	if prevHeaderWithSum.Header.Coinbase == targetCoinbase {
+		lastSeenHeight = prevHeaderWithSum.Header.Number.Int64()
+	} else {
+		nextRecentParentHash := prevHeaderWithSum.Header.ParentHash
+		defer func() {
+			if err == nil {
+				maxV := len(phv.Validators)
+				if maxV < len(pphv.Validators) {
+					maxV = len(pphv.Validators)
+				}
+				maxLimit := maxV / 2
+				for i := 0; i < maxLimit-1; i++ {
+					prevHeaderWithSum, err := e.GetHeader(native, nextRecentParentHash, ctx.ChainID)
+					if err != nil {
+						err = fmt.Errorf("%s Handler getHeader error: %v", e.cfg.Name, err)
+						return
+					}
+					if prevHeaderWithSum.Header.Coinbase == targetCoinbase {
+						lastSeenHeight = prevHeaderWithSum.Header.Number.Int64()
+						return
+					}
+
+					if nextRecentParentHash == genesisHeaderHash {
+						return
+					}
+					nextRecentParentHash = prevHeaderWithSum.Header.ParentHash
+				}
+			}
+		}()
+	}
+
+	var (
+		hv             *HeightAndValidators
+		nextParentHash ecommon.Hash
+	)
+
+	currentPV := &phv
+
+	for {
+		hv, err = e.epochValidators(ctx, prevHeaderWithSum.Header)
+		if err != nil {
+			err = fmt.Errorf("%s Handler ParseValidators error: %v", e.cfg.Name, err)
+			return
+		}
+		if hv != nil {
+			*currentPV = hv
+			switch *currentPV {
+			case phv:
+				hash := prevHeaderWithSum.Header.Hash()
+				phv.Hash = &hash
+				currentPV = &pphv
+			case pphv:
+				return
+			default:
+				err = fmt.Errorf("bug in %s Handler", e.cfg.Name)
+				return
+			}
+		}
+
+		nextParentHash = prevHeaderWithSum.Header.ParentHash
+		if prevHeaderWithSum.EpochParentHash != nil {
+			nextParentHash = *prevHeaderWithSum.EpochParentHash
+		}
+
+		if nextParentHash == genesisHeaderHash {
+			switch *currentPV {
+			case phv:
+				phv = &genesis.PrevValidators[0]
+				phv.Hash = &genesisHeaderHash
+				pphv = &genesis.PrevValidators[1]
+			case pphv:
+				pphv = &genesis.PrevValidators[0]
+			default:
+				err = fmt.Errorf("bug in %s Handler", e.cfg.Name)
+				return
+			}
+			return
+		}
+
+		prevHeaderWithSum, err = e.GetHeader(native, nextParentHash, ctx.ChainID)
+		if err != nil {
+			err = fmt.Errorf("%s Handler getHeader error: %v", e.cfg.Name, err)
+			return
+		}
+	}
+}
+
+// ParseValidators ...
+func ParseValidators(validatorsBytes []byte) ([]ecommon.Address, error) {
+	if len(validatorsBytes)%ecommon.AddressLength != 0 {
+		return nil, errors.New("invalid validators bytes")
+	}
+	n := len(validatorsBytes) / ecommon.AddressLength
+	result := make([]ecommon.Address, n)
+	for i := 0; i < n; i++ {
+		address := make([]byte, ecommon.AddressLength)
+		copy(address, validatorsBytes[i*ecommon.AddressLength:(i+1)*ecommon.AddressLength])
+		result[i] = ecommon.BytesToAddress(address)
+	}
+	return result, nil
+}
@@ -188,6 +188,7 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) (err error) {
 		if err != nil {
 			return fmt.Errorf("HarmonyHandler failed to update state, err: %v", err)
 		}
+		native.AddSyncedHeaders(1)
 	}
 
 	return
@@ -244,7 +244,7 @@ func getHeaderHashByHeight(native *native.NativeService, height uint64) ethcommo
 }
 
 func getHeaderByHash(native *native.NativeService, hash ethcommon.Hash) []byte {
-	hws, err := getHeader(native, hash, hecoChainID)
+	hws, err := engine.GetHeader(native, hash, hecoChainID)
 	if err != nil {
 		return nil
 	}
@@ -1,14 +1,13 @@
 package heco
 
 import (
-	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/evmpoa"
 )
 
 var (
@@ -23,24 +22,6 @@ func is120(h *eth.Header) bool {
 	return h.BaseFee != nil || h.Number.Uint64() >= config.GetHeco120Height(config.DefConfig.P2PNode.NetworkId)
 }
 
-// VerifyGaslimit verifies the header gas limit according increase/decrease
-// in relation to the parent gas limit.
-func VerifyGaslimit(parentGasLimit, headerGasLimit uint64) error {
-	// Verify that the gas limit remains within allowed bounds
-	diff := int64(parentGasLimit) - int64(headerGasLimit)
-	if diff < 0 {
-		diff *= -1
-	}
-	limit := parentGasLimit / params.GasLimitBoundDivisor
-	if uint64(diff) >= limit {
-		return fmt.Errorf("invalid gas limit: have %d, want %d +-= %d", headerGasLimit, parentGasLimit, limit-1)
-	}
-	if headerGasLimit < params.MinGasLimit {
-		return errors.New("invalid gas limit below 5000")
-	}
-	return nil
-}
-
 // VerifyEip1559Header verifies some header attributes which were changed in EIP-1559,
 // - gas limit check
 // - basefee check
@@ -48,7 +29,7 @@ func VerifyEip1559Header(parent, header *eth.Header) error {
 	// Verify that the gas limit remains within allowed bounds
 	parentGasLimit := parent.GasLimit
 
-	if err := VerifyGaslimit(parentGasLimit, header.GasLimit); err != nil {
+	if err := evmpoa.VerifyGaslimit(parentGasLimit, header.GasLimit); err != nil {
 		return err
 	}
 	// Verify the header is not malformed
@@ -18,6 +18,7 @@ package msc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -26,7 +27,9 @@ import (
 	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
 	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly-io-test/chains/eth"
 	"github.com/polynetwork/poly/account"
@@ -143,7 +146,7 @@ func getHeaderHashByHeight(native *native.NativeService, height uint64) ethcommo
 }
 
 func getHeaderByHash(native *native.NativeService, hash ethcommon.Hash) []byte {
-	hws, err := getHeader(native, hash, MSCChainID)
+	hws, err := engine.GetHeader(native, hash, MSCChainID)
 	if err != nil {
 		return nil
 	}
@@ -161,11 +164,20 @@ func getTool() *eth.ETHTools {
 
 func getBlockHeaderByHash(t *testing.T, hash ethcommon.Hash) *etypes.Header {
 	tool := getTool()
-	hdr, err := tool.GetBlockHeaderByHash(hash)
+	hdr, err := tool.GetEthClient().HeaderByHash(context.Background(), hash)
 	assert.NilError(t, err)
 	return hdr
 }
 
+// ecrecover returns the signer of a clique header
+func ecrecover(header *etypes.Header) (ethcommon.Address, error) {
+	pubkey, err := crypto.SigToPub(clique.SealHash(header).Bytes(), header.Extra[len(header.Extra)-crypto.SignatureLength:])
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
 func getBlockHeader(t *testing.T, height uint64) *etypes.Header {
 	tool := getTool()
 	hdr, err := tool.GetBlockHeader(height)
@@ -0,0 +1,292 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package msc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	extraVanity = 32                     // Fixed number of extra-data prefix bytes reserved for signer vanity
+	extraSeal   = crypto.SignatureLength // Fixed number of extra-data suffix bytes reserved for signer seal
+)
+
+// stateChain syncs a fixed clique chain signed by local keys, recording the changes of the header sync
+// storage after each step
+type stateChain struct {
+	t       *testing.T
+	db      *storage.CacheDB
+	keys    map[ecommon.Address]*ecdsa.PrivateKey
+	signers map[ecommon.Hash]ecommon.Address
+	parents map[ecommon.Hash]*etypes.Header
+	last    map[string]string
+	changes []map[string]string
+}
+
+func newStateChain(t *testing.T) *stateChain {
+	c := &stateChain{
+		t:       t,
+		keys:    make(map[ecommon.Address]*ecdsa.PrivateKey),
+		signers: make(map[ecommon.Hash]ecommon.Address),
+		parents: make(map[ecommon.Hash]*etypes.Header),
+		last:    make(map[string]string),
+	}
+	ns, err := NewNative(nil, &types.Transaction{}, nil)
+	assert.Nil(t, err)
+	c.db = ns.GetCacheDB()
+	extraInfo, _ := json.Marshal(&ExtraInfo{ChainID: big.NewInt(1), Period: 3, Epoch: 10})
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: MSCChainID,
+		ExtraInfo: extraInfo}))
+	c.record()
+	c.changes = nil
+	return c
+}
+
+// newSigners makes n signers sorted by address
+func (c *stateChain) newSigners(n int) (list []ecommon.Address) {
+	for i := 0; i < n; i++ {
+		key, err := crypto.ToECDSA(ecommon.LeftPadBytes([]byte{byte(len(c.keys) + 1)}, 32))
+		assert.Nil(c.t, err)
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		c.keys[address] = key
+		list = append(list, address)
+	}
+	return sorted(list)
+}
+
+func sorted(list []ecommon.Address) []ecommon.Address {
+	list = append([]ecommon.Address{}, list...)
+	sort.Slice(list, func(i, j int) bool { return bytes.Compare(list[i][:], list[j][:]) < 0 })
+	return list
+}
+
+// record appends the storage changes since the last record, deleted keys map to empty values
+func (c *stateChain) record() {
+	now := make(map[string]string)
+	iter := c.db.NewIterator(utils.HeaderSyncContractAddress[:])
+	for has := iter.First(); has; has = iter.Next() {
+		now[hex.EncodeToString(iter.Key())] = hex.EncodeToString(iter.Value())
+	}
+	iter.Release()
+	change := make(map[string]string)
+	for k, v := range now {
+		if c.last[k] != v {
+			change[k] = v
+		}
+	}
+	for k := range c.last {
+		if _, ok := now[k]; !ok {
+			change[k] = ""
+		}
+	}
+	c.last = now
+	c.changes = append(c.changes, change)
+}
+
+func (c *stateChain) invoke(input []byte, genesis bool) *native.NativeService {
+	ns, err := NewNative(input, &types.Transaction{SignedAddr: []common.Address{acct.Address}}, c.db)
+	assert.Nil(c.t, err)
+	if genesis {
+		err = NewHandler().SyncGenesisHeader(ns)
+	} else {
+		err = NewHandler().SyncBlockHeader(ns)
+	}
+	if !assert.Nil(c.t, err) {
+		c.t.FailNow()
+	}
+	c.record()
+	return ns
+}
+
+func (c *stateChain) sync(headers ...*etypes.Header) *native.NativeService {
+	param := &scom.SyncBlockHeaderParam{ChainID: MSCChainID}
+	for _, h := range headers {
+		raw, _ := json.Marshal(h)
+		param.Headers = append(param.Headers, raw)
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return c.invoke(sink.Bytes(), false)
+}
+
+func (c *stateChain) sign(header *etypes.Header, signer ecommon.Address) *etypes.Header {
+	sig, err := crypto.Sign(clique.SealHash(header).Bytes(), c.keys[signer])
+	assert.Nil(c.t, err)
+	copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+	c.signers[header.Hash()] = signer
+	c.parents[header.Hash()] = header
+	return header
+}
+
+// recent tells if signer signed one of the last limit-1 ancestors of the child of parent
+func (c *stateChain) recent(parent *etypes.Header, signer ecommon.Address, limit int) bool {
+	for i := 1; i < limit && parent != nil; i++ {
+		if c.signers[parent.Hash()] == signer {
+			return true
+		}
+		parent = c.parents[parent.ParentHash]
+	}
+	return false
+}
+
+// seal makes the child of parent signed in turn by signers if possible and not outOfTurn, voting for coinbase
+func (c *stateChain) seal(parent *etypes.Header, signers []ecommon.Address, outOfTurn bool,
+	coinbase ecommon.Address, authorize bool) *etypes.Header {
+	header := &etypes.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  etypes.EmptyUncleHash,
+		Coinbase:   coinbase,
+		Difficulty: big.NewInt(1),
+		Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 3,
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	if authorize {
+		header.Nonce = etypes.BlockNonce{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	}
+	limit := len(signers)/2 + 1
+	inTurn := signers[header.Number.Uint64()%uint64(len(signers))]
+	if !outOfTurn && !c.recent(parent, inTurn, limit) {
+		header.Difficulty = big.NewInt(2)
+		return c.sign(header, inTurn)
+	}
+	for _, signer := range signers {
+		if signer != inTurn && !c.recent(parent, signer, limit) {
+			return c.sign(header, signer)
+		}
+	}
+	c.t.Fatalf("no signer for %d", header.Number)
+	return nil
+}
+
+// checkpoint makes the child of parent carrying signers
+func (c *stateChain) checkpoint(parent *etypes.Header, signers []ecommon.Address) *etypes.Header {
+	header := c.seal(parent, signers, false, ecommon.Address{}, false)
+	signer := c.signers[header.Hash()]
+	extra := make([]byte, extraVanity)
+	for _, signer := range signers {
+		extra = append(extra, signer[:]...)
+	}
+	header.Extra = append(extra, make([]byte, extraSeal)...)
+	return c.sign(header, signer)
+}
+
+// run syncs the chain through votes adding and dropping a signer, checkpoints, a reorg and back
+func (c *stateChain) run() map[uint64]*etypes.Header {
+	abc := c.newSigners(3)
+	d := c.newSigners(1)[0]
+	abcd := sorted(append(abc, d))
+
+	extra := make([]byte, extraVanity)
+	for _, signer := range abc {
+		extra = append(extra, signer[:]...)
+	}
+	genesis := &etypes.Header{
+		UncleHash:  etypes.EmptyUncleHash,
+		Difficulty: big.NewInt(2),
+		Number:     big.NewInt(100),
+		GasLimit:   8000000,
+		Time:       1600000000,
+		Extra:      append(extra, make([]byte, extraSeal)...),
+	}
+	c.sign(genesis, abc[100%3])
+	raw, _ := json.Marshal(genesis)
+	sink := common.NewZeroCopySink(nil)
+	(&scom.SyncGenesisHeaderParam{ChainID: MSCChainID, GenesisHeader: raw}).Serialization(sink)
+	c.invoke(sink.Bytes(), true)
+
+	// d is voted in
+	h := map[uint64]*etypes.Header{100: genesis}
+	h[101] = c.seal(h[100], abc, false, d, true)
+	h[102] = c.seal(h[101], abc, false, d, true)
+	h[103] = c.seal(h[102], abcd, false, ecommon.Address{}, false)
+	c.sync(h[101], h[102], h[103])
+	for i := uint64(104); i < 110; i++ {
+		h[i] = c.seal(h[i-1], abcd, false, ecommon.Address{}, false)
+	}
+	h[110] = c.checkpoint(h[109], abcd)
+	c.sync(h[104], h[105], h[106], h[107], h[108], h[109], h[110])
+
+	// d is voted out
+	h[111] = c.seal(h[110], abcd, false, d, false)
+	h[112] = c.seal(h[111], abcd, false, d, false)
+	h[113] = c.seal(h[112], abcd, false, d, false)
+	h[114] = c.seal(h[113], abc, false, ecommon.Address{}, false)
+	c.sync(h[111], h[112], h[113], h[114])
+
+	// a heavier fork takes over, then the canonical chain is back
+	h[115] = c.seal(h[114], abc, false, ecommon.Address{}, false)
+	f115 := c.seal(h[114], abc, true, abc[0], false)
+	f116 := c.seal(f115, abc, false, ecommon.Address{}, false)
+	c.sync(h[115], f115)
+	c.sync(f116)
+	h[116] = c.seal(h[115], abc, false, ecommon.Address{}, false)
+	h[117] = c.seal(h[116], abc, false, ecommon.Address{}, false)
+	c.sync(h[116], h[117])
+
+	for i := uint64(118); i < 120; i++ {
+		h[i] = c.seal(h[i-1], abc, false, ecommon.Address{}, false)
+	}
+	h[120] = c.checkpoint(h[119], abc)
+	h[121] = c.seal(h[120], abc, false, ecommon.Address{}, false)
+	h[122] = c.seal(h[121], abc, false, ecommon.Address{}, false)
+	ns := c.sync(h[118], h[119], h[120], h[121], h[122])
+	assert.Equal(c.t, uint64(5), ns.GetSyncedHeaders())
+	return h
+}
+
+// TestSyncStateCompatible checks the storage written by the handler against testdata/sync_state.json,
+// which was recorded before msc moved onto evmpoa, so synced chains keep working across the upgrade
+func TestSyncStateCompatible(t *testing.T) {
+	c := newStateChain(t)
+	h := c.run()
+
+	raw, err := ioutil.ReadFile("testdata/sync_state.json")
+	assert.Nil(t, err)
+	var expected []map[string]string
+	assert.Nil(t, json.Unmarshal(raw, &expected))
+	assert.Equal(t, len(expected), len(c.changes))
+	for i := range expected {
+		assert.Equal(t, expected[i], c.changes[i], "step %d", i)
+	}
+
+	// headers stored already are not counted as synced again
+	ns := c.sync(h[121], h[122])
+	assert.Equal(t, uint64(0), ns.GetSyncedHeaders())
+}
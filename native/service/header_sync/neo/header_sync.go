@@ -91,6 +91,7 @@ func (this *NEOHandler) SyncBlockHeader(native *native.NativeService) error {
 				Height:        header.Index,
 				NextConsensus: header.NextConsensus,
 			}
+			native.AddSyncedHeaders(1)
 		}
 	}
 	if newNeoConsensus != nil {
@@ -98,6 +98,7 @@ func (this *Neo3Handler) SyncBlockHeader(native *native.NativeService) error {
 				Height:        header.GetIndex(),
 				NextConsensus: header.GetNextConsensus(),
 			}
+			native.AddSyncedHeaders(1)
 		}
 	}
 	if newNeoConsensus != nil {
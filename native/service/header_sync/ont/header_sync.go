@@ -95,6 +95,7 @@ func (this *ONTHandler) SyncBlockHeader(native *native.NativeService) error {
 		if err != nil {
 			return fmt.Errorf("SyncBlockHeader, put BlockHeader error: %v", err)
 		}
+		native.AddSyncedHeaders(1)
 		err = UpdateConsensusPeer(native, params.ChainID, header)
 		if err != nil {
 			return fmt.Errorf("SyncBlockHeader, update ConsensusPeer error: %v", err)
@@ -255,7 +255,7 @@ func getHeaderHashByHeight(native *native.NativeService, height uint64) ethcommo
 }
 
 func getHeaderByHash(native *native.NativeService, hash ethcommon.Hash) []byte {
-	hws, err := getHeader(native, hash, pixieTestnetChainID)
+	hws, err := engine.GetHeader(native, hash, pixieTestnetChainID)
 	if err != nil {
 		return nil
 	}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pixiechain
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	ecommon "github.com/ethereum/go-ethereum/common"
+	etypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	scom "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	stateVanity = 32                     // Fixed number of extra-data prefix bytes reserved for signer vanity
+	stateSeal   = crypto.SignatureLength // Fixed number of extra-data suffix bytes reserved for signer seal
+)
+
+// stateChain syncs a fixed congress chain signed by local keys, recording the changes of the header sync
+// storage after each step
+type stateChain struct {
+	t       *testing.T
+	db      *storage.CacheDB
+	keys    []*ecdsa.PrivateKey
+	last    map[string]string
+	changes []map[string]string
+}
+
+func newStateChain(t *testing.T) *stateChain {
+	c := &stateChain{t: t, last: make(map[string]string)}
+	for i := 0; i < 4; i++ {
+		key, err := crypto.ToECDSA(ecommon.LeftPadBytes([]byte{byte(i + 1)}, 32))
+		assert.Nil(t, err)
+		c.keys = append(c.keys, key)
+	}
+	ns, err := NewNative(nil, &types.Transaction{}, nil)
+	assert.Nil(t, err)
+	c.db = ns.GetCacheDB()
+	extraInfo, _ := json.Marshal(&ExtraInfo{ChainID: big.NewInt(666), Period: 3})
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: pixieTestnetChainID,
+		ExtraInfo: extraInfo}))
+	c.record()
+	c.changes = nil
+	return c
+}
+
+func (c *stateChain) address(i int) ecommon.Address {
+	return crypto.PubkeyToAddress(c.keys[i].PublicKey)
+}
+
+// validators is the validator list carried in extra of an epoch header
+func (c *stateChain) validators(signers ...int) (body []byte) {
+	for _, i := range signers {
+		body = append(body, c.address(i).Bytes()...)
+	}
+	return
+}
+
+// record appends the storage changes since the last record, deleted keys map to empty values
+func (c *stateChain) record() {
+	now := make(map[string]string)
+	iter := c.db.NewIterator(utils.HeaderSyncContractAddress[:])
+	for has := iter.First(); has; has = iter.Next() {
+		now[hex.EncodeToString(iter.Key())] = hex.EncodeToString(iter.Value())
+	}
+	iter.Release()
+	change := make(map[string]string)
+	for k, v := range now {
+		if c.last[k] != v {
+			change[k] = v
+		}
+	}
+	for k := range c.last {
+		if _, ok := now[k]; !ok {
+			change[k] = ""
+		}
+	}
+	c.last = now
+	c.changes = append(c.changes, change)
+}
+
+func (c *stateChain) invoke(input []byte, genesis bool) {
+	ns, err := NewNative(input, &types.Transaction{SignedAddr: []common.Address{acct.Address}}, c.db)
+	assert.Nil(c.t, err)
+	if genesis {
+		err = NewPixieHandler().SyncGenesisHeader(ns)
+	} else {
+		err = NewPixieHandler().SyncBlockHeader(ns)
+	}
+	if !assert.Nil(c.t, err) {
+		c.t.FailNow()
+	}
+	c.record()
+}
+
+func (c *stateChain) syncGenesis(header *eth.Header, prev []ecommon.Address) {
+	raw, _ := json.Marshal(&struct {
+		Header         *eth.Header
+		PrevValidators []*HeightAndValidators
+	}{header, []*HeightAndValidators{{Height: big.NewInt(0), Validators: prev}}})
+	sink := common.NewZeroCopySink(nil)
+	(&scom.SyncGenesisHeaderParam{ChainID: pixieTestnetChainID, GenesisHeader: raw}).Serialization(sink)
+	c.invoke(sink.Bytes(), true)
+}
+
+func (c *stateChain) sync(headers ...*eth.Header) {
+	param := &scom.SyncBlockHeaderParam{ChainID: pixieTestnetChainID}
+	for _, h := range headers {
+		raw, _ := json.Marshal(h)
+		param.Headers = append(param.Headers, raw)
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	c.invoke(sink.Bytes(), false)
+}
+
+// seal makes the child of parent signed by signer, body goes between the vanity and the seal in extra
+func (c *stateChain) seal(parent *eth.Header, signer int, inTurn bool, body []byte) *eth.Header {
+	header := &eth.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  etypes.EmptyUncleHash,
+		Coinbase:   c.address(signer),
+		Difficulty: big.NewInt(1),
+		Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 3,
+		Extra:      append(append(make([]byte, stateVanity), body...), make([]byte, stateSeal)...),
+	}
+	if inTurn {
+		header.Difficulty = big.NewInt(2)
+	}
+	enc, _ := rlp.EncodeToBytes([]interface{}{header.ParentHash, header.UncleHash, header.Coinbase,
+		header.Root, header.TxHash, header.ReceiptHash, header.Bloom, header.Difficulty, header.Number,
+		header.GasLimit, header.GasUsed, header.Time, header.Extra[:len(header.Extra)-stateSeal], header.MixDigest,
+		header.Nonce})
+	sig, err := crypto.Sign(crypto.Keccak256(enc), c.keys[signer])
+	assert.Nil(c.t, err)
+	copy(header.Extra[len(header.Extra)-stateSeal:], sig)
+	return header
+}
+
+// run syncs the chain through epoch changes, a reorg and back
+func (c *stateChain) run() {
+	a, b, cc, d := 0, 1, 2, 3
+	genesis := &eth.Header{
+		UncleHash:  etypes.EmptyUncleHash,
+		Coinbase:   c.address(cc),
+		Difficulty: big.NewInt(2),
+		Number:     big.NewInt(200),
+		GasLimit:   8000000,
+		Time:       1600000000,
+		Extra:      append(append(make([]byte, stateVanity), c.validators(a, b, cc)...), make([]byte, stateSeal)...),
+	}
+	c.syncGenesis(genesis, []ecommon.Address{c.address(a), c.address(b), c.address(cc)})
+
+	// abcd takes effect right after the epoch header
+	h := map[uint64]*eth.Header{200: genesis}
+	h[201] = c.seal(h[200], a, true, nil)
+	h[202] = c.seal(h[201], b, true, nil)
+	h[203] = c.seal(h[202], cc, true, c.validators(a, b, cc, d))
+	h[204] = c.seal(h[203], a, true, nil)
+	h[205] = c.seal(h[204], b, true, nil)
+	h[206] = c.seal(h[205], cc, true, nil)
+	c.sync(h[201], h[202], h[203])
+	c.sync(h[204], h[205], h[206])
+
+	// a heavier fork takes over, then the canonical chain is back
+	f206 := c.seal(h[205], d, false, nil)
+	f207 := c.seal(f206, a, false, nil)
+	f208 := c.seal(f207, b, false, nil)
+	c.sync(h[205], f206, f207)
+	c.sync(f208)
+	h[207] = c.seal(h[206], d, true, nil)
+	c.sync(h[207])
+
+	// back to abc
+	h[208] = c.seal(h[207], a, true, nil)
+	h[209] = c.seal(h[208], b, true, nil)
+	h[210] = c.seal(h[209], cc, true, c.validators(a, b, cc))
+	h[211] = c.seal(h[210], b, true, nil)
+	h[212] = c.seal(h[211], cc, true, nil)
+	h[213] = c.seal(h[212], a, true, nil)
+	h[214] = c.seal(h[213], b, true, nil)
+	c.sync(h[208], h[209], h[210], h[211], h[212], h[213], h[214])
+}
+
+// TestSyncStateCompatible checks the storage written by the handler against testdata/sync_state.json,
+// which was recorded before pixiechain moved onto evmpoa, so synced chains keep working across the upgrade
+func TestSyncStateCompatible(t *testing.T) {
+	TestFlagNoCheckPixieHeaderSig = false
+	c := newStateChain(t)
+	c.run()
+
+	raw, err := ioutil.ReadFile("testdata/sync_state.json")
+	assert.Nil(t, err)
+	var expected []map[string]string
+	assert.Nil(t, json.Unmarshal(raw, &expected))
+	assert.Equal(t, len(expected), len(c.changes))
+	for i := range expected {
+		assert.Equal(t, expected[i], c.changes[i], "step %d", i)
+	}
+}
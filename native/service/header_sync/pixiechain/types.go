@@ -1,43 +1,42 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
 package pixiechain
 
 import (
-	"math/big"
-
-	ecommon "github.com/ethereum/go-ethereum/common"
-	"github.com/polynetwork/poly/native/service/header_sync/eth"
+	"github.com/polynetwork/poly/native/service/header_sync/evmpoa"
 )
 
 // Handler ...
 type Handler struct {
+	*evmpoa.Engine
 }
 
-// GenesisHeader ...
-type GenesisHeader struct {
-	Header         eth.Header
-	PrevValidators []HeightAndValidators
-}
-
-// ExtraInfo ...
-type ExtraInfo struct {
-	ChainID *big.Int // chainId of pixie chain. mainnet: 6626, testnet: 666
-	Period  uint64
-}
-
-// Context ...
-type Context struct {
-	ExtraInfo ExtraInfo
-	ChainID   uint64
-}
-
-// HeaderWithChainID ...
-type HeaderWithChainID struct {
-	Header  *HeaderWithDifficultySum
-	ChainID uint64
-}
-
-// HeaderWithDifficultySum ...
-type HeaderWithDifficultySum struct {
-	Header          *eth.Header   `json:"header"`
-	DifficultySum   *big.Int      `json:"difficultySum"`
-	EpochParentHash *ecommon.Hash `json:"epochParentHash"`
-}
+type (
+	// GenesisHeader ...
+	GenesisHeader = evmpoa.GenesisHeader
+	// ExtraInfo ...
+	ExtraInfo = evmpoa.ExtraInfo
+	// Context ...
+	Context = evmpoa.Context
+	// HeaderWithChainID ...
+	HeaderWithChainID = evmpoa.HeaderWithChainID
+	// HeaderWithDifficultySum ...
+	HeaderWithDifficultySum = evmpoa.HeaderWithDifficultySum
+	// HeightAndValidators ...
+	HeightAndValidators = evmpoa.HeightAndValidators
+)
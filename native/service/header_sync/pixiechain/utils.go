@@ -1,26 +0,0 @@
-package pixiechain
-
-import (
-	"errors"
-	"fmt"
-
-	"github.com/ethereum/go-ethereum/params"
-)
-
-// VerifyGaslimit verifies the header gas limit according increase/decrease
-// in relation to the parent gas limit.
-func VerifyGaslimit(parentGasLimit, headerGasLimit uint64) error {
-	// Verify that the gas limit remains within allowed bounds
-	diff := int64(parentGasLimit) - int64(headerGasLimit)
-	if diff < 0 {
-		diff *= -1
-	}
-	limit := parentGasLimit / params.GasLimitBoundDivisor
-	if uint64(diff) >= limit {
-		return fmt.Errorf("invalid gas limit: have %d, want %d +-= %d", headerGasLimit, parentGasLimit, limit-1)
-	}
-	if headerGasLimit < params.MinGasLimit {
-		return errors.New("invalid gas limit below 5000")
-	}
-	return nil
-}
@@ -266,6 +266,7 @@ func (h *BorHandler) SyncBlockHeader(native *native.NativeService) error {
 		if err != nil {
 			return fmt.Errorf("bor Handler SyncBlockHeader, addHeader err: %v", err)
 		}
+		native.AddSyncedHeaders(1)
 
 		scom.NotifyPutHeader(native, headerParams.ChainID, headerWOP.Header.Number.Uint64(), headerWOP.Header.Hash().Hex())
 	}
@@ -124,6 +124,7 @@ func (h *HeimdallHandler) SyncBlockHeader(native *native.NativeService) error {
 		return fmt.Errorf("no header you commited is useful")
 	}
 	PutEpochSwitchInfo(native, params.ChainID, info)
+	native.AddSyncedHeaders(uint64(cnt))
 	return nil
 }
 
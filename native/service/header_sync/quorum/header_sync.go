@@ -97,6 +97,7 @@ func (h *QuorumHandler) SyncBlockHeader(ns *native.NativeService) error {
 		}
 
 		currh, vs = h, extra.Validators
+		ns.AddSyncedHeaders(1)
 	}
 
 	putValSet(ns, params.ChainID, currh, vs)
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solana
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// Handler is the light client of ed25519 stake weighted vote chains like solana. Instead of
+// headers it keeps the stake table of each epoch and the bank hashes attested by them.
+type Handler struct{}
+
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// SyncGenesisHeader takes a serialized StakeTable as genesis
+func (this *Handler) SyncGenesisHeader(native *native.NativeService) error {
+	param := new(hscommon.SyncGenesisHeaderParam)
+	if err := param.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, contract params deserialize error: %v", err)
+	}
+	// Get current epoch operator
+	operatorAddress, err := node_manager.GetCurConOperator(native)
+	if err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, get current consensus operator address error: %v", err)
+	}
+	//check witness
+	err = utils.ValidateOwner(native, operatorAddress)
+	if err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, checkWitness error: %v", err)
+	}
+	_, ok, err := getLatestEpoch(native, param.ChainID)
+	if err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, %v", err)
+	}
+	if ok {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, genesis header had been initialized")
+	}
+	if _, err := getExtraInfo(native, param.ChainID); err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, %v", err)
+	}
+	table := new(StakeTable)
+	if err := table.Deserialization(common.NewZeroCopySource(param.GenesisHeader)); err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, deserialize stake table error: %v", err)
+	}
+	if err := table.Validate(); err != nil {
+		return fmt.Errorf("SolanaHandler SyncGenesisHeader, invalid stake table: %v", err)
+	}
+	putStakeTable(native, param.ChainID, table)
+	return nil
+}
+
+// SyncBlockHeader takes serialized HeaderUpdate as headers
+func (this *Handler) SyncBlockHeader(native *native.NativeService) error {
+	params := new(hscommon.SyncBlockHeaderParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return fmt.Errorf("SolanaHandler SyncBlockHeader, contract params deserialize error: %v", err)
+	}
+	for i, v := range params.Headers {
+		update := new(HeaderUpdate)
+		if err := update.Deserialization(common.NewZeroCopySource(v)); err != nil {
+			return fmt.Errorf("SolanaHandler SyncBlockHeader, deserialize update %d error: %v", i, err)
+		}
+		exist, err := GetBankHash(native, params.ChainID, update.Attestation.Slot)
+		if err != nil {
+			return fmt.Errorf("SolanaHandler SyncBlockHeader, update %d: %v", i, err)
+		}
+		table, err := VerifyAndPutAttestation(native, params.ChainID, update.Attestation)
+		if err != nil {
+			return fmt.Errorf("SolanaHandler SyncBlockHeader, update %d: %v", i, err)
+		}
+		if exist == nil {
+			native.AddSyncedHeaders(1)
+		}
+		if update.NextStakeTable != nil {
+			err = rotateStakeTable(native, params.ChainID, table, update.Attestation, update.NextStakeTable)
+			if err != nil {
+				return fmt.Errorf("SolanaHandler SyncBlockHeader, update %d: %v", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (this *Handler) SyncCrossChainMsg(native *native.NativeService) error {
+	return nil
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	ptypes "github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const testChainID = uint64(200)
+
+var (
+	stakeTableAccount = hash("stake table account")
+	stakeTableOwner   = hash("stake program")
+)
+
+func hash(s string) []byte {
+	h := sha256.Sum256([]byte(s))
+	return h[:]
+}
+
+// testKeys are deterministic validator keys from seeds 1, 2, 3, 4
+func testKeys() []ed25519.PrivateKey {
+	keys := make([]ed25519.PrivateKey, 4)
+	for i := range keys {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0] = byte(i + 1)
+		keys[i] = ed25519.NewKeyFromSeed(seed)
+	}
+	return keys
+}
+
+func newStakeTable(epoch, first, last uint64, keys []ed25519.PrivateKey, stakes ...uint64) *StakeTable {
+	table := &StakeTable{Epoch: epoch, FirstSlot: first, LastSlot: last}
+	for i, k := range keys {
+		table.Validators = append(table.Validators, &Validator{PubKey: k.Public().(ed25519.PublicKey), Stake: stakes[i]})
+	}
+	return table
+}
+
+func attest(slot uint64, bankHash []byte, keys []ed25519.PrivateKey, indexes ...uint32) *SlotAttestation {
+	att := &SlotAttestation{Slot: slot, BankHash: bankHash}
+	for _, i := range indexes {
+		att.Votes = append(att.Votes, &Vote{Index: i, Signature: ed25519.Sign(keys[i], VoteSignBytes(slot, bankHash))})
+	}
+	return att
+}
+
+func newNativeService(t *testing.T) *native.NativeService {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns, err := native.NewNativeService(db, &ptypes.Transaction{}, 0, 0, common.Uint256{}, 0, nil, false)
+	assert.Nil(t, err)
+	extra, _ := json.Marshal(map[string]string{
+		"stake_table_account": hex.EncodeToString(stakeTableAccount),
+		"stake_table_owner":   hex.EncodeToString(stakeTableOwner),
+	})
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{
+		ChainId:   testChainID,
+		Router:    utils.SOLANA_ROUTER,
+		Name:      "solana",
+		ExtraInfo: extra,
+	}))
+	return ns
+}
+
+// makeAccountProof puts account among 20 other accounts in a two level tree and proves it
+func makeAccountProof(account *Account) *AccountProof {
+	leaves := make([][]byte, 20)
+	for i := range leaves {
+		leaves[i] = hash(string(rune('a' + i)))
+	}
+	leaves[17] = account.Hash()
+	root := func(nodes [][]byte) []byte {
+		h := sha256.New()
+		for _, n := range nodes {
+			h.Write(n)
+		}
+		return h.Sum(nil)
+	}
+	left := root(leaves[:16])
+	siblings := append(append([][]byte{}, leaves[16]), leaves[18:]...)
+	return &AccountProof{
+		Account:        account,
+		Path:           []*ProofLevel{{Index: 1, Siblings: siblings}, {Index: 1, Siblings: [][]byte{left}}},
+		ParentBankHash: hash("parent"),
+		SignatureCount: 7,
+		LastBlockhash:  hash("blockhash"),
+	}
+}
+
+// attestationFixture is a serialized HeaderUpdate of slot 100 with bank hash sha256("bank 100")
+// signed by validators 0, 1 and 3 of testKeys
+const attestationFixture = "640000000000000020c3dcc2f34132d4744ff15966d26ee0194cd99f2539b4d4f25f6936a5f3df6620030000000040a35911cd64df27041b6ff0814251e8f24a24a325a7a356fe5b1887b3f2dbf0de4beee157d4c263876b35ed23eb4593a805f3f9956b2ba5fd0a91879f3214c90f010000004051fcebd76e700838ab032a4f369374d96421074887035b71f0e7f4371ae08c59f001ea72369f8bcc437d66da363f6c69e5334435a431196b42fe1e9ad626a30b030000004004b21e2e61162fe3231890e61e242e7bcb9d8ea477bb6eafcb2aa33a6c8d7cc35210b72b933048495099491c85e558204bfc4e207a185185c03f5ed3da60220a00"
+
+func TestAttestationFixture(t *testing.T) {
+	ns := newNativeService(t)
+	keys := testKeys()
+	putStakeTable(ns, testChainID, newStakeTable(0, 0, 999, keys, 10, 10, 10, 10))
+
+	raw, _ := hex.DecodeString(attestationFixture)
+	update := new(HeaderUpdate)
+	assert.Nil(t, update.Deserialization(common.NewZeroCopySource(raw)))
+	assert.Nil(t, update.NextStakeTable)
+	assert.Equal(t, uint64(100), update.Attestation.Slot)
+	assert.Equal(t, hash("bank 100"), update.Attestation.BankHash)
+
+	_, err := VerifyAndPutAttestation(ns, testChainID, update.Attestation)
+	assert.Nil(t, err)
+	bankHash, err := GetBankHash(ns, testChainID, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, hash("bank 100"), bankHash)
+	assert.Equal(t, 1, len(ns.GetNotify()))
+
+	// the same bank hash again is not stored or notified twice
+	_, err = VerifyAndPutAttestation(ns, testChainID, update.Attestation)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ns.GetNotify()))
+
+	// a conflicting bank hash at the same slot
+	_, err = VerifyAndPutAttestation(ns, testChainID, attest(100, hash("fork"), keys, 0, 1, 2, 3))
+	assert.NotNil(t, err)
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	keys := testKeys()
+	table := newStakeTable(0, 0, 999, keys, 40, 30, 20, 10)
+	bankHash := hash("bank")
+
+	assert.Nil(t, verifyAttestation(table, attest(1, bankHash, keys, 0, 1)))
+	// exactly 2/3 is not a supermajority
+	table.Validators[3].Stake = 50
+	assert.NotNil(t, verifyAttestation(table, attest(1, bankHash, keys, 0, 1, 2)))
+	table.Validators[3].Stake = 10
+	// duplicate votes
+	assert.NotNil(t, verifyAttestation(table, attest(1, bankHash, keys, 0, 0, 1)))
+	// signed another slot
+	att := attest(1, bankHash, keys, 0, 1)
+	att.Slot = 2
+	assert.NotNil(t, verifyAttestation(table, att))
+	// out of range
+	att = attest(1, bankHash, keys, 0, 1)
+	att.Votes[0].Index = 4
+	assert.NotNil(t, verifyAttestation(table, att))
+}
+
+func TestRotateStakeTable(t *testing.T) {
+	ns := newNativeService(t)
+	keys := testKeys()
+	genesis := newStakeTable(0, 0, 999, keys[:3], 10, 10, 10)
+	putStakeTable(ns, testChainID, genesis)
+
+	next := newStakeTable(1, 1000, 1999, keys[1:], 10, 10, 10)
+	sink := common.NewZeroCopySink(nil)
+	next.Serialization(sink)
+	proof := makeAccountProof(&Account{Pubkey: stakeTableAccount, Owner: stakeTableOwner, Lamports: 1, Data: sink.Bytes()})
+	bankHash, err := proof.BankHash()
+	assert.Nil(t, err)
+
+	// slots of next epoch can not be attested yet
+	_, err = VerifyAndPutAttestation(ns, testChainID, attest(1000, hash("bank 1000"), keys[1:], 0, 1, 2))
+	assert.NotNil(t, err)
+
+	att := attest(999, bankHash, keys, 0, 1, 2)
+	table, err := VerifyAndPutAttestation(ns, testChainID, att)
+	assert.Nil(t, err)
+
+	// not the stake table account
+	proof.Account.Owner = hash("other")
+	assert.NotNil(t, rotateStakeTable(ns, testChainID, table, att, proof))
+	proof.Account.Owner = stakeTableOwner
+	// not the attested bank
+	assert.NotNil(t, rotateStakeTable(ns, testChainID, table, attest(998, hash("bank 998"), keys, 0, 1, 2), proof))
+
+	assert.Nil(t, rotateStakeTable(ns, testChainID, table, att, proof))
+	epoch, _, err := getLatestEpoch(ns, testChainID)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), epoch)
+
+	// new validator keys[3] counts from epoch 1 while old slots still use epoch 0
+	_, err = VerifyAndPutAttestation(ns, testChainID, attest(1000, hash("bank 1000"), keys[1:], 0, 1, 2))
+	assert.Nil(t, err)
+	_, err = VerifyAndPutAttestation(ns, testChainID, attest(500, hash("bank 500"), keys, 0, 1, 2))
+	assert.Nil(t, err)
+	_, err = VerifyAndPutAttestation(ns, testChainID, attest(501, hash("bank 501"), keys[1:], 0, 1, 2))
+	assert.NotNil(t, err)
+
+	// rotation only from the latest epoch
+	assert.NotNil(t, rotateStakeTable(ns, testChainID, table, att, proof))
+}
+
+func TestAccountProof(t *testing.T) {
+	proof := makeAccountProof(&Account{Pubkey: hash("pubkey"), Owner: hash("owner"), Lamports: 5, Data: []byte{1, 2}})
+	sink := common.NewZeroCopySink(nil)
+	proof.Serialization(sink)
+	decoded := new(AccountProof)
+	assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, proof, decoded)
+
+	bankHash, err := proof.BankHash()
+	assert.Nil(t, err)
+	proof.Account.Lamports = 6
+	tampered, err := proof.BankHash()
+	assert.Nil(t, err)
+	assert.NotEqual(t, bankHash, tampered)
+
+	proof.Path[0].Index = 17
+	_, err = proof.BankHash()
+	assert.NotNil(t, err)
+}
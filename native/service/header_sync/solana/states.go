@@ -0,0 +1,378 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+)
+
+// Validator is a vote account of the stake table
+type Validator struct {
+	// ed25519 public key of the vote authority
+	PubKey []byte
+	Stake  uint64
+}
+
+// StakeTable is the stake weighted validator set of an epoch covering slots [FirstSlot, LastSlot]
+type StakeTable struct {
+	Epoch      uint64
+	FirstSlot  uint64
+	LastSlot   uint64
+	Validators []*Validator
+}
+
+func (this *StakeTable) TotalStake() uint64 {
+	var sum uint64
+	for _, v := range this.Validators {
+		sum += v.Stake
+	}
+	return sum
+}
+
+func (this *StakeTable) Validate() error {
+	if this.FirstSlot > this.LastSlot {
+		return fmt.Errorf("first slot %d is greater than last slot %d", this.FirstSlot, this.LastSlot)
+	}
+	if len(this.Validators) == 0 {
+		return fmt.Errorf("no validator in stake table")
+	}
+	var sum uint64
+	keys := make(map[string]bool, len(this.Validators))
+	for i, v := range this.Validators {
+		if len(v.PubKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid public key length %d of validator %d", len(v.PubKey), i)
+		}
+		if keys[string(v.PubKey)] {
+			return fmt.Errorf("duplicate validator %x", v.PubKey)
+		}
+		keys[string(v.PubKey)] = true
+		if sum+v.Stake < sum {
+			return fmt.Errorf("total stake overflow")
+		}
+		sum += v.Stake
+	}
+	if sum == 0 {
+		return fmt.Errorf("total stake is zero")
+	}
+	return nil
+}
+
+func (this *StakeTable) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Epoch)
+	sink.WriteUint64(this.FirstSlot)
+	sink.WriteUint64(this.LastSlot)
+	sink.WriteVarUint(uint64(len(this.Validators)))
+	for _, v := range this.Validators {
+		sink.WriteVarBytes(v.PubKey)
+		sink.WriteUint64(v.Stake)
+	}
+}
+
+func (this *StakeTable) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Epoch, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("deserialize Epoch of StakeTable failed")
+	}
+	this.FirstSlot, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("deserialize FirstSlot of StakeTable failed")
+	}
+	this.LastSlot, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("deserialize LastSlot of StakeTable failed")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("deserialize validators length of StakeTable failed")
+	}
+	this.Validators = make([]*Validator, 0)
+	for i := uint64(0); i < n; i++ {
+		v := new(Validator)
+		v.PubKey, eof = source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("deserialize PubKey of validator %d failed", i)
+		}
+		v.Stake, eof = source.NextUint64()
+		if eof {
+			return fmt.Errorf("deserialize Stake of validator %d failed", i)
+		}
+		this.Validators = append(this.Validators, v)
+	}
+	return nil
+}
+
+// Vote is the signature of the validator at Index of the stake table
+type Vote struct {
+	Index     uint32
+	Signature []byte
+}
+
+// SlotAttestation attests that the bank at Slot, identified by BankHash, is finalized
+type SlotAttestation struct {
+	Slot     uint64
+	BankHash []byte
+	Votes    []*Vote
+}
+
+// VoteSignBytes is the message signed by validators: slot in little endian followed by the bank hash
+func VoteSignBytes(slot uint64, bankHash []byte) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint64(slot)
+	sink.WriteBytes(bankHash)
+	return sink.Bytes()
+}
+
+func (this *SlotAttestation) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Slot)
+	sink.WriteVarBytes(this.BankHash)
+	sink.WriteVarUint(uint64(len(this.Votes)))
+	for _, v := range this.Votes {
+		sink.WriteUint32(v.Index)
+		sink.WriteVarBytes(v.Signature)
+	}
+}
+
+func (this *SlotAttestation) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Slot, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("deserialize Slot of SlotAttestation failed")
+	}
+	this.BankHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize BankHash of SlotAttestation failed")
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("deserialize votes length of SlotAttestation failed")
+	}
+	this.Votes = make([]*Vote, 0)
+	for i := uint64(0); i < n; i++ {
+		v := new(Vote)
+		v.Index, eof = source.NextUint32()
+		if eof {
+			return fmt.Errorf("deserialize Index of vote %d failed", i)
+		}
+		v.Signature, eof = source.NextVarBytes()
+		if eof {
+			return fmt.Errorf("deserialize Signature of vote %d failed", i)
+		}
+		this.Votes = append(this.Votes, v)
+	}
+	return nil
+}
+
+// Account is the state of an account at a bank
+type Account struct {
+	Pubkey     []byte
+	Owner      []byte
+	Lamports   uint64
+	Executable bool
+	Data       []byte
+}
+
+// Hash is sha256 of lamports, data, executable, owner and pubkey
+func (this *Account) Hash() []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint64(this.Lamports)
+	sink.WriteBytes(this.Data)
+	sink.WriteBool(this.Executable)
+	sink.WriteBytes(this.Owner)
+	sink.WriteBytes(this.Pubkey)
+	hash := sha256.Sum256(sink.Bytes())
+	return hash[:]
+}
+
+func (this *Account) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Pubkey)
+	sink.WriteVarBytes(this.Owner)
+	sink.WriteUint64(this.Lamports)
+	sink.WriteBool(this.Executable)
+	sink.WriteVarBytes(this.Data)
+}
+
+func (this *Account) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Pubkey, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize Pubkey of Account failed")
+	}
+	this.Owner, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize Owner of Account failed")
+	}
+	this.Lamports, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("deserialize Lamports of Account failed")
+	}
+	this.Executable, eof = source.NextBool()
+	if eof {
+		return fmt.Errorf("deserialize Executable of Account failed")
+	}
+	this.Data, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize Data of Account failed")
+	}
+	return nil
+}
+
+// ProofLevel is a level of the merkle path with fanout up to 16: the node is at Index among
+// its siblings, which excludes the node itself
+type ProofLevel struct {
+	Index    uint8
+	Siblings [][]byte
+}
+
+// AccountProof proves an account in the accounts delta of a bank. The accounts delta hash is the
+// root of the merkle tree of account hashes with fanout 16, and the bank hash is sha256 of parent
+// bank hash, accounts delta hash, signature count in little endian and last blockhash.
+type AccountProof struct {
+	Account        *Account
+	Path           []*ProofLevel
+	ParentBankHash []byte
+	SignatureCount uint64
+	LastBlockhash  []byte
+}
+
+func (this *AccountProof) Serialization(sink *common.ZeroCopySink) {
+	this.Account.Serialization(sink)
+	sink.WriteVarUint(uint64(len(this.Path)))
+	for _, l := range this.Path {
+		sink.WriteUint8(l.Index)
+		sink.WriteVarUint(uint64(len(l.Siblings)))
+		for _, s := range l.Siblings {
+			sink.WriteVarBytes(s)
+		}
+	}
+	sink.WriteVarBytes(this.ParentBankHash)
+	sink.WriteUint64(this.SignatureCount)
+	sink.WriteVarBytes(this.LastBlockhash)
+}
+
+func (this *AccountProof) Deserialization(source *common.ZeroCopySource) error {
+	this.Account = new(Account)
+	if err := this.Account.Deserialization(source); err != nil {
+		return fmt.Errorf("deserialize Account of AccountProof failed: %v", err)
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return fmt.Errorf("deserialize path length of AccountProof failed")
+	}
+	this.Path = make([]*ProofLevel, 0)
+	for i := uint64(0); i < n; i++ {
+		l := new(ProofLevel)
+		l.Index, eof = source.NextUint8()
+		if eof {
+			return fmt.Errorf("deserialize Index of level %d failed", i)
+		}
+		m, eof := source.NextVarUint()
+		if eof {
+			return fmt.Errorf("deserialize siblings length of level %d failed", i)
+		}
+		for j := uint64(0); j < m; j++ {
+			s, eof := source.NextVarBytes()
+			if eof {
+				return fmt.Errorf("deserialize sibling %d of level %d failed", j, i)
+			}
+			l.Siblings = append(l.Siblings, s)
+		}
+		this.Path = append(this.Path, l)
+	}
+	this.ParentBankHash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize ParentBankHash of AccountProof failed")
+	}
+	this.SignatureCount, eof = source.NextUint64()
+	if eof {
+		return fmt.Errorf("deserialize SignatureCount of AccountProof failed")
+	}
+	this.LastBlockhash, eof = source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("deserialize LastBlockhash of AccountProof failed")
+	}
+	return nil
+}
+
+// BankHash computes the bank hash the account is included in
+func (this *AccountProof) BankHash() ([]byte, error) {
+	node := this.Account.Hash()
+	for i, l := range this.Path {
+		if len(l.Siblings) >= 16 || int(l.Index) > len(l.Siblings) {
+			return nil, fmt.Errorf("invalid level %d of path, index %d, %d siblings", i, l.Index, len(l.Siblings))
+		}
+		h := sha256.New()
+		for j, s := range l.Siblings {
+			if j == int(l.Index) {
+				h.Write(node)
+			}
+			if len(s) != sha256.Size {
+				return nil, fmt.Errorf("invalid sibling length %d at level %d", len(s), i)
+			}
+			h.Write(s)
+		}
+		if int(l.Index) == len(l.Siblings) {
+			h.Write(node)
+		}
+		node = h.Sum(nil)
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteBytes(this.ParentBankHash)
+	sink.WriteBytes(node)
+	sink.WriteUint64(this.SignatureCount)
+	sink.WriteBytes(this.LastBlockhash)
+	hash := sha256.Sum256(sink.Bytes())
+	return hash[:], nil
+}
+
+// HeaderUpdate is what relayers commit in SyncBlockHeader. NextStakeTable, if given, proves the
+// stake table account holding the stake table of the next epoch at the attested bank.
+type HeaderUpdate struct {
+	Attestation    *SlotAttestation
+	NextStakeTable *AccountProof
+}
+
+func (this *HeaderUpdate) Serialization(sink *common.ZeroCopySink) {
+	this.Attestation.Serialization(sink)
+	sink.WriteBool(this.NextStakeTable != nil)
+	if this.NextStakeTable != nil {
+		this.NextStakeTable.Serialization(sink)
+	}
+}
+
+func (this *HeaderUpdate) Deserialization(source *common.ZeroCopySource) error {
+	this.Attestation = new(SlotAttestation)
+	if err := this.Attestation.Deserialization(source); err != nil {
+		return err
+	}
+	has, eof := source.NextBool()
+	if eof {
+		return fmt.Errorf("deserialize NextStakeTable flag of HeaderUpdate failed")
+	}
+	if has {
+		this.NextStakeTable = new(AccountProof)
+		if err := this.NextStakeTable.Deserialization(source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
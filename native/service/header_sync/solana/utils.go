@@ -0,0 +1,270 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solana
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+// ExtraInfo is the json encoded extra info of side chains using the solana router
+type ExtraInfo struct {
+	// The account holding the serialized stake table of the next epoch
+	StakeTableAccount hexBytes `json:"stake_table_account"`
+	// Owner program of the stake table account
+	StakeTableOwner hexBytes `json:"stake_table_owner"`
+}
+
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(raw []byte) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*h = b
+	return nil
+}
+
+func getExtraInfo(native *native.NativeService, chainID uint64) (*ExtraInfo, error) {
+	sideChain, err := side_chain_manager.GetSideChain(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("getExtraInfo, get side chain error: %v", err)
+	}
+	if sideChain == nil {
+		return nil, fmt.Errorf("getExtraInfo, side chain %d is not registered", chainID)
+	}
+	info := new(ExtraInfo)
+	if err := json.Unmarshal(sideChain.ExtraInfo, info); err != nil {
+		return nil, fmt.Errorf("getExtraInfo, unmarshal extra info error: %v", err)
+	}
+	if len(info.StakeTableAccount) == 0 || len(info.StakeTableOwner) == 0 {
+		return nil, fmt.Errorf("getExtraInfo, stake table account or owner is not set")
+	}
+	return info, nil
+}
+
+func getLatestEpoch(native *native.NativeService, chainID uint64) (uint64, bool, error) {
+	val, err := native.GetCacheDB().Get(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.EPOCH_SWITCH), utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return 0, false, fmt.Errorf("getLatestEpoch, get from cache error: %v", err)
+	}
+	if val == nil {
+		return 0, false, nil
+	}
+	raw, err := cstates.GetValueFromRawStorageItem(val)
+	if err != nil {
+		return 0, false, fmt.Errorf("getLatestEpoch, deserialize from raw storage item error: %v", err)
+	}
+	return utils.GetBytesUint64(raw), true, nil
+}
+
+func putLatestEpoch(native *native.NativeService, chainID, epoch uint64) {
+	native.GetCacheDB().Put(
+		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.EPOCH_SWITCH), utils.GetUint64Bytes(chainID)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(epoch)))
+}
+
+func GetStakeTable(native *native.NativeService, chainID, epoch uint64) (*StakeTable, error) {
+	val, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.STAKE_TABLE),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(epoch)))
+	if err != nil {
+		return nil, fmt.Errorf("GetStakeTable, get from cache error: %v", err)
+	}
+	if val == nil {
+		return nil, fmt.Errorf("GetStakeTable, stake table of epoch %d not found", epoch)
+	}
+	raw, err := cstates.GetValueFromRawStorageItem(val)
+	if err != nil {
+		return nil, fmt.Errorf("GetStakeTable, deserialize from raw storage item error: %v", err)
+	}
+	table := new(StakeTable)
+	if err := table.Deserialization(common.NewZeroCopySource(raw)); err != nil {
+		return nil, fmt.Errorf("GetStakeTable, deserialize stake table error: %v", err)
+	}
+	return table, nil
+}
+
+func putStakeTable(native *native.NativeService, chainID uint64, table *StakeTable) {
+	sink := common.NewZeroCopySink(nil)
+	table.Serialization(sink)
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.STAKE_TABLE),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(table.Epoch)), cstates.GenRawStorageItem(sink.Bytes()))
+	putLatestEpoch(native, chainID, table.Epoch)
+}
+
+// GetBankHash returns the attested bank hash at slot, nil if not attested
+func GetBankHash(native *native.NativeService, chainID, slot uint64) ([]byte, error) {
+	val, err := native.GetCacheDB().Get(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.BANK_HASH),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(slot)))
+	if err != nil {
+		return nil, fmt.Errorf("GetBankHash, get from cache error: %v", err)
+	}
+	if val == nil {
+		return nil, nil
+	}
+	raw, err := cstates.GetValueFromRawStorageItem(val)
+	if err != nil {
+		return nil, fmt.Errorf("GetBankHash, deserialize from raw storage item error: %v", err)
+	}
+	return raw, nil
+}
+
+func putBankHash(native *native.NativeService, chainID, slot uint64, bankHash []byte) {
+	native.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.BANK_HASH),
+		utils.GetUint64Bytes(chainID), utils.GetUint64Bytes(slot)), cstates.GenRawStorageItem(bankHash))
+	hscommon.NotifyPutHeader(native, chainID, slot, hex.EncodeToString(bankHash))
+}
+
+// stakeTableForSlot finds the synced stake table of the epoch containing slot
+func stakeTableForSlot(native *native.NativeService, chainID, slot uint64) (*StakeTable, error) {
+	epoch, ok, err := getLatestEpoch(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("stakeTableForSlot, genesis stake table of chain %d is not synced", chainID)
+	}
+	for {
+		table, err := GetStakeTable(native, chainID, epoch)
+		if err != nil {
+			return nil, fmt.Errorf("stakeTableForSlot, slot %d is before the genesis stake table: %v", slot, err)
+		}
+		if slot > table.LastSlot {
+			return nil, fmt.Errorf("stakeTableForSlot, stake table of slot %d is not synced, latest epoch %d "+
+				"ends at slot %d", slot, table.Epoch, table.LastSlot)
+		}
+		if slot >= table.FirstSlot {
+			return table, nil
+		}
+		if epoch == 0 {
+			return nil, fmt.Errorf("stakeTableForSlot, slot %d is before epoch 0", slot)
+		}
+		epoch--
+	}
+}
+
+// verifyAttestation checks that more than 2/3 of the stake of table signed the attestation
+func verifyAttestation(table *StakeTable, att *SlotAttestation) error {
+	if len(att.BankHash) != 32 {
+		return fmt.Errorf("verifyAttestation, invalid bank hash length %d", len(att.BankHash))
+	}
+	msg := VoteSignBytes(att.Slot, att.BankHash)
+	signed := new(big.Int)
+	voted := make(map[uint32]bool, len(att.Votes))
+	for _, v := range att.Votes {
+		if int(v.Index) >= len(table.Validators) {
+			return fmt.Errorf("verifyAttestation, vote index %d out of range", v.Index)
+		}
+		if voted[v.Index] {
+			return fmt.Errorf("verifyAttestation, duplicate vote of validator %d", v.Index)
+		}
+		voted[v.Index] = true
+		val := table.Validators[v.Index]
+		if !ed25519.Verify(val.PubKey, msg, v.Signature) {
+			return fmt.Errorf("verifyAttestation, invalid signature of validator %x", val.PubKey)
+		}
+		signed.Add(signed, new(big.Int).SetUint64(val.Stake))
+	}
+	total := new(big.Int).SetUint64(table.TotalStake())
+	if signed.Mul(signed, big.NewInt(3)).Cmp(total.Mul(total, big.NewInt(2))) <= 0 {
+		return fmt.Errorf("verifyAttestation, supermajority of stake not reached at slot %d", att.Slot)
+	}
+	return nil
+}
+
+// VerifyAndPutAttestation verifies the attestation with the stake table of its epoch and stores
+// the attested bank hash, a bank hash already stored is neither written nor notified again
+func VerifyAndPutAttestation(native *native.NativeService, chainID uint64, att *SlotAttestation) (*StakeTable, error) {
+	table, err := stakeTableForSlot(native, chainID, att.Slot)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAttestation(table, att); err != nil {
+		return nil, err
+	}
+	exist, err := GetBankHash(native, chainID, att.Slot)
+	if err != nil {
+		return nil, err
+	}
+	if exist != nil {
+		if !bytes.Equal(exist, att.BankHash) {
+			return nil, fmt.Errorf("VerifyAndPutAttestation, conflicting bank hash %x at slot %d, stored: %x",
+				att.BankHash, att.Slot, exist)
+		}
+		return table, nil
+	}
+	putBankHash(native, chainID, att.Slot, att.BankHash)
+	return table, nil
+}
+
+// rotateStakeTable moves to the next epoch with the stake table proved at the bank attested by att
+func rotateStakeTable(native *native.NativeService, chainID uint64, current *StakeTable, att *SlotAttestation,
+	proof *AccountProof) error {
+	latest, _, err := getLatestEpoch(native, chainID)
+	if err != nil {
+		return err
+	}
+	if current.Epoch != latest {
+		return fmt.Errorf("rotateStakeTable, attestation of epoch %d is not in latest epoch %d", current.Epoch, latest)
+	}
+	info, err := getExtraInfo(native, chainID)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(proof.Account.Pubkey, info.StakeTableAccount) || !bytes.Equal(proof.Account.Owner, info.StakeTableOwner) {
+		return fmt.Errorf("rotateStakeTable, account %x owned by %x is not the stake table account",
+			proof.Account.Pubkey, proof.Account.Owner)
+	}
+	bankHash, err := proof.BankHash()
+	if err != nil {
+		return fmt.Errorf("rotateStakeTable, %v", err)
+	}
+	if !bytes.Equal(bankHash, att.BankHash) {
+		return fmt.Errorf("rotateStakeTable, account proof is for bank %x, attested %x", bankHash, att.BankHash)
+	}
+	next := new(StakeTable)
+	if err := next.Deserialization(common.NewZeroCopySource(proof.Account.Data)); err != nil {
+		return fmt.Errorf("rotateStakeTable, deserialize next stake table error: %v", err)
+	}
+	if next.Epoch != current.Epoch+1 || next.FirstSlot != current.LastSlot+1 {
+		return fmt.Errorf("rotateStakeTable, stake table of epoch %d from slot %d does not follow epoch %d ending "+
+			"at slot %d", next.Epoch, next.FirstSlot, current.Epoch, current.LastSlot)
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("rotateStakeTable, invalid next stake table: %v", err)
+	}
+	putStakeTable(native, chainID, next)
+	return nil
+}
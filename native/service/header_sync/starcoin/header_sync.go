@@ -219,6 +219,7 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 		if err != nil {
 			return errors.Errorf("SyncGenesisHeader, put blockHeader error: %v, header: %s", err, string(v))
 		}
+		native.AddSyncedHeaders(1)
 
 		// get current header of main
 		currentHeader, err := GetCurrentHeader(native, headerParams.ChainID)
@@ -154,6 +154,7 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 			if err != nil {
 				return fmt.Errorf("SyncDsBlockHeader, put blockHeader failed. Error:%s, header: %s", err, string(v))
 			}
+			native.AddSyncedHeaders(1)
 		}
 
 		if txBlock != nil {
@@ -191,6 +192,7 @@ func (h *Handler) SyncBlockHeader(native *native.NativeService) error {
 			if err != nil {
 				return fmt.Errorf("SyncTxBlockHeader, put blockHeader failed. Error:%s, header: %s", err, string(v))
 			}
+			native.AddSyncedHeaders(1)
 
 			// 5. update header of main
 			AppendHeader2Main(native, txBlock.BlockHeader.BlockNum, txBlock.BlockHash[:], headerParams.ChainID)
@@ -68,6 +68,9 @@ var (
 	HARMONY_ROUTER          = uint64(21)
 	BYTOM_ROUTER            = uint64(22)
 	RIPPLE_ROUTER           = uint64(23)
+	COMETBFT_ROUTER         = uint64(24)
+	ROLLUP_ROUTER           = uint64(25)
+	SOLANA_ROUTER           = uint64(26)
 )
 
 //Check router StartBlock to prevent hard forks
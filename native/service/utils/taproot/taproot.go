@@ -0,0 +1,319 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package taproot implements what poly needs of BIP340 schnorr signatures and BIP341 taproot to
+// keep btc in a P2TR vault spent by a single OP_CHECKSIGADD multisig leaf.
+package taproot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	OP_CHECKSIGADD = 0xba
+
+	// LeafVersion of tapscript
+	LeafVersion = 0xc0
+
+	SigHashDefault = txscript.SigHashType(0x00)
+	SigHashAll     = txscript.SigHashAll
+)
+
+var (
+	curve = btcec.S256()
+
+	// nums is the x coordinate of the BIP341 point with no known discrete logarithm, used as
+	// internal key so that the vault can only be spent through the script path
+	nums, _ = new(big.Int).SetString("50929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0", 16)
+)
+
+// TaggedHash is sha256(sha256(tag) || sha256(tag) || msgs...)
+func TaggedHash(tag string, msgs ...[]byte) []byte {
+	th := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(th[:])
+	h.Write(th[:])
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+func pad32(i *big.Int) []byte {
+	b := i.Bytes()
+	if len(b) >= 32 {
+		return b
+	}
+	res := make([]byte, 32)
+	copy(res[32-len(b):], b)
+	return res
+}
+
+// liftX returns the point with even y of x coordinate x
+func liftX(x *big.Int) (*big.Int, *big.Int, error) {
+	p := curve.P
+	if x.Cmp(p) >= 0 {
+		return nil, nil, fmt.Errorf("x is not less than field size")
+	}
+	c := new(big.Int).Exp(x, big.NewInt(3), p)
+	c.Add(c, curve.B).Mod(c, p)
+	e := new(big.Int).Add(p, big.NewInt(1))
+	e.Rsh(e, 2)
+	y := new(big.Int).Exp(c, e, p)
+	if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(c) != 0 {
+		return nil, nil, fmt.Errorf("x is not on the curve")
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(p, y)
+	}
+	return x, y, nil
+}
+
+// VerifySchnorr verifies the BIP340 signature of 32 bytes msg by x-only public key pubKey
+func VerifySchnorr(pubKey, msg, sig []byte) bool {
+	if len(pubKey) != 32 || len(msg) != 32 || len(sig) != 64 {
+		return false
+	}
+	px, py, err := liftX(new(big.Int).SetBytes(pubKey))
+	if err != nil {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(curve.P) >= 0 || s.Cmp(curve.N) >= 0 {
+		return false
+	}
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", sig[:32], pubKey, msg))
+	e.Mod(e, curve.N)
+	sx, sy := curve.ScalarBaseMult(pad32(s))
+	ex, ey := curve.ScalarMult(px, py, pad32(new(big.Int).Sub(curve.N, e)))
+	rx, ry := curve.Add(sx, sy, ex, ey)
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false
+	}
+	return ry.Bit(0) == 0 && rx.Cmp(r) == 0
+}
+
+// SignSchnorr makes a BIP340 signature with auxiliary randomness aux, only used in test
+func SignSchnorr(priv *btcec.PrivateKey, msg, aux []byte) ([]byte, error) {
+	if len(msg) != 32 || len(aux) != 32 {
+		return nil, fmt.Errorf("msg and aux must be 32 bytes")
+	}
+	d := new(big.Int).Set(priv.D)
+	px, py := curve.ScalarBaseMult(pad32(d))
+	if py.Bit(0) == 1 {
+		d.Sub(curve.N, d)
+	}
+	t := new(big.Int).Xor(d, new(big.Int).SetBytes(TaggedHash("BIP0340/aux", aux)))
+	k := new(big.Int).SetBytes(TaggedHash("BIP0340/nonce", pad32(t), pad32(px), msg))
+	k.Mod(k, curve.N)
+	if k.Sign() == 0 {
+		return nil, fmt.Errorf("nonce is zero")
+	}
+	rx, ry := curve.ScalarBaseMult(pad32(k))
+	if ry.Bit(0) == 1 {
+		k.Sub(curve.N, k)
+	}
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", pad32(rx), pad32(px), msg))
+	e.Mod(e, curve.N)
+	s := e.Mul(e, d)
+	s.Add(s, k).Mod(s, curve.N)
+	return append(pad32(rx), pad32(s)...), nil
+}
+
+// XOnlyPubKey is the 32 bytes x coordinate of the public key
+func XOnlyPubKey(pub *btcec.PublicKey) []byte {
+	return pad32(pub.X)
+}
+
+// MultisigScript makes the tapscript leaf requiring m of the x-only pubKeys:
+// <pk1> OP_CHECKSIG <pk2> OP_CHECKSIGADD ... <pkn> OP_CHECKSIGADD <m> OP_NUMEQUAL
+func MultisigScript(pubKeys [][]byte, m int) ([]byte, error) {
+	if m < 1 || m > len(pubKeys) || m > 127 {
+		return nil, fmt.Errorf("invalid m %d of %d keys", m, len(pubKeys))
+	}
+	builder := txscript.NewScriptBuilder()
+	for i, pk := range pubKeys {
+		if len(pk) != 32 {
+			return nil, fmt.Errorf("invalid length %d of no.%d x-only public key", len(pk), i)
+		}
+		builder.AddData(pk)
+		if i == 0 {
+			builder.AddOp(txscript.OP_CHECKSIG)
+		} else {
+			builder.AddOp(OP_CHECKSIGADD)
+		}
+	}
+	builder.AddInt64(int64(m)).AddOp(txscript.OP_NUMEQUAL)
+	return builder.Script()
+}
+
+// ParseMultisigScript returns the x-only public keys and the number of required signatures of
+// a script made by MultisigScript
+func ParseMultisigScript(script []byte) ([][]byte, int, error) {
+	var keys [][]byte
+	i := 0
+	for i+34 <= len(script) && script[i] == txscript.OP_DATA_32 {
+		op := script[i+33]
+		if (len(keys) == 0 && op != txscript.OP_CHECKSIG) || (len(keys) > 0 && op != OP_CHECKSIGADD) {
+			return nil, 0, fmt.Errorf("unexpected opcode %x after no.%d key", op, len(keys))
+		}
+		keys = append(keys, script[i+1:i+33])
+		i += 34
+	}
+	if len(keys) == 0 {
+		return nil, 0, fmt.Errorf("no public key in script")
+	}
+	var m int
+	switch rest := script[i:]; {
+	case len(rest) == 2 && rest[0] >= txscript.OP_1 && rest[0] <= txscript.OP_16:
+		m = int(rest[0] - txscript.OP_1 + 1)
+	case len(rest) == 3 && rest[0] == txscript.OP_DATA_1 && rest[1] > 16 && rest[1] < 0x80:
+		m = int(rest[1])
+	default:
+		return nil, 0, fmt.Errorf("unexpected end of script")
+	}
+	if script[len(script)-1] != txscript.OP_NUMEQUAL {
+		return nil, 0, fmt.Errorf("script is not ended with OP_NUMEQUAL")
+	}
+	if m > len(keys) {
+		return nil, 0, fmt.Errorf("required %d signatures of %d keys", m, len(keys))
+	}
+	return keys, m, nil
+}
+
+// IsMultisigScript tells if script is a taproot multisig leaf
+func IsMultisigScript(script []byte) bool {
+	_, _, err := ParseMultisigScript(script)
+	return err == nil
+}
+
+func compactSize(n int) []byte {
+	var buf bytes.Buffer
+	_ = wire.WriteVarInt(&buf, 0, uint64(n))
+	return buf.Bytes()
+}
+
+// LeafHash is the tapleaf hash of script
+func LeafHash(script []byte) []byte {
+	return TaggedHash("TapLeaf", []byte{LeafVersion}, compactSize(len(script)), script)
+}
+
+// OutputKey tweaks the NUMS internal key with script as the only leaf, returning the x-only
+// output key and whether its y is odd
+func OutputKey(script []byte) ([]byte, bool) {
+	px, py, _ := liftX(nums)
+	t := TaggedHash("TapTweak", pad32(px), LeafHash(script))
+	tx, ty := curve.ScalarBaseMult(t)
+	qx, qy := curve.Add(px, py, tx, ty)
+	return pad32(qx), qy.Bit(0) == 1
+}
+
+// PayToTaprootScript is the P2TR script pubkey of the vault with script as the only leaf
+func PayToTaprootScript(script []byte) []byte {
+	key, _ := OutputKey(script)
+	return append([]byte{txscript.OP_1, txscript.OP_DATA_32}, key...)
+}
+
+// IsPayToTaproot tells if pkScript is a segwit v1 output
+func IsPayToTaproot(pkScript []byte) bool {
+	return len(pkScript) == 34 && pkScript[0] == txscript.OP_1 && pkScript[1] == txscript.OP_DATA_32
+}
+
+// ControlBlock proves script is the only leaf of the vault
+func ControlBlock(script []byte) []byte {
+	_, odd := OutputKey(script)
+	first := byte(LeafVersion)
+	if odd {
+		first |= 1
+	}
+	return append([]byte{first}, pad32(nums)...)
+}
+
+// CalcScriptPathSigHash is the BIP341 signature hash of input idx spending the leaf script.
+// prevAmounts and prevScripts are of all inputs of tx. Only SIGHASH_DEFAULT and SIGHASH_ALL
+// are supported.
+func CalcScriptPathSigHash(tx *wire.MsgTx, idx int, prevAmounts []int64, prevScripts [][]byte,
+	hashType txscript.SigHashType, script []byte) ([]byte, error) {
+	if hashType != SigHashDefault && hashType != SigHashAll {
+		return nil, fmt.Errorf("unsupported sighash type %x", hashType)
+	}
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, fmt.Errorf("input index %d out of range", idx)
+	}
+	if len(prevAmounts) != len(tx.TxIn) || len(prevScripts) != len(tx.TxIn) {
+		return nil, fmt.Errorf("amounts and scripts of all inputs are required")
+	}
+	var prevouts, amounts, scripts, sequences, outputs bytes.Buffer
+	for i, in := range tx.TxIn {
+		prevouts.Write(in.PreviousOutPoint.Hash[:])
+		_ = binary.Write(&prevouts, binary.LittleEndian, in.PreviousOutPoint.Index)
+		_ = binary.Write(&amounts, binary.LittleEndian, prevAmounts[i])
+		scripts.Write(compactSize(len(prevScripts[i])))
+		scripts.Write(prevScripts[i])
+		_ = binary.Write(&sequences, binary.LittleEndian, in.Sequence)
+	}
+	for _, out := range tx.TxOut {
+		if err := wire.WriteTxOut(&outputs, 0, 0, out); err != nil {
+			return nil, fmt.Errorf("serialize output error: %v", err)
+		}
+	}
+	sum := func(b *bytes.Buffer) []byte {
+		h := sha256.Sum256(b.Bytes())
+		return h[:]
+	}
+
+	var msg bytes.Buffer
+	msg.WriteByte(0x00) // epoch
+	msg.WriteByte(byte(hashType))
+	_ = binary.Write(&msg, binary.LittleEndian, tx.Version)
+	_ = binary.Write(&msg, binary.LittleEndian, tx.LockTime)
+	msg.Write(sum(&prevouts))
+	msg.Write(sum(&amounts))
+	msg.Write(sum(&scripts))
+	msg.Write(sum(&sequences))
+	msg.Write(sum(&outputs))
+	msg.WriteByte(2) // spend type: script path without annex
+	_ = binary.Write(&msg, binary.LittleEndian, uint32(idx))
+	msg.Write(LeafHash(script))
+	msg.WriteByte(0x00) // key version
+	_ = binary.Write(&msg, binary.LittleEndian, uint32(0xffffffff))
+	return TaggedHash("TapSighash", msg.Bytes()), nil
+}
+
+// ParseSignature splits a 64 bytes signature with default sighash or 65 bytes with an explicit one
+func ParseSignature(sig []byte) ([]byte, txscript.SigHashType, error) {
+	switch len(sig) {
+	case 64:
+		return sig, SigHashDefault, nil
+	case 65:
+		if txscript.SigHashType(sig[64]) == SigHashDefault {
+			return nil, 0, fmt.Errorf("explicit default sighash type is not allowed")
+		}
+		return sig[:64], txscript.SigHashType(sig[64]), nil
+	default:
+		return nil, 0, fmt.Errorf("invalid schnorr signature length %d", len(sig))
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package taproot
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestSchnorrVector(t *testing.T) {
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), []byte{3})
+	msg := make([]byte, 32)
+	sig, err := SignSchnorr(priv, msg, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := XOnlyPubKey(priv.PubKey())
+	if hex.EncodeToString(pub) != "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9" {
+		t.Fatalf("wrong public key %x", pub)
+	}
+	if hex.EncodeToString(sig) != "e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca8215"+
+		"25f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0" {
+		t.Fatalf("wrong signature %x", sig)
+	}
+	if !VerifySchnorr(pub, msg, sig) {
+		t.Fatal("failed to verify")
+	}
+	sig[63] ^= 1
+	if VerifySchnorr(pub, msg, sig) {
+		t.Fatal("verified a tampered signature")
+	}
+}
+
+func TestMultisigScript(t *testing.T) {
+	var keys [][]byte
+	for i := 0; i < 3; i++ {
+		priv, _ := btcec.NewPrivateKey(btcec.S256())
+		keys = append(keys, XOnlyPubKey(priv.PubKey()))
+	}
+	script, err := MultisigScript(keys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, m, err := ParseMultisigScript(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != 2 || len(parsed) != 3 || !bytes.Equal(parsed[2], keys[2]) {
+		t.Fatalf("wrong parsed script: m %d, %d keys", m, len(parsed))
+	}
+	if IsMultisigScript(script[1:]) || IsMultisigScript(script[:len(script)-1]) {
+		t.Fatal("malformed script parsed")
+	}
+	if _, err := MultisigScript(keys, 4); err == nil {
+		t.Fatal("m larger than n should fail")
+	}
+
+	pk := PayToTaprootScript(script)
+	if !IsPayToTaproot(pk) {
+		t.Fatal("not a P2TR script")
+	}
+	cb := ControlBlock(script)
+	if len(cb) != 33 || cb[0]&0xfe != LeafVersion {
+		t.Fatalf("wrong control block %x", cb)
+	}
+}
+
+func TestScriptPathSigHash(t *testing.T) {
+	var privs []*btcec.PrivateKey
+	var keys [][]byte
+	for i := 0; i < 2; i++ {
+		priv, _ := btcec.NewPrivateKey(btcec.S256())
+		privs = append(privs, priv)
+		keys = append(keys, XOnlyPubKey(priv.PubKey()))
+	}
+	script, _ := MultisigScript(keys, 2)
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{2}, 1), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, PayToTaprootScript(script)))
+	amts := []int64{600, 500}
+	scripts := [][]byte{PayToTaprootScript(script), PayToTaprootScript(script)}
+
+	h0, err := CalcScriptPathSigHash(tx, 0, amts, scripts, SigHashDefault, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h1, _ := CalcScriptPathSigHash(tx, 1, amts, scripts, SigHashDefault, script)
+	hAll, _ := CalcScriptPathSigHash(tx, 0, amts, scripts, SigHashAll, script)
+	if bytes.Equal(h0, h1) || bytes.Equal(h0, hAll) {
+		t.Fatal("sighash should commit to input index and hash type")
+	}
+	amts[1] = 501
+	if h, _ := CalcScriptPathSigHash(tx, 0, amts, scripts, SigHashDefault, script); bytes.Equal(h, h0) {
+		t.Fatal("sighash should commit to amounts of all inputs")
+	}
+	if _, err := CalcScriptPathSigHash(tx, 0, amts[:1], scripts, SigHashDefault, script); err == nil {
+		t.Fatal("missing amounts should fail")
+	}
+
+	sig, _ := SignSchnorr(privs[0], h0, make([]byte, 32))
+	raw, ht, err := ParseSignature(sig)
+	if err != nil || ht != SigHashDefault || !VerifySchnorr(keys[0], h0, raw) {
+		t.Fatal("failed to verify default sighash signature")
+	}
+	if _, _, err := ParseSignature(append(sig, 0)); err == nil {
+		t.Fatal("explicit default sighash should fail")
+	}
+}
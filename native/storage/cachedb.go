@@ -24,16 +24,26 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+// AccessTracer observes the storage accesses of contracts, used to trace a simulated transaction
+type AccessTracer interface {
+	TraceGet(key, value []byte)
+	TracePut(key, value []byte)
+	TraceDelete(key []byte)
+}
+
 // CacheDB is smart contract execute cache, it contain transaction cache and block cache
 // When smart contract execute finish, need to commit transaction cache to block cache
 type CacheDB struct {
 	memdb      *overlaydb.MemDB
+	layers     []*overlaydb.MemDB // transaction cache under memdb, one layer per open snapshot
 	backend    *overlaydb.OverlayDB
 	keyScratch []byte
+	tracer     AccessTracer
 }
 
 const initCap = 16 * 1024
 const initKvNum = 16
+const layerCap = 1024
 
 // NewCacheDB return a new contract cache
 func NewCacheDB(store *overlaydb.OverlayDB) *CacheDB {
@@ -43,10 +53,40 @@ func NewCacheDB(store *overlaydb.OverlayDB) *CacheDB {
 	}
 }
 
+// SetTracer sets the tracer observing the storage accesses through this cache
+func (self *CacheDB) SetTracer(tracer AccessTracer) {
+	self.tracer = tracer
+}
+
 func (self *CacheDB) Reset() {
+	self.layers = nil
 	self.memdb.Reset()
 }
 
+// Snapshot opens a layer of the transaction cache taking the writes after it, the layer is closed by
+// either RevertToSnapshot or ReleaseSnapshot, which also close the snapshots taken after it
+func (self *CacheDB) Snapshot() int {
+	self.layers = append(self.layers, self.memdb)
+	self.memdb = overlaydb.NewMemDB(layerCap, initKvNum)
+	return len(self.layers)
+}
+
+// RevertToSnapshot drops the writes made after snapshot
+func (self *CacheDB) RevertToSnapshot(snapshot int) {
+	self.memdb = self.layers[snapshot-1]
+	self.layers = self.layers[:snapshot-1]
+}
+
+// ReleaseSnapshot keeps the writes made after snapshot, merging them into the layer under it
+func (self *CacheDB) ReleaseSnapshot(snapshot int) {
+	for len(self.layers) >= snapshot {
+		parent := self.layers[len(self.layers)-1]
+		self.memdb.ForEach(parent.Put)
+		self.memdb = parent
+		self.layers = self.layers[:len(self.layers)-1]
+	}
+}
+
 func ensureBuffer(b []byte, n int) []byte {
 	if cap(b) < n {
 		return make([]byte, n)
@@ -61,8 +101,9 @@ func makePrefixedKey(dst []byte, prefix byte, key []byte) []byte {
 	return dst
 }
 
-// Commit current transaction cache to block cache
+// Commit current transaction cache to block cache, open snapshots are released first
 func (self *CacheDB) Commit() {
+	self.ReleaseSnapshot(1)
 	self.memdb.ForEach(func(key, val []byte) {
 		if len(val) == 0 {
 			self.backend.Delete(key)
@@ -73,6 +114,9 @@ func (self *CacheDB) Commit() {
 }
 
 func (self *CacheDB) Put(key []byte, value []byte) {
+	if self.tracer != nil {
+		self.tracer.TracePut(key, value)
+	}
 	self.put(common.ST_STORAGE, key, value)
 }
 
@@ -82,12 +126,19 @@ func (self *CacheDB) put(prefix common.DataEntryPrefix, key []byte, value []byte
 }
 
 func (self *CacheDB) Get(key []byte) ([]byte, error) {
-	return self.get(common.ST_STORAGE, key)
+	value, err := self.get(common.ST_STORAGE, key)
+	if err == nil && self.tracer != nil {
+		self.tracer.TraceGet(key, value)
+	}
+	return value, err
 }
 
 func (self *CacheDB) get(prefix common.DataEntryPrefix, key []byte) ([]byte, error) {
 	self.keyScratch = makePrefixedKey(self.keyScratch, byte(prefix), key)
 	value, unknown := self.memdb.Get(self.keyScratch)
+	for i := len(self.layers) - 1; unknown && i >= 0; i-- {
+		value, unknown = self.layers[i].Get(self.keyScratch)
+	}
 	if unknown {
 		v, err := self.backend.Get(self.keyScratch)
 		if err != nil {
@@ -100,6 +151,9 @@ func (self *CacheDB) get(prefix common.DataEntryPrefix, key []byte) ([]byte, err
 }
 
 func (self *CacheDB) Delete(key []byte) {
+	if self.tracer != nil {
+		self.tracer.TraceDelete(key)
+	}
 	self.delete(common.ST_STORAGE, key)
 }
 
@@ -109,15 +163,31 @@ func (self *CacheDB) delete(prefix common.DataEntryPrefix, key []byte) {
 	self.memdb.Delete(self.keyScratch)
 }
 
+// ForEachChange calls f with the storage keys written in the transaction cache, their values in block cache
+// and transaction cache, after is empty if the key is deleted. Open snapshots are released first
+func (self *CacheDB) ForEachChange(f func(key, before, after []byte)) {
+	self.ReleaseSnapshot(1)
+	self.memdb.ForEach(func(key, val []byte) {
+		if len(key) == 0 || key[0] != byte(common.ST_STORAGE) {
+			return
+		}
+		before, _ := self.backend.Get(key)
+		f(key[1:], before, val)
+	})
+}
+
 func (self *CacheDB) NewIterator(key []byte) common.StoreIterator {
 	pkey := make([]byte, 1+len(key))
 	pkey[0] = byte(common.ST_STORAGE)
 	copy(pkey[1:], key)
 	prefixRange := util.BytesPrefix(pkey)
-	backIter := self.backend.NewIterator(pkey)
+	var iter common.StoreIterator = self.backend.NewIterator(pkey)
+	for _, layer := range self.layers {
+		iter = overlaydb.NewJoinIter(layer.NewIterator(prefixRange), iter)
+	}
 	memIter := self.memdb.NewIterator(prefixRange)
 
-	return &Iter{overlaydb.NewJoinIter(memIter, backIter)}
+	return &Iter{overlaydb.NewJoinIter(memIter, iter)}
 }
 
 type Iter struct {
@@ -82,3 +82,67 @@ func TestCacheDB(t *testing.T) {
 	}
 
 }
+
+func TestCacheDBSnapshot(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	cache := NewCacheDB(overlaydb.NewOverlayDB(memback))
+	cache.Put([]byte("kept"), []byte("v1"))
+	cache.Put([]byte("deleted"), []byte("v1"))
+	cache.Delete([]byte("deleted"))
+
+	snapshot := cache.Snapshot()
+	cache.Put([]byte("kept"), []byte("v2"))
+	cache.Put([]byte("deleted"), []byte("v2"))
+	cache.Put([]byte("new"), []byte("v2"))
+	cache.RevertToSnapshot(snapshot)
+
+	value, err := cache.Get([]byte("kept"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	value, err = cache.Get([]byte("deleted"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+	value, err = cache.Get([]byte("new"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+}
+
+func TestCacheDBNestedSnapshot(t *testing.T) {
+	memback, _ := leveldbstore.NewMemLevelDBStore()
+	cache := NewCacheDB(overlaydb.NewOverlayDB(memback))
+	cache.Put([]byte("a"), []byte("v1"))
+	cache.Put([]byte("b"), []byte("v1"))
+
+	outer := cache.Snapshot()
+	cache.Put([]byte("a"), []byte("v2"))
+	cache.Delete([]byte("b"))
+	inner := cache.Snapshot()
+	cache.Put([]byte("c"), []byte("v3"))
+	cache.ReleaseSnapshot(inner)
+	inner = cache.Snapshot()
+	cache.Put([]byte("a"), []byte("v4"))
+	cache.RevertToSnapshot(inner)
+
+	iter := cache.NewIterator(nil)
+	var kvs []string
+	for has := iter.First(); has; has = iter.Next() {
+		kvs = append(kvs, string(iter.Key())+"="+string(iter.Value()))
+	}
+	iter.Release()
+	assert.Equal(t, []string{"a=v2", "c=v3"}, kvs)
+
+	cache.RevertToSnapshot(outer)
+	value, err := cache.Get([]byte("b"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	value, err = cache.Get([]byte("c"))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	cache.Snapshot()
+	cache.Put([]byte("d"), []byte("v5"))
+	cache.Commit()
+	value, err = cache.backend.Get(append([]byte{byte(common.ST_STORAGE)}, "d"...))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v5"), value)
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package trace records the execution of a simulated transaction: the native contract call stack,
+// storage accesses with decoded keys, notifications and state changes
+package trace
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+)
+
+const (
+	ACCESS_READ   = "read"
+	ACCESS_WRITE  = "write"
+	ACCESS_DELETE = "delete"
+)
+
+var contractNames = map[common.Address]string{
+	utils.HeaderSyncContractAddress:        "HeaderSync",
+	utils.CrossChainManagerContractAddress: "CrossChainManager",
+	utils.SideChainManagerContractAddress:  "SideChainManager",
+	utils.NodeManagerContractAddress:       "NodeManager",
+	utils.RelayerManagerContractAddress:    "RelayerManager",
+	utils.Neo3StateManagerContractAddress:  "Neo3StateManager",
+	utils.SignatureManagerContractAddress:  "SignatureManager",
+	utils.ReplenishContractAddress:         "Replenish",
+}
+
+// Access is a storage access of a call
+type Access struct {
+	Op         string
+	Key        string //hex
+	DecodedKey string
+	Value      string //hex, empty for delete or missing key
+}
+
+// Notify is a notification of a call
+type Notify struct {
+	ContractAddress string
+	States          interface{}
+}
+
+// Call is an invocation of native contract method
+type Call struct {
+	Contract     string
+	ContractName string
+	Method       string
+	Args         string //hex
+	Result       string //hex of the returned bytes
+	Error        string
+	Accesses     []*Access
+	Notify       []*Notify
+	Calls        []*Call //nested invocations
+}
+
+// Failure locates the call where a failed execution started to fail
+type Failure struct {
+	Path  []string //"ContractName.method" of the calls from the root to the failing one
+	Error string   //error returned by the failing call
+}
+
+// StateChange is a storage key written by the transaction
+type StateChange struct {
+	Key        string //hex
+	DecodedKey string
+	Before     string //hex, empty if the key did not exist
+	After      string //hex, empty if the key is deleted
+}
+
+// Result of a simulated transaction
+type Result struct {
+	State       byte   //event.CONTRACT_STATE_SUCCESS or event.CONTRACT_STATE_FAIL
+	Height      uint32 //height of the state executed against
+	Result      string //hex of the returned bytes
+	Error       string
+	Call        *Call //root invocation, nil if the invocation can not be decoded
+	Failure     *Failure
+	Notify      []*Notify //notifications kept by a successful execution
+	CrossHashes []string
+	StateDiff   []*StateChange
+}
+
+// Tracer records the call stack and storage accesses, implements native.Tracer and storage.AccessTracer
+type Tracer struct {
+	root  *Call
+	stack []*Call
+}
+
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+func (this *Tracer) current() *Call {
+	if len(this.stack) == 0 {
+		return nil
+	}
+	return this.stack[len(this.stack)-1]
+}
+
+func (this *Tracer) CaptureEnter(contract common.Address, method string, args []byte) {
+	call := &Call{
+		Contract:     contract.ToHexString(),
+		ContractName: contractNames[contract],
+		Method:       method,
+		Args:         hex.EncodeToString(args),
+	}
+	if parent := this.current(); parent != nil {
+		parent.Calls = append(parent.Calls, call)
+	} else if this.root == nil {
+		this.root = call
+	}
+	this.stack = append(this.stack, call)
+}
+
+func (this *Tracer) CaptureExit(result interface{}, err error) {
+	call := this.current()
+	if call == nil {
+		return
+	}
+	if data, ok := result.([]byte); ok {
+		call.Result = hex.EncodeToString(data)
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	this.stack = this.stack[:len(this.stack)-1]
+}
+
+func (this *Tracer) CaptureNotify(notify *event.NotifyEventInfo) {
+	if call := this.current(); call != nil {
+		call.Notify = append(call.Notify, NewNotify(notify))
+	}
+}
+
+func (this *Tracer) trace(op string, key, value []byte) {
+	if call := this.current(); call != nil {
+		call.Accesses = append(call.Accesses, &Access{
+			Op:         op,
+			Key:        hex.EncodeToString(key),
+			DecodedKey: DecodeKey(key),
+			Value:      hex.EncodeToString(value),
+		})
+	}
+}
+
+func (this *Tracer) TraceGet(key, value []byte) {
+	this.trace(ACCESS_READ, key, value)
+}
+
+func (this *Tracer) TracePut(key, value []byte) {
+	this.trace(ACCESS_WRITE, key, value)
+}
+
+func (this *Tracer) TraceDelete(key []byte) {
+	this.trace(ACCESS_DELETE, key, nil)
+}
+
+// Root returns the root invocation traced
+func (this *Tracer) Root() *Call {
+	return this.root
+}
+
+// Failure returns the deepest failed call along the last failed calls from the root, nil if the root succeeded
+func (this *Tracer) Failure() *Failure {
+	call := this.root
+	if call == nil || call.Error == "" {
+		return nil
+	}
+	failure := &Failure{}
+	for call != nil {
+		failure.Path = append(failure.Path, call.Name())
+		failure.Error = call.Error
+		var failed *Call
+		for i := len(call.Calls) - 1; i >= 0; i-- {
+			if call.Calls[i].Error != "" {
+				failed = call.Calls[i]
+				break
+			}
+		}
+		call = failed
+	}
+	return failure
+}
+
+// Name returns "ContractName.method", contract address in hex instead if it is not a known native contract
+func (this *Call) Name() string {
+	if this.ContractName != "" {
+		return this.ContractName + "." + this.Method
+	}
+	return this.Contract + "." + this.Method
+}
+
+func NewNotify(notify *event.NotifyEventInfo) *Notify {
+	return &Notify{ContractAddress: notify.ContractAddress.ToHexString(), States: notify.States}
+}
+
+func NewStateChange(key, before, after []byte) *StateChange {
+	return &StateChange{
+		Key:        hex.EncodeToString(key),
+		DecodedKey: DecodeKey(key),
+		Before:     hex.EncodeToString(before),
+		After:      hex.EncodeToString(after),
+	}
+}
+
+// DecodeKey decodes storage key made by utils.ConcatKey as contract/prefix/rest in hex, like
+// "CrossChainManager/request/0a00000000000000", prefix is the leading identifier characters after contract
+func DecodeKey(key []byte) string {
+	if len(key) < common.ADDR_LEN {
+		return hex.EncodeToString(key)
+	}
+	var address common.Address
+	copy(address[:], key[:common.ADDR_LEN])
+	parts := []string{contractNames[address]}
+	if parts[0] == "" {
+		parts[0] = address.ToHexString()
+	}
+	rest := key[common.ADDR_LEN:]
+	n := 0
+	for n < len(rest) && isIdentChar(rest[n]) {
+		n++
+	}
+	if n >= 2 {
+		parts = append(parts, string(rest[:n]))
+		rest = rest[n:]
+	}
+	if len(rest) > 0 {
+		parts = append(parts, hex.EncodeToString(rest))
+	}
+	return strings.Join(parts, "/")
+}
+
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
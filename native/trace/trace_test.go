@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The poly network is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The poly network is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with the poly network.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	outerAddress = common.Address{0xaa}
+	innerAddress = common.Address{0xbb}
+)
+
+func init() {
+	native.Contracts[outerAddress] = func(service *native.NativeService) {
+		service.Register("outer", func(service *native.NativeService) ([]byte, error) {
+			key := utils.ConcatKey(outerAddress, []byte("count"))
+			value, _ := service.GetCacheDB().Get(key)
+			service.GetCacheDB().Put(key, append(value, 1))
+			if _, err := service.NativeCall(innerAddress, "inner", service.GetInput()); err != nil {
+				return nil, fmt.Errorf("outer, call inner error: %v", err)
+			}
+			return []byte{1}, nil
+		})
+	}
+	native.Contracts[innerAddress] = func(service *native.NativeService) {
+		service.Register("inner", func(service *native.NativeService) ([]byte, error) {
+			if len(service.GetInput()) == 0 {
+				return nil, fmt.Errorf("inner, empty input")
+			}
+			service.GetCacheDB().Put(utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte("request"), service.GetInput()), []byte{2})
+			service.AddNotify(&event.NotifyEventInfo{ContractAddress: innerAddress, States: []interface{}{"inner"}})
+			return []byte{2}, nil
+		})
+	}
+}
+
+func simulate(t *testing.T, args []byte) (*Tracer, *storage.CacheDB, error) {
+	store, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	cache := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	sink := common.NewZeroCopySink(nil)
+	(&states.ContractInvokeParam{Address: outerAddress, Method: "outer", Args: args}).Serialization(sink)
+	service, err := native.NewNativeService(cache, &types.Transaction{}, 0, 0, common.Uint256{}, 0, sink.Bytes(), true)
+	assert.Nil(t, err)
+	tracer := NewTracer()
+	cache.SetTracer(tracer)
+	service.SetTracer(tracer)
+	_, err = service.Invoke()
+	return tracer, cache, err
+}
+
+func TestTraceSuccess(t *testing.T) {
+	tracer, cache, err := simulate(t, []byte{0x0a})
+	assert.Nil(t, err)
+	assert.Nil(t, tracer.Failure())
+
+	root := tracer.Root()
+	assert.Equal(t, outerAddress.ToHexString()+".outer", root.Name())
+	assert.Equal(t, "01", root.Result)
+	assert.Equal(t, 2, len(root.Accesses))
+	assert.Equal(t, ACCESS_READ, root.Accesses[0].Op)
+	assert.Equal(t, ACCESS_WRITE, root.Accesses[1].Op)
+	assert.Equal(t, outerAddress.ToHexString()+"/count/01", root.Accesses[1].DecodedKey+"/"+root.Accesses[1].Value)
+
+	assert.Equal(t, 1, len(root.Calls))
+	inner := root.Calls[0]
+	assert.Equal(t, "0a", inner.Args)
+	assert.Equal(t, "CrossChainManager/request/0a", inner.Accesses[0].DecodedKey)
+	assert.Equal(t, 1, len(inner.Notify))
+
+	changes := []*StateChange{}
+	cache.ForEachChange(func(key, before, after []byte) {
+		changes = append(changes, NewStateChange(key, before, after))
+	})
+	assert.Equal(t, 2, len(changes))
+	for _, change := range changes {
+		assert.Equal(t, "", change.Before)
+	}
+}
+
+func TestTraceFailure(t *testing.T) {
+	tracer, _, err := simulate(t, nil)
+	assert.NotNil(t, err)
+	failure := tracer.Failure()
+	assert.Equal(t, []string{outerAddress.ToHexString() + ".outer", innerAddress.ToHexString() + ".inner"}, failure.Path)
+	assert.Contains(t, failure.Error, "inner, empty input")
+}
+
+func TestDecodeKey(t *testing.T) {
+	key := utils.ConcatKey(utils.NodeManagerContractAddress, []byte("peerPoolMap"), []byte{0, 0, 0, 10})
+	assert.Equal(t, "NodeManager/peerPoolMap/0000000a", DecodeKey(key))
+	assert.Equal(t, "0102", DecodeKey([]byte{1, 2}))
+	assert.Equal(t, "HeaderSync/"+hex.EncodeToString([]byte{0xff}), DecodeKey(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte{0xff})))
+}
@@ -80,6 +80,8 @@ func (this *P2PActor) Receive(ctx actor.Context) {
 		this.handleGetTimeReq(ctx, msg)
 	case *GetNeighborAddrsReq:
 		this.handleGetNeighborAddrsReq(ctx, msg)
+	case *GetBestPeerHeightReq:
+		this.handleGetBestPeerHeightReq(ctx, msg)
 	case *GetRelayStateReq:
 		this.handleGetRelayStateReq(ctx, msg)
 	case *GetNodeTypeReq:
@@ -213,6 +215,17 @@ func (this *P2PActor) handleGetNeighborAddrsReq(ctx actor.Context, req *GetNeigh
 	}
 }
 
+//best peer height handler
+func (this *P2PActor) handleGetBestPeerHeightReq(ctx actor.Context, req *GetBestPeerHeightReq) {
+	height := this.server.GetBestPeerHeight()
+	if ctx.Sender() != nil {
+		resp := &GetBestPeerHeightRsp{
+			Height: height,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
+
 //peer`s relay state handler
 func (this *P2PActor) handleGetRelayStateReq(ctx actor.Context, req *GetRelayStateReq) {
 	ret := this.server.GetNetWork().GetRelay()
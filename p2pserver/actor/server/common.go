@@ -128,6 +128,15 @@ type GetNeighborAddrsRsp struct {
 	Addrs []types.PeerAddr
 }
 
+//get best known peer height request
+type GetBestPeerHeightReq struct {
+}
+
+//response of best known peer height
+type GetBestPeerHeightRsp struct {
+	Height uint32
+}
+
 type TransmitConsensusMsgReq struct {
 	Target uint64
 	Msg    ptypes.Message
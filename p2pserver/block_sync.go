@@ -834,6 +834,20 @@ func (this *BlockSyncMgr) getNodeWithMinFailedTimes(flightInfo *SyncFlightInfo,
 	}
 }
 
+//GetBestPeerHeight return the highest block height reported by established neighbors
+func (this *BlockSyncMgr) GetBestPeerHeight() uint32 {
+	bestHeight := uint32(0)
+	for _, n := range this.server.network.GetNeighbors() {
+		if n.GetSyncState() != p2pComm.ESTABLISH {
+			continue
+		}
+		if height := uint32(n.GetHeight()); height > bestHeight {
+			bestHeight = height
+		}
+	}
+	return bestHeight
+}
+
 //Stop to sync
 func (this *BlockSyncMgr) Close() {
 	close(this.exitCh)
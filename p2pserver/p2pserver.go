@@ -140,6 +140,11 @@ func (this *P2PServer) GetNeighborAddrs() []common.PeerAddr {
 	return this.network.GetNeighborAddrs()
 }
 
+//GetBestPeerHeight return the highest block height known from neighbors
+func (this *P2PServer) GetBestPeerHeight() uint32 {
+	return this.blockSync.GetBestPeerHeight()
+}
+
 //Xmit called by other module to broadcast msg
 func (this *P2PServer) Xmit(message interface{}) error {
 	log.Debug()